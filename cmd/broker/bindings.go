@@ -0,0 +1,54 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/kyma-project/kyma-environment-broker/internal/broker"
+	brokerBindings "github.com/kyma-project/kyma-environment-broker/internal/broker/bindings"
+	"github.com/kyma-project/kyma-environment-broker/internal/event"
+	"github.com/kyma-project/kyma-environment-broker/internal/kubeconfig"
+	"github.com/kyma-project/kyma-environment-broker/internal/process"
+	"github.com/kyma-project/kyma-environment-broker/internal/storage"
+)
+
+// NewBindingsManager creates the manager of the service account and OIDC bindings
+func NewBindingsManager(clientProvider K8sClientProvider, kubeconfigProvider KubeconfigProvider, kcBuilder kubeconfig.KcBuilder) brokerBindings.BindingsManager {
+	return brokerBindings.NewCredentialsTypeBindingsManager(
+		brokerBindings.NewServiceAccountBindingsManager(clientProvider, kubeconfigProvider),
+		brokerBindings.NewOIDCBindingsManager(clientProvider, kcBuilder),
+	)
+}
+
+// NewBindingProcessingQueue creates the queue which creates the credentials of the bindings accepted asynchronously.
+// The bindings have no lease of their own, so with multiple replicas the queue is stored in the database
+// and the lease of the queue item makes sure only one replica creates the credentials of a binding.
+func NewBindingProcessingQueue(ctx context.Context, cfg broker.BindingConfig, queueCfg process.PersistentQueueConfig, multipleReplicas bool,
+	db storage.BrokerStorage, bindingsManager brokerBindings.BindingsManager, publisher event.Publisher, logs *slog.Logger) *process.Queue {
+
+	processor := broker.NewBindingProcessor(cfg, db, bindingsManager, publisher, logs)
+	var queue *process.Queue
+	if queueCfg.Enabled || multipleReplicas {
+		queue = process.NewPersistentQueue(processor, db.QueueItems(), logs, "binding", queueCfg)
+	} else {
+		queue = process.NewQueue(processor, logs, "binding")
+	}
+	queue.Run(ctx.Done(), cfg.AsyncWorkersAmount)
+
+	return queue
+}
+
+// processBindingsInProgress queues the bindings accepted asynchronously whose credentials were not created before the restart,
+// the bindings already scheduled in the persistent queue are not scheduled again
+func processBindingsInProgress(bindings storage.Bindings, queue *process.Queue, log *slog.Logger) error {
+	inProgress, err := bindings.ListInProgress()
+	if err != nil {
+		return fmt.Errorf("while getting bindings in progress from storage: %w", err)
+	}
+	for _, binding := range inProgress {
+		queue.Restore(binding.OperationID)
+		log.Info(fmt.Sprintf("Resuming the creation of binding %s for instance %s, operation ID: %s", binding.ID, binding.InstanceID, binding.OperationID))
+	}
+	return nil
+}
@@ -32,6 +32,7 @@ import (
 	kcMock "github.com/kyma-project/kyma-environment-broker/internal/kubeconfig/automock"
 	"github.com/kyma-project/kyma-environment-broker/internal/metrics"
 	"github.com/kyma-project/kyma-environment-broker/internal/process"
+	"github.com/kyma-project/kyma-environment-broker/internal/process/provisioning"
 	"github.com/kyma-project/kyma-environment-broker/internal/process/steps"
 	"github.com/kyma-project/kyma-environment-broker/internal/provider/configuration"
 	kebRuntime "github.com/kyma-project/kyma-environment-broker/internal/runtime"
@@ -449,9 +450,10 @@ func (s *BrokerSuiteTest) CreateAPI(cfg *Config, db storage.BrokerStorage, provi
 	fatalOnError(err, log)
 	schemaService := broker.NewSchemaService(providerSpec, planSpec, &defaultOIDC, cfg.Broker, cfg.InfrastructureManager.IngressFilteringPlans, channelResolver)
 
-	createAPI(s.router, schemaService, servicesConfig, cfg, db, provisioningQueue, deprovisionQueue, updateQueue,
+	createAPI(s.router, schemaService, servicesConfig, cfg, db, provisioningQueue, deprovisionQueue, updateQueue, nil,
 		lager.NewLogger("api"), log, kcBuilder, skrK8sClientProvider, skrK8sClientProvider, fakeKcpK8sClient, eventBroker,
-		providerSpec, configProvider, planSpec, rulesService, gardenerClient, awsClientFactory)
+		providerSpec, configProvider, planSpec, rulesService, gardenerClient, awsClientFactory,
+		provisioning.NewCreateRuntimeResourceStep(db, s.k8sKcp, cfg.InfrastructureManager, defaultOIDC, workersProvider(cfg.InfrastructureManager, providerSpec), providerSpec, cfg.GlobalAccounts(), nil))
 
 	s.httpServer = httptest.NewServer(s.router)
 }
@@ -14,6 +14,7 @@ import (
 
 	"github.com/kyma-project/kyma-environment-broker/internal/process"
 	"github.com/kyma-project/kyma-environment-broker/internal/process/deprovisioning"
+	"github.com/kyma-project/kyma-environment-broker/internal/process/hooks"
 	"github.com/kyma-project/kyma-environment-broker/internal/storage"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -80,7 +81,9 @@ func NewDeprovisioningProcessingQueue(ctx context.Context, workersAmount int, de
 		}
 	}
 
-	queue := process.NewQueue(deprovisionManager, logs, "deprovisioning")
+	registerStepHooks(deprovisionManager, cfg.StepHooksFilePath, hooks.DeprovisioningProcess, logs)
+
+	queue := newProcessingQueue(deprovisionManager, db, cfg.PersistentQueue, cfg.QueueFairness, logs, "deprovisioning")
 	queue.Run(ctx.Done(), workersAmount)
 
 	return queue
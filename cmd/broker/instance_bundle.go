@@ -0,0 +1,28 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/kyma-project/kyma-environment-broker/internal/bundle"
+	"github.com/kyma-project/kyma-environment-broker/internal/storage"
+)
+
+func importInstanceBundle(path string, db storage.BrokerStorage, cipher *storage.Encrypter, log *slog.Logger) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("while opening the instance bundle: %w", err)
+	}
+	defer file.Close()
+
+	instanceBundle, err := bundle.Read(file)
+	if err != nil {
+		return err
+	}
+	if err := bundle.NewImporter(db, cipher, log).Import(instanceBundle, false); err != nil {
+		return fmt.Errorf("while importing the instance bundle: %w", err)
+	}
+	log.Info(fmt.Sprintf("Instance %s imported from %s", instanceBundle.Instance.InstanceID, path))
+	return nil
+}
@@ -3,13 +3,16 @@ package main
 import (
 	"context"
 	"crypto/fips140"
+	"errors"
 	"fmt"
 	"log/slog"
 	"net/http"
 	"os"
+	"os/signal"
 	gruntime "runtime"
 	"runtime/pprof"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/kyma-project/kyma-environment-broker/common/gardener"
@@ -18,9 +21,11 @@ import (
 	pkg "github.com/kyma-project/kyma-environment-broker/common/runtime"
 	"github.com/kyma-project/kyma-environment-broker/internal"
 	"github.com/kyma-project/kyma-environment-broker/internal/additionalproperties"
+	"github.com/kyma-project/kyma-environment-broker/internal/archive"
+	"github.com/kyma-project/kyma-environment-broker/internal/bindingrotation"
 	"github.com/kyma-project/kyma-environment-broker/internal/blocklist"
 	"github.com/kyma-project/kyma-environment-broker/internal/broker"
-	brokerBindings "github.com/kyma-project/kyma-environment-broker/internal/broker/bindings"
+	"github.com/kyma-project/kyma-environment-broker/internal/changes"
 	kebConfig "github.com/kyma-project/kyma-environment-broker/internal/config"
 	"github.com/kyma-project/kyma-environment-broker/internal/dashboard"
 	"github.com/kyma-project/kyma-environment-broker/internal/event"
@@ -33,7 +38,10 @@ import (
 	"github.com/kyma-project/kyma-environment-broker/internal/kubeconfig"
 	"github.com/kyma-project/kyma-environment-broker/internal/machinesavailability"
 	"github.com/kyma-project/kyma-environment-broker/internal/metrics"
+	"github.com/kyma-project/kyma-environment-broker/internal/operations"
 	"github.com/kyma-project/kyma-environment-broker/internal/process"
+	"github.com/kyma-project/kyma-environment-broker/internal/process/hooks"
+	"github.com/kyma-project/kyma-environment-broker/internal/process/provisioning"
 	"github.com/kyma-project/kyma-environment-broker/internal/provider"
 	"github.com/kyma-project/kyma-environment-broker/internal/provider/configuration"
 	"github.com/kyma-project/kyma-environment-broker/internal/quota"
@@ -63,6 +71,9 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
 	"sigs.k8s.io/controller-runtime/pkg/client/config"
+
+	// registers the driver of the SQLite storage
+	_ "modernc.org/sqlite"
 )
 
 // Config holds configuration for the whole application
@@ -70,6 +81,12 @@ type Config struct {
 	// DbInMemory allows to use memory storage instead of the postgres one.
 	// Suitable for development purposes.
 	DbInMemory bool `envconfig:"default=false"`
+	// DbSQLiteFilePath allows to use the SQLite storage kept in the given file instead of the postgres one.
+	// Suitable for development purposes, the data is kept between the restarts without a database server.
+	DbSQLiteFilePath string `envconfig:"optional"`
+	// InstanceBundleFilePath is the path of the instance bundle imported into the memory storage on start.
+	// Used only together with DbInMemory, for example, to reproduce an issue of the instance locally.
+	InstanceBundleFilePath string `envconfig:"optional"`
 
 	// DisableProcessOperationsInProgress allows to disable processing operations
 	// which are in progress on starting application. Set to true if you are
@@ -107,6 +124,7 @@ type Config struct {
 	GvisorWhitelistedGlobalAccountsFilePath    string
 	OpenShellWhitelistedGlobalAccountsFilePath string
 	OperationBlocklistFilePath                 string `envconfig:"optional"`
+	StepHooksFilePath                          string `envconfig:"optional"`
 
 	DomainName string
 
@@ -117,12 +135,20 @@ type Config struct {
 
 	Events events.Config
 
+	Changes changes.Config
+
 	Metrics metrics.Config
 
 	Provisioning   process.StagedManagerConfiguration
 	Deprovisioning process.StagedManagerConfiguration
 	Update         process.StagedManagerConfiguration
 
+	PersistentQueue process.PersistentQueueConfig
+	QueueFairness   process.QueueFairnessConfig
+	OperationLease  process.OperationLeaseConfig
+
+	Shutdown ShutdownConfig
+
 	RuntimeConfigurationConfigMapName string `envconfig:"default=keb-runtime-config"`
 
 	UpdateRuntimeResourceDelay time.Duration `envconfig:"default=4s"`
@@ -284,6 +310,11 @@ func main() {
 		fatalOnError(fmt.Errorf("AvailablePlans is not initialized properly"), log)
 	}
 
+	plansSpec, err := configuration.NewPlanSpecificationsFromFile(cfg.PlansConfigurationFilePath)
+	fatalOnError(err, log)
+	broker.AvailablePlans, err = broker.NewAvailablePlansFromSpecifications(plansSpec)
+	fatalOnError(err, log)
+
 	err = cfg.Broker.Validate()
 	fatalOnError(err, log)
 	err = cfg.InfrastructureManager.Validate()
@@ -297,7 +328,8 @@ func main() {
 	log.Info(fmt.Sprintf("Global Accounts configuration: %s", cfg.GlobalAccounts()))
 
 	log.Info("Registering healthz endpoint for health probes")
-	health.NewServer(cfg.Broker.Host, cfg.Broker.StatusPort, log).ServeAsync()
+	healthServer := health.NewServer(cfg.Broker.Host, cfg.Broker.StatusPort, log)
+	healthServer.ServeAsync()
 	go periodicProfile(log, cfg.Profiler)
 
 	logConfiguration(log, cfg)
@@ -322,12 +354,22 @@ func main() {
 		fatalOnError(err, log)
 	}
 
-	cipher := storage.NewEncrypter(cfg.Database.SecretKey)
+	cipher, err := storage.NewEncrypterFromConfig(cfg.Database)
+	fatalOnError(err, log)
 
 	// create storage
 	var db storage.BrokerStorage
 	if cfg.DbInMemory {
 		db = storage.NewMemoryStorage()
+		if cfg.InstanceBundleFilePath != "" {
+			fatalOnError(importInstanceBundle(cfg.InstanceBundleFilePath, db, cipher, log), log)
+		}
+	} else if cfg.DbSQLiteFilePath != "" {
+		store, conn, err := storage.NewSQLite(cfg.DbSQLiteFilePath, cfg.Events, cipher)
+		fatalOnError(err, log)
+		db = store
+		dbStatsCollector := sqlstats.NewStatsCollector("broker", conn)
+		prometheus.MustRegister(dbStatsCollector)
 	} else {
 		store, conn, err := storage.NewFromConfig(cfg.Database, cfg.Events, cipher)
 		fatalOnError(err, log)
@@ -364,17 +406,20 @@ func main() {
 	eventBroker := event.NewPubSub(log)
 
 	// metrics collectors
-	_ = metrics.Register(ctx, eventBroker, db, cfg.Metrics, gardenerClient, log)
+	_ = metrics.Register(ctx, eventBroker, db.ReadOnly(), cfg.Metrics, gardenerClient, log)
+
+	// operation timeline
+	stepsRecorder := process.NewStepsRecorder(db.OperationSteps(), log)
+	eventBroker.Subscribe(process.OperationStepProcessed{}, stepsRecorder.OnOperationStepProcessed)
 
 	rulesService, err := rules.NewRulesServiceFromFile(cfg.HapRuleFilePath, sets.New(broker.AvailablePlans.GetAllPlanNamesAsStrings()...), sets.New([]string(cfg.Broker.EnablePlans)...))
 	fatalOnError(err, log)
 
 	log.Info("Rules service configuration loaded successfully and valid")
 
-	plansSpec, err := configuration.NewPlanSpecificationsFromFile(cfg.PlansConfigurationFilePath)
-	fatalOnError(err, log)
 	providerSpec, err := configuration.NewProviderSpecFromFile(cfg.ProvidersConfigurationFilePath)
 	fatalOnError(err, log)
+	fatalOnError(broker.AvailablePlans.Validate(rulesService, providerSpec), log)
 	fatalOnError(providerSpec.ValidateZonesDiscovery(), log)
 	fatalOnError(providerSpec.ValidateMachinesVersions(), log)
 
@@ -403,15 +448,25 @@ func main() {
 
 	// run queues
 	provisionManager := process.NewStagedManager(db.Operations(), eventBroker, cfg.Broker.OperationTimeout, cfg.Provisioning, log.With("provisioning", "manager"))
+	deprovisionManager := process.NewStagedManager(db.Operations(), eventBroker, cfg.Broker.OperationTimeout, cfg.Deprovisioning, log.With("deprovisioning", "manager"))
+	updateManager := process.NewStagedManager(db.Operations(), eventBroker, cfg.Broker.OperationTimeout, cfg.Update, log.With("update", "manager"))
+	if cfg.OperationLease.Enabled {
+		leaseOwner, err := os.Hostname()
+		fatalOnError(err, log)
+		log.Info(fmt.Sprintf("Operations are claimed by %s for %s", leaseOwner, cfg.OperationLease.Duration))
+		for _, manager := range []*process.StagedManager{provisionManager, deprovisionManager, updateManager} {
+			manager.UseLease(leaseOwner, cfg.OperationLease.Duration)
+		}
+	}
+
 	provisionQueue := NewProvisioningProcessingQueue(ctx, provisionManager, cfg.Provisioning.WorkersAmount, &cfg, db, configProvider,
 		skrK8sClientProvider, kcpK8sClient, gardenerClient, oidcDefaultValues, log, rulesService, workersProvider, providerSpec, awsClientFactory, kcrVolumeProvider)
 
-	deprovisionManager := process.NewStagedManager(db.Operations(), eventBroker, cfg.Broker.OperationTimeout, cfg.Deprovisioning, log.With("deprovisioning", "manager"))
 	deprovisionQueue := NewDeprovisioningProcessingQueue(ctx, cfg.Deprovisioning.WorkersAmount, deprovisionManager, &cfg, db,
 		skrK8sClientProvider, kcpK8sClient, configProvider, dynamicGardener, gardenerNamespace, log)
 
-	updateManager := process.NewStagedManager(db.Operations(), eventBroker, cfg.Broker.OperationTimeout, cfg.Update, log.With("update", "manager"))
 	updateQueue := NewUpdateProcessingQueue(ctx, updateManager, cfg.Update.WorkersAmount, db, cfg, kcpK8sClient, log, workersProvider, schemaService, plansSpec, configProvider, providerSpec, gardenerClient, awsClientFactory, kcrVolumeProvider)
+
 	/***/
 	servicesConfig, err := broker.NewServicesConfigFromFile(cfg.CatalogFilePath)
 	fatalOnError(err, log)
@@ -419,15 +474,28 @@ func main() {
 	// create kubeconfig builder
 	kcBuilder := kubeconfig.NewBuilder(kcpK8sClient, skrK8sClientProvider)
 
+	var bindingQueue *process.Queue
+	if cfg.Broker.Binding.Enabled && cfg.Broker.Binding.AsyncEnabled {
+		bindingQueue = NewBindingProcessingQueue(ctx, cfg.Broker.Binding, cfg.PersistentQueue, cfg.OperationLease.Enabled, db,
+			NewBindingsManager(skrK8sClientProvider, skrK8sClientProvider, kcBuilder), eventBroker, log)
+	}
+	if cfg.Broker.Binding.Enabled && cfg.Broker.Binding.AutoRenewEnabled {
+		rotator := broker.NewBindingRotator(cfg.Broker.Binding, db, NewBindingsManager(skrK8sClientProvider, skrK8sClientProvider, kcBuilder), eventBroker, log)
+		go broker.NewBindingRenewer(cfg.Broker.Binding, db, rotator, log).Run(ctx)
+	}
+
 	// create server
 	router := httputil.NewRouter()
 
 	// Apply panic recovery middleware to all HTTP endpoints
 	router.Use(httputil.PanicRecoveryMiddleware(log))
 
-	createAPI(router, schemaService, servicesConfig, &cfg, db, provisionQueue, deprovisionQueue, updateQueue, logger, log,
+	// renders the Runtime resource for the dry run provisioning and update requests
+	runtimeRenderer := provisioning.NewCreateRuntimeResourceStep(db, kcpK8sClient, cfg.InfrastructureManager, oidcDefaultValues, workersProvider, providerSpec, cfg.GlobalAccounts(), kcrVolumeProvider)
+
+	createAPI(router, schemaService, servicesConfig, &cfg, db, provisionQueue, deprovisionQueue, updateQueue, bindingQueue, logger, log,
 		kcBuilder, skrK8sClientProvider, skrK8sClientProvider, kcpK8sClient, eventBroker,
-		providerSpec, configProvider, plansSpec, rulesService, gardenerClient, awsClientFactory)
+		providerSpec, configProvider, plansSpec, rulesService, gardenerClient, awsClientFactory, runtimeRenderer)
 
 	// create metrics endpoint
 	router.Handle("/metrics", promhttp.Handler())
@@ -443,6 +511,10 @@ func main() {
 		fatalOnError(err, log)
 		err = processOperationsInProgressByType(internal.OperationTypeUpdate, db.Operations(), updateQueue, log)
 		fatalOnError(err, log)
+		if bindingQueue != nil {
+			err = processBindingsInProgress(db.Bindings(), bindingQueue, log)
+			fatalOnError(err, log)
+		}
 	} else {
 		log.Info("Skipping processing operation in progress on start")
 	}
@@ -455,12 +527,16 @@ func main() {
 	fatalOnError(err, log)
 
 	// create list runtimes endpoint
-	runtimeHandler := runtime.NewHandler(db, cfg.MaxPaginationPage,
+	runtimeHandler := runtime.NewHandler(db.ReadOnly(), cfg.MaxPaginationPage,
 		cfg.Broker.DefaultRequestRegion,
 		kcpK8sClient,
 		log)
 	router.HandleFunc("/runtimes", runtimeHandler.GetRuntimes)
 
+	// create list archived instances endpoint
+	archivedInstancesHandler := archive.NewHandler(db.ReadOnly().InstancesArchived(), cfg.MaxPaginationPage, log)
+	archivedInstancesHandler.AttachRoutes(router)
+
 	// create list requests with additional properties endpoint
 	additionalPropertiesHandler := additionalproperties.NewHandler(log, cfg.Broker.AdditionalPropertiesPath)
 	additionalPropertiesHandler.AttachRoutes(router)
@@ -469,6 +545,10 @@ func main() {
 	expirationHandler := expiration.NewHandler(db.Instances(), db.Operations(), deprovisionQueue, log)
 	expirationHandler.AttachRoutes(router)
 
+	// create operations endpoint
+	operationsHandler := operations.NewHandler(db.Operations(), db.Actions(), db.OperationSteps(), provisionQueue, updateQueue, log)
+	operationsHandler.AttachRoutes(router)
+
 	router.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
 		http.StripPrefix("/", http.FileServer(http.Dir("/swagger"))).ServeHTTP(w, r)
 	})
@@ -478,7 +558,24 @@ func main() {
 		router.ServeHTTP(rec, r)
 		log.Info(fmt.Sprintf("Call handled: method=%s url=%s statusCode=%d size=%d", r.Method, r.URL.Path, rec.StatusCode, rec.Size))
 	})
-	fatalOnError(http.ListenAndServe(cfg.Broker.Host+":"+cfg.Broker.Port, svr), log)
+	server := &http.Server{Addr: cfg.Broker.Host + ":" + cfg.Broker.Port, Handler: svr}
+	go func() {
+		if err := server.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+			fatalOnError(err, log)
+		}
+	}()
+
+	signalCtx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, os.Interrupt)
+	defer stop()
+	<-signalCtx.Done()
+	queues := []*process.Queue{provisionQueue, deprovisionQueue, updateQueue}
+	if bindingQueue != nil {
+		queues = append(queues, bindingQueue)
+	}
+	gracefulShutdown(cfg.Shutdown, server, healthServer, queues, []*process.StagedManager{provisionManager, deprovisionManager, updateManager}, log)
+	if err := db.Close(); err != nil {
+		log.Warn(fmt.Sprintf("Unable to close the database connections: %s", err))
+	}
 }
 
 func logConfiguration(logs *slog.Logger, cfg Config) {
@@ -512,10 +609,10 @@ func logConfiguration(logs *slog.Logger, cfg Config) {
 }
 
 func createAPI(router *httputil.Router, schemaService *broker.SchemaService, servicesConfig broker.ServicesConfig, cfg *Config, db storage.BrokerStorage,
-	provisionQueue, deprovisionQueue, updateQueue *process.Queue, logger lager.Logger, logs *slog.Logger, kcBuilder kubeconfig.KcBuilder, clientProvider K8sClientProvider,
+	provisionQueue, deprovisionQueue, updateQueue, bindingQueue *process.Queue, logger lager.Logger, logs *slog.Logger, kcBuilder kubeconfig.KcBuilder, clientProvider K8sClientProvider,
 	kubeconfigProvider KubeconfigProvider, kcpK8sClient client.Client, publisher event.Publisher,
 	providerSpec *configuration.ProviderSpec, configProvider kebConfig.Provider, planSpec *configuration.PlanSpecifications, rulesService *rules.RulesService,
-	gardenerClient *gardener.Client, awsClientFactory aws.ClientFactory) {
+	gardenerClient *gardener.Client, awsClientFactory aws.ClientFactory, runtimeRenderer broker.RuntimeResourceRenderer) {
 
 	if cfg.MachinesAvailabilityEndpoint {
 		if r, _ := cfg.GardenerSubscriptionResource(); r == gardener.SecretBindingResource {
@@ -565,6 +662,8 @@ func createAPI(router *httputil.Router, schemaService *broker.SchemaService, ser
 	operationBlocklist, err = operationBlocklist.WithPlanValidator(broker.AvailablePlans)
 	fatalOnError(err, logs)
 
+	bindingsManager := NewBindingsManager(clientProvider, kubeconfigProvider, kcBuilder)
+
 	// create KymaEnvironmentBroker endpoints
 	kymaEnvBroker := &broker.KymaEnvironmentBroker{
 		ServicesEndpoint: broker.NewServices(cfg.Broker, schemaService, servicesConfig),
@@ -582,16 +681,21 @@ func createAPI(router *httputil.Router, schemaService *broker.SchemaService, ser
 			rulesService, gardenerClient, awsClientFactory, operationBlocklist),
 		GetInstanceEndpoint:          broker.NewGetInstance(cfg.Broker, db.Instances(), db.Operations(), kcBuilder, logs),
 		LastOperationEndpoint:        broker.NewLastOperation(db.Operations(), db.InstancesArchived(), logs),
-		BindEndpoint:                 broker.NewBind(cfg.Broker.Binding, db, logs, clientProvider, kubeconfigProvider, publisher),
-		UnbindEndpoint:               broker.NewUnbind(logs, db, brokerBindings.NewServiceAccountBindingsManager(clientProvider, kubeconfigProvider), publisher),
+		BindEndpoint:                 broker.NewBind(cfg.Broker.Binding, db, logs, bindingsManager, publisher),
+		UnbindEndpoint:               broker.NewUnbind(logs, db, bindingsManager, publisher),
 		GetBindingEndpoint:           broker.NewGetBinding(logs, db),
-		LastBindingOperationEndpoint: broker.NewLastBindingOperation(logs),
+		LastBindingOperationEndpoint: broker.NewLastBindingOperation(logs, db),
 	}
 
 	if r, _ := cfg.GardenerSubscriptionResource(); r == gardener.CredentialsBindingResource {
 		kymaEnvBroker.ProvisionEndpoint.UseCredentialsBindings()
 		kymaEnvBroker.UpdateEndpoint.UseCredentialsBindings()
 	}
+	if bindingQueue != nil {
+		kymaEnvBroker.BindEndpoint.UseQueue(bindingQueue)
+	}
+	kymaEnvBroker.ProvisionEndpoint.UseRuntimeResourceRenderer(runtimeRenderer)
+	kymaEnvBroker.UpdateEndpoint.UseRuntimeResourceRenderer(runtimeRenderer)
 
 	// Wrap broker with panic recovery for all OSB endpoints
 	brokerWithPanicRecovery := broker.NewWithPanicRecovery(kymaEnvBroker, logs)
@@ -600,12 +704,21 @@ func createAPI(router *httputil.Router, schemaService *broker.SchemaService, ser
 	subRouter, err := router.NewSubRouter(brokerAPISubrouterName)
 	fatalOnError(err, logs)
 	broker.AttachRoutes(subRouter, brokerWithPanicRecovery, logs, cfg.Broker.Binding.CreateBindingTimeout, cfg.Broker.DefaultRequestRegion, prefixes)
+	if cfg.Broker.Binding.Enabled {
+		// create binding rotation endpoint
+		rotationHandler := bindingrotation.NewHandler(broker.NewBindingRotator(cfg.Broker.Binding, db, bindingsManager, publisher, logs), logs)
+		rotationHandler.AttachRoutes(subRouter, prefixes)
+	}
 	router.Handle("/oauth/", http.StripPrefix("/oauth", subRouter))
 
 	// create events endpoint
 	eventsHandler := eventshandler.NewHandler(db.Events(), db.Instances())
 	router.Handle("/events", eventsHandler)
 
+	// create changes endpoint
+	changesHandler := changes.NewHandler(db.Changes(), cfg.Changes, logs)
+	changesHandler.AttachRoutes(router)
+
 	versionHandler := version.NewHandler(Version)
 	versionHandler.AttachRoutes(router)
 }
@@ -617,12 +730,35 @@ func processOperationsInProgressByType(opType internal.OperationType, op storage
 		return fmt.Errorf("while getting in progress operations from storage: %w", err)
 	}
 	for _, operation := range operations {
-		queue.Add(operation.ID)
+		queue.Restore(operation.ID)
 		log.Info(fmt.Sprintf("Resuming the processing of %s operation ID: %s", opType, operation.ID))
 	}
 	return nil
 }
 
+// newProcessingQueue creates the queue stored in the database if enabled, the in-memory one otherwise
+func newProcessingQueue(executor process.Executor, db storage.BrokerStorage, cfg process.PersistentQueueConfig, fairness process.QueueFairnessConfig, log *slog.Logger, name string) *process.Queue {
+	var queue *process.Queue
+	if cfg.Enabled {
+		queue = process.NewPersistentQueue(executor, db.QueueItems(), log, name, cfg)
+	} else {
+		queue = process.NewQueue(executor, log, name)
+	}
+	queue.UseFairness(fairness, process.GlobalAccountResolver(db.Operations()))
+	return queue
+}
+
+// registerStepHooks adds the webhook steps configured for the process, the file is optional
+func registerStepHooks(manager *process.StagedManager, path string, processName string, log *slog.Logger) {
+	if path == "" {
+		return
+	}
+	stepHooks, err := hooks.ReadFromFile(path)
+	fatalOnError(err, log)
+	err = stepHooks.Register(manager, processName)
+	fatalOnError(err, log)
+}
+
 func initClient(cfg *rest.Config) (client.Client, error) {
 	httpClient, err := rest.HTTPClientFor(cfg)
 	if err != nil {
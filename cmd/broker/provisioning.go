@@ -13,6 +13,7 @@ import (
 	"github.com/kyma-project/kyma-environment-broker/internal/config"
 	"github.com/kyma-project/kyma-environment-broker/internal/hyperscalers/aws"
 	"github.com/kyma-project/kyma-environment-broker/internal/process"
+	"github.com/kyma-project/kyma-environment-broker/internal/process/hooks"
 	"github.com/kyma-project/kyma-environment-broker/internal/process/provisioning"
 	"github.com/kyma-project/kyma-environment-broker/internal/process/steps"
 	"github.com/kyma-project/kyma-environment-broker/internal/provider"
@@ -105,7 +106,9 @@ func NewProvisioningProcessingQueue(ctx context.Context, provisionManager *proce
 		}
 	}
 
-	queue := process.NewQueue(provisionManager, logs, "provisioning")
+	registerStepHooks(provisionManager, cfg.StepHooksFilePath, hooks.ProvisioningProcess, logs)
+
+	queue := newProcessingQueue(provisionManager, db, cfg.PersistentQueue, cfg.QueueFairness, logs, "provisioning")
 	queue.Run(ctx.Done(), workersAmount)
 
 	return queue
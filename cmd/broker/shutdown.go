@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/kyma-project/kyma-environment-broker/internal/health"
+	"github.com/kyma-project/kyma-environment-broker/internal/process"
+)
+
+type ShutdownConfig struct {
+	// Timeout is the time after the termination signal in which the broker finishes the requests and the steps in progress
+	Timeout time.Duration `envconfig:"default=60s"`
+	// NotReadyPeriod is the time between failing the readiness probe and closing the server, so the Pod is removed from the service endpoints
+	NotReadyPeriod time.Duration `envconfig:"default=5s"`
+}
+
+// gracefulShutdown stops accepting new requests, waits for the requests in progress and drains the processing queues.
+// The steps in progress save their results like during the regular processing, the steps not finished within
+// the timeout are executed again by the instance which processes the operation next.
+// The managers release the leases of the operations waiting in the queues, so other replicas do not wait for the leases to expire.
+func gracefulShutdown(cfg ShutdownConfig, server *http.Server, healthServer *health.Server, queues []*process.Queue, managers []*process.StagedManager, log *slog.Logger) {
+	log.Info(fmt.Sprintf("Shutting down, waiting up to %s for the requests and the steps in progress", cfg.Timeout))
+	deadline := time.Now().Add(cfg.Timeout)
+
+	healthServer.SetNotReady()
+	time.Sleep(cfg.NotReadyPeriod)
+
+	ctx, cancel := context.WithDeadline(context.Background(), deadline)
+	defer cancel()
+	if err := server.Shutdown(ctx); err != nil {
+		log.Warn(fmt.Sprintf("Unable to finish the requests in progress: %s", err))
+	}
+
+	var wg sync.WaitGroup
+	for _, queue := range queues {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			queue.Drain(time.Until(deadline))
+		}()
+	}
+	wg.Wait()
+	for _, manager := range managers {
+		manager.ReleaseLeases()
+	}
+	log.Info("Shutdown finished")
+}
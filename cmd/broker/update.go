@@ -11,6 +11,7 @@ import (
 	"github.com/kyma-project/kyma-environment-broker/internal/config"
 	"github.com/kyma-project/kyma-environment-broker/internal/hyperscalers/aws"
 	"github.com/kyma-project/kyma-environment-broker/internal/process"
+	"github.com/kyma-project/kyma-environment-broker/internal/process/hooks"
 	"github.com/kyma-project/kyma-environment-broker/internal/process/steps"
 	"github.com/kyma-project/kyma-environment-broker/internal/process/update"
 	"github.com/kyma-project/kyma-environment-broker/internal/provider"
@@ -76,7 +77,13 @@ func NewUpdateProcessingQueue(ctx context.Context, manager *process.StagedManage
 			}
 		}
 	}
-	queue := process.NewQueue(manager, logs, "update-processing")
+	registerStepHooks(manager, cfg.StepHooksFilePath, hooks.UpdateProcess, logs)
+
+	// parameters stored in the instance must be reverted only if the Runtime resource has not been updated yet
+	manager.AddCancellationStep(update.NewRestoreInstanceParametersStep(db), func(operation internal.Operation) bool {
+		return !operation.RuntimeResourceUpdated
+	})
+	queue := newProcessingQueue(manager, db, cfg.PersistentQueue, cfg.QueueFairness, logs, "update-processing")
 	queue.Run(ctx.Done(), workersAmount)
 
 	return queue
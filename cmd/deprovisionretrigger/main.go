@@ -61,7 +61,8 @@ func main() {
 	brokerClient := broker.NewClient(ctx, cfg.Broker)
 
 	// create storage connection
-	cipher := storage.NewEncrypter(cfg.Database.SecretKey)
+	cipher, err := storage.NewEncrypterFromConfig(cfg.Database)
+	fatalOnError(err)
 	db, conn, err := storage.NewFromConfig(cfg.Database, events.Config{}, cipher)
 	fatalOnError(err)
 	svc := newDeprovisionRetriggerService(cfg, brokerClient, db.Instances())
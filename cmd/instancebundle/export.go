@@ -0,0 +1,72 @@
+package main
+
+import (
+	"os"
+
+	"github.com/kyma-project/kyma-environment-broker/internal/bundle"
+	"github.com/spf13/cobra"
+)
+
+type ExportCommand struct {
+	cobraCmd   *cobra.Command
+	instanceID string
+	file       string
+}
+
+func NewExportCmd() *cobra.Command {
+	cmd := ExportCommand{}
+	cobraCmd := &cobra.Command{
+		Use:     "export",
+		Aliases: []string{"e"},
+		Short:   "Exports the state of the instance to the bundle file.",
+		Long: `Exports the instance with its operations, operation steps, bindings, actions, events, and the subaccount state to the bundle file.
+The SM credentials and the kubeconfigs are encrypted with the active key of the database configured with the APP_DATABASE_* environment variables.`,
+		Example: `
+	# Export the instance to the instance.json file
+	keb-instance-bundle export -i 7b4b8b4a-5f2e-4a4c-9a7e-2a1f0c3d5e6f -f instance.json
+		`,
+		RunE: func(_ *cobra.Command, args []string) error {
+			return cmd.Run()
+		},
+		SilenceErrors: true,
+		SilenceUsage:  true,
+	}
+	cmd.cobraCmd = cobraCmd
+
+	cobraCmd.Flags().StringVarP(&cmd.instanceID, "instance", "i", "", "ID of the exported instance.")
+	cobraCmd.Flags().StringVarP(&cmd.file, "file", "f", "", "Path of the bundle file.")
+	_ = cobraCmd.MarkFlagRequired("instance")
+	_ = cobraCmd.MarkFlagRequired("file")
+
+	return cobraCmd
+}
+
+func (cmd *ExportCommand) Run() error {
+	db, cipher, conn, err := newStorage()
+	if err != nil {
+		cmd.cobraCmd.Printf("Error: %s\n", err)
+		return err
+	}
+	defer conn.Close()
+
+	instanceBundle, err := bundle.NewExporter(db, cipher).Export(cmd.instanceID)
+	if err != nil {
+		cmd.cobraCmd.Printf("Error: %s\n", err)
+		return err
+	}
+
+	file, err := os.Create(cmd.file)
+	if err != nil {
+		cmd.cobraCmd.Printf("Error: %s\n", err)
+		return err
+	}
+	defer file.Close()
+	if err := bundle.Write(file, instanceBundle); err != nil {
+		cmd.cobraCmd.Printf("Error: %s\n", err)
+		return err
+	}
+
+	cmd.cobraCmd.Printf("Instance %s exported to %s: %d operation(s), %d binding(s), %d action(s), %d event(s)\n",
+		cmd.instanceID, cmd.file, len(instanceBundle.Operations), len(instanceBundle.Bindings), len(instanceBundle.Actions), len(instanceBundle.Events))
+	return nil
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"log/slog"
+	"os"
+
+	"github.com/kyma-project/kyma-environment-broker/internal/bundle"
+	"github.com/spf13/cobra"
+)
+
+type ImportCommand struct {
+	cobraCmd *cobra.Command
+	file     string
+	force    bool
+}
+
+func NewImportCmd() *cobra.Command {
+	cmd := ImportCommand{}
+	cobraCmd := &cobra.Command{
+		Use:     "import",
+		Aliases: []string{"i"},
+		Short:   "Imports the state of the instance from the bundle file.",
+		Long: `Imports the instance with its operations, operation steps, bindings, actions, events, and the subaccount state from the bundle file.
+The keyring of the database configured with the APP_DATABASE_* environment variables must contain the key used to export the bundle, the data is stored encrypted with the active key.
+The import is refused if the instance, its operations or bindings already exist, unless the --force flag is set.`,
+		Example: `
+	# Import the instance from the instance.json file
+	keb-instance-bundle import -f instance.json
+
+	# Import the instance and replace the existing data
+	keb-instance-bundle import -f instance.json --force
+		`,
+		RunE: func(_ *cobra.Command, args []string) error {
+			return cmd.Run()
+		},
+		SilenceErrors: true,
+		SilenceUsage:  true,
+	}
+	cmd.cobraCmd = cobraCmd
+
+	cobraCmd.Flags().StringVarP(&cmd.file, "file", "f", "", "Path of the bundle file.")
+	cobraCmd.Flags().BoolVar(&cmd.force, "force", false, "Replaces the instance, the operations, and the bindings which already exist.")
+	_ = cobraCmd.MarkFlagRequired("file")
+
+	return cobraCmd
+}
+
+func (cmd *ImportCommand) Run() error {
+	file, err := os.Open(cmd.file)
+	if err != nil {
+		cmd.cobraCmd.Printf("Error: %s\n", err)
+		return err
+	}
+	defer file.Close()
+	instanceBundle, err := bundle.Read(file)
+	if err != nil {
+		cmd.cobraCmd.Printf("Error: %s\n", err)
+		return err
+	}
+
+	db, cipher, conn, err := newStorage()
+	if err != nil {
+		cmd.cobraCmd.Printf("Error: %s\n", err)
+		return err
+	}
+	defer conn.Close()
+
+	log := slog.New(slog.NewTextHandler(cmd.cobraCmd.OutOrStdout(), nil))
+	if err := bundle.NewImporter(db, cipher, log).Import(instanceBundle, cmd.force); err != nil {
+		cmd.cobraCmd.Printf("Error: %s\n", err)
+		return err
+	}
+
+	cmd.cobraCmd.Printf("Instance %s imported from %s\n", instanceBundle.Instance.InstanceID, cmd.file)
+	return nil
+}
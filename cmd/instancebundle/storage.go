@@ -0,0 +1,32 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/gocraft/dbr"
+	"github.com/kyma-project/kyma-environment-broker/internal/events"
+	"github.com/kyma-project/kyma-environment-broker/internal/storage"
+	"github.com/vrischmann/envconfig"
+)
+
+type Config struct {
+	Database storage.Config
+}
+
+// newStorage connects to the database configured with the APP_DATABASE_* environment variables, the same as for KEB
+func newStorage() (storage.BrokerStorage, *storage.Encrypter, *dbr.Connection, error) {
+	var cfg Config
+	if err := envconfig.InitWithPrefix(&cfg, "APP"); err != nil {
+		return nil, nil, nil, fmt.Errorf("while loading the configuration: %w", err)
+	}
+	cipher, err := storage.NewEncrypterFromConfig(cfg.Database)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("while creating the encrypter: %w", err)
+	}
+	// the events are enabled without the retention, so the tool does not remove any events
+	db, conn, err := storage.NewFromConfig(cfg.Database, events.Config{Enabled: true}, cipher)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("while connecting to the database: %w", err)
+	}
+	return db, cipher, conn, nil
+}
@@ -18,6 +18,7 @@ import (
 	"github.com/kyma-project/kyma-environment-broker/internal"
 	"github.com/kyma-project/kyma-environment-broker/internal/analytics"
 	"github.com/kyma-project/kyma-environment-broker/internal/broker"
+	"github.com/kyma-project/kyma-environment-broker/internal/storage/postsql"
 )
 
 //go:embed static/index.html
@@ -31,6 +32,11 @@ type Config struct {
 		Port     string `envconfig:"default=5432"`
 		Name     string `envconfig:"default=broker"`
 		SSLMode  string `envconfig:"default=disable"`
+		// ReadReplicaURL is the connection string of the read-only replica, if set the statistics are read from the replica
+		// while it is available and up to date, otherwise from the primary database
+		ReadReplicaURL string `envconfig:"optional"`
+		// ReadReplicaMaxStaleness is the replication lag above which the statistics are read from the primary database, 0 turns off the limit
+		ReadReplicaMaxStaleness time.Duration `envconfig:"default=30s"`
 	}
 	Port            string        `envconfig:"default=8080"`
 	RefreshInterval time.Duration `envconfig:"default=1h"`
@@ -78,6 +84,21 @@ func main() {
 	}
 
 	reader := analytics.NewDBReader(conn.NewSession(nil))
+	if cfg.Database.ReadReplicaURL != "" {
+		// the replica is not required to be available, the statistics are read from the primary database until the replica is healthy
+		replica, err := dbr.Open("postgres", cfg.Database.ReadReplicaURL, nil)
+		if err != nil {
+			slog.Error("failed to open the read replica connection", "error", err)
+			os.Exit(1)
+		}
+		defer func() {
+			if err := replica.Close(); err != nil {
+				slog.Error("failed to close the read replica connection", "error", err)
+			}
+		}()
+		slog.Info(fmt.Sprintf("Reading the statistics from the read replica, max staleness: %s", cfg.Database.ReadReplicaMaxStaleness))
+		reader = analytics.NewDBReaderWithSessions(postsql.NewReplicaSessions(conn, replica, cfg.Database.ReadReplicaMaxStaleness))
+	}
 
 	// Build planID → planName lookup from broker constants.
 	planIDToName := make(map[string]string, len(broker.PlanIDsMapping))
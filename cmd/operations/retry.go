@@ -0,0 +1,75 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"os"
+	"time"
+
+	"github.com/kyma-project/kyma-environment-broker/common/operations"
+	"github.com/spf13/cobra"
+	"golang.org/x/oauth2"
+)
+
+const tokenEnv = "KEB_TOKEN"
+
+var ErrUsage = errors.New("UsageError")
+
+type RetryCommand struct {
+	cobraCmd    *cobra.Command
+	url         string
+	token       string
+	operationID string
+}
+
+func NewRetryCmd() *cobra.Command {
+	cmd := RetryCommand{}
+	cobraCmd := &cobra.Command{
+		Use:     "retry",
+		Aliases: []string{"r"},
+		Short:   "Retries a failed provisioning or update operation.",
+		Long: `Retries a failed provisioning or update operation.
+The operation is resumed from the stage which has not been finished. The finished stages are not processed again.`,
+		Example: `
+	# Retry the failed operation, the token is read from the KEB_TOKEN environment variable
+	keb-operations retry -u https://kyma-env-broker.kyma.local -o 3b2a8a6e-5a6f-4c47-a4b7-8f2ef1d4e2a1
+		`,
+		RunE: func(_ *cobra.Command, args []string) error {
+			return cmd.Run()
+		},
+		SilenceErrors: true,
+		SilenceUsage:  true,
+	}
+	cmd.cobraCmd = cobraCmd
+
+	cobraCmd.Flags().StringVarP(&cmd.url, "url", "u", "", "Base URL of the KEB APIs.")
+	cobraCmd.Flags().StringVarP(&cmd.operationID, "operation", "o", "", "ID of the failed operation.")
+	cobraCmd.Flags().StringVarP(&cmd.token, "token", "t", "", "OIDC ID token used to authorize the request. If not set, the value of the KEB_TOKEN environment variable is used.")
+	_ = cobraCmd.MarkFlagRequired("url")
+	_ = cobraCmd.MarkFlagRequired("operation")
+
+	return cobraCmd
+}
+
+func (cmd *RetryCommand) Run() error {
+	token := cmd.token
+	if token == "" {
+		token = os.Getenv(tokenEnv)
+	}
+	if token == "" {
+		cmd.cobraCmd.Printf("Error: the token is not provided, use the --token flag or the %s environment variable\n", tokenEnv)
+		return ErrUsage
+	}
+
+	httpClient := oauth2.NewClient(context.Background(), oauth2.StaticTokenSource(&oauth2.Token{AccessToken: token}))
+	httpClient.Timeout = 30 * time.Second
+	client := operations.NewClient(cmd.url, httpClient)
+
+	operation, err := client.RetryOperation(cmd.operationID)
+	if err != nil {
+		cmd.cobraCmd.Printf("Error: %s\n", err)
+		return err
+	}
+	cmd.cobraCmd.Printf("Operation %s retried, current state: %s\n", operation.OperationID, operation.State)
+	return nil
+}
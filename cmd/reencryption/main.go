@@ -0,0 +1,86 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+
+	"github.com/kyma-project/kyma-environment-broker/internal/events"
+	"github.com/kyma-project/kyma-environment-broker/internal/reencryption"
+	"github.com/kyma-project/kyma-environment-broker/internal/schemamigrator/cleaner"
+	"github.com/kyma-project/kyma-environment-broker/internal/storage"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/vrischmann/envconfig"
+)
+
+const AppPrefix = "reencryption"
+
+type Config struct {
+	Database    storage.Config
+	Job         JobConfig
+	MetricsPort string `envconfig:"default=8081"`
+}
+
+type JobConfig struct {
+	DryRun    bool `envconfig:"default=true"`
+	BatchSize int  `envconfig:"default=100"`
+}
+
+func main() {
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+	slog.SetDefault(logger)
+
+	slog.Info("Starting re-encryption job")
+
+	var cfg Config
+	fatalOnError(envconfig.InitWithPrefix(&cfg, "APP"))
+
+	if cfg.Job.DryRun {
+		slog.Info("Dry run only - no changes")
+	}
+
+	cipher, err := storage.NewEncrypterFromConfig(cfg.Database)
+	fatalOnError(err)
+	if cipher.ActiveKeyID() == "" {
+		slog.Info("No active key in the keyring, the data is re-encrypted with the secret key")
+	} else {
+		slog.Info(fmt.Sprintf("Re-encrypting the data with the key %q", cipher.ActiveKeyID()))
+	}
+
+	db, conn, err := storage.NewFromConfig(cfg.Database, events.Config{}, cipher)
+	fatalOnError(err)
+
+	metricsRegistry := prometheus.NewRegistry()
+	metrics := reencryption.NewMetrics(metricsRegistry, AppPrefix)
+	http.Handle("/metrics", promhttp.HandlerFor(metricsRegistry, promhttp.HandlerOpts{Registry: metricsRegistry}))
+	go func() {
+		err := http.ListenAndServe(fmt.Sprintf(":%s", cfg.MetricsPort), nil)
+		if err != nil {
+			slog.Error(fmt.Sprintf("while serving metrics: %s", err))
+		}
+	}()
+
+	svc := reencryption.NewService(cfg.Job.DryRun, cfg.Job.BatchSize, cipher, db.EncryptedData(), metrics)
+	fatalOnError(svc.Run())
+
+	slog.Info("Re-encryption job finished successfully!")
+
+	fatalOnError(conn.Close())
+	logOnError(cleaner.HaltIstioSidecar())
+	fatalOnError(cleaner.Halt())
+}
+
+func fatalOnError(err error) {
+	if err != nil {
+		slog.Error(err.Error())
+		os.Exit(0)
+	}
+}
+
+func logOnError(err error) {
+	if err != nil {
+		slog.Error(err.Error())
+	}
+}
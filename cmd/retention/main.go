@@ -0,0 +1,83 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+
+	"github.com/kyma-project/kyma-environment-broker/internal/events"
+	"github.com/kyma-project/kyma-environment-broker/internal/retention"
+	"github.com/kyma-project/kyma-environment-broker/internal/schemamigrator/cleaner"
+	"github.com/kyma-project/kyma-environment-broker/internal/storage"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/vrischmann/envconfig"
+)
+
+const AppPrefix = "retention"
+
+type Config struct {
+	Database    storage.Config
+	Job         JobConfig
+	Retention   retention.Config
+	MetricsPort string `envconfig:"default=8081"`
+}
+
+type JobConfig struct {
+	DryRun    bool `envconfig:"default=true"`
+	BatchSize int  `envconfig:"default=100"`
+}
+
+func main() {
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+	slog.SetDefault(logger)
+
+	slog.Info("Starting retention job")
+
+	var cfg Config
+	fatalOnError(envconfig.InitWithPrefix(&cfg, "APP"))
+
+	if cfg.Job.DryRun {
+		slog.Info("Dry run only - no changes")
+	}
+	slog.Info(fmt.Sprintf("Update operations to keep: %d, operations max age: %s, debug events TTL: %s, info events TTL: %s, warning events TTL: %s, error events TTL: %s, changes TTL: %s",
+		cfg.Retention.UpdateOperationsToKeep, cfg.Retention.OperationsMaxAge, cfg.Retention.DebugEventsTTL, cfg.Retention.InfoEventsTTL, cfg.Retention.WarningEventsTTL, cfg.Retention.ErrorEventsTTL, cfg.Retention.ChangesTTL))
+
+	cipher, err := storage.NewEncrypterFromConfig(cfg.Database)
+	fatalOnError(err)
+	db, conn, err := storage.NewFromConfig(cfg.Database, events.Config{}, cipher)
+	fatalOnError(err)
+
+	metricsRegistry := prometheus.NewRegistry()
+	metrics := retention.NewMetrics(metricsRegistry, AppPrefix)
+	http.Handle("/metrics", promhttp.HandlerFor(metricsRegistry, promhttp.HandlerOpts{Registry: metricsRegistry}))
+	go func() {
+		err := http.ListenAndServe(fmt.Sprintf(":%s", cfg.MetricsPort), nil)
+		if err != nil {
+			slog.Error(fmt.Sprintf("while serving metrics: %s", err))
+		}
+	}()
+
+	svc := retention.NewService(cfg.Retention, cfg.Job.DryRun, cfg.Job.BatchSize, db, metrics)
+	fatalOnError(svc.Run())
+
+	slog.Info("Retention job finished successfully!")
+
+	fatalOnError(conn.Close())
+	logOnError(cleaner.HaltIstioSidecar())
+	fatalOnError(cleaner.Halt())
+}
+
+func fatalOnError(err error) {
+	if err != nil {
+		slog.Error(err.Error())
+		os.Exit(0)
+	}
+}
+
+func logOnError(err error) {
+	if err != nil {
+		slog.Error(err.Error())
+	}
+}
@@ -57,7 +57,8 @@ func main() {
 
 	logs.Info(fmt.Sprintf("runtime-reconciler running as dry run? %t", cfg.DryRun))
 
-	cipher := storage.NewEncrypter(cfg.Database.SecretKey)
+	cipher, err := storage.NewEncrypterFromConfig(cfg.Database)
+	fatalOnError(err, logs)
 
 	db, _, err := storage.NewFromConfig(cfg.Database, cfg.Events, cipher)
 	fatalOnError(err, logs)
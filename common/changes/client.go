@@ -0,0 +1,138 @@
+package changes
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+type Kind string
+
+const (
+	InstanceKind  Kind = "instance"
+	OperationKind Kind = "operation"
+)
+
+// States of the instance changes, the changes of the operations carry the state of the operation
+const (
+	InstanceCreated = "created"
+	InstanceUpdated = "updated"
+	InstanceDeleted = "deleted"
+)
+
+const (
+	SinceParam = "since"
+	LimitParam = "limit"
+	WaitParam  = "wait"
+)
+
+// ChangeDTO is a single state transition of an instance or an operation. The sequence numbers are increasing
+// in the order in which the transitions were committed.
+type ChangeDTO struct {
+	Sequence      int64     `json:"sequence"`
+	Kind          Kind      `json:"kind"`
+	InstanceID    string    `json:"instanceID"`
+	OperationID   string    `json:"operationID,omitempty"`
+	OperationType string    `json:"operationType,omitempty"`
+	State         string    `json:"state"`
+	PreviousState string    `json:"previousState,omitempty"`
+	CreatedAt     time.Time `json:"createdAt"`
+}
+
+type ChangesPage struct {
+	Data []ChangeDTO `json:"data"`
+	// Cursor is passed as the since parameter of the next request to get the changes following the page
+	Cursor string `json:"cursor"`
+}
+
+// Cursor returns the cursor pointing after the change with the sequence number
+func Cursor(sequence int64) string {
+	return strconv.FormatInt(sequence, 10)
+}
+
+// ParseCursor returns the sequence number of the last change the client has seen, the empty cursor points
+// at the beginning of the feed
+func ParseCursor(cursor string) (int64, error) {
+	if cursor == "" {
+		return 0, nil
+	}
+	sequence, err := strconv.ParseInt(cursor, 10, 64)
+	if err != nil || sequence < 0 {
+		return 0, fmt.Errorf("invalid cursor %q", cursor)
+	}
+	return sequence, nil
+}
+
+// Client is the interface to interact with the KEB /changes API as an HTTP client using OIDC ID token in JWT format.
+type Client interface {
+	// ListChanges returns the changes following the cursor, if there are none, KEB waits for them up to the given time
+	ListChanges(since string, wait time.Duration) (ChangesPage, error)
+}
+
+type client struct {
+	url        string
+	httpClient *http.Client
+}
+
+// NewClient constructs and returns new Client for KEB /changes API
+// It takes the following arguments:
+//   - url        : base url of all KEB APIs, e.g. https://kyma-env-broker.kyma.local
+//   - httpClient : underlying HTTP client used for API call to KEB, its timeout must be longer than the wait time
+func NewClient(url string, httpClient *http.Client) Client {
+	return &client{
+		url:        url,
+		httpClient: httpClient,
+	}
+}
+
+func (c *client) ListChanges(since string, wait time.Duration) (page ChangesPage, err error) {
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/changes", c.url), nil)
+	if err != nil {
+		return page, fmt.Errorf("while creating request: %v", err)
+	}
+	q := req.URL.Query()
+	if since != "" {
+		q.Add(SinceParam, since)
+	}
+	if wait > 0 {
+		q.Add(WaitParam, wait.String())
+	}
+	req.URL.RawQuery = q.Encode()
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return page, fmt.Errorf("while calling %s: %v", req.URL.String(), err)
+	}
+
+	// Drain response body and close, return error to context if there isn't any.
+	defer func() {
+		derr := drainResponseBody(resp.Body)
+		if err == nil {
+			err = derr
+		}
+		cerr := resp.Body.Close()
+		if err == nil {
+			err = cerr
+		}
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return page, fmt.Errorf("calling %s returned %d (%s) status", req.URL.String(), resp.StatusCode, resp.Status)
+	}
+
+	err = json.NewDecoder(resp.Body).Decode(&page)
+	if err != nil {
+		return page, fmt.Errorf("while decoding response body: %v", err)
+	}
+	return page, nil
+}
+
+func drainResponseBody(body io.Reader) error {
+	if body == nil {
+		return nil
+	}
+	_, err := io.Copy(io.Discard, io.LimitReader(body, 4096))
+	return err
+}
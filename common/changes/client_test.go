@@ -0,0 +1,63 @@
+package changes
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_ListChanges(t *testing.T) {
+	// given
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		assert.Equal(t, "/changes", r.URL.Path)
+		assert.Equal(t, "41", r.URL.Query().Get(SinceParam))
+		assert.Equal(t, "30s", r.URL.Query().Get(WaitParam))
+
+		page := ChangesPage{
+			Data:   []ChangeDTO{{Sequence: 42, Kind: OperationKind, InstanceID: "instance-01", OperationID: "op-01", State: "succeeded", PreviousState: "in progress"}},
+			Cursor: Cursor(42),
+		}
+		w.Header().Set("Content-Type", "application/json")
+		require.NoError(t, json.NewEncoder(w).Encode(page))
+	}))
+	defer ts.Close()
+	client := NewClient(ts.URL, ts.Client())
+
+	// when
+	page, err := client.ListChanges(Cursor(41), 30*time.Second)
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, "42", page.Cursor)
+	require.Len(t, page.Data, 1)
+	assert.Equal(t, "in progress", page.Data[0].PreviousState)
+}
+
+func TestParseCursor(t *testing.T) {
+	for name, tc := range map[string]struct {
+		cursor   string
+		expected int64
+		err      bool
+	}{
+		"empty":    {cursor: "", expected: 0},
+		"sequence": {cursor: "17", expected: 17},
+		"negative": {cursor: "-1", err: true},
+		"invalid":  {cursor: "abc", err: true},
+	} {
+		t.Run(name, func(t *testing.T) {
+			sequence, err := ParseCursor(tc.cursor)
+			if tc.err {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, sequence)
+		})
+	}
+}
@@ -1,10 +1,12 @@
 package events
 
 import (
+	"database/sql/driver"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"strings"
 	"time"
 )
@@ -12,27 +14,99 @@ import (
 type EventLevel string
 
 const (
-	InfoEventLevel  EventLevel = "info"
-	ErrorEventLevel EventLevel = "error"
+	DebugEventLevel   EventLevel = "debug"
+	InfoEventLevel    EventLevel = "info"
+	WarningEventLevel EventLevel = "warning"
+	ErrorEventLevel   EventLevel = "error"
 )
 
+// EventLevels are all levels of the events, from the least to the most severe
+var EventLevels = []EventLevel{DebugEventLevel, InfoEventLevel, WarningEventLevel, ErrorEventLevel}
+
+func (l EventLevel) IsValid() bool {
+	for _, level := range EventLevels {
+		if l == level {
+			return true
+		}
+	}
+	return false
+}
+
+// The query parameters of the /events API, the values of the list parameters are separated by commas
+const (
+	InstanceIDsParam  = "instance_ids"
+	RuntimeIDsParam   = "runtime_ids"
+	OperationIDsParam = "operation_ids"
+	LevelsParam       = "levels"
+	CategoriesParam   = "categories"
+	FromParam         = "from"
+	ToParam           = "to"
+	TextParam         = "text"
+)
+
+// Attributes are the structured key/value details of the event, for example the dependency which returned the error
+type Attributes map[string]string
+
+// Scan reads the attributes kept as a JSON object in the database
+func (a *Attributes) Scan(src any) error {
+	var data []byte
+	switch v := src.(type) {
+	case nil:
+		*a = nil
+		return nil
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return fmt.Errorf("unsupported type %T of the event attributes", src)
+	}
+	return json.Unmarshal(data, a)
+}
+
+// Value writes the attributes as a JSON object to the database
+func (a Attributes) Value() (driver.Value, error) {
+	if len(a) == 0 {
+		return nil, nil
+	}
+	data, err := json.Marshal(a)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
 type EventDTO struct {
 	ID          string
 	Level       EventLevel
 	InstanceID  *string
 	OperationID *string
-	Message     string
-	CreatedAt   time.Time
+	// Category groups the events, the operation events are in the category of the step, or of the dependency which caused the error,
+	// such as infrastructure-manager, the step is always in the step attribute
+	Category   string
+	Message    string
+	Attributes Attributes
+	CreatedAt  time.Time
 }
 
+// EventFilter selects the events, the empty fields match all events
 type EventFilter struct {
 	InstanceIDs  []string
 	OperationIDs []string
+	Levels       []EventLevel
+	Categories   []string
+	// From and To limit the time when the event was created to [From, To)
+	From time.Time
+	To   time.Time
+	// Text matches the events which contain the text in the message or in the attributes, regardless of the case
+	Text string
 }
 
 // Client is the interface to interact with the KEB /events API as an HTTP client using OIDC ID token in JWT format.
 type Client interface {
 	ListEvents(instanceIDs []string) ([]EventDTO, error)
+	// SearchEvents returns the events matching the filter, for example the errors of a category created in the last hour
+	SearchEvents(filter EventFilter) ([]EventDTO, error)
 }
 
 type client struct {
@@ -53,14 +127,15 @@ func NewClient(url string, httpClient *http.Client) Client {
 
 // ListEvents
 func (c *client) ListEvents(instanceIDs []string) ([]EventDTO, error) {
-	var events []EventDTO
+	return c.SearchEvents(EventFilter{InstanceIDs: instanceIDs})
+}
+
+func (c *client) SearchEvents(filter EventFilter) (events []EventDTO, err error) {
 	req, err := http.NewRequest("GET", fmt.Sprintf("%s/events", c.url), nil)
 	if err != nil {
 		return events, fmt.Errorf("while creating request: %v", err)
 	}
-	q := req.URL.Query()
-	q.Add("instance_ids", strings.Join(instanceIDs, ","))
-	req.URL.RawQuery = q.Encode()
+	req.URL.RawQuery = filter.query().Encode()
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
 		return events, fmt.Errorf("while calling %s: %v", req.URL.String(), err)
@@ -90,6 +165,33 @@ func (c *client) ListEvents(instanceIDs []string) ([]EventDTO, error) {
 	return events, nil
 }
 
+func (f EventFilter) query() url.Values {
+	q := url.Values{}
+	addList := func(name string, values []string) {
+		if len(values) > 0 {
+			q.Add(name, strings.Join(values, ","))
+		}
+	}
+	addList(InstanceIDsParam, f.InstanceIDs)
+	addList(OperationIDsParam, f.OperationIDs)
+	levels := make([]string, 0, len(f.Levels))
+	for _, level := range f.Levels {
+		levels = append(levels, string(level))
+	}
+	addList(LevelsParam, levels)
+	addList(CategoriesParam, f.Categories)
+	if !f.From.IsZero() {
+		q.Add(FromParam, f.From.Format(time.RFC3339Nano))
+	}
+	if !f.To.IsZero() {
+		q.Add(ToParam, f.To.Format(time.RFC3339Nano))
+	}
+	if f.Text != "" {
+		q.Add(TextParam, f.Text)
+	}
+	return q
+}
+
 func drainResponseBody(body io.Reader) error {
 	if body == nil {
 		return nil
@@ -0,0 +1,71 @@
+package events
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_SearchEvents(t *testing.T) {
+	// given
+	from := time.Date(2026, 10, 17, 11, 0, 0, 0, time.UTC)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/events", r.URL.Path)
+		query := r.URL.Query()
+		assert.Equal(t, "error,warning", query.Get(LevelsParam))
+		assert.Equal(t, "create_runtime_resource", query.Get(CategoriesParam))
+		assert.Equal(t, "2026-10-17T11:00:00Z", query.Get(FromParam))
+		assert.Empty(t, query.Get(ToParam))
+		assert.Equal(t, "quota", query.Get(TextParam))
+		assert.False(t, query.Has(InstanceIDsParam))
+
+		instanceID := "instance-01"
+		require.NoError(t, json.NewEncoder(w).Encode([]EventDTO{{
+			Level:      ErrorEventLevel,
+			InstanceID: &instanceID,
+			Category:   "create_runtime_resource",
+			Message:    "quota exceeded",
+			Attributes: Attributes{"component": "infrastructure-manager"},
+		}}))
+	}))
+	defer ts.Close()
+	client := NewClient(ts.URL, ts.Client())
+
+	// when
+	events, err := client.SearchEvents(EventFilter{
+		Levels:     []EventLevel{ErrorEventLevel, WarningEventLevel},
+		Categories: []string{"create_runtime_resource"},
+		From:       from,
+		Text:       "quota",
+	})
+
+	// then
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, "infrastructure-manager", events[0].Attributes["component"])
+}
+
+func TestAttributes_ScanAndValue(t *testing.T) {
+	// given
+	attributes := Attributes{"component": "cis"}
+
+	// when
+	value, err := attributes.Value()
+	require.NoError(t, err)
+	var scanned Attributes
+	require.NoError(t, scanned.Scan([]byte(value.(string))))
+
+	// then
+	assert.Equal(t, attributes, scanned)
+
+	empty, err := Attributes{}.Value()
+	require.NoError(t, err)
+	assert.Nil(t, empty)
+	require.NoError(t, scanned.Scan(nil))
+	assert.Nil(t, scanned)
+}
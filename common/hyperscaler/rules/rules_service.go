@@ -138,6 +138,14 @@ func (rs *RulesService) getSortedRulesForPlan(plan string) []ValidRule {
 	return rulesForPlan
 }
 
+// HasRulesForPlan returns true when at least one valid rule applies to the plan
+func (rs *RulesService) HasRulesForPlan(plan string) bool {
+	if rs.ValidRules == nil {
+		return false
+	}
+	return len(rs.getSortedRulesForPlan(plan)) > 0
+}
+
 func (rs *RulesService) MatchProvisioningAttributesWithValidRuleset(provisioningAttributes *ProvisioningAttributes) (Result, bool) {
 	if rs.ValidRules == nil || len(rs.ValidRules.Rules) == 0 {
 		slog.Warn("No valid ruleset or empty valid ruleset")
@@ -527,6 +527,17 @@ func TestRulesService_CheckPlans(t *testing.T) {
 	}
 }
 
+func TestRulesService_HasRulesForPlan(t *testing.T) {
+	// given
+	rulesService, err := NewRulesServiceFromSlice([]string{"aws", "build-runtime-aws(PR=cf-eu11)"}, sets.New("aws", "build-runtime-aws", "gcp"), sets.New[string]())
+	require.NoError(t, err)
+
+	// then
+	assert.True(t, rulesService.HasRulesForPlan("aws"))
+	assert.True(t, rulesService.HasRulesForPlan("build-runtime-aws"))
+	assert.False(t, rulesService.HasRulesForPlan("gcp"))
+}
+
 func fixRulesService() *RulesService {
 
 	rs := &RulesService{
@@ -0,0 +1,98 @@
+package operations
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+type OperationDTO struct {
+	OperationID string `json:"operation"`
+	State       string `json:"state"`
+}
+
+// Client is the interface to interact with the KEB /operations API as an HTTP client using OIDC ID token in JWT format.
+type Client interface {
+	RetryOperation(operationID string) (OperationDTO, error)
+	CancelOperation(operationID string) (OperationDTO, error)
+}
+
+type client struct {
+	url        string
+	httpClient *http.Client
+}
+
+// NewClient constructs and returns new Client for KEB /operations API
+// It takes the following arguments:
+//   - url        : base url of all KEB APIs, e.g. https://kyma-env-broker.kyma.local
+//   - httpClient : underlying HTTP client used for API call to KEB
+func NewClient(url string, httpClient *http.Client) Client {
+	return &client{
+		url:        url,
+		httpClient: httpClient,
+	}
+}
+
+// RetryOperation resumes the failed operation from the stage which has not been finished
+func (c *client) RetryOperation(operationID string) (OperationDTO, error) {
+	return c.post(fmt.Sprintf("%s/operations/%s/retry", c.url, operationID))
+}
+
+// CancelOperation requests the cancellation of the operation in progress
+func (c *client) CancelOperation(operationID string) (OperationDTO, error) {
+	return c.post(fmt.Sprintf("%s/operations/%s/cancel", c.url, operationID))
+}
+
+func (c *client) post(url string) (OperationDTO, error) {
+	var operation OperationDTO
+	req, err := http.NewRequest(http.MethodPost, url, nil)
+	if err != nil {
+		return operation, fmt.Errorf("while creating request: %v", err)
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return operation, fmt.Errorf("while calling %s: %v", req.URL.String(), err)
+	}
+
+	// Drain response body and close, return error to context if there isn't any.
+	defer func() {
+		derr := drainResponseBody(resp.Body)
+		if err == nil {
+			err = derr
+		}
+		cerr := resp.Body.Close()
+		if err == nil {
+			err = cerr
+		}
+	}()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusAccepted {
+		return operation, fmt.Errorf("calling %s returned %d (%s) status: %s", req.URL.String(), resp.StatusCode, resp.Status, readErrorMessage(resp.Body))
+	}
+
+	decoder := json.NewDecoder(resp.Body)
+	err = decoder.Decode(&operation)
+	if err != nil {
+		return operation, fmt.Errorf("while decoding response body: %v", err)
+	}
+	return operation, nil
+}
+
+func readErrorMessage(body io.Reader) string {
+	var response struct {
+		Error string `json:"error"`
+	}
+	if err := json.NewDecoder(io.LimitReader(body, 4096)).Decode(&response); err != nil {
+		return ""
+	}
+	return response.Error
+}
+
+func drainResponseBody(body io.Reader) error {
+	if body == nil {
+		return nil
+	}
+	_, err := io.Copy(io.Discard, io.LimitReader(body, 4096))
+	return err
+}
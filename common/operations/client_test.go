@@ -0,0 +1,56 @@
+package operations
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_RetryOperation(t *testing.T) {
+	t.Run("test request URL and response are correct", func(t *testing.T) {
+		// given
+		called := 0
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			called++
+			assert.Equal(t, http.MethodPost, r.Method)
+			assert.Equal(t, "/operations/op-1/retry", r.URL.Path)
+
+			w.WriteHeader(http.StatusAccepted)
+			err := json.NewEncoder(w).Encode(OperationDTO{OperationID: "op-1", State: "in progress"})
+			require.NoError(t, err)
+		}))
+		defer ts.Close()
+		client := NewClient(ts.URL, ts.Client())
+
+		// when
+		operation, err := client.RetryOperation("op-1")
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, 1, called)
+		assert.Equal(t, OperationDTO{OperationID: "op-1", State: "in progress"}, operation)
+	})
+
+	t.Run("test error message is returned", func(t *testing.T) {
+		// given
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusConflict)
+			_, err := w.Write([]byte(`{"error":"only failed operations can be retried"}`))
+			require.NoError(t, err)
+		}))
+		defer ts.Close()
+		client := NewClient(ts.URL, ts.Client())
+
+		// when
+		_, err := client.RetryOperation("op-1")
+
+		// then
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "409")
+		assert.Contains(t, err.Error(), "only failed operations can be retried")
+	})
+}
@@ -0,0 +1,69 @@
+package pagination
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// CursorParam is the continuation token returned with the previous page, it is used instead of the page parameter
+const CursorParam = "cursor"
+
+// Cursor points at the last item of a page. The items are ordered by the creation time and the ID, so the next page
+// starts right after the cursor, also when new items are inserted in the meantime.
+type Cursor struct {
+	CreatedAt time.Time `json:"createdAt"`
+	ID        string    `json:"id"`
+}
+
+func NewCursor(createdAt time.Time, id string) Cursor {
+	return Cursor{CreatedAt: createdAt, ID: id}
+}
+
+// Encode returns the opaque token which is passed to the client
+func (c Cursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// Precedes reports whether the item with the given creation time and ID is placed after the cursor
+func (c Cursor) Precedes(createdAt time.Time, id string) bool {
+	if createdAt.Equal(c.CreatedAt) {
+		return id > c.ID
+	}
+	return createdAt.After(c.CreatedAt)
+}
+
+func DecodeCursor(token string) (Cursor, error) {
+	var c Cursor
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return c, fmt.Errorf("cursor is malformed")
+	}
+	if err := json.Unmarshal(data, &c); err != nil || c.ID == "" {
+		return c, fmt.Errorf("cursor is malformed")
+	}
+	return c, nil
+}
+
+// ExtractCursorFromRequest returns the cursor passed in the request, or nil if the request does not contain it
+func ExtractCursorFromRequest(req *http.Request) (*Cursor, error) {
+	params := req.URL.Query()
+	cursorArr, ok := params[CursorParam]
+	if !ok {
+		return nil, nil
+	}
+	if len(cursorArr) > 1 {
+		return nil, fmt.Errorf("cursor has to be one parameter")
+	}
+	if _, ok := params[PageParam]; ok {
+		return nil, fmt.Errorf("cursor and page cannot be used together")
+	}
+	cursor, err := DecodeCursor(cursorArr[0])
+	if err != nil {
+		return nil, err
+	}
+	return &cursor, nil
+}
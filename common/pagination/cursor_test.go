@@ -0,0 +1,81 @@
+package pagination
+
+import (
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCursor_EncodeDecode(t *testing.T) {
+	// given
+	cursor := NewCursor(time.Date(2025, 3, 4, 5, 6, 7, 123456000, time.UTC), "instance-1")
+
+	// when
+	decoded, err := DecodeCursor(cursor.Encode())
+
+	// then
+	require.NoError(t, err)
+	assert.True(t, cursor.CreatedAt.Equal(decoded.CreatedAt))
+	assert.Equal(t, cursor.ID, decoded.ID)
+}
+
+func TestCursor_Precedes(t *testing.T) {
+	now := time.Now()
+	cursor := NewCursor(now, "b")
+
+	assert.True(t, cursor.Precedes(now.Add(time.Second), "a"))
+	assert.True(t, cursor.Precedes(now, "c"))
+	assert.False(t, cursor.Precedes(now, "b"))
+	assert.False(t, cursor.Precedes(now, "a"))
+	assert.False(t, cursor.Precedes(now.Add(-time.Second), "c"))
+}
+
+func TestExtractCursorFromRequest(t *testing.T) {
+	token := NewCursor(time.Now(), "instance-1").Encode()
+
+	for tn, tc := range map[string]struct {
+		query     url.Values
+		expectErr bool
+		expectNil bool
+	}{
+		"no cursor": {
+			query:     url.Values{PageParam: {"2"}},
+			expectNil: true,
+		},
+		"cursor": {
+			query: url.Values{CursorParam: {token}, PageSizeParam: {"10"}},
+		},
+		"cursor with page": {
+			query:     url.Values{CursorParam: {token}, PageParam: {"2"}},
+			expectErr: true,
+		},
+		"malformed cursor": {
+			query:     url.Values{CursorParam: {"not-a-cursor"}},
+			expectErr: true,
+		},
+		"two cursors": {
+			query:     url.Values{CursorParam: {token, token}},
+			expectErr: true,
+		},
+	} {
+		t.Run(tn, func(t *testing.T) {
+			// given
+			req := httptest.NewRequest("GET", "/runtimes?"+tc.query.Encode(), nil)
+
+			// when
+			cursor, err := ExtractCursorFromRequest(req)
+
+			// then
+			if tc.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectNil, cursor == nil)
+		})
+	}
+}
@@ -96,7 +96,11 @@ func (c *client) ListRuntimes(params ListParameters) (RuntimesPage, error) {
 		runtimes.Count += rp.Count
 		runtimes.Data = append(runtimes.Data, rp.Data...)
 		if getAll {
-			params.Page++
+			if rp.NextCursor != "" {
+				params.Cursor = rp.NextCursor
+			} else {
+				params.Page++
+			}
 			fetchedAll = runtimes.Count >= runtimes.TotalCount
 		} else {
 			fetchedAll = true
@@ -114,7 +118,11 @@ func (c *client) ListRuntimes(params ListParameters) (RuntimesPage, error) {
 
 func setQuery(url *url.URL, params ListParameters) {
 	query := url.Query()
-	query.Add(pagination.PageParam, strconv.Itoa(params.Page))
+	if params.Cursor != "" {
+		query.Add(pagination.CursorParam, params.Cursor)
+	} else {
+		query.Add(pagination.PageParam, strconv.Itoa(params.Page))
+	}
 	query.Add(pagination.PageSizeParam, strconv.Itoa(params.PageSize))
 	if params.OperationDetail != "" {
 		query.Add(OperationDetailParam, string(params.OperationDetail))
@@ -143,6 +151,9 @@ func setQuery(url *url.URL, params ListParameters) {
 	if params.Actions {
 		query.Add(ActionsParam, "true")
 	}
+	if params.Timeline {
+		query.Add(TimelineParam, "true")
+	}
 	setParamList(query, GlobalAccountIDParam, params.GlobalAccountIDs)
 	setParamList(query, SubAccountIDParam, params.SubAccountIDs)
 	setParamList(query, InstanceIDParam, params.InstanceIDs)
@@ -120,6 +120,35 @@ func TestClient_ListRuntimes(t *testing.T) {
 		assert.Len(t, rp.Data, 4)
 	})
 
+	t.Run("test pagination with cursor", func(t *testing.T) {
+		var cursors []string
+		params := ListParameters{
+			PageSize: 2,
+		}
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			query := r.URL.Query()
+			cursors = append(cursors, query.Get(pagination.CursorParam))
+			if query.Has(pagination.CursorParam) {
+				assert.False(t, query.Has(pagination.PageParam))
+			}
+
+			rp := RuntimesPage{Data: []RuntimeDTO{runtime1, runtime2}, Count: 2, TotalCount: 4, NextCursor: "next"}
+			w.Header().Set("Content-Type", "application/json")
+			require.NoError(t, json.NewEncoder(w).Encode(rp))
+		}))
+		defer ts.Close()
+		client := NewClient(ts.URL, oauth2.NewClient(context.Background(), fixToken))
+
+		//when
+		rp, err := client.ListRuntimes(params)
+
+		//then
+		require.NoError(t, err)
+		assert.Equal(t, []string{"", "next"}, cursors)
+		assert.Equal(t, 4, rp.Count)
+		assert.Len(t, rp.Data, 4)
+	})
+
 	t.Run("Test deprovisioned runtimes limit", func(t *testing.T) {
 		//given
 		params := ListParameters{
@@ -373,6 +373,8 @@ type BindingDTO struct {
 	ExpiresAt         time.Time `json:"expiresAt"`
 	KubeconfigExists  bool      `json:"kubeconfigExists"`
 	CreatedBy         string    `json:"createdBy"`
+	Role              string    `json:"role,omitempty"`
+	Namespaces        []string  `json:"namespaces,omitempty"`
 }
 
 type ActionType string
@@ -380,6 +382,7 @@ type ActionType string
 const (
 	PlanUpdateActionType         ActionType = "plan_update"
 	SubaccountMovementActionType ActionType = "subaccount_movement"
+	OperationRetryActionType     ActionType = "operation_retry"
 )
 
 type Action struct {
@@ -442,12 +445,66 @@ type Operation struct {
 	Parameters                   ProvisioningParametersDTO `json:"parameters,omitempty"`
 	Error                        *kebError.LastError       `json:"error,omitempty"`
 	UpdatedPlanName              string                    `json:"updatedPlanName,omitempty"`
+	Timeline                     []OperationStep           `json:"timeline,omitempty"`
+}
+
+type StepOutcome string
+
+const (
+	StepSucceeded StepOutcome = "succeeded"
+	StepRetried   StepOutcome = "retried"
+	StepFailed    StepOutcome = "failed"
+)
+
+// OperationStep describes a single execution of an operation step
+type OperationStep struct {
+	ID                string      `json:"-"`
+	OperationID       string      `json:"operationID"`
+	Stage             string      `json:"stage,omitempty"`
+	Step              string      `json:"step"`
+	StartedAt         time.Time   `json:"startedAt"`
+	FinishedAt        time.Time   `json:"finishedAt"`
+	Outcome           StepOutcome `json:"outcome"`
+	RetryAfterSeconds float64     `json:"retryAfterSeconds,omitempty"`
+	ErrorReason       string      `json:"errorReason,omitempty"`
+	ErrorComponent    string      `json:"errorComponent,omitempty"`
+	ErrorMessage      string      `json:"errorMessage,omitempty"`
+}
+
+// ArchivedInstanceDTO describes the instance which was deprovisioned and removed from the instances table
+type ArchivedInstanceDTO struct {
+	InstanceID                    string    `json:"instanceID"`
+	RuntimeID                     string    `json:"runtimeID"`
+	GlobalAccountID               string    `json:"globalAccountID"`
+	SubscriptionGlobalAccountID   string    `json:"subscriptionGlobalAccountID"`
+	SubAccountID                  string    `json:"subAccountID"`
+	ProviderRegion                string    `json:"region"`
+	SubAccountRegion              string    `json:"subAccountRegion"`
+	ShootName                     string    `json:"shootName"`
+	ServicePlanID                 string    `json:"servicePlanID"`
+	ServicePlanName               string    `json:"servicePlanName"`
+	Provider                      string    `json:"provider"`
+	InternalUser                  bool      `json:"internalUser"`
+	ProvisioningState             string    `json:"provisioningState"`
+	ProvisioningStartedAt         time.Time `json:"provisioningStartedAt"`
+	ProvisioningFinishedAt        time.Time `json:"provisioningFinishedAt"`
+	FirstDeprovisioningStartedAt  time.Time `json:"firstDeprovisioningStartedAt"`
+	FirstDeprovisioningFinishedAt time.Time `json:"firstDeprovisioningFinishedAt"`
+	LastDeprovisioningFinishedAt  time.Time `json:"lastDeprovisioningFinishedAt"`
+}
+
+type ArchivedInstancesPage struct {
+	Data       []ArchivedInstanceDTO `json:"data"`
+	Count      int                   `json:"count"`
+	TotalCount int                   `json:"totalCount"`
 }
 
 type RuntimesPage struct {
 	Data       []RuntimeDTO `json:"data"`
 	Count      int          `json:"count"`
 	TotalCount int          `json:"totalCount"`
+	// NextCursor is the token which returns the next page when passed in the cursor parameter, it is empty if the page is not full
+	NextCursor string `json:"nextCursor,omitempty"`
 }
 
 const (
@@ -468,6 +525,11 @@ const (
 	BindingsParam        = "bindings"
 	WithBindingsParam    = "with_bindings"
 	ActionsParam         = "actions"
+	TimelineParam        = "timeline"
+
+	ProviderParam          = "provider"
+	DeprovisionedFromParam = "deprovisioned_from"
+	DeprovisionedToParam   = "deprovisioned_to"
 )
 
 type OperationDetail string
@@ -482,6 +544,8 @@ type ListParameters struct {
 	Page int
 	// PageSize specifies the count of matching runtimes returned in a response
 	PageSize int
+	// Cursor specifies the continuation token returned with the previous page, it is used instead of Page
+	Cursor string
 	// OperationDetail specifies whether the server should respond with all operations, or only the last operation. If not set, the server by default sends all operations
 	OperationDetail OperationDetail
 	// KymaConfig specifies whether kyma configuration details should be included in the response for each runtime
@@ -518,6 +582,8 @@ type ListParameters struct {
 	Events string
 	// Actions specifies whether audit logs should be included in the response for each runtime
 	Actions bool
+	// Timeline specifies whether the executed steps should be included in each operation, used only with all operations details
+	Timeline bool
 }
 
 func (rt RuntimeDTO) LastOperation() Operation {
@@ -12,12 +12,18 @@ import (
 
 // DBReader wraps a raw dbr session for analytics queries.
 type DBReader struct {
-	session *dbr.Session
+	sessions func() *dbr.Session
 }
 
 // NewDBReader creates a DBReader from a dbr session.
 func NewDBReader(session *dbr.Session) *DBReader {
-	return &DBReader{session: session}
+	return &DBReader{sessions: func() *dbr.Session { return session }}
+}
+
+// NewDBReaderWithSessions creates a DBReader which opens a session for every query,
+// for example, on the read replica only when it is healthy.
+func NewDBReaderWithSessions(sessions func() *dbr.Session) *DBReader {
+	return &DBReader{sessions: sessions}
 }
 
 // TimeRange optionally constrains queries to operations created within [From, To).
@@ -50,7 +56,7 @@ WHERE o.type = 'provision'
 	var rows []struct {
 		ProvisioningParameters string `db:"provisioning_parameters"`
 	}
-	_, err := r.session.SelectBySql(q, args...).Load(&rows)
+	_, err := r.sessions().SelectBySql(q, args...).Load(&rows)
 	if err != nil {
 		return nil, fmt.Errorf("fetching active provisioning params: %w", err)
 	}
@@ -105,7 +111,7 @@ WHERE o.type = 'update'
 	var rows []struct {
 		Data string `db:"data"`
 	}
-	_, err := r.session.SelectBySql(q, args...).Load(&rows)
+	_, err := r.sessions().SelectBySql(q, args...).Load(&rows)
 	if err != nil {
 		return nil, fmt.Errorf("fetching update params: %w", err)
 	}
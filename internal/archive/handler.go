@@ -0,0 +1,201 @@
+package archive
+
+import (
+	"encoding/csv"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kyma-project/kyma-environment-broker/common/pagination"
+	pkg "github.com/kyma-project/kyma-environment-broker/common/runtime"
+	"github.com/kyma-project/kyma-environment-broker/internal"
+	"github.com/kyma-project/kyma-environment-broker/internal/httputil"
+	"github.com/kyma-project/kyma-environment-broker/internal/storage"
+	"github.com/kyma-project/kyma-environment-broker/internal/storage/dbmodel"
+)
+
+const csvContentType = "text/csv"
+
+var csvHeader = []string{
+	"instanceID", "runtimeID", "globalAccountID", "subscriptionGlobalAccountID", "subAccountID", "region", "subAccountRegion",
+	"shootName", "servicePlanID", "servicePlanName", "provider", "internalUser", "provisioningState", "provisioningStartedAt",
+	"provisioningFinishedAt", "firstDeprovisioningStartedAt", "firstDeprovisioningFinishedAt", "lastDeprovisioningFinishedAt",
+}
+
+// Handler lists the archived instances for the reporting and the audit. The instances are returned as JSON pages,
+// or, if the client accepts text/csv, all matching instances are exported in one CSV document.
+type Handler struct {
+	instancesArchived storage.InstancesArchived
+	defaultMaxPage    int
+	log               *slog.Logger
+}
+
+func NewHandler(instancesArchived storage.InstancesArchived, defaultMaxPage int, log *slog.Logger) *Handler {
+	return &Handler{
+		instancesArchived: instancesArchived,
+		defaultMaxPage:    defaultMaxPage,
+		log:               log.With("service", "ArchivedInstancesEndpoint"),
+	}
+}
+
+func (h *Handler) AttachRoutes(router *httputil.Router) {
+	router.HandleFunc("GET /archived_instances", h.listArchivedInstances)
+}
+
+func (h *Handler) listArchivedInstances(w http.ResponseWriter, req *http.Request) {
+	filter, err := getFilters(req)
+	if err != nil {
+		httputil.WriteErrorResponse(w, http.StatusBadRequest, fmt.Errorf("while getting query parameters: %w", err))
+		return
+	}
+	if strings.Contains(req.Header.Get("Accept"), csvContentType) {
+		h.exportCSV(w, filter)
+		return
+	}
+
+	filter.PageSize, filter.Page, err = pagination.ExtractPaginationConfigFromRequest(req, h.defaultMaxPage)
+	if err != nil {
+		httputil.WriteErrorResponse(w, http.StatusBadRequest, fmt.Errorf("while getting query parameters: %w", err))
+		return
+	}
+	instances, count, totalCount, err := h.instancesArchived.List(filter)
+	if err != nil {
+		h.log.Error(fmt.Sprintf("unable to list archived instances: %s", err))
+		httputil.WriteErrorResponse(w, http.StatusInternalServerError, fmt.Errorf("while fetching archived instances: %w", err))
+		return
+	}
+
+	data := make([]pkg.ArchivedInstanceDTO, 0, len(instances))
+	for _, instance := range instances {
+		data = append(data, toDTO(instance))
+	}
+	httputil.WriteResponse(w, http.StatusOK, pkg.ArchivedInstancesPage{
+		Data:       data,
+		Count:      count,
+		TotalCount: totalCount,
+	})
+}
+
+// exportCSV writes all matching instances, the instances are read from the storage page by page
+func (h *Handler) exportCSV(w http.ResponseWriter, filter dbmodel.InstanceFilter) {
+	filter.PageSize = h.defaultMaxPage
+	filter.Page = 1
+	instances, _, totalCount, err := h.instancesArchived.List(filter)
+	if err != nil {
+		h.log.Error(fmt.Sprintf("unable to list archived instances: %s", err))
+		httputil.WriteErrorResponse(w, http.StatusInternalServerError, fmt.Errorf("while fetching archived instances: %w", err))
+		return
+	}
+
+	w.Header().Set("Content-Type", csvContentType)
+	w.Header().Set("Content-Disposition", `attachment; filename="archived_instances.csv"`)
+	w.WriteHeader(http.StatusOK)
+	writer := csv.NewWriter(w)
+	_ = writer.Write(csvHeader)
+	written := 0
+	for len(instances) > 0 {
+		for _, instance := range instances {
+			_ = writer.Write(toCSVRecord(toDTO(instance)))
+		}
+		written += len(instances)
+		if written >= totalCount || len(instances) < filter.PageSize {
+			break
+		}
+		filter.Page++
+		instances, _, _, err = h.instancesArchived.List(filter)
+		if err != nil {
+			// the status is already sent, the client gets an incomplete document
+			h.log.Error(fmt.Sprintf("unable to list archived instances, page %d: %s", filter.Page, err))
+			break
+		}
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		h.log.Warn(fmt.Sprintf("unable to write CSV: %s", err))
+	}
+}
+
+func getFilters(req *http.Request) (dbmodel.InstanceFilter, error) {
+	var filter dbmodel.InstanceFilter
+	query := req.URL.Query()
+	// For optional filter, zero value (nil) is fine if not supplied
+	filter.GlobalAccountIDs = query[pkg.GlobalAccountIDParam]
+	filter.SubAccountIDs = query[pkg.SubAccountIDParam]
+	filter.InstanceIDs = query[pkg.InstanceIDParam]
+	filter.RuntimeIDs = query[pkg.RuntimeIDParam]
+	filter.Regions = query[pkg.RegionParam]
+	filter.Plans = query[pkg.PlanParam]
+	filter.Providers = query[pkg.ProviderParam]
+
+	var err error
+	if filter.DeprovisionedFrom, err = getTimeParam(query.Get(pkg.DeprovisionedFromParam), pkg.DeprovisionedFromParam, false); err != nil {
+		return filter, err
+	}
+	if filter.DeprovisionedTo, err = getTimeParam(query.Get(pkg.DeprovisionedToParam), pkg.DeprovisionedToParam, true); err != nil {
+		return filter, err
+	}
+	if !filter.DeprovisionedFrom.IsZero() && !filter.DeprovisionedTo.IsZero() && !filter.DeprovisionedFrom.Before(filter.DeprovisionedTo) {
+		return filter, fmt.Errorf("%s must be before %s", pkg.DeprovisionedFromParam, pkg.DeprovisionedToParam)
+	}
+	return filter, nil
+}
+
+// getTimeParam accepts the time in the RFC 3339 format or the date only.
+// The end of the range given as the date only is moved to the next day, so the range includes the whole day.
+func getTimeParam(value, name string, rangeEnd bool) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(time.DateOnly, value); err == nil {
+		if rangeEnd {
+			return t.AddDate(0, 0, 1), nil
+		}
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("%s must be a date or a time in the RFC 3339 format", name)
+}
+
+func toDTO(instance internal.InstanceArchived) pkg.ArchivedInstanceDTO {
+	return pkg.ArchivedInstanceDTO{
+		InstanceID:                    instance.InstanceID,
+		RuntimeID:                     instance.LastRuntimeID,
+		GlobalAccountID:               instance.GlobalAccountID,
+		SubscriptionGlobalAccountID:   instance.SubscriptionGlobalAccountID,
+		SubAccountID:                  instance.SubaccountID,
+		ProviderRegion:                instance.Region,
+		SubAccountRegion:              instance.SubaccountRegion,
+		ShootName:                     instance.ShootName,
+		ServicePlanID:                 instance.PlanID,
+		ServicePlanName:               instance.PlanName,
+		Provider:                      instance.Provider,
+		InternalUser:                  instance.InternalUser,
+		ProvisioningState:             string(instance.ProvisioningState),
+		ProvisioningStartedAt:         instance.ProvisioningStartedAt,
+		ProvisioningFinishedAt:        instance.ProvisioningFinishedAt,
+		FirstDeprovisioningStartedAt:  instance.FirstDeprovisioningStartedAt,
+		FirstDeprovisioningFinishedAt: instance.FirstDeprovisioningFinishedAt,
+		LastDeprovisioningFinishedAt:  instance.LastDeprovisioningFinishedAt,
+	}
+}
+
+func toCSVRecord(dto pkg.ArchivedInstanceDTO) []string {
+	return []string{
+		dto.InstanceID, dto.RuntimeID, dto.GlobalAccountID, dto.SubscriptionGlobalAccountID, dto.SubAccountID, dto.ProviderRegion, dto.SubAccountRegion,
+		dto.ShootName, dto.ServicePlanID, dto.ServicePlanName, dto.Provider, strconv.FormatBool(dto.InternalUser), dto.ProvisioningState,
+		formatTime(dto.ProvisioningStartedAt), formatTime(dto.ProvisioningFinishedAt), formatTime(dto.FirstDeprovisioningStartedAt),
+		formatTime(dto.FirstDeprovisioningFinishedAt), formatTime(dto.LastDeprovisioningFinishedAt),
+	}
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
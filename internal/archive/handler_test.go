@@ -0,0 +1,120 @@
+package archive
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	pkg "github.com/kyma-project/kyma-environment-broker/common/runtime"
+	"github.com/kyma-project/kyma-environment-broker/internal"
+	"github.com/kyma-project/kyma-environment-broker/internal/httputil"
+	"github.com/kyma-project/kyma-environment-broker/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler_ListArchivedInstances(t *testing.T) {
+	db := storage.NewMemoryStorage()
+	fixArchived := func(id, plan, provider string, deprovisionedAt time.Time) {
+		require.NoError(t, db.InstancesArchived().Insert(internal.InstanceArchived{
+			InstanceID:                   id,
+			GlobalAccountID:              "ga-01",
+			SubaccountID:                 "sa-" + id,
+			PlanName:                     plan,
+			Region:                       "eu-central-1",
+			Provider:                     provider,
+			ProvisioningState:            "succeeded",
+			LastDeprovisioningFinishedAt: deprovisionedAt,
+		}))
+	}
+	fixArchived("inst-1", "aws", "AWS", time.Date(2026, 4, 10, 0, 0, 0, 0, time.UTC))
+	fixArchived("inst-2", "aws", "AWS", time.Date(2026, 7, 2, 0, 0, 0, 0, time.UTC))
+	fixArchived("inst-3", "azure", "Azure", time.Date(2026, 5, 20, 0, 0, 0, 0, time.UTC))
+	fixArchived("inst-4", "aws", "AWS", time.Date(2026, 6, 30, 23, 59, 0, 0, time.UTC))
+
+	router := httputil.NewRouter()
+	NewHandler(db.InstancesArchived(), 100, slog.New(slog.NewTextHandler(os.Stdout, nil))).AttachRoutes(router)
+
+	t.Run("should return the instances of the plan deprovisioned in the time range", func(t *testing.T) {
+		// given
+		req := httptest.NewRequest(http.MethodGet, "/archived_instances?plan=aws&provider=AWS&deprovisioned_from=2026-04-01&deprovisioned_to=2026-06-30", nil)
+		w := httptest.NewRecorder()
+
+		// when
+		router.ServeHTTP(w, req)
+
+		// then
+		require.Equal(t, http.StatusOK, w.Code)
+		var page pkg.ArchivedInstancesPage
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &page))
+		assert.Equal(t, 2, page.TotalCount)
+		require.Len(t, page.Data, 2)
+		var ids []string
+		for _, instance := range page.Data {
+			ids = append(ids, instance.InstanceID)
+			assert.Equal(t, "sa-"+instance.InstanceID, instance.SubAccountID)
+		}
+		assert.ElementsMatch(t, []string{"inst-1", "inst-4"}, ids)
+	})
+
+	t.Run("should include the whole day of the date only end of the range", func(t *testing.T) {
+		for query, expected := range map[string][]string{
+			"deprovisioned_from=2026-06-30&deprovisioned_to=2026-06-30":                     {"inst-4"},
+			"deprovisioned_from=2026-06-30&deprovisioned_to=2026-06-30T23:00:00Z":           {},
+			"deprovisioned_from=2026-06-30T23:00:00Z&deprovisioned_to=2026-07-02":           {"inst-4", "inst-2"},
+			"deprovisioned_from=2026-06-30T23:00:00Z&deprovisioned_to=2026-07-02T00:00:00Z": {"inst-4"},
+		} {
+			// given
+			req := httptest.NewRequest(http.MethodGet, "/archived_instances?"+query, nil)
+			w := httptest.NewRecorder()
+
+			// when
+			router.ServeHTTP(w, req)
+
+			// then
+			require.Equal(t, http.StatusOK, w.Code, query)
+			var page pkg.ArchivedInstancesPage
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &page))
+			ids := []string{}
+			for _, instance := range page.Data {
+				ids = append(ids, instance.InstanceID)
+			}
+			assert.ElementsMatch(t, expected, ids, query)
+		}
+	})
+
+	t.Run("should export the instances as CSV", func(t *testing.T) {
+		// given
+		req := httptest.NewRequest(http.MethodGet, "/archived_instances?provider=Azure", nil)
+		req.Header.Set("Accept", "text/csv")
+		w := httptest.NewRecorder()
+
+		// when
+		router.ServeHTTP(w, req)
+
+		// then
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "text/csv", w.Header().Get("Content-Type"))
+		records, err := csv.NewReader(strings.NewReader(w.Body.String())).ReadAll()
+		require.NoError(t, err)
+		require.Len(t, records, 2)
+		assert.Equal(t, csvHeader, records[0])
+		assert.Equal(t, "inst-3", records[1][0])
+		assert.Equal(t, "2026-05-20T00:00:00Z", records[1][len(csvHeader)-1])
+		assert.Empty(t, records[1][len(csvHeader)-2])
+	})
+
+	t.Run("should reject the invalid time range", func(t *testing.T) {
+		for _, query := range []string{"deprovisioned_from=yesterday", "deprovisioned_from=2026-07-01&deprovisioned_to=2026-04-01"} {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/archived_instances?"+query, nil))
+			assert.Equal(t, http.StatusBadRequest, w.Code, query)
+		}
+	})
+}
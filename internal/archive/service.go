@@ -13,6 +13,7 @@ import (
 type Service struct {
 	instances  storage.Instances
 	operations storage.Operations
+	steps      storage.OperationSteps
 	archived   storage.InstancesArchived
 
 	dryRun          bool
@@ -24,6 +25,7 @@ func NewService(db storage.BrokerStorage, dryRun bool, performDeletion bool, bat
 	return &Service{
 		instances:       db.Instances(),
 		operations:      db.Operations(),
+		steps:           db.OperationSteps(),
 		archived:        db.InstancesArchived(),
 		dryRun:          dryRun,
 		performDeletion: performDeletion,
@@ -94,11 +96,18 @@ func (s *Service) Run() (int, int, error) {
 				continue
 			}
 
-			// first - delete all runtime states
+			// first - delete the steps of the operation
 			// second - delete the operation
 			// If the deletion of operation fails, it can be retried, because such instance ID will be fetched by
 			// the next run of ListDeletedInstanceIDs() method.
 
+			logger.Debug("Deleting operation steps")
+			err = s.steps.DeleteByOperationID(operation.ID)
+			if err != nil {
+				logger.Error(fmt.Sprintf("Unable to delete operation steps: %s", err.Error()))
+				continue
+			}
+
 			logger.Debug("Deleting operation")
 			err = s.operations.DeleteByID(operation.ID)
 			if err != nil {
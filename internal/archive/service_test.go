@@ -6,6 +6,7 @@ import (
 	"os"
 	"testing"
 
+	"github.com/kyma-project/kyma-environment-broker/common/runtime"
 	"github.com/kyma-project/kyma-environment-broker/internal/fixture"
 	"github.com/kyma-project/kyma-environment-broker/internal/storage"
 	"github.com/pivotal-cf/brokerapi/v12/domain"
@@ -49,6 +50,12 @@ func TestService_Run(t *testing.T) {
 	require.NoError(t, err)
 	assert.Empty(t, operations)
 
+	// check if the steps of the deleted operations are deleted
+	steps, err := db.OperationSteps().ListByOperationIDs([]string{"inst-deleted-01-provisioninig", "inst-failed-deprovisioning-01-provisioninig"})
+	require.NoError(t, err)
+	require.Len(t, steps, 1)
+	assert.Equal(t, "inst-failed-deprovisioning-01-provisioninig", steps[0].OperationID)
+
 	// check if operations for existing instance still exists
 	operations, err = db.Operations().ListOperationsByInstanceID("inst-failed-deprovisioning-01")
 	require.NoError(t, err)
@@ -73,6 +80,8 @@ func prepareDataForDeletedInstance(t *testing.T, db storage.BrokerStorage, insta
 	provisioningOperation := fixture.FixProvisioningOperation(fmt.Sprintf("%s-%s", instanceId, "provisioninig"), instanceId)
 	err := db.Operations().InsertOperation(provisioningOperation)
 	require.NoError(t, err)
+	err = db.OperationSteps().Insert(runtime.OperationStep{OperationID: provisioningOperation.ID, Step: "Start"})
+	require.NoError(t, err)
 	err = db.Operations().InsertOperation(fixture.FixDeprovisioningOperationAsOperation(fmt.Sprintf("%s-%s", instanceId, "deprovisioning"), instanceId))
 	require.NoError(t, err)
 }
@@ -81,6 +90,8 @@ func prepareDataForInstanceWithFailedDeprovisioning(t *testing.T, db storage.Bro
 	provisioningOperation := fixture.FixProvisioningOperation(fmt.Sprintf("%s-%s", instanceId, "provisioninig"), instanceId)
 	err := db.Operations().InsertOperation(provisioningOperation)
 	require.NoError(t, err)
+	err = db.OperationSteps().Insert(runtime.OperationStep{OperationID: provisioningOperation.ID, Step: "Start"})
+	require.NoError(t, err)
 	op := fixture.FixDeprovisioningOperationAsOperation(fmt.Sprintf("%s-%s", instanceId, "deprovisioning"), instanceId)
 	op.State = domain.Failed
 	err = db.Operations().InsertOperation(op)
@@ -0,0 +1,61 @@
+package bindingrotation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/kyma-project/kyma-environment-broker/internal/httputil"
+	"github.com/pivotal-cf/brokerapi/v12/domain"
+	"github.com/pivotal-cf/brokerapi/v12/domain/apiresponses"
+)
+
+type router interface {
+	HandleFunc(pattern string, handler func(http.ResponseWriter, *http.Request))
+}
+
+type Rotator interface {
+	Rotate(ctx context.Context, instanceID, bindingID string) (domain.GetBindingSpec, error)
+}
+
+type Handler struct {
+	rotator Rotator
+	log     *slog.Logger
+}
+
+func NewHandler(rotator Rotator, log *slog.Logger) *Handler {
+	return &Handler{
+		rotator: rotator,
+		log:     log.With("service", "BindingRotationEndpoint"),
+	}
+}
+
+// AttachRoutes registers the rotation next to the binding endpoints for every prefix of the broker API
+func (h *Handler) AttachRoutes(r router, prefixes []string) {
+	for _, prefix := range prefixes {
+		r.HandleFunc(fmt.Sprintf("POST %s/v2/service_instances/{instance_id}/service_bindings/{binding_id}/rotate", prefix), h.rotateBinding)
+	}
+}
+
+func (h *Handler) rotateBinding(w http.ResponseWriter, req *http.Request) {
+	instanceID := req.PathValue("instance_id")
+	bindingID := req.PathValue("binding_id")
+
+	h.log.Info(fmt.Sprintf("Rotation requested for binding %s of instance %s", bindingID, instanceID))
+
+	spec, err := h.rotator.Rotate(req.Context(), instanceID, bindingID)
+	if err != nil {
+		h.log.Warn(fmt.Sprintf("unable to rotate binding %s of instance %s: %s", bindingID, instanceID, err))
+		status := http.StatusInternalServerError
+		var failure *apiresponses.FailureResponse
+		if errors.As(err, &failure) {
+			status = failure.ValidatedStatusCode(nil)
+		}
+		httputil.WriteErrorResponse(w, status, err)
+		return
+	}
+
+	httputil.WriteResponse(w, http.StatusOK, spec)
+}
@@ -0,0 +1,88 @@
+package bindingrotation_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/kyma-project/kyma-environment-broker/internal/bindingrotation"
+	"github.com/kyma-project/kyma-environment-broker/internal/httputil"
+	"github.com/pivotal-cf/brokerapi/v12/domain"
+	"github.com/pivotal-cf/brokerapi/v12/domain/apiresponses"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeRotator struct {
+	err error
+}
+
+func (r *fakeRotator) Rotate(_ context.Context, instanceID, bindingID string) (domain.GetBindingSpec, error) {
+	if r.err != nil {
+		return domain.GetBindingSpec{}, r.err
+	}
+	return domain.GetBindingSpec{
+		Credentials: map[string]string{"kubeconfig": "kubeconfig-" + instanceID + "-" + bindingID},
+		Metadata:    domain.BindingMetadata{ExpiresAt: "2026-10-17T18:00:00.0Z"},
+	}, nil
+}
+
+func TestRotateBinding(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelInfo}))
+
+	t.Run("should return the rotated credentials", func(t *testing.T) {
+		// given
+		router := httputil.NewRouter()
+		bindingrotation.NewHandler(&fakeRotator{}, logger).AttachRoutes(router, []string{"/{region}", ""})
+		req := httptest.NewRequest(http.MethodPost, "/cf-eu10/v2/service_instances/instance-id/service_bindings/binding-id/rotate", nil)
+		w := httptest.NewRecorder()
+
+		// when
+		router.ServeHTTP(w, req)
+
+		// then
+		require.Equal(t, http.StatusOK, w.Code)
+		var response struct {
+			Credentials map[string]string      `json:"credentials"`
+			Metadata    domain.BindingMetadata `json:"metadata"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "kubeconfig-instance-id-binding-id", response.Credentials["kubeconfig"])
+		assert.Equal(t, "2026-10-17T18:00:00.0Z", response.Metadata.ExpiresAt)
+	})
+
+	t.Run("should return the status code of the failure response", func(t *testing.T) {
+		// given
+		router := httputil.NewRouter()
+		failure := apiresponses.NewFailureResponse(errors.New("binding expired"), http.StatusNotFound, "binding expired")
+		bindingrotation.NewHandler(&fakeRotator{err: failure}, logger).AttachRoutes(router, []string{""})
+		req := httptest.NewRequest(http.MethodPost, "/v2/service_instances/instance-id/service_bindings/binding-id/rotate", nil)
+		w := httptest.NewRecorder()
+
+		// when
+		router.ServeHTTP(w, req)
+
+		// then
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Contains(t, w.Body.String(), "binding expired")
+	})
+
+	t.Run("should return 500 for other errors", func(t *testing.T) {
+		// given
+		router := httputil.NewRouter()
+		bindingrotation.NewHandler(&fakeRotator{err: errors.New("unexpected")}, logger).AttachRoutes(router, []string{""})
+		req := httptest.NewRequest(http.MethodPost, "/v2/service_instances/instance-id/service_bindings/binding-id/rotate", nil)
+		w := httptest.NewRecorder()
+
+		// when
+		router.ServeHTTP(w, req)
+
+		// then
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+}
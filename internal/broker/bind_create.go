@@ -7,9 +7,12 @@ import (
 	"fmt"
 	"log/slog"
 	"net/http"
+	"regexp"
+	"slices"
 	"strings"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/kyma-project/kyma-environment-broker/internal/event"
 
 	"github.com/kyma-project/kyma-environment-broker/internal"
@@ -17,6 +20,7 @@ import (
 	"github.com/kyma-project/kyma-environment-broker/internal/storage"
 	"github.com/kyma-project/kyma-environment-broker/internal/storage/dberr"
 	"github.com/pivotal-cf/brokerapi/v12/domain/apiresponses"
+	"k8s.io/apimachinery/pkg/util/validation"
 
 	"github.com/pivotal-cf/brokerapi/v12/domain"
 )
@@ -33,6 +37,63 @@ type BindingConfig struct {
 	MinExpirationSeconds int           `envconfig:"default=600"`
 	MaxBindingsCount     int           `envconfig:"default=10"`
 	CreateBindingTimeout time.Duration `envconfig:"default=15s"`
+	// OIDCBindablePlans are the plans which allow the bindings with the oidc credentials type
+	OIDCBindablePlans StringList `envconfig:"optional"`
+	// OIDCAllowedGroups are the OIDC groups which can be bound, the bindings with the oidc credentials type are rejected if it is empty
+	OIDCAllowedGroups StringList `envconfig:"optional"`
+
+	// AsyncEnabled makes KEB create the bindings requested with accepts_incomplete=true in the bindings queue
+	AsyncEnabled         bool          `envconfig:"default=false"`
+	AsyncWorkersAmount   int           `envconfig:"default=5"`
+	AsyncRetryInterval   time.Duration `envconfig:"default=10s"`
+	AsyncCreationTimeout time.Duration `envconfig:"default=10m"`
+
+	// AutoRenewEnabled allows the bindings with auto_renew, their credentials are rotated AutoRenewBefore they expire
+	AutoRenewEnabled  bool          `envconfig:"default=false"`
+	AutoRenewInterval time.Duration `envconfig:"default=1m"`
+	AutoRenewBefore   time.Duration `envconfig:"default=5m"`
+
+	// AllowedRoles are the role templates which can be requested for the bindings of each plan
+	AllowedRoles RolesByPlan `envconfig:"optional"`
+}
+
+// RolesByPlan is the allow-list of the binding role templates per plan, for example: aws=viewer,editor;gcp=viewer
+type RolesByPlan map[string]StringList
+
+// Unmarshal provides custom parsing of the role templates allowed for the plans.
+// Implements envconfig.Unmarshal interface.
+func (m *RolesByPlan) Unmarshal(in string) error {
+	roles := RolesByPlan{}
+	for _, entry := range strings.Split(in, ";") {
+		if strings.TrimSpace(entry) == "" {
+			continue
+		}
+		planName, planRoles, found := strings.Cut(entry, "=")
+		if !found {
+			return fmt.Errorf("missing role templates of plan %q", entry)
+		}
+		planName = strings.ToLower(strings.TrimSpace(planName))
+		for _, role := range strings.Split(planRoles, ",") {
+			role = strings.TrimSpace(role)
+			if _, known := broker.RoleTemplates[role]; !known {
+				return fmt.Errorf("unknown role template %q for plan %s", role, planName)
+			}
+			roles[planName] = append(roles[planName], role)
+		}
+	}
+	*m = roles
+	return nil
+}
+
+func (m RolesByPlan) Allows(planName, role string) bool {
+	roles := m[strings.ToLower(planName)]
+	return roles.Contains(role)
+}
+
+// Restricts returns true if the bindings of the plan are limited to the allowed role templates, so they cannot get the full access
+func (m RolesByPlan) Restricts(planName string) bool {
+	_, found := m[strings.ToLower(planName)]
+	return found
 }
 
 type BindEndpoint struct {
@@ -41,8 +102,9 @@ type BindEndpoint struct {
 	bindingsStorage   storage.Bindings
 	operationsStorage storage.Operations
 
-	serviceAccountBindingManager broker.BindingsManager
-	publisher                    event.Publisher
+	bindingsManager broker.BindingsManager
+	publisher       event.Publisher
+	queue           Queue
 
 	log *slog.Logger
 }
@@ -62,25 +124,34 @@ func (b *BindingContext) CreatedBy() string {
 }
 
 type BindingParams struct {
-	ExpirationSeconds int `json:"expiration_seconds,omitempty"`
+	ExpirationSeconds int      `json:"expiration_seconds,omitempty"`
+	CredentialsType   string   `json:"credentials_type,omitempty"`
+	Group             string   `json:"group,omitempty"`
+	AutoRenew         bool     `json:"auto_renew,omitempty"`
+	Role              string   `json:"role,omitempty"`
+	Namespaces        []string `json:"namespaces,omitempty"`
 }
 
 type Credentials struct {
 	Kubeconfig string `json:"kubeconfig"`
 }
 
-func NewBind(cfg BindingConfig, db storage.BrokerStorage, log *slog.Logger, clientProvider broker.ClientProvider, kubeconfigProvider broker.KubeconfigProvider,
-	publisher event.Publisher) *BindEndpoint {
+func NewBind(cfg BindingConfig, db storage.BrokerStorage, log *slog.Logger, bindingsManager broker.BindingsManager, publisher event.Publisher) *BindEndpoint {
 	return &BindEndpoint{config: cfg,
-		instancesStorage:             db.Instances(),
-		bindingsStorage:              db.Bindings(),
-		publisher:                    publisher,
-		operationsStorage:            db.Operations(),
-		log:                          log.With("service", "BindEndpoint"),
-		serviceAccountBindingManager: broker.NewServiceAccountBindingsManager(clientProvider, kubeconfigProvider),
+		instancesStorage:  db.Instances(),
+		bindingsStorage:   db.Bindings(),
+		publisher:         publisher,
+		operationsStorage: db.Operations(),
+		log:               log.With("service", "BindEndpoint"),
+		bindingsManager:   bindingsManager,
 	}
 }
 
+// UseQueue makes the endpoint create the bindings asynchronously in the queue when the platform accepts incomplete bindings
+func (b *BindEndpoint) UseQueue(queue Queue) {
+	b.queue = queue
+}
+
 // Bind creates a new service binding
 //
 //	PUT /v2/service_instances/{instance_id}/service_bindings/{binding_id}
@@ -151,6 +222,19 @@ func (b *BindEndpoint) bind(ctx context.Context, instanceID, bindingID string, d
 		expirationSeconds = parameters.ExpirationSeconds
 	}
 
+	credentialsType, err := b.validateCredentialsType(parameters, instance.ServicePlanName)
+	if err != nil {
+		return domain.Binding{}, err
+	}
+	namespaces, err := b.validateRBAC(parameters, instance.ServicePlanName)
+	if err != nil {
+		return domain.Binding{}, err
+	}
+	if parameters.AutoRenew && !b.config.AutoRenewEnabled {
+		message := "auto_renew is not supported"
+		return domain.Binding{}, apiresponses.NewFailureResponse(errors.New(message), http.StatusBadRequest, message)
+	}
+
 	lastOperation, err := b.operationsStorage.GetLastOperation(instance.InstanceID)
 	if err != nil {
 		return domain.Binding{}, apiresponses.NewFailureResponse(fmt.Errorf("failed to get last operation for instance %s", instanceID), http.StatusInternalServerError, fmt.Sprintf("failed to get last operation for instance %s", instanceID))
@@ -165,7 +249,25 @@ func (b *BindEndpoint) bind(ctx context.Context, instanceID, bindingID string, d
 		return domain.Binding{}, apiresponses.NewFailureResponse(errors.New(message), http.StatusBadRequest, message) // Agreed with Provisioning API team to return 400
 	}
 
-	binding, err := b.searchDbForBinding(instanceID, bindingID, expirationSeconds)
+	newBinding := &internal.Binding{
+		ID:         bindingID,
+		InstanceID: instanceID,
+
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+
+		ExpirationSeconds: int64(expirationSeconds),
+		ExpiresAt:         time.Now().Add(time.Duration(expirationSeconds) * time.Second),
+		CreatedBy:         bindingContext.CreatedBy(),
+		State:             domain.InProgress,
+		CredentialsType:   credentialsType,
+		Group:             parameters.Group,
+		AutoRenew:         parameters.AutoRenew,
+		Role:              parameters.Role,
+		Namespaces:        namespaces,
+	}
+
+	binding, err := b.searchDbForBinding(ctx, newBinding, instance, asyncAllowed)
 	if err != nil {
 		return domain.Binding{}, err
 	}
@@ -185,22 +287,120 @@ func (b *BindEndpoint) bind(ctx context.Context, instanceID, bindingID string, d
 		return domain.Binding{}, err
 	}
 
-	return b.createNewBinding(ctx, instanceID, bindingID, expirationSeconds, bindingContext, instance)
+	if asyncAllowed && b.config.AsyncEnabled && b.queue != nil {
+		return b.createNewBindingAsync(newBinding)
+	}
+	return b.createNewBinding(ctx, newBinding, instance)
+}
+
+// oidcGroupRegexp is the format of the OIDC groups, including the optional prefix of the groups claim, for example, "oidc:kyma-admins"
+var oidcGroupRegexp = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9._:@/-]{0,252}$`)
+
+func (b *BindEndpoint) validateCredentialsType(parameters BindingParams, planName string) (string, error) {
+	switch parameters.CredentialsType {
+	case "", broker.ServiceAccountCredentialsType:
+		if parameters.Group != "" {
+			message := fmt.Sprintf("group can be set only for the %s credentials type", broker.OIDCCredentialsType)
+			return "", apiresponses.NewFailureResponse(errors.New(message), http.StatusBadRequest, message)
+		}
+		return broker.ServiceAccountCredentialsType, nil
+	case broker.OIDCCredentialsType:
+		if !b.config.OIDCBindablePlans.Contains(planName) {
+			message := fmt.Sprintf("%s credentials type is not supported for plan %s", broker.OIDCCredentialsType, planName)
+			return "", apiresponses.NewFailureResponseBuilder(errors.New(message), http.StatusUnprocessableEntity, message).
+				WithErrorKey("BindingNotSupported").Build()
+		}
+		if parameters.Group == "" {
+			message := fmt.Sprintf("group is required for the %s credentials type", broker.OIDCCredentialsType)
+			return "", apiresponses.NewFailureResponse(errors.New(message), http.StatusBadRequest, message)
+		}
+		if !oidcGroupRegexp.MatchString(parameters.Group) {
+			message := fmt.Sprintf("invalid group %q, the group must match %s", parameters.Group, oidcGroupRegexp.String())
+			return "", apiresponses.NewFailureResponse(errors.New(message), http.StatusBadRequest, message)
+		}
+		if !slices.ContainsFunc(b.config.OIDCAllowedGroups, func(group string) bool { return strings.TrimSpace(group) == parameters.Group }) {
+			message := fmt.Sprintf("group %s is not allowed for the %s credentials type", parameters.Group, broker.OIDCCredentialsType)
+			return "", apiresponses.NewFailureResponseBuilder(errors.New(message), http.StatusUnprocessableEntity, message).
+				WithErrorKey("BindingNotSupported").Build()
+		}
+		return broker.OIDCCredentialsType, nil
+	default:
+		message := fmt.Sprintf("credentials_type must be one of: %s, %s", broker.ServiceAccountCredentialsType, broker.OIDCCredentialsType)
+		return "", apiresponses.NewFailureResponse(errors.New(message), http.StatusBadRequest, message)
+	}
+}
+
+// validateRBAC checks the role template against the allow-list of the plan and returns the sorted namespaces of the binding
+func (b *BindEndpoint) validateRBAC(parameters BindingParams, planName string) ([]string, error) {
+	if parameters.Role != "" {
+		if _, found := broker.RoleTemplates[parameters.Role]; !found {
+			message := fmt.Sprintf("role must be one of: %s, %s, %s", broker.ViewerRole, broker.EditorRole, broker.NamespaceAdminRole)
+			return nil, apiresponses.NewFailureResponse(errors.New(message), http.StatusBadRequest, message)
+		}
+		if !b.config.AllowedRoles.Allows(planName, parameters.Role) {
+			message := fmt.Sprintf("role %s is not allowed for plan %s", parameters.Role, planName)
+			return nil, apiresponses.NewFailureResponseBuilder(errors.New(message), http.StatusUnprocessableEntity, message).
+				WithErrorKey("BindingNotSupported").Build()
+		}
+	} else if b.config.AllowedRoles.Restricts(planName) {
+		message := fmt.Sprintf("role is required for plan %s, allowed roles: %s", planName, strings.Join(b.config.AllowedRoles[strings.ToLower(planName)], ", "))
+		return nil, apiresponses.NewFailureResponseBuilder(errors.New(message), http.StatusUnprocessableEntity, message).
+			WithErrorKey("BindingNotSupported").Build()
+	}
+	if parameters.Role == broker.NamespaceAdminRole && len(parameters.Namespaces) == 0 {
+		message := fmt.Sprintf("namespaces are required for the %s role", broker.NamespaceAdminRole)
+		return nil, apiresponses.NewFailureResponse(errors.New(message), http.StatusBadRequest, message)
+	}
+
+	for _, namespace := range parameters.Namespaces {
+		if errs := validation.IsDNS1123Label(namespace); len(errs) != 0 {
+			message := fmt.Sprintf("invalid namespace %q: %s", namespace, strings.Join(errs, ", "))
+			return nil, apiresponses.NewFailureResponse(errors.New(message), http.StatusBadRequest, message)
+		}
+	}
+	// the order of the namespaces does not matter when the binding is requested again
+	namespaces := slices.Clone(parameters.Namespaces)
+	slices.Sort(namespaces)
+	return slices.Compact(namespaces), nil
 }
 
-func (b *BindEndpoint) searchDbForBinding(instanceID string, bindingID string, expirationSeconds int) (*domain.Binding, error) {
+func (b *BindEndpoint) searchDbForBinding(ctx context.Context, newBinding *internal.Binding, instance *internal.Instance, asyncAllowed bool) (*domain.Binding, error) {
+	instanceID, bindingID := newBinding.InstanceID, newBinding.ID
 	bindingFromDB, err := b.bindingsStorage.Get(instanceID, bindingID)
 	if err != nil && !dberr.IsNotFound(err) {
 		message := fmt.Sprintf("failed to get Kyma binding from storage: %s", err)
 		return nil, apiresponses.NewFailureResponse(errors.New(message), http.StatusInternalServerError, message)
 	}
+	if bindingFromDB != nil && bindingFromDB.State == domain.Failed {
+		// the failed binding has no credentials, it is created again
+		b.log.Info(fmt.Sprintf("removing failed binding %s for instance %s: %s", bindingID, instanceID, bindingFromDB.Description))
+		// the creation could fail after some of the resources were created, they are removed before the binding is created again
+		if err := b.bindingsManager.Delete(ctx, instance, bindingFromDB); err != nil {
+			message := fmt.Sprintf("failed to remove the resources of failed Kyma binding: %s", err)
+			return nil, apiresponses.NewFailureResponse(errors.New(message), http.StatusInternalServerError, message)
+		}
+		if err := b.bindingsStorage.Delete(instanceID, bindingID); err != nil {
+			message := fmt.Sprintf("failed to remove failed Kyma binding from storage: %s", err)
+			return nil, apiresponses.NewFailureResponse(errors.New(message), http.StatusInternalServerError, message)
+		}
+		return nil, nil
+	}
 	if bindingFromDB != nil {
-		if bindingFromDB.ExpirationSeconds != int64(expirationSeconds) {
+		if bindingFromDB.ExpirationSeconds != newBinding.ExpirationSeconds ||
+			broker.CredentialsType(bindingFromDB) != newBinding.CredentialsType || bindingFromDB.Group != newBinding.Group ||
+			bindingFromDB.AutoRenew != newBinding.AutoRenew || bindingFromDB.Role != newBinding.Role ||
+			!slices.Equal(bindingFromDB.Namespaces, newBinding.Namespaces) {
 			message := "binding already exists but with different parameters"
 			return nil, apiresponses.NewFailureResponse(errors.New(message), http.StatusConflict, message)
 		}
 		if bindingFromDB.ExpiresAt.After(time.Now()) {
 			if len(bindingFromDB.Kubeconfig) == 0 {
+				if asyncAllowed && bindingFromDB.OperationID != "" {
+					return &domain.Binding{
+						IsAsync:       true,
+						OperationData: bindingFromDB.OperationID,
+					}, nil
+				}
 				message := "binding creation already in progress"
 				return nil, apiresponses.NewFailureResponse(errors.New(message), http.StatusUnprocessableEntity, message)
 			}
@@ -244,20 +444,8 @@ func (b *BindEndpoint) checkAgainstLimit(bindingList []internal.Binding, instanc
 	return nil
 }
 
-func (b *BindEndpoint) createNewBinding(ctx context.Context, instanceID string, bindingID string, expirationSeconds int, bindingContext BindingContext, instance *internal.Instance) (domain.Binding, error) {
-	var kubeconfig string
-	binding := &internal.Binding{
-		ID:         bindingID,
-		InstanceID: instanceID,
-
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
-
-		ExpirationSeconds: int64(expirationSeconds),
-		ExpiresAt:         time.Now().Add(time.Duration(expirationSeconds) * time.Second),
-		CreatedBy:         bindingContext.CreatedBy(),
-	}
-
+func (b *BindEndpoint) createNewBinding(ctx context.Context, binding *internal.Binding, instance *internal.Instance) (domain.Binding, error) {
+	instanceID, bindingID := binding.InstanceID, binding.ID
 	err := b.bindingsStorage.Insert(binding)
 	switch {
 	case dberr.IsAlreadyExists(err):
@@ -269,16 +457,21 @@ func (b *BindEndpoint) createNewBinding(ctx context.Context, instanceID string,
 	}
 
 	// create kubeconfig for the instance
-	var expiresAt time.Time
-	kubeconfig, expiresAt, err = b.serviceAccountBindingManager.Create(ctx, instance, bindingID, expirationSeconds)
+	kubeconfig, expiresAt, err := b.bindingsManager.Create(ctx, instance, binding)
 	if err != nil {
-		message := fmt.Sprintf("failed to create a Kyma binding using service account's kubeconfig: %s", err)
+		message := fmt.Sprintf("failed to create a Kyma binding using %s kubeconfig: %s", binding.CredentialsType, err)
 		b.log.Error(fmt.Sprintf("for instance %s %s", instanceID, message))
+		binding.State = domain.Failed
+		binding.Description = message
+		if err := b.bindingsStorage.Update(binding); err != nil {
+			b.log.Error(fmt.Sprintf("failed to store the state of the binding %s for instance %s: %s", bindingID, instanceID, err))
+		}
 		return domain.Binding{}, apiresponses.NewFailureResponse(errors.New(message), http.StatusBadRequest, message)
 	}
 
 	binding.ExpiresAt = expiresAt
 	binding.Kubeconfig = kubeconfig
+	binding.State = domain.Succeeded
 
 	err = b.bindingsStorage.Update(binding)
 	if err != nil {
@@ -299,7 +492,34 @@ func (b *BindEndpoint) createNewBinding(ctx context.Context, instanceID string,
 	}, nil
 }
 
+// createNewBindingAsync stores the binding in progress, the BindingProcessor creates its credentials
+func (b *BindEndpoint) createNewBindingAsync(binding *internal.Binding) (domain.Binding, error) {
+	instanceID, bindingID := binding.InstanceID, binding.ID
+	binding.OperationID = uuid.New().String()
+
+	err := b.bindingsStorage.Insert(binding)
+	switch {
+	case dberr.IsAlreadyExists(err):
+		message := fmt.Sprintf("failed to insert Kyma binding into storage: %s", err)
+		return domain.Binding{}, apiresponses.NewFailureResponse(errors.New(message), http.StatusBadRequest, message)
+	case err != nil:
+		message := fmt.Sprintf("failed to insert Kyma binding into storage: %s", err)
+		return domain.Binding{}, apiresponses.NewFailureResponse(errors.New(message), http.StatusInternalServerError, message)
+	}
+
+	b.queue.Add(binding.OperationID)
+	b.log.Info(fmt.Sprintf("Accepted binding %s for instance %s, operation %s", bindingID, instanceID, binding.OperationID))
+
+	return domain.Binding{
+		IsAsync:       true,
+		OperationData: binding.OperationID,
+	}, nil
+}
+
 func (b *BindEndpoint) IsPlanBindable(planName string) bool {
+	if IsBindablePlan(planName) {
+		return true
+	}
 	planNameLowerCase := strings.ToLower(planName)
 	for _, p := range b.config.BindablePlans {
 		if strings.ToLower(p) == planNameLowerCase {
@@ -322,3 +542,8 @@ type UnbindRequestProcessed struct {
 type BindingCreated struct {
 	PlanID string
 }
+
+type BindingRotated struct {
+	PlanID    string
+	AutoRenew bool
+}
@@ -11,6 +11,8 @@ import (
 	"time"
 
 	"github.com/kyma-project/kyma-environment-broker/internal"
+	"github.com/kyma-project/kyma-environment-broker/internal/broker/automock"
+	brokerBindings "github.com/kyma-project/kyma-environment-broker/internal/broker/bindings"
 	"github.com/kyma-project/kyma-environment-broker/internal/kubeconfig"
 	"github.com/pivotal-cf/brokerapi/v12/domain/apiresponses"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
@@ -19,6 +21,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"k8s.io/client-go/kubernetes"
 
 	"github.com/kyma-project/kyma-environment-broker/internal/fixture"
@@ -93,7 +96,7 @@ func TestCreateBindingEndpoint_dbInsertionInCaseOfError(t *testing.T) {
 	publisher := event.NewPubSub(log)
 
 	//// api handler
-	bindEndpoint := NewBind(*bindingCfg, db, fixLogger(), &dummyProvider{}, &dummyProvider{}, publisher)
+	bindEndpoint := NewBind(*bindingCfg, db, fixLogger(), brokerBindings.NewServiceAccountBindingsManager(&dummyProvider{}, &dummyProvider{}), publisher)
 
 	// test relies on checking if got nil on kubeconfig dummyProvider but the instance got inserted either way
 	t.Run("should INSERT binding despite error on k8s api call", func(t *testing.T) {
@@ -343,7 +346,7 @@ func TestCreateSecondBindingWithTheSameIdButDifferentParams(t *testing.T) {
 
 	publisher := event.NewPubSub(log)
 
-	svc := NewBind(*bindingCfg, brokerStorage, fixLogger(), nil, nil, publisher)
+	svc := NewBind(*bindingCfg, brokerStorage, fixLogger(), brokerBindings.NewServiceAccountBindingsManager(nil, nil), publisher)
 	params := BindingParams{
 		ExpirationSeconds: 601,
 	}
@@ -394,7 +397,7 @@ func TestCreateSecondBindingWithTheSameIdAndParams(t *testing.T) {
 
 	publisher := event.NewPubSub(log)
 
-	svc := NewBind(*bindingCfg, brokerStorage, fixLogger(), nil, nil, publisher)
+	svc := NewBind(*bindingCfg, brokerStorage, fixLogger(), brokerBindings.NewServiceAccountBindingsManager(nil, nil), publisher)
 	params := BindingParams{
 		ExpirationSeconds: 600,
 	}
@@ -445,7 +448,7 @@ func TestCreateSecondBindingWithTheSameIdAndParamsForExpired(t *testing.T) {
 	// event publisher
 	publisher := event.NewPubSub(log)
 
-	svc := NewBind(*bindingCfg, brokerStorage, fixLogger(), nil, nil, publisher)
+	svc := NewBind(*bindingCfg, brokerStorage, fixLogger(), brokerBindings.NewServiceAccountBindingsManager(nil, nil), publisher)
 	params := BindingParams{
 		ExpirationSeconds: 600,
 	}
@@ -498,7 +501,7 @@ func TestCreateSecondBindingWithTheSameIdAndParamsForBindingInProgress(t *testin
 	// event publisher
 	publisher := event.NewPubSub(log)
 
-	svc := NewBind(*bindingCfg, brokerStorage, fixLogger(), nil, nil, publisher)
+	svc := NewBind(*bindingCfg, brokerStorage, fixLogger(), brokerBindings.NewServiceAccountBindingsManager(nil, nil), publisher)
 	params := BindingParams{
 		ExpirationSeconds: 600,
 	}
@@ -549,7 +552,7 @@ func TestCreateSecondBindingWithTheSameIdAndParamsNotExplicitlyDefined(t *testin
 
 	publisher := event.NewPubSub(log)
 
-	svc := NewBind(*bindingCfg, brokerStorage, fixLogger(), nil, nil, publisher)
+	svc := NewBind(*bindingCfg, brokerStorage, fixLogger(), brokerBindings.NewServiceAccountBindingsManager(nil, nil), publisher)
 
 	// when
 	resp, err := svc.Bind(context.Background(), instanceID, bindingID, domain.BindDetails{}, false)
@@ -575,5 +578,336 @@ func prepareBindingEndpoint(t *testing.T, cfg BindingConfig) (*BindEndpoint, sto
 	err = db.Operations().InsertOperation(operation)
 	require.NoError(t, err)
 
-	return NewBind(cfg, db, log, k8sClientProvider, k8sClientProvider, event.NewPubSub(log)), db
+	return NewBind(cfg, db, log, brokerBindings.NewServiceAccountBindingsManager(k8sClientProvider, k8sClientProvider), event.NewPubSub(log)), db
+}
+
+func TestCreateBindingAsync(t *testing.T) {
+	// given
+	cfg := fixBindingConfig()
+	cfg.AsyncEnabled = true
+	svc, db := prepareBindingEndpoint(t, cfg)
+	queue := automock.NewQueue(t)
+	svc.UseQueue(queue)
+	params, err := json.Marshal(BindingParams{ExpirationSeconds: 600})
+	require.NoError(t, err)
+	details := domain.BindDetails{RawParameters: params}
+
+	t.Run("should accept the binding and add it to the queue", func(t *testing.T) {
+		// given
+		queue.On("Add", mock.AnythingOfType("string")).Return().Once()
+
+		// when
+		resp, err := svc.Bind(context.Background(), instanceID1, "async-binding", details, true)
+
+		// then
+		require.NoError(t, err)
+		assert.True(t, resp.IsAsync)
+		assert.NotEmpty(t, resp.OperationData)
+		queue.AssertCalled(t, "Add", resp.OperationData)
+
+		binding, err := db.Bindings().Get(instanceID1, "async-binding")
+		require.NoError(t, err)
+		assert.Equal(t, domain.InProgress, binding.State)
+		assert.Equal(t, resp.OperationData, binding.OperationID)
+		assert.Empty(t, binding.Kubeconfig)
+	})
+
+	t.Run("should return the same operation for the binding in progress", func(t *testing.T) {
+		// given
+		binding, err := db.Bindings().Get(instanceID1, "async-binding")
+		require.NoError(t, err)
+
+		// when
+		resp, err := svc.Bind(context.Background(), instanceID1, "async-binding", details, true)
+
+		// then
+		require.NoError(t, err)
+		assert.True(t, resp.IsAsync)
+		assert.Equal(t, binding.OperationID, resp.OperationData)
+	})
+
+	t.Run("should reject the binding in progress when the platform does not accept incomplete bindings", func(t *testing.T) {
+		// when
+		_, err := svc.Bind(context.Background(), instanceID1, "async-binding", details, false)
+
+		// then
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "binding creation already in progress")
+	})
+
+	t.Run("should accept the failed binding again", func(t *testing.T) {
+		// given
+		failed := fixture.FixBinding("failed-binding", fixture.WithInstanceID(instanceID1))
+		failed.Kubeconfig = ""
+		failed.State = domain.Failed
+		failed.OperationID = "failed-operation"
+		require.NoError(t, db.Bindings().Insert(&failed))
+		queue.On("Add", mock.AnythingOfType("string")).Return().Once()
+		manager := &fakeBindingsManager{}
+		svc.bindingsManager = manager
+
+		// when
+		resp, err := svc.Bind(context.Background(), instanceID1, "failed-binding", details, true)
+
+		// then
+		require.NoError(t, err)
+		assert.True(t, resp.IsAsync)
+		assert.NotEqual(t, "failed-operation", resp.OperationData)
+
+		binding, err := db.Bindings().Get(instanceID1, "failed-binding")
+		require.NoError(t, err)
+		assert.Equal(t, domain.InProgress, binding.State)
+		assert.Equal(t, []string{"failed-binding"}, manager.deleted)
+	})
+}
+
+func TestCreateBindingCredentialsType(t *testing.T) {
+	// given
+	cfg := fixBindingConfig()
+	cfg.OIDCBindablePlans = StringList{fixture.PlanName}
+	cfg.OIDCAllowedGroups = StringList{"kyma-admins", "kyma-developers"}
+	svc, db := prepareBindingEndpoint(t, cfg)
+
+	for tn, tc := range map[string]struct {
+		params          BindingParams
+		expectedMessage string
+	}{
+		"unknown credentials type": {
+			params:          BindingParams{CredentialsType: "certificate"},
+			expectedMessage: "credentials_type must be one of: service_account, oidc",
+		},
+		"oidc without group": {
+			params:          BindingParams{CredentialsType: brokerBindings.OIDCCredentialsType},
+			expectedMessage: "group is required for the oidc credentials type",
+		},
+		"oidc with an invalid group": {
+			params:          BindingParams{CredentialsType: brokerBindings.OIDCCredentialsType, Group: "kyma admins"},
+			expectedMessage: `invalid group "kyma admins"`,
+		},
+		"oidc with a group not allowed": {
+			params:          BindingParams{CredentialsType: brokerBindings.OIDCCredentialsType, Group: "system:masters"},
+			expectedMessage: "group system:masters is not allowed for the oidc credentials type",
+		},
+		"group for service account": {
+			params:          BindingParams{Group: "kyma-admins"},
+			expectedMessage: "group can be set only for the oidc credentials type",
+		},
+	} {
+		t.Run(tn, func(t *testing.T) {
+			rawParams, err := json.Marshal(tc.params)
+			require.NoError(t, err)
+
+			// when
+			_, err = svc.Bind(context.Background(), instanceID1, "binding-id", domain.BindDetails{RawParameters: rawParams}, false)
+
+			// then
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.expectedMessage)
+		})
+	}
+
+	t.Run("should reject oidc for the plan without oidc bindings", func(t *testing.T) {
+		// given
+		cfg := fixBindingConfig()
+		svc, _ := prepareBindingEndpoint(t, cfg)
+		rawParams, err := json.Marshal(BindingParams{CredentialsType: brokerBindings.OIDCCredentialsType, Group: "kyma-admins"})
+		require.NoError(t, err)
+
+		// when
+		_, err = svc.Bind(context.Background(), instanceID1, "binding-id", domain.BindDetails{RawParameters: rawParams}, false)
+
+		// then
+		require.Error(t, err)
+		apiErr, ok := err.(*apiresponses.FailureResponse)
+		require.True(t, ok)
+		assert.Equal(t, http.StatusUnprocessableEntity, apiErr.ValidatedStatusCode(nil))
+	})
+
+	t.Run("should reject the existing binding with a different group", func(t *testing.T) {
+		// given
+		binding := fixture.FixBinding("oidc-binding-id", fixture.WithInstanceID(instanceID1))
+		binding.CredentialsType = brokerBindings.OIDCCredentialsType
+		binding.Group = "kyma-admins"
+		require.NoError(t, db.Bindings().Insert(&binding))
+		rawParams, err := json.Marshal(BindingParams{ExpirationSeconds: 600, CredentialsType: brokerBindings.OIDCCredentialsType, Group: "kyma-developers"})
+		require.NoError(t, err)
+
+		// when
+		_, err = svc.Bind(context.Background(), instanceID1, "oidc-binding-id", domain.BindDetails{RawParameters: rawParams}, false)
+
+		// then
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "binding already exists but with different parameters")
+	})
+
+	t.Run("should return the existing oidc binding", func(t *testing.T) {
+		// given
+		rawParams, err := json.Marshal(BindingParams{ExpirationSeconds: 600, CredentialsType: brokerBindings.OIDCCredentialsType, Group: "kyma-admins"})
+		require.NoError(t, err)
+
+		// when
+		resp, err := svc.Bind(context.Background(), instanceID1, "oidc-binding-id", domain.BindDetails{RawParameters: rawParams}, false)
+
+		// then
+		require.NoError(t, err)
+		assert.True(t, resp.AlreadyExists)
+	})
+}
+
+func TestCreateBindingAutoRenew(t *testing.T) {
+	rawParams, err := json.Marshal(BindingParams{AutoRenew: true})
+	require.NoError(t, err)
+
+	t.Run("should reject auto_renew when the renewal is disabled", func(t *testing.T) {
+		// given
+		svc, _ := prepareBindingEndpoint(t, fixBindingConfig())
+
+		// when
+		_, err := svc.Bind(context.Background(), instanceID1, "binding-id", domain.BindDetails{RawParameters: rawParams}, false)
+
+		// then
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "auto_renew is not supported")
+	})
+
+	t.Run("should store auto_renew of the binding", func(t *testing.T) {
+		// given
+		cfg := fixBindingConfig()
+		cfg.AutoRenewEnabled = true
+		cfg.AsyncEnabled = true
+		svc, db := prepareBindingEndpoint(t, cfg)
+		queue := automock.NewQueue(t)
+		queue.On("Add", mock.AnythingOfType("string")).Return().Once()
+		svc.UseQueue(queue)
+
+		// when
+		_, err := svc.Bind(context.Background(), instanceID1, "binding-id", domain.BindDetails{RawParameters: rawParams}, true)
+
+		// then
+		require.NoError(t, err)
+		binding, err := db.Bindings().Get(instanceID1, "binding-id")
+		require.NoError(t, err)
+		assert.True(t, binding.AutoRenew)
+	})
+}
+
+func TestCreateBindingRBAC(t *testing.T) {
+	// given
+	cfg := fixBindingConfig()
+	cfg.AsyncEnabled = true
+	cfg.AllowedRoles = RolesByPlan{fixture.PlanName: StringList{brokerBindings.ViewerRole, brokerBindings.NamespaceAdminRole}}
+	svc, db := prepareBindingEndpoint(t, cfg)
+
+	for tn, tc := range map[string]struct {
+		params          BindingParams
+		expectedMessage string
+	}{
+		"unknown role": {
+			params:          BindingParams{Role: "owner"},
+			expectedMessage: "role must be one of: viewer, editor, namespace-admin",
+		},
+		"role omitted for the restricted plan": {
+			params:          BindingParams{Namespaces: []string{"team-a"}},
+			expectedMessage: "role is required for plan",
+		},
+		"role not allowed for the plan": {
+			params:          BindingParams{Role: brokerBindings.EditorRole},
+			expectedMessage: "role editor is not allowed for plan",
+		},
+		"namespace-admin without namespaces": {
+			params:          BindingParams{Role: brokerBindings.NamespaceAdminRole},
+			expectedMessage: "namespaces are required for the namespace-admin role",
+		},
+		"invalid namespace": {
+			params:          BindingParams{Role: brokerBindings.ViewerRole, Namespaces: []string{"Default"}},
+			expectedMessage: `invalid namespace "Default"`,
+		},
+	} {
+		t.Run(tn, func(t *testing.T) {
+			rawParams, err := json.Marshal(tc.params)
+			require.NoError(t, err)
+
+			// when
+			_, err = svc.Bind(context.Background(), instanceID1, "binding-id", domain.BindDetails{RawParameters: rawParams}, false)
+
+			// then
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.expectedMessage)
+		})
+	}
+
+	t.Run("should store the role and the sorted namespaces of the binding", func(t *testing.T) {
+		// given
+		queue := automock.NewQueue(t)
+		queue.On("Add", mock.AnythingOfType("string")).Return().Once()
+		svc.UseQueue(queue)
+		rawParams, err := json.Marshal(BindingParams{Role: brokerBindings.NamespaceAdminRole, Namespaces: []string{"team-b", "team-a", "team-b"}})
+		require.NoError(t, err)
+
+		// when
+		_, err = svc.Bind(context.Background(), instanceID1, "rbac-binding-id", domain.BindDetails{RawParameters: rawParams}, true)
+
+		// then
+		require.NoError(t, err)
+		binding, err := db.Bindings().Get(instanceID1, "rbac-binding-id")
+		require.NoError(t, err)
+		assert.Equal(t, brokerBindings.NamespaceAdminRole, binding.Role)
+		assert.Equal(t, []string{"team-a", "team-b"}, binding.Namespaces)
+	})
+
+	t.Run("should reject the existing binding with different namespaces", func(t *testing.T) {
+		// given
+		rawParams, err := json.Marshal(BindingParams{Role: brokerBindings.NamespaceAdminRole, Namespaces: []string{"team-a"}})
+		require.NoError(t, err)
+
+		// when
+		_, err = svc.Bind(context.Background(), instanceID1, "rbac-binding-id", domain.BindDetails{RawParameters: rawParams}, true)
+
+		// then
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "binding already exists but with different parameters")
+	})
+
+	t.Run("should return the binding in progress for the namespaces in a different order", func(t *testing.T) {
+		// given
+		rawParams, err := json.Marshal(BindingParams{Role: brokerBindings.NamespaceAdminRole, Namespaces: []string{"team-b", "team-a"}})
+		require.NoError(t, err)
+
+		// when
+		resp, err := svc.Bind(context.Background(), instanceID1, "rbac-binding-id", domain.BindDetails{RawParameters: rawParams}, true)
+
+		// then
+		require.NoError(t, err)
+		assert.True(t, resp.IsAsync)
+	})
+}
+
+func TestRolesByPlan_Unmarshal(t *testing.T) {
+	t.Run("should parse the role templates of the plans", func(t *testing.T) {
+		// given
+		var roles RolesByPlan
+
+		// when
+		err := roles.Unmarshal("aws=viewer,editor; GCP=namespace-admin")
+
+		// then
+		require.NoError(t, err)
+		assert.True(t, roles.Allows("aws", brokerBindings.EditorRole))
+		assert.True(t, roles.Allows("gcp", brokerBindings.NamespaceAdminRole))
+		assert.False(t, roles.Allows("aws", brokerBindings.NamespaceAdminRole))
+		assert.False(t, roles.Allows("azure", brokerBindings.ViewerRole))
+		assert.True(t, roles.Restricts("AWS"))
+		assert.False(t, roles.Restricts("azure"))
+	})
+
+	t.Run("should reject the unknown role template", func(t *testing.T) {
+		// given
+		var roles RolesByPlan
+
+		// when
+		err := roles.Unmarshal("aws=viewer,owner")
+
+		// then
+		require.ErrorContains(t, err, `unknown role template "owner"`)
+	})
 }
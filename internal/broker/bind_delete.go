@@ -67,7 +67,7 @@ func (b *UnbindEndpoint) unbind(ctx context.Context, instanceID, bindingID strin
 		return domain.UnbindSpec{}, apiresponses.NewFailureResponse(fmt.Errorf("failed to get instance %s", instanceID), http.StatusInternalServerError, fmt.Sprintf("failed to get instance %s", instanceID))
 	}
 
-	_, err = b.bindingsStorage.Get(instanceID, bindingID)
+	binding, err := b.bindingsStorage.Get(instanceID, bindingID)
 	switch {
 	case dberr.IsNotFound(err):
 		return domain.UnbindSpec{}, apiresponses.ErrBindingDoesNotExist
@@ -81,7 +81,7 @@ func (b *UnbindEndpoint) unbind(ctx context.Context, instanceID, bindingID strin
 	}
 
 	if lastOperation.Type != internal.OperationTypeDeprovision {
-		err = b.bindingsManager.Delete(ctx, instance, bindingID)
+		err = b.bindingsManager.Delete(ctx, instance, binding)
 		if err != nil {
 			b.log.Error(fmt.Sprintf("Unbind error during removal of service account resources: %s", err))
 			return domain.UnbindSpec{}, apiresponses.NewFailureResponse(fmt.Errorf("failed to delete binding resources for binding %s and instance %s: %v", bindingID, instanceID, err), http.StatusInternalServerError, fmt.Sprintf("failed to delete resources for binding %s and instance %s: %v", bindingID, instanceID, err))
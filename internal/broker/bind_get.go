@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/kyma-project/kyma-environment-broker/internal"
+	broker "github.com/kyma-project/kyma-environment-broker/internal/broker/bindings"
 
 	"github.com/kyma-project/kyma-environment-broker/internal/storage"
 	"github.com/pivotal-cf/brokerapi/v12/domain"
@@ -54,6 +55,11 @@ func (b *GetBindingEndpoint) GetBinding(_ context.Context, instanceID, bindingID
 		return domain.GetBindingSpec{}, apiresponses.NewFailureResponse(errors.New(message), http.StatusNotFound, message)
 	}
 
+	if binding.State == domain.Failed {
+		message := "Binding creation failed"
+		return domain.GetBindingSpec{}, apiresponses.NewFailureResponse(errors.New(message), http.StatusNotFound, message)
+	}
+
 	if len(binding.Kubeconfig) == 0 {
 		message := "Binding creation in progress"
 		return domain.GetBindingSpec{}, apiresponses.NewFailureResponse(errors.New(message), http.StatusNotFound, message)
@@ -69,5 +75,18 @@ func (b *GetBindingEndpoint) GetBinding(_ context.Context, instanceID, bindingID
 		Credentials: Credentials{
 			Kubeconfig: binding.Kubeconfig,
 		},
+		Parameters: bindingParameters(binding),
 	}, nil
 }
+
+// bindingParameters returns the parameters the binding was created with, the role and the namespaces show the granted access
+func bindingParameters(binding *internal.Binding) BindingParams {
+	return BindingParams{
+		ExpirationSeconds: int(binding.ExpirationSeconds),
+		CredentialsType:   broker.CredentialsType(binding),
+		Group:             binding.Group,
+		AutoRenew:         binding.AutoRenew,
+		Role:              binding.Role,
+		Namespaces:        binding.Namespaces,
+	}
+}
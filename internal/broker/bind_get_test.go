@@ -124,4 +124,44 @@ func TestGetBinding(t *testing.T) {
 		errorResponse := apiErr.ErrorResponse().(apiresponses.ErrorResponse)
 		require.Equal(t, "Binding not found", errorResponse.Description)
 	})
+
+	t.Run("should return the role and the namespaces of the binding", func(t *testing.T) {
+		// given
+		bindingsMemory := memory.NewBinding()
+		operationsMemory := memory.NewOperation()
+
+		operation := fixture.FixOperation("operation-001", "test-instance-id", internal.OperationTypeProvision)
+		err := operationsMemory.InsertOperation(operation)
+		require.NoError(t, err)
+
+		binding := &internal.Binding{
+			ID:                "test-binding-id",
+			InstanceID:        "test-instance-id",
+			ExpiresAt:         time.Now().Add(5 * time.Minute),
+			ExpirationSeconds: 600,
+			Kubeconfig:        "kubeconfig",
+			Role:              "viewer",
+			Namespaces:        []string{"team-a", "team-b"},
+		}
+		err = bindingsMemory.Insert(binding)
+		require.NoError(t, err)
+
+		endpoint := &GetBindingEndpoint{
+			bindings:   bindingsMemory,
+			operations: operationsMemory,
+			log:        fixLogger(),
+		}
+
+		// when
+		spec, err := endpoint.GetBinding(context.Background(), "test-instance-id", "test-binding-id", domain.FetchBindingDetails{})
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, BindingParams{
+			ExpirationSeconds: 600,
+			CredentialsType:   "service_account",
+			Role:              "viewer",
+			Namespaces:        []string{"team-a", "team-b"},
+		}, spec.Parameters)
+	})
 }
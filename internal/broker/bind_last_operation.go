@@ -2,18 +2,24 @@ package broker
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
+	"net/http"
 
+	"github.com/kyma-project/kyma-environment-broker/internal/storage"
+	"github.com/kyma-project/kyma-environment-broker/internal/storage/dberr"
 	"github.com/pivotal-cf/brokerapi/v12/domain"
+	"github.com/pivotal-cf/brokerapi/v12/domain/apiresponses"
 )
 
 type LastBindingOperationEndpoint struct {
-	log *slog.Logger
+	log      *slog.Logger
+	bindings storage.Bindings
 }
 
-func NewLastBindingOperation(log *slog.Logger) *LastBindingOperationEndpoint {
-	return &LastBindingOperationEndpoint{log: log.With("service", "LastBindingOperationEndpoint")}
+func NewLastBindingOperation(log *slog.Logger, db storage.BrokerStorage) *LastBindingOperationEndpoint {
+	return &LastBindingOperationEndpoint{log: log.With("service", "LastBindingOperationEndpoint"), bindings: db.Bindings()}
 }
 
 // LastBindingOperation fetches last operation state for a service binding
@@ -24,5 +30,28 @@ func (b *LastBindingOperationEndpoint) LastBindingOperation(ctx context.Context,
 	b.log.Info(fmt.Sprintf("LastBindingOperation bindingID: %s", bindingID))
 	b.log.Info(fmt.Sprintf("LastBindingOperation details: %+v", details))
 
-	return domain.LastOperation{}, fmt.Errorf("not supported")
+	binding, err := b.bindings.Get(instanceID, bindingID)
+	switch {
+	case dberr.IsNotFound(err):
+		// OSB API expects 410 when the binding was removed, for example, by the unbind request
+		message := fmt.Sprintf("binding %s does not exist", bindingID)
+		return domain.LastOperation{}, apiresponses.NewFailureResponse(errors.New(message), http.StatusGone, message)
+	case err != nil:
+		message := fmt.Sprintf("failed to get binding %s for instance %s", bindingID, instanceID)
+		return domain.LastOperation{}, apiresponses.NewFailureResponse(errors.New(message), http.StatusInternalServerError, message)
+	}
+
+	if details.OperationData != "" && details.OperationData != binding.OperationID {
+		message := fmt.Sprintf("operation %s does not exist for binding %s", details.OperationData, bindingID)
+		return domain.LastOperation{}, apiresponses.NewFailureResponse(errors.New(message), http.StatusNotFound, message)
+	}
+
+	switch binding.State {
+	case domain.InProgress:
+		return domain.LastOperation{State: domain.InProgress, Description: "binding creation in progress"}, nil
+	case domain.Failed:
+		return domain.LastOperation{State: domain.Failed, Description: binding.Description}, nil
+	default:
+		return domain.LastOperation{State: domain.Succeeded, Description: "binding created"}, nil
+	}
 }
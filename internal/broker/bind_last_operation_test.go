@@ -0,0 +1,95 @@
+package broker
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/kyma-project/kyma-environment-broker/internal/fixture"
+	"github.com/kyma-project/kyma-environment-broker/internal/storage"
+	"github.com/pivotal-cf/brokerapi/v12/domain"
+	"github.com/pivotal-cf/brokerapi/v12/domain/apiresponses"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLastBindingOperation(t *testing.T) {
+	for tn, tc := range map[string]struct {
+		state               domain.LastOperationState
+		description         string
+		expectedState       domain.LastOperationState
+		expectedDescription string
+	}{
+		"in progress": {
+			state:               domain.InProgress,
+			expectedState:       domain.InProgress,
+			expectedDescription: "binding creation in progress",
+		},
+		"failed": {
+			state:               domain.Failed,
+			description:         "failed to create a Kyma binding",
+			expectedState:       domain.Failed,
+			expectedDescription: "failed to create a Kyma binding",
+		},
+		"succeeded": {
+			state:               domain.Succeeded,
+			expectedState:       domain.Succeeded,
+			expectedDescription: "binding created",
+		},
+		"created before the state was stored": {
+			expectedState:       domain.Succeeded,
+			expectedDescription: "binding created",
+		},
+	} {
+		t.Run(tn, func(t *testing.T) {
+			// given
+			db := storage.NewMemoryStorage()
+			binding := fixture.FixBinding("binding-id")
+			binding.State = tc.state
+			binding.Description = tc.description
+			binding.OperationID = "operation-id"
+			require.NoError(t, db.Bindings().Insert(&binding))
+			endpoint := NewLastBindingOperation(fixLogger(), db)
+
+			// when
+			lastOperation, err := endpoint.LastBindingOperation(context.Background(), binding.InstanceID, binding.ID, domain.PollDetails{OperationData: "operation-id"})
+
+			// then
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedState, lastOperation.State)
+			assert.Equal(t, tc.expectedDescription, lastOperation.Description)
+		})
+	}
+
+	t.Run("should return 410 for the removed binding", func(t *testing.T) {
+		// given
+		endpoint := NewLastBindingOperation(fixLogger(), storage.NewMemoryStorage())
+
+		// when
+		_, err := endpoint.LastBindingOperation(context.Background(), "instance-id", "binding-id", domain.PollDetails{})
+
+		// then
+		require.Error(t, err)
+		apiErr, ok := err.(*apiresponses.FailureResponse)
+		require.True(t, ok)
+		assert.Equal(t, http.StatusGone, apiErr.ValidatedStatusCode(nil))
+	})
+
+	t.Run("should return 404 for the unknown operation", func(t *testing.T) {
+		// given
+		db := storage.NewMemoryStorage()
+		binding := fixture.FixBinding("binding-id")
+		binding.OperationID = "operation-id"
+		require.NoError(t, db.Bindings().Insert(&binding))
+		endpoint := NewLastBindingOperation(fixLogger(), db)
+
+		// when
+		_, err := endpoint.LastBindingOperation(context.Background(), binding.InstanceID, binding.ID, domain.PollDetails{OperationData: "other-operation-id"})
+
+		// then
+		require.Error(t, err)
+		apiErr, ok := err.(*apiresponses.FailureResponse)
+		require.True(t, ok)
+		assert.Equal(t, http.StatusNotFound, apiErr.ValidatedStatusCode(nil))
+	})
+}
@@ -0,0 +1,108 @@
+package broker
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/kyma-project/kyma-environment-broker/internal"
+	broker "github.com/kyma-project/kyma-environment-broker/internal/broker/bindings"
+	"github.com/kyma-project/kyma-environment-broker/internal/event"
+	"github.com/kyma-project/kyma-environment-broker/internal/storage"
+	"github.com/kyma-project/kyma-environment-broker/internal/storage/dberr"
+	"github.com/pivotal-cf/brokerapi/v12/domain"
+)
+
+// BindingProcessor creates the credentials of the bindings accepted asynchronously, it is the executor of the bindings queue.
+// The items of the queue are the operation IDs of the bindings.
+type BindingProcessor struct {
+	config    BindingConfig
+	bindings  storage.Bindings
+	instances storage.Instances
+
+	bindingsManager broker.BindingsManager
+	publisher       event.Publisher
+
+	log *slog.Logger
+}
+
+func NewBindingProcessor(cfg BindingConfig, db storage.BrokerStorage, bindingsManager broker.BindingsManager, publisher event.Publisher, log *slog.Logger) *BindingProcessor {
+	return &BindingProcessor{
+		config:          cfg,
+		bindings:        db.Bindings(),
+		instances:       db.Instances(),
+		bindingsManager: bindingsManager,
+		publisher:       publisher,
+		log:             log.With("service", "BindingProcessor"),
+	}
+}
+
+func (p *BindingProcessor) Execute(operationID string) (time.Duration, error) {
+	log := p.log.With("operationID", operationID)
+
+	binding, err := p.bindings.GetByOperationID(operationID)
+	switch {
+	case dberr.IsNotFound(err):
+		log.Info("binding does not exist, it was removed before the credentials were created")
+		return 0, nil
+	case err != nil:
+		log.Warn(fmt.Sprintf("unable to get the binding, retrying in %s: %s", p.config.AsyncRetryInterval, err))
+		return p.config.AsyncRetryInterval, nil
+	}
+	if binding.State != domain.InProgress {
+		return 0, nil
+	}
+	log = log.With("instanceID", binding.InstanceID, "bindingID", binding.ID)
+
+	instance, err := p.instances.GetByID(binding.InstanceID)
+	switch {
+	case dberr.IsNotFound(err):
+		return 0, p.fail(binding, fmt.Sprintf("instance %s does not exist", binding.InstanceID), log)
+	case err != nil:
+		log.Warn(fmt.Sprintf("unable to get the instance, retrying in %s: %s", p.config.AsyncRetryInterval, err))
+		return p.config.AsyncRetryInterval, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), p.config.CreateBindingTimeout)
+	defer cancel()
+	kubeconfig, expiresAt, err := p.bindingsManager.Create(ctx, instance, binding)
+	if err != nil {
+		message := fmt.Sprintf("failed to create a Kyma binding using %s kubeconfig: %s", broker.CredentialsType(binding), err)
+		if time.Since(binding.CreatedAt) < p.config.AsyncCreationTimeout {
+			log.Warn(fmt.Sprintf("%s, retrying in %s", message, p.config.AsyncRetryInterval))
+			return p.config.AsyncRetryInterval, nil
+		}
+		return 0, p.fail(binding, message, log)
+	}
+
+	if _, err := p.bindings.Get(binding.InstanceID, binding.ID); dberr.IsNotFound(err) {
+		// the binding was removed while the credentials were created
+		log.Info("binding was removed during the creation, removing the service account")
+		return 0, p.bindingsManager.Delete(context.Background(), instance, binding)
+	}
+
+	binding.Kubeconfig = kubeconfig
+	binding.ExpiresAt = expiresAt
+	binding.State = domain.Succeeded
+	binding.UpdatedAt = time.Now()
+	if err := p.bindings.Update(binding); err != nil {
+		log.Warn(fmt.Sprintf("unable to update the binding, retrying in %s: %s", p.config.AsyncRetryInterval, err))
+		return p.config.AsyncRetryInterval, nil
+	}
+
+	log.Info("Successfully created binding")
+	p.publisher.Publish(context.Background(), BindingCreated{PlanID: instance.ServicePlanID})
+	return 0, nil
+}
+
+func (p *BindingProcessor) fail(binding *internal.Binding, message string, log *slog.Logger) error {
+	log.Error(message)
+	binding.State = domain.Failed
+	binding.Description = message
+	binding.UpdatedAt = time.Now()
+	if err := p.bindings.Update(binding); err != nil {
+		return fmt.Errorf("while updating the failed binding: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,135 @@
+package broker
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/kyma-project/kyma-environment-broker/internal"
+	"github.com/kyma-project/kyma-environment-broker/internal/event"
+	"github.com/kyma-project/kyma-environment-broker/internal/fixture"
+	"github.com/kyma-project/kyma-environment-broker/internal/storage"
+	"github.com/pivotal-cf/brokerapi/v12/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeBindingsManager struct {
+	err     error
+	deleted []string
+	revoked []string
+}
+
+func (m *fakeBindingsManager) Create(_ context.Context, _ *internal.Instance, binding *internal.Binding) (string, time.Time, error) {
+	if m.err != nil {
+		return "", time.Time{}, m.err
+	}
+	return fmt.Sprintf("kubeconfig-%s", binding.ID), time.Now().Add(time.Duration(binding.ExpirationSeconds) * time.Second), nil
+}
+
+func (m *fakeBindingsManager) Delete(_ context.Context, _ *internal.Instance, binding *internal.Binding) error {
+	m.deleted = append(m.deleted, binding.ID)
+	return nil
+}
+
+func (m *fakeBindingsManager) Revoke(_ context.Context, _ *internal.Instance, binding *internal.Binding) error {
+	m.revoked = append(m.revoked, binding.ID)
+	return nil
+}
+
+func TestBindingProcessor_Execute(t *testing.T) {
+	cfg := fixBindingConfig()
+	cfg.CreateBindingTimeout = time.Second
+	cfg.AsyncRetryInterval = time.Second
+	cfg.AsyncCreationTimeout = time.Minute
+
+	prepare := func(t *testing.T, createdAt time.Time) (storage.BrokerStorage, internal.Binding) {
+		db := storage.NewMemoryStorage()
+		require.NoError(t, db.Instances().Insert(fixture.FixInstance(instanceID1)))
+		binding := fixture.FixBinding("binding-id", fixture.WithInstanceID(instanceID1))
+		binding.Kubeconfig = ""
+		binding.CreatedAt = createdAt
+		binding.State = domain.InProgress
+		binding.OperationID = "operation-id"
+		require.NoError(t, db.Bindings().Insert(&binding))
+		return db, binding
+	}
+
+	t.Run("should create the credentials", func(t *testing.T) {
+		// given
+		db, binding := prepare(t, time.Now())
+		publisher := event.NewPubSub(fixLogger())
+		created := make(chan BindingCreated, 1)
+		publisher.Subscribe(BindingCreated{}, func(_ context.Context, ev interface{}) error {
+			created <- ev.(BindingCreated)
+			return nil
+		})
+		processor := NewBindingProcessor(cfg, db, &fakeBindingsManager{}, publisher, fixLogger())
+
+		// when
+		retry, err := processor.Execute(binding.OperationID)
+
+		// then
+		require.NoError(t, err)
+		assert.Zero(t, retry)
+		stored, err := db.Bindings().Get(instanceID1, binding.ID)
+		require.NoError(t, err)
+		assert.Equal(t, domain.Succeeded, stored.State)
+		assert.Equal(t, "kubeconfig-binding-id", stored.Kubeconfig)
+		select {
+		case <-created:
+		case <-time.After(time.Second):
+			t.Fatal("BindingCreated event was not published")
+		}
+	})
+
+	t.Run("should retry the creation", func(t *testing.T) {
+		// given
+		db, binding := prepare(t, time.Now())
+		processor := NewBindingProcessor(cfg, db, &fakeBindingsManager{err: fmt.Errorf("runtime not reachable")}, event.NewPubSub(fixLogger()), fixLogger())
+
+		// when
+		retry, err := processor.Execute(binding.OperationID)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, cfg.AsyncRetryInterval, retry)
+		stored, err := db.Bindings().Get(instanceID1, binding.ID)
+		require.NoError(t, err)
+		assert.Equal(t, domain.InProgress, stored.State)
+	})
+
+	t.Run("should fail the binding after the creation timeout", func(t *testing.T) {
+		// given
+		db, binding := prepare(t, time.Now().Add(-2*cfg.AsyncCreationTimeout))
+		processor := NewBindingProcessor(cfg, db, &fakeBindingsManager{err: fmt.Errorf("runtime not reachable")}, event.NewPubSub(fixLogger()), fixLogger())
+
+		// when
+		retry, err := processor.Execute(binding.OperationID)
+
+		// then
+		require.NoError(t, err)
+		assert.Zero(t, retry)
+		stored, err := db.Bindings().Get(instanceID1, binding.ID)
+		require.NoError(t, err)
+		assert.Equal(t, domain.Failed, stored.State)
+		assert.Contains(t, stored.Description, "runtime not reachable")
+	})
+
+	t.Run("should skip the removed binding", func(t *testing.T) {
+		// given
+		db, binding := prepare(t, time.Now())
+		require.NoError(t, db.Bindings().Delete(instanceID1, binding.ID))
+		manager := &fakeBindingsManager{}
+		processor := NewBindingProcessor(cfg, db, manager, event.NewPubSub(fixLogger()), fixLogger())
+
+		// when
+		retry, err := processor.Execute(binding.OperationID)
+
+		// then
+		require.NoError(t, err)
+		assert.Zero(t, retry)
+		assert.Empty(t, manager.deleted)
+	})
+}
@@ -0,0 +1,233 @@
+package broker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kyma-project/kyma-environment-broker/internal"
+	broker "github.com/kyma-project/kyma-environment-broker/internal/broker/bindings"
+	"github.com/kyma-project/kyma-environment-broker/internal/event"
+	"github.com/kyma-project/kyma-environment-broker/internal/storage"
+	"github.com/kyma-project/kyma-environment-broker/internal/storage/dberr"
+	"github.com/pivotal-cf/brokerapi/v12/domain"
+	"github.com/pivotal-cf/brokerapi/v12/domain/apiresponses"
+)
+
+// BindingRotator replaces the credentials of an existing binding, the binding keeps its ID and gets a new expiration time.
+// The explicit rotation revokes the previous service account token, the automatic renewal keeps it valid until it expires.
+type BindingRotator struct {
+	config    BindingConfig
+	bindings  storage.Bindings
+	instances storage.Instances
+
+	bindingsManager broker.BindingsManager
+	publisher       event.Publisher
+
+	log *slog.Logger
+}
+
+func NewBindingRotator(cfg BindingConfig, db storage.BrokerStorage, bindingsManager broker.BindingsManager, publisher event.Publisher, log *slog.Logger) *BindingRotator {
+	return &BindingRotator{
+		config:          cfg,
+		bindings:        db.Bindings(),
+		instances:       db.Instances(),
+		bindingsManager: bindingsManager,
+		publisher:       publisher,
+		log:             log.With("service", "BindingRotator"),
+	}
+}
+
+// Rotate creates new credentials for the binding, the errors are failure responses with the HTTP status code
+func (r *BindingRotator) Rotate(ctx context.Context, instanceID, bindingID string) (domain.GetBindingSpec, error) {
+	binding, err := r.bindings.Get(instanceID, bindingID)
+	switch {
+	case dberr.IsNotFound(err):
+		message := fmt.Sprintf("binding %s does not exist for instance %s", bindingID, instanceID)
+		return domain.GetBindingSpec{}, apiresponses.NewFailureResponse(errors.New(message), http.StatusNotFound, message)
+	case err != nil:
+		message := fmt.Sprintf("failed to get binding %s for instance %s", bindingID, instanceID)
+		return domain.GetBindingSpec{}, apiresponses.NewFailureResponse(errors.New(message), http.StatusInternalServerError, message)
+	}
+
+	binding, err = r.rotate(ctx, binding, false)
+	if err != nil {
+		return domain.GetBindingSpec{}, err
+	}
+	return domain.GetBindingSpec{
+		Credentials: Credentials{
+			Kubeconfig: binding.Kubeconfig,
+		},
+		Parameters: bindingParameters(binding),
+		Metadata: domain.BindingMetadata{
+			ExpiresAt: binding.ExpiresAt.Format(expiresAtLayout),
+		},
+	}, nil
+}
+
+func (r *BindingRotator) rotate(ctx context.Context, binding *internal.Binding, autoRenew bool) (*internal.Binding, error) {
+	switch {
+	case binding.ExpiresAt.Before(time.Now()):
+		message := "binding expired"
+		return nil, apiresponses.NewFailureResponse(errors.New(message), http.StatusNotFound, message)
+	case binding.State == domain.InProgress || len(binding.Kubeconfig) == 0:
+		message := "binding creation in progress"
+		return nil, apiresponses.NewFailureResponse(errors.New(message), http.StatusUnprocessableEntity, message)
+	case binding.State == domain.Failed:
+		message := "binding creation failed"
+		return nil, apiresponses.NewFailureResponse(errors.New(message), http.StatusUnprocessableEntity, message)
+	}
+
+	instance, err := r.instances.GetByID(binding.InstanceID)
+	switch {
+	case dberr.IsNotFound(err):
+		message := fmt.Sprintf("instance %s does not exist", binding.InstanceID)
+		return nil, apiresponses.NewFailureResponse(errors.New(message), http.StatusNotFound, message)
+	case err != nil:
+		message := fmt.Sprintf("failed to get instance %s", binding.InstanceID)
+		return nil, apiresponses.NewFailureResponse(errors.New(message), http.StatusInternalServerError, message)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, r.config.CreateBindingTimeout)
+	defer cancel()
+	// the tokens are bound to the service account, so the previous credentials cannot be revoked after the new ones are created
+	revoked := false
+	if !autoRenew {
+		// the explicit rotation invalidates the previous credentials, the renewed ones stay valid until they expire,
+		// so the consumer has time to fetch the new kubeconfig
+		if err := r.bindingsManager.Revoke(ctx, instance, binding); err != nil {
+			message := fmt.Sprintf("failed to revoke the credentials of the Kyma binding: %s", err)
+			return nil, apiresponses.NewFailureResponse(errors.New(message), http.StatusInternalServerError, message)
+		}
+		revoked = true
+	}
+	kubeconfig, expiresAt, err := r.bindingsManager.Create(ctx, instance, binding)
+	if err != nil {
+		message := fmt.Sprintf("failed to rotate the credentials of the Kyma binding: %s", err)
+		if revoked {
+			r.fail(binding, message)
+		}
+		return nil, apiresponses.NewFailureResponse(errors.New(message), http.StatusInternalServerError, message)
+	}
+
+	if _, err := r.bindings.Get(binding.InstanceID, binding.ID); dberr.IsNotFound(err) {
+		// the binding was removed while the credentials were created
+		r.log.Info(fmt.Sprintf("binding %s for instance %s was removed during the rotation, removing the service account", binding.ID, binding.InstanceID))
+		if err := r.bindingsManager.Delete(context.Background(), instance, binding); err != nil {
+			r.log.Error(fmt.Sprintf("unable to remove the resources of the removed binding %s for instance %s: %s", binding.ID, binding.InstanceID, err))
+		}
+		message := fmt.Sprintf("binding %s does not exist for instance %s", binding.ID, binding.InstanceID)
+		return nil, apiresponses.NewFailureResponse(errors.New(message), http.StatusNotFound, message)
+	}
+
+	binding.Kubeconfig = kubeconfig
+	binding.ExpiresAt = expiresAt
+	binding.UpdatedAt = time.Now()
+	if err := r.bindings.Update(binding); err != nil {
+		message := fmt.Sprintf("failed to update Kyma binding in storage: %s", err)
+		if revoked {
+			r.fail(binding, message)
+		}
+		return nil, apiresponses.NewFailureResponse(errors.New(message), http.StatusInternalServerError, message)
+	}
+
+	r.log.Info(fmt.Sprintf("Rotated the credentials of binding %s for instance %s, expires at %s", binding.ID, binding.InstanceID, expiresAt.Format(expiresAtLayout)))
+	r.publisher.Publish(context.Background(), BindingRotated{PlanID: instance.ServicePlanID, AutoRenew: autoRenew})
+	return binding, nil
+}
+
+// fail marks the binding with revoked credentials as failed, so the previous kubeconfig is not returned anymore.
+// A new PUT request creates the binding again.
+func (r *BindingRotator) fail(binding *internal.Binding, message string) {
+	r.log.Error(fmt.Sprintf("binding %s for instance %s has revoked credentials, marking it as failed: %s", binding.ID, binding.InstanceID, message))
+	binding.State = domain.Failed
+	binding.Description = message
+	binding.UpdatedAt = time.Now()
+	if err := r.bindings.Update(binding); err != nil {
+		r.log.Error(fmt.Sprintf("unable to mark binding %s for instance %s as failed: %s", binding.ID, binding.InstanceID, err))
+	}
+}
+
+const (
+	// bindingRenewalQueueName is the queue of the renewal item, the replica which leases the item renews the bindings
+	bindingRenewalQueueName = "binding-renewal"
+	bindingRenewalItemID    = "renewal"
+)
+
+// BindingRenewer rotates the credentials of the bindings marked with auto_renew which expire within AutoRenewBefore.
+// The renewal is scheduled in the queue items, so only one of the broker replicas renews the bindings in every interval.
+type BindingRenewer struct {
+	config     BindingConfig
+	bindings   storage.Bindings
+	queueItems storage.QueueItems
+	rotator    *BindingRotator
+	owner      string
+	log        *slog.Logger
+}
+
+func NewBindingRenewer(cfg BindingConfig, db storage.BrokerStorage, rotator *BindingRotator, log *slog.Logger) *BindingRenewer {
+	hostname, _ := os.Hostname()
+	return &BindingRenewer{
+		config:     cfg,
+		bindings:   db.Bindings(),
+		queueItems: db.QueueItems(),
+		rotator:    rotator,
+		owner:      fmt.Sprintf("%s-%s", hostname, uuid.NewString()),
+		log:        log.With("service", "BindingRenewer"),
+	}
+}
+
+func (r *BindingRenewer) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.config.AutoRenewInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			r.renewIfLeased(ctx)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// renewIfLeased renews the bindings if the renewal is due and not leased by another replica.
+// The lease lasts AutoRenewBefore, after that time the bindings would expire anyway, so another replica can take over.
+func (r *BindingRenewer) renewIfLeased(ctx context.Context) {
+	if err := r.queueItems.ScheduleIfAbsent(bindingRenewalQueueName, bindingRenewalItemID, time.Now()); err != nil {
+		r.log.Error(fmt.Sprintf("unable to schedule the renewal of the bindings: %s", err))
+		return
+	}
+	item, err := r.queueItems.Lease(bindingRenewalQueueName, r.owner, r.config.AutoRenewBefore)
+	switch {
+	case dberr.IsNotFound(err):
+		return
+	case err != nil:
+		r.log.Error(fmt.Sprintf("unable to lease the renewal of the bindings: %s", err))
+		return
+	}
+
+	r.renew(ctx)
+
+	if err := r.queueItems.Reschedule(*item, time.Now().Add(r.config.AutoRenewInterval)); err != nil {
+		r.log.Error(fmt.Sprintf("unable to schedule the next renewal of the bindings: %s", err))
+	}
+}
+
+func (r *BindingRenewer) renew(ctx context.Context) {
+	bindings, err := r.bindings.ListToRenew(time.Now().Add(r.config.AutoRenewBefore))
+	if err != nil {
+		r.log.Error(fmt.Sprintf("unable to list the bindings to renew: %s", err))
+		return
+	}
+	for _, binding := range bindings {
+		if _, err := r.rotator.rotate(ctx, &binding, true); err != nil {
+			r.log.Warn(fmt.Sprintf("unable to renew binding %s for instance %s, retrying in %s: %s", binding.ID, binding.InstanceID, r.config.AutoRenewInterval, err))
+		}
+	}
+}
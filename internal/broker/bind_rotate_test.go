@@ -0,0 +1,250 @@
+package broker
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/kyma-project/kyma-environment-broker/internal"
+	"github.com/kyma-project/kyma-environment-broker/internal/event"
+	"github.com/kyma-project/kyma-environment-broker/internal/fixture"
+	"github.com/kyma-project/kyma-environment-broker/internal/storage"
+	"github.com/pivotal-cf/brokerapi/v12/domain"
+	"github.com/pivotal-cf/brokerapi/v12/domain/apiresponses"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBindingRotator_Rotate(t *testing.T) {
+	cfg := fixBindingConfig()
+	cfg.CreateBindingTimeout = time.Second
+
+	prepare := func(t *testing.T) storage.BrokerStorage {
+		db := storage.NewMemoryStorage()
+		require.NoError(t, db.Instances().Insert(fixture.FixInstance(instanceID1)))
+		return db
+	}
+
+	t.Run("should replace the credentials and publish the event", func(t *testing.T) {
+		// given
+		db := prepare(t)
+		binding := fixture.FixBinding("binding-id", fixture.WithInstanceID(instanceID1))
+		binding.State = domain.Succeeded
+		require.NoError(t, db.Bindings().Insert(&binding))
+		publisher := event.NewPubSub(fixLogger())
+		rotated := make(chan BindingRotated, 1)
+		publisher.Subscribe(BindingRotated{}, func(_ context.Context, ev interface{}) error {
+			rotated <- ev.(BindingRotated)
+			return nil
+		})
+		manager := &fakeBindingsManager{}
+		rotator := NewBindingRotator(cfg, db, manager, publisher, fixLogger())
+
+		// when
+		spec, err := rotator.Rotate(context.Background(), instanceID1, binding.ID)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, []string{binding.ID}, manager.revoked)
+		assert.Equal(t, Credentials{Kubeconfig: "kubeconfig-binding-id"}, spec.Credentials)
+		stored, err := db.Bindings().Get(instanceID1, binding.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "kubeconfig-binding-id", stored.Kubeconfig)
+		assert.Equal(t, stored.ExpiresAt.Format(expiresAtLayout), spec.Metadata.ExpiresAt)
+		assert.True(t, stored.ExpiresAt.After(binding.ExpiresAt))
+		select {
+		case ev := <-rotated:
+			assert.False(t, ev.AutoRenew)
+		case <-time.After(time.Second):
+			t.Fatal("BindingRotated event was not published")
+		}
+	})
+
+	for tn, tc := range map[string]struct {
+		modify         func(binding *internal.Binding)
+		expectedStatus int
+	}{
+		"expired binding": {
+			modify:         func(b *internal.Binding) { b.ExpiresAt = time.Now().Add(-time.Minute) },
+			expectedStatus: http.StatusNotFound,
+		},
+		"binding in progress": {
+			modify:         func(b *internal.Binding) { b.State = domain.InProgress; b.Kubeconfig = "" },
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+		"failed binding": {
+			modify:         func(b *internal.Binding) { b.State = domain.Failed },
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+	} {
+		t.Run(fmt.Sprintf("should reject the %s", tn), func(t *testing.T) {
+			// given
+			db := prepare(t)
+			binding := fixture.FixBinding("binding-id", fixture.WithInstanceID(instanceID1))
+			tc.modify(&binding)
+			require.NoError(t, db.Bindings().Insert(&binding))
+			rotator := NewBindingRotator(cfg, db, &fakeBindingsManager{}, event.NewPubSub(fixLogger()), fixLogger())
+
+			// when
+			_, err := rotator.Rotate(context.Background(), instanceID1, binding.ID)
+
+			// then
+			require.Error(t, err)
+			apiErr, ok := err.(*apiresponses.FailureResponse)
+			require.True(t, ok)
+			assert.Equal(t, tc.expectedStatus, apiErr.ValidatedStatusCode(nil))
+		})
+	}
+
+	t.Run("should remove the credentials of the binding removed during the rotation", func(t *testing.T) {
+		// given
+		db := prepare(t)
+		binding := fixture.FixBinding("binding-id", fixture.WithInstanceID(instanceID1))
+		binding.State = domain.Succeeded
+		require.NoError(t, db.Bindings().Insert(&binding))
+		manager := &removingBindingsManager{bindings: db.Bindings()}
+		rotator := NewBindingRotator(cfg, db, manager, event.NewPubSub(fixLogger()), fixLogger())
+
+		// when
+		_, err := rotator.Rotate(context.Background(), instanceID1, binding.ID)
+
+		// then
+		require.Error(t, err)
+		apiErr, ok := err.(*apiresponses.FailureResponse)
+		require.True(t, ok)
+		assert.Equal(t, http.StatusNotFound, apiErr.ValidatedStatusCode(nil))
+		assert.Equal(t, []string{binding.ID}, manager.deleted)
+	})
+
+	t.Run("should mark the binding as failed when the credentials cannot be created after the revocation", func(t *testing.T) {
+		// given
+		db := prepare(t)
+		binding := fixture.FixBinding("binding-id", fixture.WithInstanceID(instanceID1))
+		binding.State = domain.Succeeded
+		require.NoError(t, db.Bindings().Insert(&binding))
+		manager := &fakeBindingsManager{err: fmt.Errorf("runtime not reachable")}
+		rotator := NewBindingRotator(cfg, db, manager, event.NewPubSub(fixLogger()), fixLogger())
+
+		// when
+		_, err := rotator.Rotate(context.Background(), instanceID1, binding.ID)
+
+		// then
+		require.Error(t, err)
+		assert.Equal(t, []string{binding.ID}, manager.revoked)
+		stored, err := db.Bindings().Get(instanceID1, binding.ID)
+		require.NoError(t, err)
+		assert.Equal(t, domain.Failed, stored.State)
+		assert.Contains(t, stored.Description, "runtime not reachable")
+	})
+
+	t.Run("should keep the binding when the automatic renewal fails", func(t *testing.T) {
+		// given
+		db := prepare(t)
+		binding := fixture.FixBinding("binding-id", fixture.WithInstanceID(instanceID1))
+		binding.State = domain.Succeeded
+		require.NoError(t, db.Bindings().Insert(&binding))
+		manager := &fakeBindingsManager{err: fmt.Errorf("runtime not reachable")}
+		rotator := NewBindingRotator(cfg, db, manager, event.NewPubSub(fixLogger()), fixLogger())
+
+		// when
+		_, err := rotator.rotate(context.Background(), &binding, true)
+
+		// then
+		require.Error(t, err)
+		assert.Empty(t, manager.revoked)
+		stored, err := db.Bindings().Get(instanceID1, binding.ID)
+		require.NoError(t, err)
+		assert.Equal(t, domain.Succeeded, stored.State)
+	})
+
+	t.Run("should return 404 for the missing binding", func(t *testing.T) {
+		// given
+		rotator := NewBindingRotator(cfg, prepare(t), &fakeBindingsManager{}, event.NewPubSub(fixLogger()), fixLogger())
+
+		// when
+		_, err := rotator.Rotate(context.Background(), instanceID1, "missing")
+
+		// then
+		require.Error(t, err)
+		apiErr, ok := err.(*apiresponses.FailureResponse)
+		require.True(t, ok)
+		assert.Equal(t, http.StatusNotFound, apiErr.ValidatedStatusCode(nil))
+	})
+}
+
+func TestBindingRenewer(t *testing.T) {
+	// given
+	cfg := fixBindingConfig()
+	cfg.CreateBindingTimeout = time.Second
+	cfg.AutoRenewBefore = 5 * time.Minute
+	cfg.AutoRenewInterval = time.Hour
+	db := storage.NewMemoryStorage()
+	require.NoError(t, db.Instances().Insert(fixture.FixInstance(instanceID1)))
+
+	expiring := fixture.FixBinding("expiring", fixture.WithInstanceID(instanceID1))
+	expiring.State = domain.Succeeded
+	expiring.AutoRenew = true
+	expiring.ExpiresAt = time.Now().Add(2 * time.Minute)
+	notMarked := fixture.FixBinding("not-marked", fixture.WithInstanceID(instanceID1))
+	notMarked.State = domain.Succeeded
+	notMarked.ExpiresAt = time.Now().Add(2 * time.Minute)
+	require.NoError(t, db.Bindings().Insert(&expiring))
+	require.NoError(t, db.Bindings().Insert(&notMarked))
+
+	publisher := event.NewPubSub(fixLogger())
+	rotated := make(chan BindingRotated, 2)
+	publisher.Subscribe(BindingRotated{}, func(_ context.Context, ev interface{}) error {
+		rotated <- ev.(BindingRotated)
+		return nil
+	})
+	manager := &fakeBindingsManager{}
+	rotator := NewBindingRotator(cfg, db, manager, publisher, fixLogger())
+	renewer := NewBindingRenewer(cfg, db, rotator, fixLogger())
+	otherReplica := NewBindingRenewer(cfg, db, rotator, fixLogger())
+
+	// when
+	renewer.renewIfLeased(context.Background())
+	expiringLater := fixture.FixBinding("expiring-later", fixture.WithInstanceID(instanceID1))
+	expiringLater.State = domain.Succeeded
+	expiringLater.AutoRenew = true
+	expiringLater.ExpiresAt = time.Now().Add(2 * time.Minute)
+	require.NoError(t, db.Bindings().Insert(&expiringLater))
+	otherReplica.renewIfLeased(context.Background())
+
+	// then
+	renewed, err := db.Bindings().Get(instanceID1, "expiring")
+	require.NoError(t, err)
+	assert.Equal(t, "kubeconfig-expiring", renewed.Kubeconfig)
+	assert.True(t, renewed.ExpiresAt.After(time.Now().Add(cfg.AutoRenewBefore)))
+	assert.Empty(t, manager.revoked)
+
+	untouched, err := db.Bindings().Get(instanceID1, "not-marked")
+	require.NoError(t, err)
+	assert.Equal(t, notMarked.Kubeconfig, untouched.Kubeconfig)
+
+	notDue, err := db.Bindings().Get(instanceID1, "expiring-later")
+	require.NoError(t, err)
+	assert.Equal(t, expiringLater.Kubeconfig, notDue.Kubeconfig)
+
+	select {
+	case ev := <-rotated:
+		assert.True(t, ev.AutoRenew)
+	case <-time.After(time.Second):
+		t.Fatal("BindingRotated event was not published")
+	}
+}
+
+// removingBindingsManager removes the binding from the storage while the credentials are created
+type removingBindingsManager struct {
+	fakeBindingsManager
+	bindings storage.Bindings
+}
+
+func (m *removingBindingsManager) Create(ctx context.Context, instance *internal.Instance, binding *internal.Binding) (string, time.Time, error) {
+	if err := m.bindings.Delete(binding.InstanceID, binding.ID); err != nil {
+		return "", time.Time{}, err
+	}
+	return m.fakeBindingsManager.Create(ctx, instance, binding)
+}
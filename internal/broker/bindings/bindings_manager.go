@@ -21,14 +21,19 @@ import (
 const (
 	BindingNameFormat = "kyma-binding-%s"
 	BindingNamespace  = "kyma-system"
+
+	ServiceAccountCredentialsType = "service_account"
+	OIDCCredentialsType           = "oidc"
 )
 
 type Credentials struct {
 }
 
 type BindingsManager interface {
-	Create(ctx context.Context, instance *internal.Instance, bindingID string, expirationSeconds int) (string, time.Time, error)
-	Delete(ctx context.Context, instance *internal.Instance, bindingID string) error
+	Create(ctx context.Context, instance *internal.Instance, binding *internal.Binding) (string, time.Time, error)
+	Delete(ctx context.Context, instance *internal.Instance, binding *internal.Binding) error
+	// Revoke invalidates the credentials created for the binding before, the next Create returns new ones
+	Revoke(ctx context.Context, instance *internal.Instance, binding *internal.Binding) error
 }
 
 type ClientProvider interface {
@@ -51,14 +56,14 @@ func NewServiceAccountBindingsManager(clientProvider ClientProvider, kubeconfigP
 	}
 }
 
-func (c *ServiceAccountBindingsManager) Create(ctx context.Context, instance *internal.Instance, bindingID string, expirationSeconds int) (string, time.Time, error) {
+func (c *ServiceAccountBindingsManager) Create(ctx context.Context, instance *internal.Instance, binding *internal.Binding) (string, time.Time, error) {
 	clientset, err := c.clientProvider.K8sClientSetForRuntimeID(instance.RuntimeID)
 
 	if err != nil {
 		return "", time.Time{}, fmt.Errorf("while creating a runtime client for binding creation: %v", err)
 	}
 
-	serviceBindingName := BindingName(bindingID)
+	serviceBindingName := BindingName(binding.ID)
 	fmt.Printf("Creating a service account binding for runtime %s with name %s", instance.RuntimeID, serviceBindingName)
 
 	_, err = clientset.CoreV1().ServiceAccounts(BindingNamespace).Create(ctx,
@@ -74,47 +79,35 @@ func (c *ServiceAccountBindingsManager) Create(ctx context.Context, instance *in
 		return "", time.Time{}, fmt.Errorf("while creating a service account: %v", err)
 	}
 
-	_, err = clientset.RbacV1().ClusterRoles().Create(ctx,
-		&rbacv1.ClusterRole{
-			TypeMeta: mv1.TypeMeta{APIVersion: rbacv1.SchemeGroupVersion.String(), Kind: "ClusterRole"},
-			ObjectMeta: mv1.ObjectMeta{
-				Name:   serviceBindingName,
-				Labels: map[string]string{"app.kubernetes.io/managed-by": "kcp-kyma-environment-broker"},
-			},
-			Rules: []rbacv1.PolicyRule{
-				{
-					Verbs:     []string{"*"},
-					APIGroups: []string{"*"},
-					Resources: []string{"*"},
+	// the bindings with a role template use the cluster role of the template
+	if binding.Role == "" {
+		_, err = clientset.RbacV1().ClusterRoles().Create(ctx,
+			&rbacv1.ClusterRole{
+				TypeMeta: mv1.TypeMeta{APIVersion: rbacv1.SchemeGroupVersion.String(), Kind: "ClusterRole"},
+				ObjectMeta: mv1.ObjectMeta{
+					Name:   serviceBindingName,
+					Labels: map[string]string{"app.kubernetes.io/managed-by": "kcp-kyma-environment-broker"},
 				},
-			},
-		}, mv1.CreateOptions{})
-	if err != nil && !apierrors.IsAlreadyExists(err) {
-		return "", time.Time{}, fmt.Errorf("while creating a cluster role: %v", err)
+				Rules: []rbacv1.PolicyRule{
+					{
+						Verbs:     []string{"*"},
+						APIGroups: []string{"*"},
+						Resources: []string{"*"},
+					},
+				},
+			}, mv1.CreateOptions{})
+		if err != nil && !apierrors.IsAlreadyExists(err) {
+			return "", time.Time{}, fmt.Errorf("while creating a cluster role: %v", err)
+		}
 	}
 
-	_, err = clientset.RbacV1().ClusterRoleBindings().Create(ctx, &rbacv1.ClusterRoleBinding{
-		TypeMeta: mv1.TypeMeta{APIVersion: rbacv1.SchemeGroupVersion.String(), Kind: "ClusterRoleBinding"},
-		ObjectMeta: mv1.ObjectMeta{
-			Name:   serviceBindingName,
-			Labels: map[string]string{"app.kubernetes.io/managed-by": "kcp-kyma-environment-broker"},
-		},
-		RoleRef: rbacv1.RoleRef{
-			APIGroup: rbacv1.GroupName,
-			Kind:     "ClusterRole",
-			Name:     serviceBindingName,
-		},
-		Subjects: []rbacv1.Subject{
-			{
-				Kind:      rbacv1.ServiceAccountKind,
-				Namespace: "kyma-system",
-				Name:      serviceBindingName,
-			},
-		},
-	}, mv1.CreateOptions{})
-
-	if err != nil && !apierrors.IsAlreadyExists(err) {
-		return "", time.Time{}, fmt.Errorf("while creating a cluster role binding: %v", err)
+	err = bindRole(ctx, clientset, binding, rbacv1.Subject{
+		Kind:      rbacv1.ServiceAccountKind,
+		Namespace: "kyma-system",
+		Name:      serviceBindingName,
+	}, serviceBindingName)
+	if err != nil {
+		return "", time.Time{}, err
 	}
 
 	tokenRequest := &authv1.TokenRequest{
@@ -124,7 +117,7 @@ func (c *ServiceAccountBindingsManager) Create(ctx context.Context, instance *in
 			Labels:    map[string]string{"app.kubernetes.io/managed-by": "kcp-kyma-environment-broker"},
 		},
 		Spec: authv1.TokenRequestSpec{
-			ExpirationSeconds: ptr.Integer64(int64(expirationSeconds)),
+			ExpirationSeconds: ptr.Integer64(binding.ExpirationSeconds),
 		},
 	}
 
@@ -144,20 +137,20 @@ func (c *ServiceAccountBindingsManager) Create(ctx context.Context, instance *in
 	return kubeconfigContent, expiresAt, nil
 }
 
-func (c *ServiceAccountBindingsManager) Delete(ctx context.Context, instance *internal.Instance, bindingID string) error {
+func (c *ServiceAccountBindingsManager) Delete(ctx context.Context, instance *internal.Instance, binding *internal.Binding) error {
 	clientset, err := c.clientProvider.K8sClientSetForRuntimeID(instance.RuntimeID)
 
 	if err != nil {
 		return fmt.Errorf("while creating a runtime client for binding creation: %v", err)
 	}
 
-	serviceBindingName := BindingName(bindingID)
+	serviceBindingName := BindingName(binding.ID)
 
 	// remove a binding
-	err = clientset.RbacV1().ClusterRoleBindings().Delete(ctx, serviceBindingName, mv1.DeleteOptions{})
+	err = unbindRole(ctx, clientset, binding)
 
-	if err != nil && !apierrors.IsNotFound(err) {
-		return fmt.Errorf("while removing a cluster role binding: %v", err)
+	if err != nil {
+		return err
 	}
 
 	// remove a role
@@ -177,6 +170,20 @@ func (c *ServiceAccountBindingsManager) Delete(ctx context.Context, instance *in
 	return nil
 }
 
+// Revoke removes the service account, the tokens are bound to it and become invalid. Create recreates the service account.
+func (c *ServiceAccountBindingsManager) Revoke(ctx context.Context, instance *internal.Instance, binding *internal.Binding) error {
+	clientset, err := c.clientProvider.K8sClientSetForRuntimeID(instance.RuntimeID)
+	if err != nil {
+		return fmt.Errorf("while creating a runtime client for binding revocation: %v", err)
+	}
+
+	err = clientset.CoreV1().ServiceAccounts(BindingNamespace).Delete(ctx, BindingName(binding.ID), mv1.DeleteOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("while removing a service account: %v", err)
+	}
+	return nil
+}
+
 func BindingName(bindingID string) string {
 	return fmt.Sprintf(BindingNameFormat, bindingID)
 }
@@ -0,0 +1,128 @@
+package broker
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kyma-project/kyma-environment-broker/internal"
+	rbacv1 "k8s.io/api/rbac/v1"
+)
+
+// OIDCClusterRoleName is the cluster role bound to the group of the OIDC binding without a role template
+const OIDCClusterRoleName = "cluster-admin"
+
+type OIDCKubeconfigBuilder interface {
+	Build(instance *internal.Instance) (string, error)
+}
+
+// OIDCBindingsManager creates the kubeconfig which authenticates the user with the OIDC configuration of the runtime,
+// the access is granted to the group of the binding with a cluster role binding or with role bindings in the namespaces of the binding
+type OIDCBindingsManager struct {
+	clientProvider    ClientProvider
+	kubeconfigBuilder OIDCKubeconfigBuilder
+}
+
+func NewOIDCBindingsManager(clientProvider ClientProvider, kubeconfigBuilder OIDCKubeconfigBuilder) *OIDCBindingsManager {
+	return &OIDCBindingsManager{
+		clientProvider:    clientProvider,
+		kubeconfigBuilder: kubeconfigBuilder,
+	}
+}
+
+func (c *OIDCBindingsManager) Create(ctx context.Context, instance *internal.Instance, binding *internal.Binding) (string, time.Time, error) {
+	if binding.Group == "" {
+		return "", time.Time{}, fmt.Errorf("group is required for the OIDC binding")
+	}
+	clientset, err := c.clientProvider.K8sClientSetForRuntimeID(instance.RuntimeID)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("while creating a runtime client for binding creation: %v", err)
+	}
+
+	err = bindRole(ctx, clientset, binding, rbacv1.Subject{
+		Kind:     rbacv1.GroupKind,
+		APIGroup: rbacv1.GroupName,
+		Name:     binding.Group,
+	}, OIDCClusterRoleName)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	kubeconfigContent, err := c.kubeconfigBuilder.Build(instance)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("while creating an OIDC kubeconfig: %v", err)
+	}
+
+	// the kubeconfig holds no token, the access is revoked when the expired binding and its cluster role binding are removed
+	return kubeconfigContent, time.Now().Add(time.Duration(binding.ExpirationSeconds) * time.Second), nil
+}
+
+func (c *OIDCBindingsManager) Delete(ctx context.Context, instance *internal.Instance, binding *internal.Binding) error {
+	clientset, err := c.clientProvider.K8sClientSetForRuntimeID(instance.RuntimeID)
+	if err != nil {
+		return fmt.Errorf("while creating a runtime client for binding removal: %v", err)
+	}
+
+	return unbindRole(ctx, clientset, binding)
+}
+
+// Revoke does nothing, the OIDC kubeconfig holds no token and the access of the group is granted until the binding is removed
+func (c *OIDCBindingsManager) Revoke(_ context.Context, _ *internal.Instance, _ *internal.Binding) error {
+	return nil
+}
+
+// CredentialsTypeBindingsManager passes the bindings to the manager of their credentials type,
+// the bindings without the credentials type use service accounts
+type CredentialsTypeBindingsManager struct {
+	managers map[string]BindingsManager
+}
+
+func NewCredentialsTypeBindingsManager(serviceAccount, oidc BindingsManager) *CredentialsTypeBindingsManager {
+	return &CredentialsTypeBindingsManager{
+		managers: map[string]BindingsManager{
+			ServiceAccountCredentialsType: serviceAccount,
+			OIDCCredentialsType:           oidc,
+		},
+	}
+}
+
+func (c *CredentialsTypeBindingsManager) Create(ctx context.Context, instance *internal.Instance, binding *internal.Binding) (string, time.Time, error) {
+	manager, err := c.managerFor(binding)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return manager.Create(ctx, instance, binding)
+}
+
+func (c *CredentialsTypeBindingsManager) Delete(ctx context.Context, instance *internal.Instance, binding *internal.Binding) error {
+	manager, err := c.managerFor(binding)
+	if err != nil {
+		return err
+	}
+	return manager.Delete(ctx, instance, binding)
+}
+
+func (c *CredentialsTypeBindingsManager) Revoke(ctx context.Context, instance *internal.Instance, binding *internal.Binding) error {
+	manager, err := c.managerFor(binding)
+	if err != nil {
+		return err
+	}
+	return manager.Revoke(ctx, instance, binding)
+}
+
+func (c *CredentialsTypeBindingsManager) managerFor(binding *internal.Binding) (BindingsManager, error) {
+	credentialsType := CredentialsType(binding)
+	manager, found := c.managers[credentialsType]
+	if !found || manager == nil {
+		return nil, fmt.Errorf("unsupported credentials type %q", credentialsType)
+	}
+	return manager, nil
+}
+
+// CredentialsType returns the credentials type of the binding, the bindings created before the type was stored use service accounts
+func CredentialsType(binding *internal.Binding) string {
+	if binding.CredentialsType == "" {
+		return ServiceAccountCredentialsType
+	}
+	return binding.CredentialsType
+}
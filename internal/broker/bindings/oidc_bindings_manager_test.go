@@ -0,0 +1,123 @@
+package broker
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kyma-project/kyma-environment-broker/internal"
+	"github.com/kyma-project/kyma-environment-broker/internal/fixture"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	mv1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+)
+
+type fakeClientProvider struct {
+	clientset kubernetes.Interface
+}
+
+func (p *fakeClientProvider) K8sClientSetForRuntimeID(_ string) (kubernetes.Interface, error) {
+	return p.clientset, nil
+}
+
+type fakeOIDCKubeconfigBuilder struct{}
+
+func (b *fakeOIDCKubeconfigBuilder) Build(instance *internal.Instance) (string, error) {
+	return "oidc-kubeconfig-" + instance.RuntimeID, nil
+}
+
+func TestOIDCBindingsManager(t *testing.T) {
+	// given
+	clientset := k8sfake.NewSimpleClientset()
+	manager := NewOIDCBindingsManager(&fakeClientProvider{clientset: clientset}, &fakeOIDCKubeconfigBuilder{})
+	instance := fixture.FixInstance("instance-id")
+	binding := fixture.FixBinding("binding-id")
+	binding.CredentialsType = OIDCCredentialsType
+	binding.Group = "kyma-admins"
+
+	t.Run("should bind the group and return the OIDC kubeconfig", func(t *testing.T) {
+		// when
+		kubeconfig, expiresAt, err := manager.Create(context.Background(), &instance, &binding)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "oidc-kubeconfig-"+instance.RuntimeID, kubeconfig)
+		assert.False(t, expiresAt.IsZero())
+
+		crb, err := clientset.RbacV1().ClusterRoleBindings().Get(context.Background(), BindingName(binding.ID), mv1.GetOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, OIDCClusterRoleName, crb.RoleRef.Name)
+		require.Len(t, crb.Subjects, 1)
+		assert.Equal(t, rbacv1.GroupKind, crb.Subjects[0].Kind)
+		assert.Equal(t, "kyma-admins", crb.Subjects[0].Name)
+	})
+
+	t.Run("should remove the cluster role binding", func(t *testing.T) {
+		// when
+		err := manager.Delete(context.Background(), &instance, &binding)
+
+		// then
+		require.NoError(t, err)
+		_, err = clientset.RbacV1().ClusterRoleBindings().Get(context.Background(), BindingName(binding.ID), mv1.GetOptions{})
+		assert.True(t, apierrors.IsNotFound(err))
+	})
+
+	t.Run("should require the group", func(t *testing.T) {
+		// given
+		withoutGroup := fixture.FixBinding("other-binding-id")
+		withoutGroup.CredentialsType = OIDCCredentialsType
+
+		// when
+		_, _, err := manager.Create(context.Background(), &instance, &withoutGroup)
+
+		// then
+		require.Error(t, err)
+	})
+}
+
+func TestCredentialsTypeBindingsManager(t *testing.T) {
+	// given
+	clientset := k8sfake.NewSimpleClientset()
+	manager := NewCredentialsTypeBindingsManager(nil, NewOIDCBindingsManager(&fakeClientProvider{clientset: clientset}, &fakeOIDCKubeconfigBuilder{}))
+	instance := fixture.FixInstance("instance-id")
+
+	t.Run("should create the OIDC binding", func(t *testing.T) {
+		// given
+		binding := fixture.FixBinding("binding-id")
+		binding.CredentialsType = OIDCCredentialsType
+		binding.Group = "kyma-admins"
+
+		// when
+		kubeconfig, _, err := manager.Create(context.Background(), &instance, &binding)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "oidc-kubeconfig-"+instance.RuntimeID, kubeconfig)
+	})
+
+	t.Run("should reject the unknown credentials type", func(t *testing.T) {
+		// given
+		binding := fixture.FixBinding("binding-id")
+		binding.CredentialsType = "certificate"
+
+		// when
+		_, _, err := manager.Create(context.Background(), &instance, &binding)
+
+		// then
+		require.ErrorContains(t, err, "unsupported credentials type")
+	})
+
+	t.Run("should use service accounts for the bindings without the credentials type", func(t *testing.T) {
+		// given
+		binding := fixture.FixBinding("binding-id")
+
+		// when
+		err := manager.Delete(context.Background(), &instance, &binding)
+
+		// then
+		require.ErrorContains(t, err, `unsupported credentials type "service_account"`)
+	})
+}
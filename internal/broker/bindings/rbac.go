@@ -0,0 +1,140 @@
+package broker
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/kyma-project/kyma-environment-broker/internal"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	mv1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// The role templates which can be requested for a binding
+const (
+	ViewerRole         = "viewer"
+	EditorRole         = "editor"
+	NamespaceAdminRole = "namespace-admin"
+)
+
+// RoleTemplates maps the role templates to the default user-facing cluster roles of Kubernetes
+var RoleTemplates = map[string]string{
+	ViewerRole:         "view",
+	EditorRole:         "edit",
+	NamespaceAdminRole: "admin",
+}
+
+// bindRole grants the subject the cluster role of the binding role template, or the given default cluster role for the bindings without a role.
+// The binding limited to namespaces gets a role binding in each of them instead of the cluster role binding.
+func bindRole(ctx context.Context, clientset kubernetes.Interface, binding *internal.Binding, subject rbacv1.Subject, defaultClusterRole string) error {
+	clusterRole := defaultClusterRole
+	if binding.Role != "" {
+		template, found := RoleTemplates[binding.Role]
+		if !found {
+			return fmt.Errorf("unknown role %q", binding.Role)
+		}
+		clusterRole = template
+	}
+	roleRef := rbacv1.RoleRef{
+		APIGroup: rbacv1.GroupName,
+		Kind:     "ClusterRole",
+		Name:     clusterRole,
+	}
+
+	if len(binding.Namespaces) == 0 {
+		err := createClusterRoleBinding(ctx, clientset, &rbacv1.ClusterRoleBinding{
+			TypeMeta: mv1.TypeMeta{APIVersion: rbacv1.SchemeGroupVersion.String(), Kind: "ClusterRoleBinding"},
+			ObjectMeta: mv1.ObjectMeta{
+				Name:   BindingName(binding.ID),
+				Labels: map[string]string{"app.kubernetes.io/managed-by": "kcp-kyma-environment-broker"},
+			},
+			RoleRef:  roleRef,
+			Subjects: []rbacv1.Subject{subject},
+		})
+		if err != nil {
+			return fmt.Errorf("while creating a cluster role binding: %v", err)
+		}
+		return nil
+	}
+
+	for _, namespace := range binding.Namespaces {
+		err := createRoleBinding(ctx, clientset, &rbacv1.RoleBinding{
+			TypeMeta: mv1.TypeMeta{APIVersion: rbacv1.SchemeGroupVersion.String(), Kind: "RoleBinding"},
+			ObjectMeta: mv1.ObjectMeta{
+				Name:      BindingName(binding.ID),
+				Namespace: namespace,
+				Labels:    map[string]string{"app.kubernetes.io/managed-by": "kcp-kyma-environment-broker"},
+			},
+			RoleRef:  roleRef,
+			Subjects: []rbacv1.Subject{subject},
+		})
+		if err != nil {
+			return fmt.Errorf("while creating a role binding in namespace %s: %v", namespace, err)
+		}
+	}
+	return nil
+}
+
+// createClusterRoleBinding creates the cluster role binding. The existing one is kept only if it grants the same role to the same subjects,
+// otherwise it is a leftover of another binding and it is recreated, because the role of the binding cannot be changed.
+func createClusterRoleBinding(ctx context.Context, clientset kubernetes.Interface, crb *rbacv1.ClusterRoleBinding) error {
+	client := clientset.RbacV1().ClusterRoleBindings()
+	_, err := client.Create(ctx, crb, mv1.CreateOptions{})
+	if !apierrors.IsAlreadyExists(err) {
+		return err
+	}
+	existing, err := client.Get(ctx, crb.Name, mv1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	if reflect.DeepEqual(existing.RoleRef, crb.RoleRef) && reflect.DeepEqual(existing.Subjects, crb.Subjects) {
+		return nil
+	}
+	if err := client.Delete(ctx, crb.Name, mv1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	_, err = client.Create(ctx, crb, mv1.CreateOptions{})
+	return err
+}
+
+// createRoleBinding creates the role binding, the existing one with a different role or subjects is recreated like in createClusterRoleBinding
+func createRoleBinding(ctx context.Context, clientset kubernetes.Interface, rb *rbacv1.RoleBinding) error {
+	client := clientset.RbacV1().RoleBindings(rb.Namespace)
+	_, err := client.Create(ctx, rb, mv1.CreateOptions{})
+	if !apierrors.IsAlreadyExists(err) {
+		return err
+	}
+	existing, err := client.Get(ctx, rb.Name, mv1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	if reflect.DeepEqual(existing.RoleRef, rb.RoleRef) && reflect.DeepEqual(existing.Subjects, rb.Subjects) {
+		return nil
+	}
+	if err := client.Delete(ctx, rb.Name, mv1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	_, err = client.Create(ctx, rb, mv1.CreateOptions{})
+	return err
+}
+
+// unbindRole removes the cluster role binding or the role bindings created by bindRole
+func unbindRole(ctx context.Context, clientset kubernetes.Interface, binding *internal.Binding) error {
+	if len(binding.Namespaces) == 0 {
+		err := clientset.RbacV1().ClusterRoleBindings().Delete(ctx, BindingName(binding.ID), mv1.DeleteOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("while removing a cluster role binding: %v", err)
+		}
+		return nil
+	}
+
+	for _, namespace := range binding.Namespaces {
+		err := clientset.RbacV1().RoleBindings(namespace).Delete(ctx, BindingName(binding.ID), mv1.DeleteOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("while removing a role binding in namespace %s: %v", namespace, err)
+		}
+	}
+	return nil
+}
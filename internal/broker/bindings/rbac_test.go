@@ -0,0 +1,124 @@
+package broker
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kyma-project/kyma-environment-broker/internal/fixture"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	mv1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestBindRole(t *testing.T) {
+	subject := rbacv1.Subject{Kind: rbacv1.GroupKind, APIGroup: rbacv1.GroupName, Name: "kyma-developers"}
+
+	t.Run("should bind the cluster role of the role template", func(t *testing.T) {
+		// given
+		clientset := k8sfake.NewSimpleClientset()
+		binding := fixture.FixBinding("binding-id")
+		binding.Role = ViewerRole
+
+		// when
+		err := bindRole(context.Background(), clientset, &binding, subject, OIDCClusterRoleName)
+
+		// then
+		require.NoError(t, err)
+		crb, err := clientset.RbacV1().ClusterRoleBindings().Get(context.Background(), BindingName(binding.ID), mv1.GetOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, "view", crb.RoleRef.Name)
+	})
+
+	t.Run("should bind the role in the namespaces of the binding", func(t *testing.T) {
+		// given
+		clientset := k8sfake.NewSimpleClientset()
+		binding := fixture.FixBinding("binding-id")
+		binding.Role = NamespaceAdminRole
+		binding.Namespaces = []string{"team-a", "team-b"}
+
+		// when
+		err := bindRole(context.Background(), clientset, &binding, subject, OIDCClusterRoleName)
+
+		// then
+		require.NoError(t, err)
+		for _, namespace := range binding.Namespaces {
+			rb, err := clientset.RbacV1().RoleBindings(namespace).Get(context.Background(), BindingName(binding.ID), mv1.GetOptions{})
+			require.NoError(t, err)
+			assert.Equal(t, "admin", rb.RoleRef.Name)
+			assert.Equal(t, []rbacv1.Subject{subject}, rb.Subjects)
+		}
+		_, err = clientset.RbacV1().ClusterRoleBindings().Get(context.Background(), BindingName(binding.ID), mv1.GetOptions{})
+		assert.True(t, apierrors.IsNotFound(err))
+
+		// when
+		err = unbindRole(context.Background(), clientset, &binding)
+
+		// then
+		require.NoError(t, err)
+		for _, namespace := range binding.Namespaces {
+			_, err := clientset.RbacV1().RoleBindings(namespace).Get(context.Background(), BindingName(binding.ID), mv1.GetOptions{})
+			assert.True(t, apierrors.IsNotFound(err))
+		}
+	})
+
+	t.Run("should bind the default cluster role in the namespaces of the binding without a role", func(t *testing.T) {
+		// given
+		clientset := k8sfake.NewSimpleClientset()
+		binding := fixture.FixBinding("binding-id")
+		binding.Namespaces = []string{"team-a"}
+
+		// when
+		err := bindRole(context.Background(), clientset, &binding, subject, OIDCClusterRoleName)
+
+		// then
+		require.NoError(t, err)
+		rb, err := clientset.RbacV1().RoleBindings("team-a").Get(context.Background(), BindingName(binding.ID), mv1.GetOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, OIDCClusterRoleName, rb.RoleRef.Name)
+	})
+
+	t.Run("should recreate the leftover cluster role binding with a different role and subject", func(t *testing.T) {
+		// given
+		binding := fixture.FixBinding("binding-id")
+		binding.Role = ViewerRole
+		clientset := k8sfake.NewSimpleClientset(&rbacv1.ClusterRoleBinding{
+			ObjectMeta: mv1.ObjectMeta{Name: BindingName(binding.ID)},
+			RoleRef:    rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "ClusterRole", Name: "cluster-admin"},
+			Subjects:   []rbacv1.Subject{{Kind: rbacv1.GroupKind, APIGroup: rbacv1.GroupName, Name: "other-group"}},
+		})
+
+		// when
+		err := bindRole(context.Background(), clientset, &binding, subject, OIDCClusterRoleName)
+
+		// then
+		require.NoError(t, err)
+		crb, err := clientset.RbacV1().ClusterRoleBindings().Get(context.Background(), BindingName(binding.ID), mv1.GetOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, "view", crb.RoleRef.Name)
+		assert.Equal(t, []rbacv1.Subject{subject}, crb.Subjects)
+	})
+
+	t.Run("should recreate the leftover role binding with a different role", func(t *testing.T) {
+		// given
+		binding := fixture.FixBinding("binding-id")
+		binding.Role = ViewerRole
+		binding.Namespaces = []string{"team-a"}
+		clientset := k8sfake.NewSimpleClientset(&rbacv1.RoleBinding{
+			ObjectMeta: mv1.ObjectMeta{Name: BindingName(binding.ID), Namespace: "team-a"},
+			RoleRef:    rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "ClusterRole", Name: "admin"},
+			Subjects:   []rbacv1.Subject{subject},
+		})
+
+		// when
+		err := bindRole(context.Background(), clientset, &binding, subject, OIDCClusterRoleName)
+
+		// then
+		require.NoError(t, err)
+		rb, err := clientset.RbacV1().RoleBindings("team-a").Get(context.Background(), BindingName(binding.ID), mv1.GetOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, "view", rb.RoleRef.Name)
+	})
+}
@@ -0,0 +1,209 @@
+package broker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/kyma-project/kyma-environment-broker/common/hyperscaler/rules"
+	pkg "github.com/kyma-project/kyma-environment-broker/common/runtime"
+	"github.com/kyma-project/kyma-environment-broker/internal"
+	"github.com/kyma-project/kyma-environment-broker/internal/dashboard"
+	"github.com/kyma-project/kyma-environment-broker/internal/hyperscalers/aws"
+	"github.com/kyma-project/kyma-environment-broker/internal/ptr"
+
+	"github.com/google/uuid"
+	"github.com/pivotal-cf/brokerapi/v12/domain"
+	"github.com/pivotal-cf/brokerapi/v12/domain/apiresponses"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// RuntimeResourceRenderer renders the Runtime resource which the provisioning process creates for the operation
+type RuntimeResourceRenderer interface {
+	RenderRuntimeResource(operation internal.Operation, log *slog.Logger) (client.Object, error)
+}
+
+const (
+	DryRunAttribute                 = "dryRun"
+	ProvisioningParametersAttribute = "provisioningParameters"
+	ProviderValuesAttribute         = "providerValues"
+	HyperscalerRuleAttribute        = "hyperscalerRule"
+	RuntimeResourceAttribute        = "runtime"
+	UpdatedParametersAttribute      = "updatedParameters"
+)
+
+// UseRuntimeResourceRenderer enables rendering of the Runtime resource in the responses to the dry run requests
+func (b *ProvisionEndpoint) UseRuntimeResourceRenderer(renderer RuntimeResourceRenderer) {
+	b.runtimeRenderer = renderer
+}
+
+// UseRuntimeResourceRenderer enables rendering of the Runtime resource in the responses to the dry run requests
+func (b *UpdateEndpoint) UseRuntimeResourceRenderer(renderer RuntimeResourceRenderer) {
+	b.runtimeRenderer = renderer
+}
+
+// dryRun returns the resolved parameters and the Runtime resource of the validated provisioning request, nothing is stored
+func (b *ProvisionEndpoint) dryRun(ctx context.Context, operation internal.ProvisioningOperation, logger *slog.Logger) (domain.ProvisionedServiceSpec, error) {
+	logger.Info("Dry run, the instance is not created")
+
+	attributes := map[string]any{
+		DryRunAttribute:                 true,
+		ProvisioningParametersAttribute: dryRunParameters(operation.ProvisioningParameters),
+		ProviderValuesAttribute:         operation.ProviderValues,
+	}
+
+	if b.rulesService != nil {
+		rule, err := matchHyperscalerRule(b.rulesService, operation.ProvisioningParameters, *operation.ProviderValues)
+		if err != nil {
+			return domain.ProvisionedServiceSpec{}, err
+		}
+		attributes[HyperscalerRuleAttribute] = rule
+	}
+
+	if b.runtimeRenderer != nil {
+		// the values below are set by the provisioning steps, the subscription is assigned when the runtime is provisioned
+		operation.RuntimeID = uuid.New().String()
+		operation.KymaResourceName = strings.ToLower(operation.RuntimeID)
+		operation.KymaResourceNamespace = KcpNamespace
+		operation.ProvisioningParameters.Parameters.TargetSecret = ptr.String("")
+
+		if b.providerSpec.ZonesDiscovery(pkg.CloudProviderFromString(operation.ProviderValues.ProviderType)) {
+			zones, err := b.discoverZones(ctx, operation, logger)
+			if err != nil {
+				logger.Error(fmt.Sprintf("unable to discover available zones: %s", err))
+				return domain.ProvisionedServiceSpec{}, apiresponses.NewFailureResponse(errors.New(FailedToValidateZonesMsg), http.StatusUnprocessableEntity, FailedToValidateZonesMsg)
+			}
+			operation.DiscoveredZones = zones
+		}
+
+		runtime, err := b.runtimeRenderer.RenderRuntimeResource(operation.Operation, logger)
+		if err != nil {
+			message := fmt.Sprintf("unable to render the Runtime resource: %s", err)
+			return domain.ProvisionedServiceSpec{}, apiresponses.NewFailureResponse(errors.New(message), http.StatusUnprocessableEntity, message)
+		}
+		attributes[RuntimeResourceAttribute] = runtime
+	}
+
+	// AlreadyExists makes the response status 200 OK, the dry run does not create anything
+	return domain.ProvisionedServiceSpec{
+		AlreadyExists: true,
+		Metadata: domain.InstanceMetadata{
+			Attributes: attributes,
+		},
+	}, nil
+}
+
+func (b *ProvisionEndpoint) discoverZones(ctx context.Context, operation internal.ProvisioningOperation, logger *slog.Logger) (map[string][]string, error) {
+	values := *operation.ProviderValues
+	var awsClient aws.Client
+	var err error
+	if b.useCredentialsBindings {
+		awsClient, err = newAWSClientUsingCredentialsBinding(ctx, logger, b.rulesService, b.gardenerClient, b.awsClientFactory, operation.ProvisioningParameters, values)
+	} else {
+		awsClient, err = newAWSClient(ctx, logger, b.rulesService, b.gardenerClient, b.awsClientFactory, operation.ProvisioningParameters, values)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	kymaMachineType := values.DefaultMachineType
+	if operation.ProvisioningParameters.Parameters.MachineType != nil {
+		kymaMachineType = *operation.ProvisioningParameters.Parameters.MachineType
+	}
+	discoveredZones := map[string][]string{kymaMachineType: nil}
+	for _, pool := range operation.ProvisioningParameters.Parameters.AdditionalWorkerNodePools {
+		discoveredZones[pool.MachineType] = nil
+	}
+	for machineType := range discoveredZones {
+		zones, err := awsClient.AvailableZones(ctx, machineType)
+		if err != nil {
+			return nil, fmt.Errorf("while getting available zones for machine type %s: %w", machineType, err)
+		}
+		discoveredZones[machineType] = zones
+	}
+	return discoveredZones, nil
+}
+
+// dryRun returns the parameters of the instance after applying the validated update request and the Runtime resource rendered for them, nothing is stored
+func (b *UpdateEndpoint) dryRun(instance *internal.Instance, lastProvisioningOperation *internal.ProvisioningOperation, updated []string, logger *slog.Logger) (domain.UpdateServiceSpec, error) {
+	logger.Info("Dry run, the instance is not updated")
+
+	if updated == nil {
+		updated = []string{}
+	}
+	attributes := map[string]any{
+		DryRunAttribute:                 true,
+		ProvisioningParametersAttribute: dryRunParameters(instance.Parameters),
+		UpdatedParametersAttribute:      updated,
+	}
+
+	providerValues, err := b.valuesProvider.ValuesForPlanAndParameters(instance.Parameters)
+	if err != nil {
+		logger.Error(fmt.Sprintf("unable to obtain provider values: %s", err))
+		return domain.UpdateServiceSpec{}, fmt.Errorf("unable to process the request")
+	}
+	attributes[ProviderValuesAttribute] = providerValues
+
+	if b.rulesService != nil {
+		rule, err := matchHyperscalerRule(b.rulesService, instance.Parameters, providerValues)
+		if err != nil {
+			return domain.UpdateServiceSpec{}, err
+		}
+		attributes[HyperscalerRuleAttribute] = rule
+	}
+
+	if b.runtimeRenderer != nil && lastProvisioningOperation != nil {
+		// the Runtime resource is rendered by the same step as for the provisioning dry run, from the operation which provisioned the instance
+		operation := lastProvisioningOperation.Operation
+		operation.ProvisioningParameters = instance.Parameters
+		operation.ProvisioningParameters.Parameters.TargetSecret = ptr.String(instance.SubscriptionSecretName)
+		operation.ProviderValues = &providerValues
+		operation.RuntimeID = instance.RuntimeID
+		if operation.KymaResourceNamespace == "" {
+			operation.KymaResourceNamespace = KcpNamespace
+		}
+
+		runtime, err := b.runtimeRenderer.RenderRuntimeResource(operation, logger)
+		if err != nil {
+			message := fmt.Sprintf("unable to render the Runtime resource: %s", err)
+			return domain.UpdateServiceSpec{}, apiresponses.NewFailureResponse(errors.New(message), http.StatusUnprocessableEntity, message)
+		}
+		attributes[RuntimeResourceAttribute] = runtime
+	}
+
+	return domain.UpdateServiceSpec{
+		IsAsync:      false,
+		DashboardURL: dashboard.ProvideURL(instance, lastProvisioningOperation),
+		Metadata: domain.InstanceMetadata{
+			Attributes: attributes,
+		},
+	}, nil
+}
+
+// matchHyperscalerRule returns the hyperscaler account pool rule which the provisioning uses for the parameters
+func matchHyperscalerRule(rulesService *rules.RulesService, parameters internal.ProvisioningParameters, values internal.ProviderValues) (string, error) {
+	attr := &rules.ProvisioningAttributes{
+		Plan:              AvailablePlans.GetPlanNameOrEmpty(PlanIDType(parameters.PlanID)),
+		PlatformRegion:    parameters.PlatformRegion,
+		HyperscalerRegion: values.Region,
+		Hyperscaler:       values.ProviderType,
+	}
+	result, found := rulesService.MatchProvisioningAttributesWithValidRuleset(attr)
+	if !found {
+		message := fmt.Sprintf("no hyperscaler account pool rule matches the provisioning attributes %q", attr)
+		return "", apiresponses.NewFailureResponse(errors.New(message), http.StatusUnprocessableEntity, message)
+	}
+	return result.Rule(), nil
+}
+
+// dryRunParameters hides the credentials which must not be returned in the response
+func dryRunParameters(parameters internal.ProvisioningParameters) internal.ProvisioningParameters {
+	parameters.ErsContext.SMOperatorCredentials = nil
+	if parameters.Parameters.Kubeconfig != "" {
+		parameters.Parameters.Kubeconfig = maskedKubeconfig
+	}
+	return parameters
+}
@@ -0,0 +1,232 @@
+package broker_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/kyma-project/kyma-environment-broker/internal"
+	"github.com/kyma-project/kyma-environment-broker/internal/blocklist"
+	"github.com/kyma-project/kyma-environment-broker/internal/broker"
+	"github.com/kyma-project/kyma-environment-broker/internal/broker/automock"
+	kcMock "github.com/kyma-project/kyma-environment-broker/internal/kubeconfig/automock"
+	"github.com/kyma-project/kyma-environment-broker/internal/middleware"
+	"github.com/kyma-project/kyma-environment-broker/internal/ptr"
+	"github.com/kyma-project/kyma-environment-broker/internal/storage"
+	"github.com/kyma-project/kyma-environment-broker/internal/storage/dberr"
+
+	"github.com/pivotal-cf/brokerapi/v12/domain"
+	"github.com/pivotal-cf/brokerapi/v12/domain/apiresponses"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestProvision_DryRun(t *testing.T) {
+	// given
+	memoryStorage := storage.NewMemoryStorage()
+	queue := &automock.Queue{}
+	kcBuilder := &kcMock.KcBuilder{}
+	kcBuilder.On("GetServerURL", "").Return("", fmt.Errorf("error"))
+	renderer := &fakeRuntimeRenderer{}
+
+	provisionEndpoint := broker.NewFakeProvisionEndpointBuilder().
+		WithConfig(broker.Config{
+			EnablePlans:          []string{"gcp", "azure"},
+			URL:                  brokerURL,
+			OnlySingleTrialPerGA: true}).
+		WithGardenerConfig(fixGardenerConfig()).
+		WithInfrastructureManager(imConfigFixture).
+		WithStorage(memoryStorage).
+		WithQueue(queue).
+		WithLogger(fixLogger()).
+		WithDashboardConfig(dashboardConfig).
+		WithKubeconfigBuilder(kcBuilder).
+		WithSchemaService(newSchemaService(t)).
+		WithConfigurationProvider(newProviderSpec(t)).
+		WithValuesProvider(fixValueProvider(t)).
+		Build()
+	provisionEndpoint.UseRuntimeResourceRenderer(renderer)
+
+	t.Run("should return the resolved parameters without creating the instance", func(t *testing.T) {
+		// when
+		response, err := provisionEndpoint.Provision(middleware.AddDryRunToCtx(fixRequestContext(t, "req-region")), instanceID, domain.ProvisionDetails{
+			ServiceID:     serviceID,
+			PlanID:        planID,
+			RawParameters: json.RawMessage(fmt.Sprintf(`{"name": "%s", "region": "%s"}`, clusterName, clusterRegion)),
+			RawContext:    json.RawMessage(fmt.Sprintf(`{"globalaccount_id": "%s", "subaccount_id": "%s", "user_id": "%s"}`, globalAccountID, subAccountID, userID)),
+		}, true)
+
+		// then
+		require.NoError(t, err)
+		assert.True(t, response.AlreadyExists)
+		assert.Empty(t, response.OperationData)
+		assert.Equal(t, true, response.Metadata.Attributes[broker.DryRunAttribute])
+
+		parameters, ok := response.Metadata.Attributes[broker.ProvisioningParametersAttribute].(internal.ProvisioningParameters)
+		require.True(t, ok)
+		assert.Equal(t, clusterName, parameters.Parameters.Name)
+		assert.Equal(t, "req-region", parameters.PlatformRegion)
+		assert.Nil(t, parameters.Parameters.TargetSecret)
+
+		runtime, ok := response.Metadata.Attributes[broker.RuntimeResourceAttribute].(client.Object)
+		require.True(t, ok)
+		assert.Equal(t, strings.ToLower(renderer.operation.RuntimeID), runtime.GetName())
+		assert.Equal(t, broker.KcpNamespace, runtime.GetNamespace())
+		assert.Equal(t, clusterRegion, renderer.operation.ProviderValues.Region)
+
+		_, err = memoryStorage.Instances().GetByID(instanceID)
+		assert.True(t, dberr.IsNotFound(err))
+		_, err = memoryStorage.Operations().GetProvisioningOperationByInstanceID(instanceID)
+		assert.True(t, dberr.IsNotFound(err))
+		queue.AssertNotCalled(t, "Add", mock.Anything)
+	})
+
+	t.Run("should validate the parameters", func(t *testing.T) {
+		// when
+		_, err := provisionEndpoint.Provision(middleware.AddDryRunToCtx(fixRequestContext(t, "req-region")), instanceID, domain.ProvisionDetails{
+			ServiceID:     serviceID,
+			PlanID:        broker.AWSPlanID,
+			RawParameters: json.RawMessage(fmt.Sprintf(`{"name": "%s", "region": "%s"}`, clusterName, clusterRegion)),
+			RawContext:    json.RawMessage(fmt.Sprintf(`{"globalaccount_id": "%s", "subaccount_id": "%s", "user_id": "%s"}`, globalAccountID, subAccountID, userID)),
+		}, true)
+
+		// then
+		require.Error(t, err)
+		_, err = memoryStorage.Instances().GetByID(instanceID)
+		assert.True(t, dberr.IsNotFound(err))
+	})
+}
+
+func TestUpdate_DryRun(t *testing.T) {
+	// given
+	instance := internal.Instance{
+		InstanceID:    instanceID,
+		RuntimeID:     "Runtime-ID",
+		ServicePlanID: broker.AWSPlanID,
+		Parameters: internal.ProvisioningParameters{
+			PlanID: broker.AWSPlanID,
+			ErsContext: internal.ERSContext{
+				Active: ptr.Bool(true),
+			},
+		},
+	}
+	st := storage.NewMemoryStorage()
+	require.NoError(t, st.Instances().Insert(instance))
+	provisioning := fixProvisioningOperation("01")
+	provisioning.ProviderValues = &internal.ProviderValues{
+		ProviderType: "aws",
+	}
+	require.NoError(t, st.Operations().InsertProvisioningOperation(provisioning))
+
+	q := &automock.Queue{}
+	kcBuilder := &kcMock.KcBuilder{}
+	svc := broker.NewUpdate(broker.Config{}, st, &handler{}, true, false, true, q, broker.PlansConfig{},
+		fixValueProvider(t), fixLogger(), dashboardConfig, kcBuilder,
+		fakeKcpK8sClient, newProviderSpec(t), newPlanSpec(t), imConfigFixture, newSchemaService(t), nil, nil, nil, nil, nil, nil, blocklist.OperationBlocklist{})
+	renderer := &fakeRuntimeRenderer{}
+	svc.UseRuntimeResourceRenderer(renderer)
+
+	t.Run("should return the updated parameters without updating the instance", func(t *testing.T) {
+		// when
+		response, err := svc.Update(middleware.AddDryRunToCtx(context.Background()), instanceID, domain.UpdateDetails{
+			PlanID:        broker.AWSPlanID,
+			RawParameters: json.RawMessage(`{"autoScalerMin": 4, "autoScalerMax": 5}`),
+			RawContext:    json.RawMessage(`{"active":true}`),
+		}, false)
+
+		// then
+		require.NoError(t, err)
+		assert.False(t, response.IsAsync)
+		assert.Equal(t, true, response.Metadata.Attributes[broker.DryRunAttribute])
+		assert.Equal(t, []string{"Auto Scaler parameters"}, response.Metadata.Attributes[broker.UpdatedParametersAttribute])
+		parameters, ok := response.Metadata.Attributes[broker.ProvisioningParametersAttribute].(internal.ProvisioningParameters)
+		require.True(t, ok)
+		assert.Equal(t, ptr.Integer(4), parameters.Parameters.AutoScalerMin)
+
+		runtime, ok := response.Metadata.Attributes[broker.RuntimeResourceAttribute].(client.Object)
+		require.True(t, ok)
+		assert.Equal(t, "runtime-id", runtime.GetName())
+		assert.Equal(t, provisioning.ID, renderer.operation.ID)
+		assert.Equal(t, ptr.Integer(4), renderer.operation.ProvisioningParameters.Parameters.AutoScalerMin)
+		assert.Equal(t, ptr.Integer(5), renderer.operation.ProvisioningParameters.Parameters.AutoScalerMax)
+
+		stored, err := st.Instances().GetByID(instanceID)
+		require.NoError(t, err)
+		assert.Nil(t, stored.Parameters.Parameters.AutoScalerMin)
+		q.AssertNotCalled(t, "Add", mock.Anything)
+	})
+
+	t.Run("should apply the context without storing the instance", func(t *testing.T) {
+		// when
+		response, err := svc.Update(middleware.AddDryRunToCtx(context.Background()), instanceID, domain.UpdateDetails{
+			PlanID:        broker.AWSPlanID,
+			RawParameters: json.RawMessage(`{"autoScalerMin": 4, "autoScalerMax": 5}`),
+			RawContext:    json.RawMessage(`{"active":true,"license_type":"CUSTOMER"}`),
+		}, false)
+
+		// then
+		require.NoError(t, err)
+		parameters, ok := response.Metadata.Attributes[broker.ProvisioningParametersAttribute].(internal.ProvisioningParameters)
+		require.True(t, ok)
+		assert.Equal(t, ptr.String("CUSTOMER"), parameters.ErsContext.LicenseType)
+
+		stored, err := st.Instances().GetByID(instanceID)
+		require.NoError(t, err)
+		assert.Nil(t, stored.Parameters.ErsContext.LicenseType)
+	})
+
+	t.Run("should not save the request with additional properties", func(t *testing.T) {
+		// given
+		tempDir := t.TempDir()
+		monitoringSvc := broker.NewUpdate(broker.Config{MonitorAdditionalProperties: true, AdditionalPropertiesPath: tempDir}, st, &handler{}, true, false, true, q, broker.PlansConfig{},
+			fixValueProvider(t), fixLogger(), dashboardConfig, kcBuilder,
+			fakeKcpK8sClient, newProviderSpec(t), newPlanSpec(t), imConfigFixture, newSchemaService(t), nil, nil, nil, nil, nil, nil, blocklist.OperationBlocklist{})
+
+		// when
+		_, _ = monitoringSvc.Update(middleware.AddDryRunToCtx(context.Background()), instanceID, domain.UpdateDetails{
+			PlanID:        broker.AWSPlanID,
+			RawParameters: json.RawMessage(`{"autoScalerMin": 4, "additionalProperty": "value"}`),
+			RawContext:    json.RawMessage(`{"active":true}`),
+		}, false)
+
+		// then
+		entries, err := os.ReadDir(tempDir)
+		require.NoError(t, err)
+		assert.Empty(t, entries)
+	})
+
+	t.Run("should validate the parameters", func(t *testing.T) {
+		// when
+		_, err := svc.Update(middleware.AddDryRunToCtx(context.Background()), instanceID, domain.UpdateDetails{
+			PlanID:        broker.AWSPlanID,
+			RawParameters: json.RawMessage(`{"autoScalerMin": 4, "autoScalerMax": 3}`),
+			RawContext:    json.RawMessage(`{"active":true}`),
+		}, false)
+
+		// then
+		require.Error(t, err)
+		apierr, ok := err.(*apiresponses.FailureResponse)
+		require.True(t, ok)
+		assert.Equal(t, http.StatusBadRequest, apierr.ValidatedStatusCode(nil))
+	})
+}
+
+type fakeRuntimeRenderer struct {
+	operation internal.Operation
+}
+
+func (r *fakeRuntimeRenderer) RenderRuntimeResource(operation internal.Operation, _ *slog.Logger) (client.Object, error) {
+	r.operation = operation
+	runtime := &unstructured.Unstructured{}
+	runtime.SetName(strings.ToLower(operation.RuntimeID))
+	runtime.SetNamespace(operation.KymaResourceNamespace)
+	return runtime, nil
+}
@@ -105,6 +105,7 @@ type ProvisionEndpoint struct {
 	awsClientFactory       aws.ClientFactory
 	useCredentialsBindings bool
 	operationBlocklist     blocklist.OperationBlocklist
+	runtimeRenderer        RuntimeResourceRenderer
 }
 
 const (
@@ -214,7 +215,7 @@ func (b *ProvisionEndpoint) Provision(ctx context.Context, instanceID string, de
 		PlatformProvider: platformProvider,
 	}
 	// TODO: remove once we implemented proper filtering of parameters - removing parameters that are not supported by the plan
-	if details.PlanID == TrialPlanID {
+	if IsTrialPlan(details.PlanID) {
 		provisioningParameters.Parameters.MachineType = nil
 		provisioningParameters.Parameters.AutoScalerMin = nil
 		provisioningParameters.Parameters.AutoScalerMax = nil
@@ -240,13 +241,15 @@ func (b *ProvisionEndpoint) Provision(ctx context.Context, instanceID string, de
 		valueOfBoolPtr(parameters.ColocateControlPlane), valueOfPtr(parameters.MachineType)))
 	logParametersWithMaskedKubeconfig(parameters, logger)
 
+	dryRun := middleware.DryRunFromContext(ctx)
+
 	// check if operation with instance ID already created
 	existingOperation, errStorage := b.operationsStorage.GetProvisioningOperationByInstanceID(instanceID)
 	switch {
 	case errStorage != nil && !dberr.IsNotFound(errStorage):
 		logger.Error(fmt.Sprintf("cannot get existing operation from storage %s", errStorage))
 		return domain.ProvisionedServiceSpec{}, fmt.Errorf("cannot get existing operation from storage")
-	case existingOperation != nil && !dberr.IsNotFound(errStorage):
+	case existingOperation != nil && !dberr.IsNotFound(errStorage) && !dryRun:
 		return b.handleExistingOperation(existingOperation, provisioningParameters)
 	}
 
@@ -269,6 +272,10 @@ func (b *ProvisionEndpoint) Provision(ctx context.Context, instanceID string, de
 	operation.DashboardURL = dashboardURL
 	logger.Info(fmt.Sprintf("Runtime ShootDomain: %s", operation.ShootDomain))
 
+	if dryRun {
+		return b.dryRun(ctx, operation, logger)
+	}
+
 	err = b.operationsStorage.InsertOperation(operation.Operation)
 	if err != nil {
 		logger.Error(fmt.Sprintf("cannot save operation: %s", err))
@@ -503,7 +510,7 @@ func (b *ProvisionEndpoint) validateGvisorAccess(parameters pkg.ProvisioningPara
 
 func (b *ProvisionEndpoint) validateFreePlanConstraints(details domain.ProvisionDetails, provisioningParameters internal.ProvisioningParameters, logger *slog.Logger) error {
 	if IsFreemiumPlan(details.PlanID) && b.config.OnlyOneFreePerGA && whitelist.IsNotWhitelisted(provisioningParameters.ErsContext.GlobalAccountID, b.freemiumWhiteList) {
-		count, err := b.instanceArchivedStorage.TotalNumberOfInstancesArchivedForGlobalAccountID(provisioningParameters.ErsContext.GlobalAccountID, FreemiumPlanID)
+		count, err := b.instanceArchivedStorage.TotalNumberOfInstancesArchivedForGlobalAccountID(provisioningParameters.ErsContext.GlobalAccountID, details.PlanID)
 		if err != nil {
 			return fmt.Errorf("while checking if a free Kyma instance existed for given global account: %w", err)
 		}
@@ -514,7 +521,7 @@ func (b *ProvisionEndpoint) validateFreePlanConstraints(details domain.Provision
 
 		instanceFilter := dbmodel.InstanceFilter{
 			GlobalAccountIDs: []string{provisioningParameters.ErsContext.GlobalAccountID},
-			PlanIDs:          []string{FreemiumPlanID},
+			PlanIDs:          []string{details.PlanID},
 			States:           []dbmodel.InstanceState{dbmodel.InstanceSucceeded},
 		}
 		_, _, count, err = b.instanceStorage.List(instanceFilter)
@@ -639,16 +646,7 @@ func isEuRestrictedAccess(ctx context.Context) bool {
 }
 
 func supportsAdditionalWorkerNodePools(planID string) bool {
-	var unsupportedPlans = []string{
-		FreemiumPlanID,
-		TrialPlanID,
-	}
-	for _, unsupportedPlan := range unsupportedPlans {
-		if planID == unsupportedPlan {
-			return false
-		}
-	}
-	return true
+	return !IsFreemiumPlan(planID) && !IsTrialPlan(planID)
 }
 
 func AreNamesUnique(pools []pkg.AdditionalWorkerNodePool) bool {
@@ -81,11 +81,11 @@ func (b *GetInstanceEndpoint) GetInstance(_ context.Context, instanceID string,
 		},
 	}
 
-	if instance.ServicePlanID == TrialPlanID {
+	if IsTrialPlan(instance.ServicePlanID) {
 		spec.Metadata.Labels = ResponseLabelsWithExpirationInfo(*instance, b.config.URL, b.config.TrialDocsURL, trialDocsKey, trialExpireDuration, trialExpiryDetailsKey, trialExpiredInfoFormat, b.kcBuilder)
 	}
 
-	if instance.ServicePlanID == FreemiumPlanID {
+	if IsFreemiumPlan(instance.ServicePlanID) {
 		spec.Metadata.Labels = ResponseLabelsWithExpirationInfo(*instance, b.config.URL, b.config.FreeDocsURL, freeDocsKey, b.config.FreeExpirationPeriod, freeExpiryDetailsKey, freeExpiredInfoFormat, b.kcBuilder)
 	}
 
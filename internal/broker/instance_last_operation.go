@@ -100,10 +100,11 @@ func (b *LastOperationEndpoint) responseFromInstanceArchived(instanceID string,
 
 func mapStateToOSBCompliantState(opState domain.LastOperationState) domain.LastOperationState {
 	switch opState {
-	case internal.OperationStatePending, internal.OperationStateRetrying:
+	case internal.OperationStatePending, internal.OperationStateRetrying, internal.OperationStateCanceling:
 		return domain.InProgress
-	case internal.OperationStateCanceled, internal.OperationStateCanceling:
-		return domain.Succeeded
+	case internal.OperationStateCanceled:
+		// the operation was stopped before it finished, the requested changes are not applied
+		return domain.Failed
 	default:
 		return opState
 	}
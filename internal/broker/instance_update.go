@@ -21,6 +21,7 @@ import (
 	"github.com/kyma-project/kyma-environment-broker/internal/dashboard"
 	"github.com/kyma-project/kyma-environment-broker/internal/hyperscalers/aws"
 	"github.com/kyma-project/kyma-environment-broker/internal/kubeconfig"
+	"github.com/kyma-project/kyma-environment-broker/internal/middleware"
 	"github.com/kyma-project/kyma-environment-broker/internal/provider/configuration"
 	"github.com/kyma-project/kyma-environment-broker/internal/ptr"
 	"github.com/kyma-project/kyma-environment-broker/internal/storage"
@@ -79,6 +80,7 @@ type UpdateEndpoint struct {
 	useCredentialsBindings         bool
 	syncEmptyUpdateResponseEnabled bool
 	operationBlocklist             blocklist.OperationBlocklist
+	runtimeRenderer                RuntimeResourceRenderer
 }
 
 func NewUpdate(cfg Config,
@@ -180,7 +182,8 @@ func (b *UpdateEndpoint) update(ctx context.Context, instanceID string, details
 	}
 	logger.Info(fmt.Sprintf("Global account ID: %s active: %s", instance.GlobalAccountID, ptr.BoolAsString(ersContext.Active)))
 	logger.Info(fmt.Sprintf("Received context: %s", marshallRawContext(hideSensitiveDataFromRawContext(details.RawContext))))
-	if b.config.MonitorAdditionalProperties {
+	dryRun := middleware.DryRunFromContext(ctx)
+	if b.config.MonitorAdditionalProperties && !dryRun {
 		b.monitorAdditionalProperties(instanceID, ersContext, details.RawParameters)
 	}
 	// validation of incoming input
@@ -216,6 +219,19 @@ func (b *UpdateEndpoint) update(ctx context.Context, instanceID string, details
 		instance.DashboardURL = fmt.Sprintf("%s/?kubeconfigID=%s", b.dashboardConfig.LandscapeURL, instanceID)
 	}
 
+	if b.processingEnabled && dryRun {
+		previousInstance := *instance
+		suspendStatusChange, err := b.dryRunContext(instance, details, lastProvisioningOperation, logger)
+		if err != nil {
+			return domain.UpdateServiceSpec{}, err
+		}
+		if suspendStatusChange || instance.IsExpired() {
+			// the parameters are ignored in the same way as for a regular update
+			return b.dryRun(instance, lastProvisioningOperation, nil, logger)
+		}
+		return b.processUpdateParameters(ctx, &previousInstance, instance, details, lastProvisioningOperation, asyncAllowed, ersContext, logger)
+	}
+
 	if b.processingEnabled {
 		previousInstance := *instance
 		instance, suspendStatusChange, err := b.processContext(instance, details, lastProvisioningOperation, logger)
@@ -298,8 +314,9 @@ func (b *UpdateEndpoint) processUpdateParameters(ctx context.Context, previousIn
 			},
 		}, nil
 	}
+	dryRun := middleware.DryRunFromContext(ctx)
 	// asyncAllowed needed, see https://github.com/openservicebrokerapi/servicebroker/blob/v2.16/spec.md#updating-a-service-instance
-	if !asyncAllowed {
+	if !asyncAllowed && !dryRun {
 		return domain.UpdateServiceSpec{}, apiresponses.ErrAsyncRequired
 	}
 
@@ -362,6 +379,10 @@ func (b *UpdateEndpoint) processUpdateParameters(ctx context.Context, previousIn
 		return domain.UpdateServiceSpec{}, err
 	}
 
+	if dryRun {
+		return b.dryRun(instance, lastProvisioningOperation, updateStorage, logger)
+	}
+
 	if len(updateStorage) > 0 {
 		instance, err = b.instanceStorage.Update(*instance)
 		if err != nil {
@@ -462,7 +483,7 @@ func (b *UpdateEndpoint) unmarshalParams(details domain.UpdateDetails, logger *s
 	return params, nil
 }
 func (b *UpdateEndpoint) ZeroFieldsForTrialPlan(details domain.UpdateDetails, params *internal.UpdatingParametersDTO) {
-	if details.PlanID == TrialPlanID {
+	if IsTrialPlan(details.PlanID) {
 		params.MachineType = nil
 		params.AutoScalerMin = nil
 		params.AutoScalerMax = nil
@@ -641,32 +662,10 @@ func (b *UpdateEndpoint) UseCredentialsBindings() {
 }
 
 func (b *UpdateEndpoint) processContext(instance *internal.Instance, details domain.UpdateDetails, lastProvisioningOperation *internal.ProvisioningOperation, logger *slog.Logger) (*internal.Instance, bool, error) {
-	var ersContext internal.ERSContext
-	err := json.Unmarshal(details.RawContext, &ersContext)
-	if err != nil {
-		logger.Error(fmt.Sprintf("unable to decode context: %s", err.Error()))
-		return nil, false, fmt.Errorf("unable to unmarshal context")
-	}
-	logger.Info(fmt.Sprintf("Global account ID: %s active: %s", instance.GlobalAccountID, ptr.BoolAsString(ersContext.Active)))
-
-	lastOp, err := b.operationStorage.GetLastOperation(instance.InstanceID)
-	if err != nil {
-		logger.Error(fmt.Sprintf("unable to get last operation: %s", err.Error()))
-		return nil, false, fmt.Errorf("failed to process ERS context")
-	}
-
-	// todo: remove the code below when we are sure the ERSContext contains required values.
-	// This code is done because the PATCH request contains only some of fields and that requests made the ERS context empty in the past.
-	existingSMOperatorCredentials := instance.Parameters.ErsContext.SMOperatorCredentials
-	instance.Parameters.ErsContext = lastProvisioningOperation.ProvisioningParameters.ErsContext
-	// but do not change existing SM operator credentials
-	instance.Parameters.ErsContext.SMOperatorCredentials = existingSMOperatorCredentials
-	instance.Parameters.ErsContext.Active, err = b.extractActiveValue(instance.InstanceID, *lastProvisioningOperation)
+	ersContext, err := b.applyContext(instance, details, lastProvisioningOperation, logger)
 	if err != nil {
-		return nil, false, fmt.Errorf("unable to process the update")
+		return nil, false, err
 	}
-	instance.Parameters.ErsContext = internal.InheritMissingERSContext(instance.Parameters.ErsContext, lastOp.ProvisioningParameters.ErsContext)
-	instance.Parameters.ErsContext = internal.UpdateInstanceERSContext(instance.Parameters.ErsContext, ersContext)
 
 	changed, err := b.contextUpdateHandler.Handle(instance, ersContext)
 	if err != nil {
@@ -701,6 +700,61 @@ func (b *UpdateEndpoint) processContext(instance *internal.Instance, details dom
 	return newInstance, changed, nil
 }
 
+// applyContext sets the context received in the request in the instance, the instance is not stored
+func (b *UpdateEndpoint) applyContext(instance *internal.Instance, details domain.UpdateDetails, lastProvisioningOperation *internal.ProvisioningOperation, logger *slog.Logger) (internal.ERSContext, error) {
+	var ersContext internal.ERSContext
+	err := json.Unmarshal(details.RawContext, &ersContext)
+	if err != nil {
+		logger.Error(fmt.Sprintf("unable to decode context: %s", err.Error()))
+		return ersContext, fmt.Errorf("unable to unmarshal context")
+	}
+	logger.Info(fmt.Sprintf("Global account ID: %s active: %s", instance.GlobalAccountID, ptr.BoolAsString(ersContext.Active)))
+
+	lastOp, err := b.operationStorage.GetLastOperation(instance.InstanceID)
+	if err != nil {
+		logger.Error(fmt.Sprintf("unable to get last operation: %s", err.Error()))
+		return ersContext, fmt.Errorf("failed to process ERS context")
+	}
+
+	// todo: remove the code below when we are sure the ERSContext contains required values.
+	// This code is done because the PATCH request contains only some of fields and that requests made the ERS context empty in the past.
+	existingSMOperatorCredentials := instance.Parameters.ErsContext.SMOperatorCredentials
+	instance.Parameters.ErsContext = lastProvisioningOperation.ProvisioningParameters.ErsContext
+	// but do not change existing SM operator credentials
+	instance.Parameters.ErsContext.SMOperatorCredentials = existingSMOperatorCredentials
+	instance.Parameters.ErsContext.Active, err = b.extractActiveValue(instance.InstanceID, *lastProvisioningOperation)
+	if err != nil {
+		return ersContext, fmt.Errorf("unable to process the update")
+	}
+	instance.Parameters.ErsContext = internal.InheritMissingERSContext(instance.Parameters.ErsContext, lastOp.ProvisioningParameters.ErsContext)
+	instance.Parameters.ErsContext = internal.UpdateInstanceERSContext(instance.Parameters.ErsContext, ersContext)
+
+	return ersContext, nil
+}
+
+// dryRunContext processes the context of the dry run request like processContext, but the suspension is not triggered,
+// and the instance, the action, and the labels are not stored. It returns true if the request changes the suspension state.
+func (b *UpdateEndpoint) dryRunContext(instance *internal.Instance, details domain.UpdateDetails, lastProvisioningOperation *internal.ProvisioningOperation, logger *slog.Logger) (bool, error) {
+	ersContext, err := b.applyContext(instance, details, lastProvisioningOperation, logger)
+	if err != nil {
+		return false, err
+	}
+
+	// the suspension is handled only for the trial instances, see the ContextUpdateHandler
+	active := instance.Parameters.ErsContext.Active == nil || *instance.Parameters.ErsContext.Active
+	suspendStatusChange := IsTrialPlan(instance.ServicePlanID) && ersContext.Active != nil && *ersContext.Active != active
+
+	if ersContext.Active != nil {
+		instance.Parameters.ErsContext.Active = ersContext.Active
+	}
+	if b.subaccountMovementEnabled && (instance.GlobalAccountID != ersContext.GlobalAccountID && ersContext.GlobalAccountID != "") {
+		logger.Info(fmt.Sprintf("Dry run, global account ID would change to: %s", ersContext.GlobalAccountID))
+		moveSubaccount(instance, ersContext)
+	}
+
+	return suspendStatusChange, nil
+}
+
 func (b *UpdateEndpoint) handleSubaccountMoveRequest(instance *internal.Instance, ersContext internal.ERSContext, logger *slog.Logger) bool {
 	needUpdateCustomResources := false
 	if b.subaccountMovementEnabled && (instance.GlobalAccountID != ersContext.GlobalAccountID && ersContext.GlobalAccountID != "") {
@@ -714,16 +768,21 @@ func (b *UpdateEndpoint) handleSubaccountMoveRequest(instance *internal.Instance
 		); err != nil {
 			logger.Error(fmt.Sprintf("while inserting action %q with message %s for instance ID %s: %v", pkg.SubaccountMovementActionType, message, instance.InstanceID, err))
 		}
-		if instance.SubscriptionGlobalAccountID == "" {
-			instance.SubscriptionGlobalAccountID = instance.GlobalAccountID
-		}
-		instance.GlobalAccountID = ersContext.GlobalAccountID
+		moveSubaccount(instance, ersContext)
 		needUpdateCustomResources = true
 		logger.Info(fmt.Sprintf("Global account ID changed to: %s. need update labels", instance.GlobalAccountID))
 	}
 	return needUpdateCustomResources
 }
 
+// moveSubaccount sets the global account of the context in the instance, the global account of the subscription is kept
+func moveSubaccount(instance *internal.Instance, ersContext internal.ERSContext) {
+	if instance.SubscriptionGlobalAccountID == "" {
+		instance.SubscriptionGlobalAccountID = instance.GlobalAccountID
+	}
+	instance.GlobalAccountID = ersContext.GlobalAccountID
+}
+
 func (b *UpdateEndpoint) extractActiveValue(id string, provisioning internal.ProvisioningOperation) (*bool, error) {
 	deprovisioning, dErr := b.operationStorage.GetDeprovisioningOperationByInstanceID(id)
 	if dErr != nil && !dberr.IsNotFound(dErr) {
@@ -1,9 +1,14 @@
 package broker
 
 import (
+	"errors"
+	"fmt"
+	"sort"
 	"strings"
 
+	"github.com/kyma-project/kyma-environment-broker/common/hyperscaler/rules"
 	pkg "github.com/kyma-project/kyma-environment-broker/common/runtime"
+	"github.com/kyma-project/kyma-environment-broker/internal/provider/configuration"
 	"github.com/labstack/gommon/log"
 	"github.com/pivotal-cf/brokerapi/v12/domain"
 )
@@ -53,9 +58,28 @@ var PlanIDsMapping = map[PlanNameType]PlanIDType{
 	BuildRuntimeAlicloudPlanName: BuildRuntimeAlicloudPlanID,
 }
 
+// builtInPlans are the plans offered without any plan configuration, the plans configuration can add new plans
+// and change the semantics of these plans
+var builtInPlans = []configuration.PlanDefinition{
+	{ID: AWSPlanID, Name: AWSPlanName, Provider: pkg.AWS, Schema: configuration.RegularSchema},
+	{ID: GCPPlanID, Name: GCPPlanName, Provider: pkg.GCP, Schema: configuration.RegularSchema},
+	{ID: AzurePlanID, Name: AzurePlanName, Provider: pkg.Azure, Schema: configuration.RegularSchema},
+	{ID: SapConvergedCloudPlanID, Name: SapConvergedCloudPlanName, Provider: pkg.SapConvergedCloud, Schema: configuration.RegularSchema},
+	{ID: AlicloudPlanID, Name: AlicloudPlanName, Provider: pkg.Alicloud, Schema: configuration.RegularSchema},
+	{ID: PreviewPlanID, Name: PreviewPlanName, Provider: pkg.AWS, Schema: configuration.RegularSchema},
+	{ID: BuildRuntimeAWSPlanID, Name: BuildRuntimeAWSPlanName, Provider: pkg.AWS, Schema: configuration.RegularSchema},
+	{ID: BuildRuntimeGCPPlanID, Name: BuildRuntimeGCPPlanName, Provider: pkg.GCP, Schema: configuration.RegularSchema},
+	{ID: BuildRuntimeAzurePlanID, Name: BuildRuntimeAzurePlanName, Provider: pkg.Azure, Schema: configuration.RegularSchema},
+	{ID: BuildRuntimeAlicloudPlanID, Name: BuildRuntimeAlicloudPlanName, Provider: pkg.Alicloud, Schema: configuration.RegularSchema},
+	{ID: AzureLitePlanID, Name: AzureLitePlanName, Provider: pkg.Azure, Schema: configuration.AzureLiteSchema},
+	{ID: FreemiumPlanID, Name: FreemiumPlanName, Free: true, Schema: configuration.FreeSchema},
+	{ID: TrialPlanID, Name: TrialPlanName, Trial: true, Schema: configuration.TrialSchema},
+}
+
 type PlanIDType string
 type PlanNameType string
 
+// AvailablePlans contains the built-in plans until KEB replaces it with the catalog built from the plans configuration
 var AvailablePlans = NewAvailablePlans(PlanIDsMapping)
 
 type ControlFlagsObject struct {
@@ -65,8 +89,10 @@ type ControlFlagsObject struct {
 }
 
 type AvailablePlansType struct {
-	idToName map[PlanIDType]PlanNameType
-	nameToID map[PlanNameType]PlanIDType
+	idToName    map[PlanIDType]PlanNameType
+	nameToID    map[PlanNameType]PlanIDType
+	definitions map[PlanIDType]configuration.PlanDefinition
+	configured  map[PlanIDType]bool
 }
 
 func NewAvailablePlans(nameToIDMap map[PlanNameType]PlanIDType) *AvailablePlansType {
@@ -75,10 +101,99 @@ func NewAvailablePlans(nameToIDMap map[PlanNameType]PlanIDType) *AvailablePlansT
 		log.Error("plan IDs and names mapping is not bijective, cannot create AvailablePlans object")
 		return nil
 	}
+	definitions := make(map[PlanIDType]configuration.PlanDefinition, len(r))
+	for _, definition := range builtInPlans {
+		if r[PlanIDType(definition.ID)] == PlanNameType(definition.Name) {
+			definitions[PlanIDType(definition.ID)] = definition
+		}
+	}
 	return &AvailablePlansType{
-		idToName: r,
-		nameToID: nameToIDMap,
+		idToName:    r,
+		nameToID:    nameToIDMap,
+		definitions: definitions,
+		configured:  map[PlanIDType]bool{},
+	}
+}
+
+// NewAvailablePlansFromSpecifications builds the catalog from the built-in plans and the plans defined in the plans configuration.
+// A configured plan with the name of a built-in plan must have the same ID, the attributes it leaves empty are taken from the built-in plan.
+func NewAvailablePlansFromSpecifications(planSpec *configuration.PlanSpecifications) (*AvailablePlansType, error) {
+	byName := make(map[string]configuration.PlanDefinition, len(builtInPlans))
+	for _, definition := range builtInPlans {
+		byName[definition.Name] = definition
+	}
+	configured := map[PlanIDType]bool{}
+	for _, definition := range planSpec.PlanDefinitions() {
+		if builtIn, found := byName[definition.Name]; found {
+			if builtIn.ID != definition.ID {
+				return nil, fmt.Errorf("plan %s is built in with ID %s, the configured ID %s cannot be used", definition.Name, builtIn.ID, definition.ID)
+			}
+			if definition.Provider == "" {
+				definition.Provider = builtIn.Provider
+			}
+			if definition.Schema == "" {
+				definition.Schema = builtIn.Schema
+			}
+			definition.Trial = definition.Trial || builtIn.Trial
+			definition.Free = definition.Free || builtIn.Free
+		}
+		if definition.Schema == "" {
+			definition.Schema = configuration.RegularSchema
+		}
+		byName[definition.Name] = definition
+		configured[PlanIDType(definition.ID)] = true
+	}
+
+	ap := &AvailablePlansType{
+		idToName:    make(map[PlanIDType]PlanNameType, len(byName)),
+		nameToID:    make(map[PlanNameType]PlanIDType, len(byName)),
+		definitions: make(map[PlanIDType]configuration.PlanDefinition, len(byName)),
+		configured:  configured,
+	}
+	for name, definition := range byName {
+		id := PlanIDType(definition.ID)
+		if other, found := ap.idToName[id]; found {
+			return nil, fmt.Errorf("plans %s and %s have the same ID %s", other, name, id)
+		}
+		if err := validatePlanDefinition(definition); err != nil {
+			return nil, err
+		}
+		ap.idToName[id] = PlanNameType(name)
+		ap.nameToID[PlanNameType(name)] = id
+		ap.definitions[id] = definition
+	}
+	return ap, nil
+}
+
+func validatePlanDefinition(definition configuration.PlanDefinition) error {
+	if definition.Trial && definition.Free {
+		return fmt.Errorf("plan %s cannot be both a trial and a free plan", definition.Name)
+	}
+	switch definition.Schema {
+	case configuration.RegularSchema, configuration.AzureLiteSchema:
+		if definition.Provider == "" || definition.Provider == pkg.UnknownProvider {
+			return fmt.Errorf("plan %s must define a supported provider", definition.Name)
+		}
 	}
+	return nil
+}
+
+// Validate checks if the plans defined in the plans configuration can be provisioned, which requires the HAP rules for the plan
+// and the configuration of its provider
+func (ap AvailablePlansType) Validate(rulesService *rules.RulesService, providerSpec *configuration.ProviderSpec) error {
+	var errs []error
+	for _, definition := range ap.Definitions() {
+		if !ap.configured[PlanIDType(definition.ID)] {
+			continue
+		}
+		if !rulesService.HasRulesForPlan(definition.Name) {
+			errs = append(errs, fmt.Errorf("plan %s is not covered by HAP rules", definition.Name))
+		}
+		if definition.Provider != "" && len(providerSpec.Regions(definition.Provider)) == 0 {
+			errs = append(errs, fmt.Errorf("provider %s of the plan %s is not configured", definition.Provider, definition.Name))
+		}
+	}
+	return errors.Join(errs...)
 }
 
 func reverseMap(initialMap map[PlanNameType]PlanIDType) map[PlanIDType]PlanNameType {
@@ -119,6 +234,31 @@ func (ap AvailablePlansType) GetAllPlanIDs() []PlanIDType {
 	return ids
 }
 
+// Definitions returns the definitions of all plans sorted by name
+func (ap AvailablePlansType) Definitions() []configuration.PlanDefinition {
+	definitions := make([]configuration.PlanDefinition, 0, len(ap.definitions))
+	for _, definition := range ap.definitions {
+		definitions = append(definitions, definition)
+	}
+	sort.Slice(definitions, func(i, j int) bool {
+		return definitions[i].Name < definitions[j].Name
+	})
+	return definitions
+}
+
+func (ap AvailablePlansType) GetDefinition(planID PlanIDType) (configuration.PlanDefinition, bool) {
+	definition, exists := ap.definitions[planID]
+	return definition, exists
+}
+
+func (ap AvailablePlansType) GetDefinitionByName(planName PlanNameType) (configuration.PlanDefinition, bool) {
+	planID, exists := ap.nameToID[planName]
+	if !exists {
+		return configuration.PlanDefinition{}, false
+	}
+	return ap.GetDefinition(planID)
+}
+
 func (ap AvailablePlansType) GetAllPlanNamesAsStrings() []string {
 	names := make([]string, 0, len(ap.nameToID))
 	for name := range ap.nameToID {
@@ -224,21 +364,19 @@ func defaultMetadata(planName string, plans PlansConfig) *domain.ServicePlanMeta
 }
 
 func IsTrialPlan(planID string) bool {
-	switch planID {
-	case TrialPlanID:
-		return true
-	default:
-		return false
-	}
+	definition, _ := AvailablePlans.GetDefinition(PlanIDType(planID))
+	return definition.Trial
 }
 
 func IsFreemiumPlan(planID string) bool {
-	switch planID {
-	case FreemiumPlanID:
-		return true
-	default:
-		return false
-	}
+	definition, _ := AvailablePlans.GetDefinition(PlanIDType(planID))
+	return definition.Free
+}
+
+// IsBindablePlan returns true when the plans configuration marks the plan as bindable
+func IsBindablePlan(planName string) bool {
+	definition, _ := AvailablePlans.GetDefinitionByName(PlanNameType(strings.ToLower(planName)))
+	return definition.Bindable
 }
 
 func filter(items *[]interface{}, included map[string]interface{}) interface{} {
@@ -5,9 +5,12 @@ import (
 	"encoding/json"
 	"os"
 	"path"
+	"strings"
 	"testing"
 
+	"github.com/kyma-project/kyma-environment-broker/common/hyperscaler/rules"
 	"github.com/kyma-project/kyma-environment-broker/internal/provider/configuration"
+	"k8s.io/apimachinery/pkg/util/sets"
 
 	pkg "github.com/kyma-project/kyma-environment-broker/common/runtime"
 	"github.com/kyma-project/kyma-environment-broker/internal/fixture"
@@ -462,6 +465,131 @@ func TestNewAvailablePlans_NonBijectiveMappingReturnsEmptyAvailablePlans(t *test
 	assert.Nil(t, ap)
 }
 
+const buildRuntimeSapConvergedCloudPlanConfig = `
+build-runtime-sap-converged-cloud:
+  id: 5e6b1b2a-6a3c-4b1f-9d5e-0c4f1a2b3c4d
+  provider: openstack
+  bindable: true
+  regularMachines: ["g_c2_m8", "g_c4_m16"]
+  regions:
+    cf-eu20: ["eu-de-1"]
+aws:
+  id: 361c511f-f939-4621-b228-d0fb79a1fe15
+  bindable: true
+`
+
+func TestNewAvailablePlansFromSpecifications(t *testing.T) {
+	// given
+	spec, err := configuration.NewPlanSpecifications(strings.NewReader(buildRuntimeSapConvergedCloudPlanConfig))
+	require.NoError(t, err)
+
+	// when
+	ap, err := NewAvailablePlansFromSpecifications(spec)
+
+	// then
+	require.NoError(t, err)
+	assert.Len(t, ap.GetAllPlanIDs(), len(PlanIDsMapping)+1)
+
+	id, found := ap.GetPlanIDByName("build-runtime-sap-converged-cloud")
+	assert.True(t, found)
+	assert.Equal(t, PlanIDType("5e6b1b2a-6a3c-4b1f-9d5e-0c4f1a2b3c4d"), id)
+	definition, _ := ap.GetDefinition(id)
+	assert.Equal(t, pkg.SapConvergedCloud, definition.Provider)
+	assert.Equal(t, configuration.RegularSchema, definition.Schema)
+	assert.True(t, definition.Bindable)
+
+	definition, _ = ap.GetDefinition(AWSPlanID)
+	assert.Equal(t, pkg.AWS, definition.Provider)
+	assert.True(t, definition.Bindable)
+
+	definition, _ = ap.GetDefinition(TrialPlanID)
+	assert.True(t, definition.Trial)
+	assert.Equal(t, configuration.TrialSchema, definition.Schema)
+}
+
+func TestNewAvailablePlansFromSpecifications_InvalidDefinitions(t *testing.T) {
+	for name, config := range map[string]string{
+		"changed ID of a built-in plan": `
+aws:
+  id: 5e6b1b2a-6a3c-4b1f-9d5e-0c4f1a2b3c4d
+`,
+		"ID of a built-in plan": `
+new-plan:
+  id: 361c511f-f939-4621-b228-d0fb79a1fe15
+  provider: aws
+`,
+		"missing provider": `
+new-plan:
+  id: 5e6b1b2a-6a3c-4b1f-9d5e-0c4f1a2b3c4d
+`,
+		"trial and free": `
+new-plan:
+  id: 5e6b1b2a-6a3c-4b1f-9d5e-0c4f1a2b3c4d
+  trial: true
+  free: true
+  schema: trial
+`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			spec, err := configuration.NewPlanSpecifications(strings.NewReader(config))
+			require.NoError(t, err)
+
+			_, err = NewAvailablePlansFromSpecifications(spec)
+
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestAvailablePlans_Validate(t *testing.T) {
+	// given
+	spec, err := configuration.NewPlanSpecifications(strings.NewReader(buildRuntimeSapConvergedCloudPlanConfig))
+	require.NoError(t, err)
+	ap, err := NewAvailablePlansFromSpecifications(spec)
+	require.NoError(t, err)
+	providerSpec, err := configuration.NewProviderSpecFromFile("testdata/providers.yaml")
+	require.NoError(t, err)
+
+	t.Run("should pass when configured plans are covered by HAP rules", func(t *testing.T) {
+		rulesService, err := rules.NewRulesServiceFromSlice([]string{"aws", "build-runtime-sap-converged-cloud"}, sets.New(ap.GetAllPlanNamesAsStrings()...), sets.New[string]())
+		require.NoError(t, err)
+
+		assert.NoError(t, ap.Validate(rulesService, providerSpec))
+	})
+
+	t.Run("should fail when a configured plan is not covered by HAP rules", func(t *testing.T) {
+		rulesService, err := rules.NewRulesServiceFromSlice([]string{"aws"}, sets.New(ap.GetAllPlanNamesAsStrings()...), sets.New[string]())
+		require.NoError(t, err)
+
+		assert.ErrorContains(t, ap.Validate(rulesService, providerSpec), "build-runtime-sap-converged-cloud")
+	})
+}
+
+func TestSchemaService_PlansFromConfiguration(t *testing.T) {
+	// given
+	spec, err := configuration.NewPlanSpecifications(strings.NewReader(buildRuntimeSapConvergedCloudPlanConfig))
+	require.NoError(t, err)
+	ap, err := NewAvailablePlansFromSpecifications(spec)
+	require.NoError(t, err)
+	providerSpec, err := configuration.NewProviderSpecFromFile("testdata/providers.yaml")
+	require.NoError(t, err)
+
+	defaultPlans := AvailablePlans
+	AvailablePlans = ap
+	defer func() { AvailablePlans = defaultPlans }()
+	schemaService := NewSchemaService(providerSpec, spec, nil, Config{}, StringList{}, &fixture.FakeChannelResolver{})
+
+	// when
+	plans := schemaService.Plans(PlansConfig{}, platformRegionEU20, pkg.SapConvergedCloud)
+
+	// then
+	plan, found := plans["5e6b1b2a-6a3c-4b1f-9d5e-0c4f1a2b3c4d"]
+	require.True(t, found)
+	assert.Equal(t, "build-runtime-sap-converged-cloud", plan.Name)
+	assert.Contains(t, plans, TrialPlanID)
+	assert.NotContains(t, plans, AWSPlanID)
+}
+
 func createSchemaService(t *testing.T) *SchemaService {
 	return createSchemaServiceWithConfig(t, Config{
 		RejectUnsupportedParameters: true,
@@ -31,23 +31,12 @@ func NewSchemaService(providerSpec *configuration.ProviderSpec, planSpec *config
 
 func (s *SchemaService) Validate() error {
 	for planName, regions := range s.planSpec.AllRegionsByPlan() {
-		var provider pkg.CloudProvider
-		switch planName {
-		case AWSPlanName, BuildRuntimeAWSPlanName, PreviewPlanName:
-			provider = pkg.AWS
-		case GCPPlanName, BuildRuntimeGCPPlanName:
-			provider = pkg.GCP
-		case AzurePlanName, BuildRuntimeAzurePlanName, AzureLitePlanName:
-			provider = pkg.Azure
-		case SapConvergedCloudPlanName:
-			provider = pkg.SapConvergedCloud
-		case AlicloudPlanName, BuildRuntimeAlicloudPlanName:
-			provider = pkg.Alicloud
-		default:
+		definition, found := AvailablePlans.GetDefinitionByName(PlanNameType(planName))
+		if !found || definition.Provider == "" {
 			continue
 		}
 		for _, region := range regions {
-			err := s.providerSpec.Validate(provider, region)
+			err := s.providerSpec.Validate(definition.Provider, region)
 			if err != nil {
 				return err
 			}
@@ -60,47 +49,24 @@ func (s *SchemaService) Plans(plans PlansConfig, platformRegion string, cp pkg.C
 
 	outputPlans := map[string]domain.ServicePlan{}
 
-	if createSchema, updateSchema, available := s.AWSSchemas(platformRegion); available {
-		outputPlans[AWSPlanID] = s.defaultServicePlan(AWSPlanID, AWSPlanName, plans, createSchema, updateSchema)
-	}
-	if createSchema, updateSchema, available := s.GCPSchemas(platformRegion); available {
-		outputPlans[GCPPlanID] = s.defaultServicePlan(GCPPlanID, GCPPlanName, plans, createSchema, updateSchema)
-	}
-	if createSchema, updateSchema, available := s.AzureSchemas(platformRegion); available {
-		outputPlans[AzurePlanID] = s.defaultServicePlan(AzurePlanID, AzurePlanName, plans, createSchema, updateSchema)
-	}
-	if createSchema, updateSchema, available := s.SapConvergedCloudSchemas(platformRegion); available {
-		outputPlans[SapConvergedCloudPlanID] = s.defaultServicePlan(SapConvergedCloudPlanID, SapConvergedCloudPlanName, plans, createSchema, updateSchema)
-	}
-	if createSchema, updateSchema, available := s.AlicloudSchemas(platformRegion); available {
-		outputPlans[AlicloudPlanID] = s.defaultServicePlan(AlicloudPlanID, AlicloudPlanName, plans, createSchema, updateSchema)
-	}
-	if createSchema, updateSchema, available := s.PreviewSchemas(platformRegion); available {
-		outputPlans[PreviewPlanID] = s.defaultServicePlan(PreviewPlanID, PreviewPlanName, plans, createSchema, updateSchema)
-	}
-	if createSchema, updateSchema, available := s.BuildRuntimeAWSSchemas(platformRegion); available {
-		outputPlans[BuildRuntimeAWSPlanID] = s.defaultServicePlan(BuildRuntimeAWSPlanID, BuildRuntimeAWSPlanName, plans, createSchema, updateSchema)
-	}
-	if createSchema, updateSchema, available := s.BuildRuntimeGcpSchemas(platformRegion); available {
-		outputPlans[BuildRuntimeGCPPlanID] = s.defaultServicePlan(BuildRuntimeGCPPlanID, BuildRuntimeGCPPlanName, plans, createSchema, updateSchema)
-	}
-	if createSchema, updateSchema, available := s.BuildRuntimeAzureSchemas(platformRegion); available {
-		outputPlans[BuildRuntimeAzurePlanID] = s.defaultServicePlan(BuildRuntimeAzurePlanID, BuildRuntimeAzurePlanName, plans, createSchema, updateSchema)
-	}
-	if createSchema, updateSchema, available := s.BuildRuntimeAlicloudSchemas(platformRegion); available {
-		outputPlans[BuildRuntimeAlicloudPlanID] = s.defaultServicePlan(BuildRuntimeAlicloudPlanID, BuildRuntimeAlicloudPlanName, plans, createSchema, updateSchema)
-	}
-	if azureLiteCreateSchema, azureLiteUpdateSchema, available := s.AzureLiteSchemas(platformRegion); available {
-		outputPlans[AzureLitePlanID] = s.defaultServicePlan(AzureLitePlanID, AzureLitePlanName, plans, azureLiteCreateSchema, azureLiteUpdateSchema)
-	}
-	if freemiumCreateSchema, freemiumUpdateSchema, available := s.FreeSchemas(cp, platformRegion); available {
-		outputPlans[FreemiumPlanID] = s.defaultServicePlan(FreemiumPlanID, FreemiumPlanName, plans, freemiumCreateSchema, freemiumUpdateSchema)
+	for _, plan := range AvailablePlans.Definitions() {
+		var createSchema, updateSchema *map[string]interface{}
+		available := true
+		switch plan.Schema {
+		case configuration.TrialSchema:
+			createSchema, updateSchema = s.trialSchema(plan.Name, false), s.trialSchema(plan.Name, true)
+		case configuration.FreeSchema:
+			createSchema, updateSchema, available = s.freeSchemas(plan.Name, cp, platformRegion)
+		case configuration.AzureLiteSchema:
+			createSchema, updateSchema, available = s.azureLiteSchemas(plan.Name, platformRegion)
+		default:
+			createSchema, updateSchema, available = s.planSchemas(plan.Provider, plan.Name, platformRegion)
+		}
+		if available {
+			outputPlans[plan.ID] = s.defaultServicePlan(plan.ID, plan.Name, plans, createSchema, updateSchema)
+		}
 	}
 
-	trialCreateSchema := s.TrialSchema(false)
-	trialUpdateSchema := s.TrialSchema(true)
-	outputPlans[TrialPlanID] = s.defaultServicePlan(TrialPlanID, TrialPlanName, plans, trialCreateSchema, trialUpdateSchema)
-
 	return outputPlans
 }
 
@@ -218,8 +184,12 @@ func (s *SchemaService) BuildRuntimeAlicloudSchemas(platformRegion string) (crea
 }
 
 func (s *SchemaService) AzureLiteSchema(platformRegion string, regions []string, update bool) *map[string]interface{} {
-	flags := s.createFlags(AzureLitePlanName)
-	machines := s.planSpec.RegularMachines(AzureLitePlanName)
+	return s.azureLiteSchema(AzureLitePlanName, regions, update)
+}
+
+func (s *SchemaService) azureLiteSchema(planName string, regions []string, update bool) *map[string]interface{} {
+	flags := s.createFlags(planName)
+	machines := s.planSpec.RegularMachines(planName)
 	displayNames := s.providerSpec.MachineDisplayNames(pkg.Azure, machines)
 
 	properties := NewProvisioningProperties(
@@ -234,10 +204,10 @@ func (s *SchemaService) AzureLiteSchema(platformRegion string, regions []string,
 		s.providerSpec,
 		pkg.Azure,
 		s.cfg.DualStackDocsURL,
-		AzureLitePlanName,
+		planName,
 		s.channelResolver,
 	)
-	if s.cfg.IsACLEnabledForPlanName(AzureLitePlanName) {
+	if s.cfg.IsACLEnabledForPlanName(planName) {
 		properties.AccessControlList = ACLProperty()
 	}
 	properties.AutoScalerMax.Minimum = 2
@@ -264,15 +234,23 @@ func (s *SchemaService) AzureLiteSchema(platformRegion string, regions []string,
 }
 
 func (s *SchemaService) AzureLiteSchemas(platformRegion string) (create, update *map[string]interface{}, available bool) {
-	regions := s.planSpec.Regions(AzureLitePlanName, platformRegion)
+	return s.azureLiteSchemas(AzureLitePlanName, platformRegion)
+}
+
+func (s *SchemaService) azureLiteSchemas(planName, platformRegion string) (create, update *map[string]interface{}, available bool) {
+	regions := s.planSpec.Regions(planName, platformRegion)
 	if len(regions) == 0 {
 		return nil, nil, false
 	}
-	return s.AzureLiteSchema(platformRegion, regions, false),
-		s.AzureLiteSchema(platformRegion, regions, true), true
+	return s.azureLiteSchema(planName, regions, false),
+		s.azureLiteSchema(planName, regions, true), true
 }
 
 func (s *SchemaService) FreeSchema(provider pkg.CloudProvider, platformRegion string, update bool) *map[string]interface{} {
+	return s.freeSchema(FreemiumPlanName, provider, platformRegion, update)
+}
+
+func (s *SchemaService) freeSchema(planName string, provider pkg.CloudProvider, platformRegion string, update bool) *map[string]interface{} {
 	var regions []string
 	var regionsDisplayNames map[string]string
 	switch provider {
@@ -283,7 +261,7 @@ func (s *SchemaService) FreeSchema(provider pkg.CloudProvider, platformRegion st
 		regions = s.planSpec.Regions(AWSPlanName, platformRegion)
 		regionsDisplayNames = s.providerSpec.RegionDisplayNames(pkg.AWS, regions)
 	}
-	flags := s.createFlags(FreemiumPlanName)
+	flags := s.createFlags(planName)
 
 	properties := ProvisioningProperties{
 		UpdateProperties: UpdateProperties{
@@ -296,13 +274,13 @@ func (s *SchemaService) FreeSchema(provider pkg.CloudProvider, platformRegion st
 			EnumDisplayName: regionsDisplayNames,
 		},
 	}
-	if s.cfg.IsACLEnabledForPlanName(FreemiumPlanName) {
+	if s.cfg.IsACLEnabledForPlanName(planName) {
 		properties.AccessControlList = ACLProperty()
 	}
 	if !update {
 		defaultChannel := "regular"
 		if s.channelResolver != nil {
-			defaultChannel, _ = s.channelResolver.GetChannelForPlan(FreemiumPlanName)
+			defaultChannel, _ = s.channelResolver.GetChannelForPlan(planName)
 		}
 		properties.Networking = NewNetworkingSchema(flags.rejectUnsupportedParameters, s.providerSpec, provider, s.cfg.DualStackDocsURL)
 		properties.Modules = NewModulesSchema(flags.rejectUnsupportedParameters, defaultChannel)
@@ -312,27 +290,35 @@ func (s *SchemaService) FreeSchema(provider pkg.CloudProvider, platformRegion st
 }
 
 func (s *SchemaService) FreeSchemas(provider pkg.CloudProvider, platformRegion string) (create, update *map[string]interface{}, available bool) {
-	create = s.FreeSchema(provider, platformRegion, false)
-	update = s.FreeSchema(provider, platformRegion, true)
+	return s.freeSchemas(FreemiumPlanName, provider, platformRegion)
+}
+
+func (s *SchemaService) freeSchemas(planName string, provider pkg.CloudProvider, platformRegion string) (create, update *map[string]interface{}, available bool) {
+	create = s.freeSchema(planName, provider, platformRegion, false)
+	update = s.freeSchema(planName, provider, platformRegion, true)
 	return create, update, true
 }
 
 func (s *SchemaService) TrialSchema(update bool) *map[string]interface{} {
-	flags := s.createFlags(TrialPlanName)
+	return s.trialSchema(TrialPlanName, update)
+}
+
+func (s *SchemaService) trialSchema(planName string, update bool) *map[string]interface{} {
+	flags := s.createFlags(planName)
 
 	properties := ProvisioningProperties{
 		UpdateProperties: UpdateProperties{
 			Name: NameProperty(update),
 		},
 	}
-	if s.cfg.IsACLEnabledForPlanName(TrialPlanName) {
+	if s.cfg.IsACLEnabledForPlanName(planName) {
 		properties.AccessControlList = ACLProperty()
 	}
 
 	if !update {
 		defaultChannel := "regular"
 		if s.channelResolver != nil {
-			defaultChannel, _ = s.channelResolver.GetChannelForPlan(TrialPlanName)
+			defaultChannel, _ = s.channelResolver.GetChannelForPlan(planName)
 		}
 		properties.Modules = NewModulesSchema(flags.rejectUnsupportedParameters, defaultChannel)
 	}
@@ -56,10 +56,11 @@ func registerRoutesAndHandlers(router *httputil.Router, apiHandler *handlers.API
 	router.HandleFunc(buildPathPattern(http.MethodGet, pathPrefix, "/v2/catalog"), apiHandler.Catalog)
 
 	router.HandleFunc(buildPathPattern(http.MethodGet, pathPrefix, "/v2/service_instances/{instance_id}"), apiHandler.GetInstance)
-	router.HandleFunc(buildPathPattern(http.MethodPut, pathPrefix, "/v2/service_instances/{instance_id}"), apiHandler.Provision)
+	dryRun := middleware.AddDryRunToContext()
+	router.Handle(buildPathPattern(http.MethodPut, pathPrefix, "/v2/service_instances/{instance_id}"), dryRun(http.HandlerFunc(apiHandler.Provision)))
 	router.HandleFunc(buildPathPattern(http.MethodDelete, pathPrefix, "/v2/service_instances/{instance_id}"), deprovisionFunc)
 	router.HandleFunc(buildPathPattern(http.MethodGet, pathPrefix, "/v2/service_instances/{instance_id}/last_operation"), apiHandler.LastOperation)
-	router.HandleFunc(buildPathPattern(http.MethodPatch, pathPrefix, "/v2/service_instances/{instance_id}"), apiHandler.Update)
+	router.Handle(buildPathPattern(http.MethodPatch, pathPrefix, "/v2/service_instances/{instance_id}"), dryRun(http.HandlerFunc(apiHandler.Update)))
 
 	router.HandleFunc(buildPathPattern(http.MethodGet, pathPrefix, "/v2/service_instances/{instance_id}/service_bindings/{binding_id}"), apiHandler.GetBinding)
 	router.Handle(buildPathPattern(http.MethodPut, pathPrefix, "/v2/service_instances/{instance_id}/service_bindings/{binding_id}"), http.TimeoutHandler(CreateBindingHandler{apiHandler.Bind}, createBindingTimeout, fmt.Sprintf("request timeout: time exceeded %s", createBindingTimeout)))
@@ -59,7 +59,7 @@ func (se *ServicesEndpoint) Services(ctx context.Context) ([]domain.Service, err
 			continue
 		}
 
-		if se.cfg.Binding.Enabled && se.cfg.Binding.BindablePlans.Contains(plan.Name) {
+		if se.cfg.Binding.Enabled && (se.cfg.Binding.BindablePlans.Contains(plan.Name) || IsBindablePlan(plan.Name)) {
 			plan.Bindable = &bindable
 		}
 		availableServicePlans = append(availableServicePlans, plan)
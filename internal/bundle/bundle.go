@@ -0,0 +1,101 @@
+package bundle
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/kyma-project/kyma-environment-broker/common/events"
+	"github.com/kyma-project/kyma-environment-broker/common/runtime"
+	"github.com/kyma-project/kyma-environment-broker/internal"
+
+	"github.com/pivotal-cf/brokerapi/v12/domain"
+)
+
+// Version is the version of the bundle format, a bundle with a different version is not imported
+const Version = 1
+
+// Bundle is the full state of one instance. The SM credentials and the kubeconfigs are encrypted
+// with the encrypter of the storage from which the bundle was exported.
+type Bundle struct {
+	Version         int                       `json:"version"`
+	ExportedAt      time.Time                 `json:"exportedAt"`
+	Instance        internal.Instance         `json:"instance"`
+	Operations      []Operation               `json:"operations"`
+	OperationSteps  []runtime.OperationStep   `json:"operationSteps"`
+	Bindings        []internal.Binding        `json:"bindings"`
+	Actions         []runtime.Action          `json:"actions"`
+	Events          []events.EventDTO         `json:"events"`
+	SubaccountState *internal.SubaccountState `json:"subaccountState,omitempty"`
+}
+
+// Operation keeps the fields of the operation which are stored in separate columns, the other fields are kept in Data
+type Operation struct {
+	ID                     string                          `json:"id"`
+	Version                int                             `json:"version"`
+	CreatedAt              time.Time                       `json:"createdAt"`
+	UpdatedAt              time.Time                       `json:"updatedAt"`
+	Type                   internal.OperationType          `json:"type"`
+	ProvisionerOperationID string                          `json:"provisionerOperationID"`
+	State                  domain.LastOperationState       `json:"state"`
+	Description            string                          `json:"description"`
+	ProvisioningParameters internal.ProvisioningParameters `json:"provisioningParameters"`
+	FinishedStages         []string                        `json:"finishedStages"`
+	Data                   internal.Operation              `json:"data"`
+}
+
+func newOperation(op internal.Operation) Operation {
+	return Operation{
+		ID:                     op.ID,
+		Version:                op.Version,
+		CreatedAt:              op.CreatedAt,
+		UpdatedAt:              op.UpdatedAt,
+		Type:                   op.Type,
+		ProvisionerOperationID: op.ProvisionerOperationID,
+		State:                  op.State,
+		Description:            op.Description,
+		ProvisioningParameters: op.ProvisioningParameters,
+		FinishedStages:         op.FinishedStages,
+		Data:                   op,
+	}
+}
+
+func (o Operation) toOperation(instanceID string) internal.Operation {
+	op := o.Data
+	op.ID = o.ID
+	op.Version = o.Version
+	op.CreatedAt = o.CreatedAt
+	op.UpdatedAt = o.UpdatedAt
+	op.Type = o.Type
+	op.InstanceID = instanceID
+	op.ProvisionerOperationID = o.ProvisionerOperationID
+	op.State = o.State
+	op.Description = o.Description
+	op.ProvisioningParameters = o.ProvisioningParameters
+	op.FinishedStages = o.FinishedStages
+	return op
+}
+
+func Write(w io.Writer, bundle Bundle) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(bundle); err != nil {
+		return fmt.Errorf("while writing the bundle: %w", err)
+	}
+	return nil
+}
+
+func Read(r io.Reader) (Bundle, error) {
+	var bundle Bundle
+	if err := json.NewDecoder(r).Decode(&bundle); err != nil {
+		return Bundle{}, fmt.Errorf("while reading the bundle: %w", err)
+	}
+	if bundle.Version != Version {
+		return Bundle{}, fmt.Errorf("unsupported bundle version %d, expected %d", bundle.Version, Version)
+	}
+	if bundle.Instance.InstanceID == "" {
+		return Bundle{}, fmt.Errorf("the bundle does not contain an instance")
+	}
+	return bundle, nil
+}
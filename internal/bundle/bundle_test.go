@@ -0,0 +1,166 @@
+package bundle_test
+
+import (
+	"bytes"
+	"log/slog"
+	"os"
+	"testing"
+
+	"github.com/kyma-project/kyma-environment-broker/common/runtime"
+	"github.com/kyma-project/kyma-environment-broker/internal"
+	"github.com/kyma-project/kyma-environment-broker/internal/bundle"
+	"github.com/kyma-project/kyma-environment-broker/internal/fixture"
+	"github.com/kyma-project/kyma-environment-broker/internal/storage"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	instanceID  = "instance-id"
+	operationID = "operation-id"
+	bindingID   = "binding-id"
+)
+
+func TestExportImport(t *testing.T) {
+	// given
+	source := fixSourceStorage(t)
+	sourceCipher := storage.NewEncrypter("################################")
+	log := slog.New(slog.NewTextHandler(os.Stdout, nil))
+
+	// when
+	exported, err := bundle.NewExporter(source, sourceCipher).Export(instanceID)
+	require.NoError(t, err)
+	var buf bytes.Buffer
+	require.NoError(t, bundle.Write(&buf, exported))
+
+	// then
+	assert.NotContains(t, buf.String(), "kubeconfig-content")
+	assert.NotContains(t, buf.String(), "client-secret")
+
+	// when
+	read, err := bundle.Read(&buf)
+	require.NoError(t, err)
+	target := storage.NewMemoryStorage()
+	err = bundle.NewImporter(target, sourceCipher, log).Import(read, false)
+
+	// then
+	require.NoError(t, err)
+	instance, err := target.Instances().GetByID(instanceID)
+	require.NoError(t, err)
+	assert.Equal(t, "client-secret", instance.Parameters.ErsContext.SMOperatorCredentials.ClientSecret)
+
+	operation, err := target.Operations().GetOperationByID(operationID)
+	require.NoError(t, err)
+	assert.Equal(t, internal.OperationTypeProvision, operation.Type)
+	assert.Equal(t, "client-secret", operation.ProvisioningParameters.ErsContext.SMOperatorCredentials.ClientSecret)
+	assert.Equal(t, []string{"start"}, operation.FinishedStages)
+
+	binding, err := target.Bindings().Get(instanceID, bindingID)
+	require.NoError(t, err)
+	assert.Equal(t, "kubeconfig-content", binding.Kubeconfig)
+
+	steps, err := target.OperationSteps().ListByOperationIDs([]string{operationID})
+	require.NoError(t, err)
+	assert.Len(t, steps, 1)
+
+	actions, err := target.Actions().ListActionsByInstanceID(instanceID)
+	require.NoError(t, err)
+	require.Len(t, actions, 1)
+	assert.Equal(t, exported.Actions[0].ID, actions[0].ID)
+	assert.True(t, exported.Actions[0].CreatedAt.Equal(actions[0].CreatedAt))
+
+	states, err := target.SubaccountStates().ListStates()
+	require.NoError(t, err)
+	assert.Len(t, states, 1)
+}
+
+func TestImport_Conflict(t *testing.T) {
+	// given
+	source := fixSourceStorage(t)
+	cipher := storage.NewEncrypter("################################")
+	log := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	exported, err := bundle.NewExporter(source, cipher).Export(instanceID)
+	require.NoError(t, err)
+
+	t.Run("should refuse to overwrite the existing data", func(t *testing.T) {
+		// when
+		err := bundle.NewImporter(source, cipher, log).Import(exported, false)
+
+		// then
+		assert.ErrorIs(t, err, bundle.ErrConflict)
+		assert.ErrorContains(t, err, "instance instance-id")
+		assert.ErrorContains(t, err, "operation operation-id")
+		assert.ErrorContains(t, err, "binding binding-id")
+	})
+
+	t.Run("should replace the existing data when forced", func(t *testing.T) {
+		// given
+		exported.Instance.GlobalAccountID = "new-global-account"
+		require.NoError(t, source.Operations().InsertOperation(fixture.FixUpdatingOperation("other-operation-id", instanceID).Operation))
+		require.NoError(t, source.OperationSteps().Insert(runtime.OperationStep{OperationID: "other-operation-id", Step: "Update"}))
+		otherBinding := fixture.FixBinding("other-binding-id", fixture.WithInstanceID(instanceID))
+		require.NoError(t, source.Bindings().Insert(&otherBinding))
+
+		// when
+		err := bundle.NewImporter(source, cipher, log).Import(exported, true)
+
+		// then
+		require.NoError(t, err)
+		instance, err := source.Instances().GetByID(instanceID)
+		require.NoError(t, err)
+		assert.Equal(t, "new-global-account", instance.GlobalAccountID)
+
+		operations, err := source.Operations().ListOperationsByInstanceID(instanceID)
+		require.NoError(t, err)
+		require.Len(t, operations, 1)
+		assert.Equal(t, operationID, operations[0].ID)
+
+		steps, err := source.OperationSteps().ListByOperationIDs([]string{operationID, "other-operation-id"})
+		require.NoError(t, err)
+		assert.Len(t, steps, 1)
+
+		bindings, err := source.Bindings().ListByInstanceID(instanceID)
+		require.NoError(t, err)
+		assert.Len(t, bindings, 1)
+
+		actions, err := source.Actions().ListActionsByInstanceID(instanceID)
+		require.NoError(t, err)
+		assert.Len(t, actions, 1)
+	})
+}
+
+func TestRead_UnsupportedVersion(t *testing.T) {
+	// when
+	_, err := bundle.Read(bytes.NewBufferString(`{"version": 2, "instance": {"instanceId": "instance-id"}}`))
+
+	// then
+	assert.ErrorContains(t, err, "unsupported bundle version 2")
+}
+
+func fixSourceStorage(t *testing.T) storage.BrokerStorage {
+	db := storage.NewMemoryStorage()
+
+	parameters := fixture.FixProvisioningParameters(instanceID)
+	parameters.ErsContext.SMOperatorCredentials = &internal.ServiceManagerOperatorCredentials{
+		ClientID:     "client-id",
+		ClientSecret: "client-secret",
+	}
+	instance := fixture.FixInstance(instanceID)
+	instance.Parameters = parameters
+	require.NoError(t, db.Instances().Insert(instance))
+
+	operation := fixture.FixProvisioningOperation(operationID, instanceID, fixture.WithProvisioningParameters(parameters))
+	operation.FinishedStages = []string{"start"}
+	require.NoError(t, db.Operations().InsertOperation(operation))
+	require.NoError(t, db.OperationSteps().Insert(runtime.OperationStep{OperationID: operationID, Step: "Start"}))
+
+	binding := fixture.FixBinding(bindingID, fixture.WithInstanceID(instanceID))
+	binding.Kubeconfig = "kubeconfig-content"
+	require.NoError(t, db.Bindings().Insert(&binding))
+
+	require.NoError(t, db.Actions().InsertAction(runtime.PlanUpdateActionType, instanceID, "plan updated", "old", "new"))
+	require.NoError(t, db.SubaccountStates().UpsertState(internal.SubaccountState{ID: instance.SubAccountID, BetaEnabled: "true"}))
+
+	return db
+}
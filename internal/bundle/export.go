@@ -0,0 +1,114 @@
+package bundle
+
+import (
+	"fmt"
+	"time"
+
+	eventsapi "github.com/kyma-project/kyma-environment-broker/common/events"
+	"github.com/kyma-project/kyma-environment-broker/internal"
+	"github.com/kyma-project/kyma-environment-broker/internal/storage"
+	postgres "github.com/kyma-project/kyma-environment-broker/internal/storage/driver/postsql"
+)
+
+type Exporter struct {
+	db     storage.BrokerStorage
+	cipher postgres.Cipher
+}
+
+// NewExporter creates the exporter which reads the instance state from the storage and encrypts the SM credentials
+// and the kubeconfigs with the cipher, so the bundle does not contain them in plain text
+func NewExporter(db storage.BrokerStorage, cipher postgres.Cipher) *Exporter {
+	return &Exporter{
+		db:     db,
+		cipher: cipher,
+	}
+}
+
+func (e *Exporter) Export(instanceID string) (Bundle, error) {
+	instance, err := e.db.Instances().GetByID(instanceID)
+	if err != nil {
+		return Bundle{}, fmt.Errorf("while getting instance %s: %w", instanceID, err)
+	}
+	bundle := Bundle{
+		Version:    Version,
+		ExportedAt: time.Now().UTC(),
+		Instance:   *instance,
+	}
+	if err := e.encryptParameters(&bundle.Instance.Parameters); err != nil {
+		return Bundle{}, fmt.Errorf("while encrypting the parameters of instance %s: %w", instanceID, err)
+	}
+
+	operations, err := e.db.Operations().ListOperationsByInstanceID(instanceID)
+	if err != nil {
+		return Bundle{}, fmt.Errorf("while listing operations of instance %s: %w", instanceID, err)
+	}
+	operationIDs := make([]string, 0, len(operations))
+	for _, op := range operations {
+		operation := newOperation(op)
+		if err := e.encryptParameters(&operation.ProvisioningParameters); err != nil {
+			return Bundle{}, fmt.Errorf("while encrypting the parameters of operation %s: %w", op.ID, err)
+		}
+		bundle.Operations = append(bundle.Operations, operation)
+		operationIDs = append(operationIDs, op.ID)
+	}
+
+	bundle.OperationSteps, err = e.db.OperationSteps().ListByOperationIDs(operationIDs)
+	if err != nil {
+		return Bundle{}, fmt.Errorf("while listing operation steps of instance %s: %w", instanceID, err)
+	}
+
+	bindings, err := e.db.Bindings().ListByInstanceID(instanceID)
+	if err != nil {
+		return Bundle{}, fmt.Errorf("while listing bindings of instance %s: %w", instanceID, err)
+	}
+	for _, binding := range bindings {
+		if binding.Kubeconfig != "" {
+			encrypted, err := e.cipher.Encrypt([]byte(binding.Kubeconfig))
+			if err != nil {
+				return Bundle{}, fmt.Errorf("while encrypting the kubeconfig of binding %s: %w", binding.ID, err)
+			}
+			binding.Kubeconfig = string(encrypted)
+		}
+		bundle.Bindings = append(bundle.Bindings, binding)
+	}
+
+	bundle.Actions, err = e.db.Actions().ListActionsByInstanceID(instanceID)
+	if err != nil {
+		return Bundle{}, fmt.Errorf("while listing actions of instance %s: %w", instanceID, err)
+	}
+
+	// the events are nil if they are disabled in the storage
+	if e.db.Events() != nil {
+		bundle.Events, err = e.db.Events().ListEvents(eventsapi.EventFilter{InstanceIDs: []string{instanceID}})
+		if err != nil {
+			return Bundle{}, fmt.Errorf("while listing events of instance %s: %w", instanceID, err)
+		}
+	}
+
+	bundle.SubaccountState, err = e.subaccountState(instance.SubAccountID)
+	if err != nil {
+		return Bundle{}, err
+	}
+
+	return bundle, nil
+}
+
+func (e *Exporter) encryptParameters(parameters *internal.ProvisioningParameters) error {
+	if err := e.cipher.EncryptSMCredentials(parameters); err != nil {
+		return err
+	}
+	return e.cipher.EncryptKubeconfig(parameters)
+}
+
+func (e *Exporter) subaccountState(subaccountID string) (*internal.SubaccountState, error) {
+	states, err := e.db.SubaccountStates().ListStates()
+	if err != nil {
+		return nil, fmt.Errorf("while listing subaccount states: %w", err)
+	}
+	for _, state := range states {
+		if state.ID == subaccountID {
+			return &state, nil
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,253 @@
+package bundle
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"github.com/kyma-project/kyma-environment-broker/internal"
+	"github.com/kyma-project/kyma-environment-broker/internal/storage"
+	"github.com/kyma-project/kyma-environment-broker/internal/storage/dberr"
+	postgres "github.com/kyma-project/kyma-environment-broker/internal/storage/driver/postsql"
+)
+
+// ErrConflict is returned when the instance, its operations or bindings already exist in the storage
+var ErrConflict = errors.New("the bundle conflicts with the data in the storage")
+
+type Importer struct {
+	db     storage.BrokerStorage
+	cipher postgres.Cipher
+	log    *slog.Logger
+}
+
+// NewImporter creates the importer which decrypts the SM credentials and the kubeconfigs from the bundle with the cipher.
+// The cipher must know the key used to encrypt the bundle, the storage encrypts the data again with its active key.
+func NewImporter(db storage.BrokerStorage, cipher postgres.Cipher, log *slog.Logger) *Importer {
+	return &Importer{
+		db:     db,
+		cipher: cipher,
+		log:    log,
+	}
+}
+
+// Import saves the instance state from the bundle. The import is refused if the instance, its operations, bindings or
+// a different subaccount state already exist, unless force is set, then all the existing data of the instance is replaced.
+// The data is written in one transaction, so a failed import does not leave a part of the bundle in the storage.
+func (i *Importer) Import(bundle Bundle, force bool) error {
+	if bundle.Version != Version {
+		return fmt.Errorf("unsupported bundle version %d, expected %d", bundle.Version, Version)
+	}
+	instanceID := bundle.Instance.InstanceID
+	if err := i.decrypt(&bundle); err != nil {
+		return err
+	}
+
+	conflicts, err := i.conflicts(bundle)
+	if err != nil {
+		return err
+	}
+	if len(conflicts) > 0 && !force {
+		return fmt.Errorf("%w: %s", ErrConflict, strings.Join(conflicts, ", "))
+	}
+
+	return i.db.InTransaction(func(tx storage.BrokerStorage) error {
+		if len(conflicts) > 0 {
+			i.log.Info(fmt.Sprintf("Replacing the existing data of instance %s: %s", instanceID, strings.Join(conflicts, ", ")))
+			if err := deleteExisting(tx, bundle); err != nil {
+				return err
+			}
+		}
+		return i.insert(tx, bundle)
+	})
+}
+
+func (i *Importer) insert(db storage.BrokerStorage, bundle Bundle) error {
+	instanceID := bundle.Instance.InstanceID
+	if err := db.Instances().Insert(bundle.Instance); err != nil {
+		return fmt.Errorf("while inserting instance %s: %w", instanceID, err)
+	}
+	for _, op := range bundle.Operations {
+		if err := db.Operations().InsertOperation(op.toOperation(instanceID)); err != nil {
+			return fmt.Errorf("while inserting operation %s: %w", op.ID, err)
+		}
+	}
+	for _, step := range bundle.OperationSteps {
+		if err := db.OperationSteps().Insert(step); err != nil {
+			return fmt.Errorf("while inserting step %s of operation %s: %w", step.Step, step.OperationID, err)
+		}
+	}
+	for _, binding := range bundle.Bindings {
+		binding.InstanceID = instanceID
+		if err := db.Bindings().Insert(&binding); err != nil {
+			return fmt.Errorf("while inserting binding %s: %w", binding.ID, err)
+		}
+	}
+	// actions and events keep their IDs and creation times, so the history of the instance is not changed by the import
+	for _, action := range bundle.Actions {
+		action.InstanceID = instanceID
+		if err := db.Actions().ImportAction(action); err != nil {
+			return fmt.Errorf("while inserting action %s: %w", action.ID, err)
+		}
+	}
+	if db.Events() != nil {
+		for _, event := range bundle.Events {
+			event.InstanceID = &instanceID
+			if err := db.Events().ImportEvent(event); err != nil {
+				return fmt.Errorf("while inserting event %s: %w", event.ID, err)
+			}
+		}
+	} else if len(bundle.Events) > 0 {
+		i.log.Warn(fmt.Sprintf("Events are disabled in the storage, %d event(s) of instance %s are not imported", len(bundle.Events), instanceID))
+	}
+	if bundle.SubaccountState != nil {
+		if err := db.SubaccountStates().UpsertState(*bundle.SubaccountState); err != nil {
+			return fmt.Errorf("while saving the state of subaccount %s: %w", bundle.SubaccountState.ID, err)
+		}
+	}
+	return nil
+}
+
+// decrypt decrypts the copies of the operations and the bindings, so the bundle passed to Import is not modified
+func (i *Importer) decrypt(bundle *Bundle) error {
+	if err := i.decryptParameters(&bundle.Instance.Parameters); err != nil {
+		return fmt.Errorf("while decrypting the parameters of instance %s: %w", bundle.Instance.InstanceID, err)
+	}
+	operations := make([]Operation, 0, len(bundle.Operations))
+	for _, op := range bundle.Operations {
+		if err := i.decryptParameters(&op.ProvisioningParameters); err != nil {
+			return fmt.Errorf("while decrypting the parameters of operation %s: %w", op.ID, err)
+		}
+		operations = append(operations, op)
+	}
+	bundle.Operations = operations
+
+	bindings := make([]internal.Binding, 0, len(bundle.Bindings))
+	for _, binding := range bundle.Bindings {
+		if binding.Kubeconfig != "" {
+			decrypted, err := i.cipher.DecryptUsingMode([]byte(binding.Kubeconfig))
+			if err != nil {
+				return fmt.Errorf("while decrypting the kubeconfig of binding %s: %w", binding.ID, err)
+			}
+			binding.Kubeconfig = string(decrypted)
+		}
+		bindings = append(bindings, binding)
+	}
+	bundle.Bindings = bindings
+	return nil
+}
+
+func (i *Importer) decryptParameters(parameters *internal.ProvisioningParameters) error {
+	if parameters.ErsContext.SMOperatorCredentials != nil {
+		// the credentials are decrypted in place
+		credentials := *parameters.ErsContext.SMOperatorCredentials
+		parameters.ErsContext.SMOperatorCredentials = &credentials
+	}
+	if err := i.cipher.DecryptSMCredentialsUsingMode(parameters); err != nil {
+		return err
+	}
+	return i.cipher.DecryptKubeconfigUsingMode(parameters)
+}
+
+func (i *Importer) conflicts(bundle Bundle) ([]string, error) {
+	var conflicts []string
+	instanceID := bundle.Instance.InstanceID
+
+	exists, err := found(i.db.Instances().GetByID(instanceID))
+	if err != nil {
+		return nil, fmt.Errorf("while getting instance %s: %w", instanceID, err)
+	}
+	if exists {
+		conflicts = append(conflicts, fmt.Sprintf("instance %s", instanceID))
+	}
+	for _, op := range bundle.Operations {
+		exists, err := found(i.db.Operations().GetOperationByID(op.ID))
+		if err != nil {
+			return nil, fmt.Errorf("while getting operation %s: %w", op.ID, err)
+		}
+		if exists {
+			conflicts = append(conflicts, fmt.Sprintf("operation %s", op.ID))
+		}
+	}
+	for _, binding := range bundle.Bindings {
+		exists, err := found(i.db.Bindings().Get(instanceID, binding.ID))
+		if err != nil {
+			return nil, fmt.Errorf("while getting binding %s: %w", binding.ID, err)
+		}
+		if exists {
+			conflicts = append(conflicts, fmt.Sprintf("binding %s", binding.ID))
+		}
+	}
+	if bundle.SubaccountState != nil {
+		states, err := i.db.SubaccountStates().ListStates()
+		if err != nil {
+			return nil, fmt.Errorf("while listing subaccount states: %w", err)
+		}
+		for _, state := range states {
+			if state.ID == bundle.SubaccountState.ID && state != *bundle.SubaccountState {
+				conflicts = append(conflicts, fmt.Sprintf("subaccount state %s", state.ID))
+			}
+		}
+	}
+	return conflicts, nil
+}
+
+// deleteExisting deletes the instance with all its operations, their steps, bindings, actions and events,
+// and the operations of the bundle which belong to another instance
+func deleteExisting(db storage.BrokerStorage, bundle Bundle) error {
+	instanceID := bundle.Instance.InstanceID
+	bindings, err := db.Bindings().ListByInstanceID(instanceID)
+	if err != nil && !dberr.IsNotFound(err) {
+		return fmt.Errorf("while listing bindings of instance %s: %w", instanceID, err)
+	}
+	for _, binding := range bindings {
+		if err := db.Bindings().Delete(instanceID, binding.ID); err != nil && !dberr.IsNotFound(err) {
+			return fmt.Errorf("while deleting binding %s: %w", binding.ID, err)
+		}
+	}
+
+	operations, err := db.Operations().ListOperationsByInstanceID(instanceID)
+	if err != nil && !dberr.IsNotFound(err) {
+		return fmt.Errorf("while listing operations of instance %s: %w", instanceID, err)
+	}
+	operationIDs := make([]string, 0, len(operations)+len(bundle.Operations))
+	for _, op := range operations {
+		operationIDs = append(operationIDs, op.ID)
+	}
+	for _, op := range bundle.Operations {
+		if !slices.Contains(operationIDs, op.ID) {
+			operationIDs = append(operationIDs, op.ID)
+		}
+	}
+	for _, operationID := range operationIDs {
+		if err := db.OperationSteps().DeleteByOperationID(operationID); err != nil && !dberr.IsNotFound(err) {
+			return fmt.Errorf("while deleting steps of operation %s: %w", operationID, err)
+		}
+		if err := db.Operations().DeleteByID(operationID); err != nil && !dberr.IsNotFound(err) {
+			return fmt.Errorf("while deleting operation %s: %w", operationID, err)
+		}
+	}
+
+	if err := db.Actions().DeleteByInstanceID(instanceID); err != nil {
+		return fmt.Errorf("while deleting actions of instance %s: %w", instanceID, err)
+	}
+	if err := db.Retention().DeleteInstanceEvents(instanceID); err != nil {
+		return fmt.Errorf("while deleting events of instance %s: %w", instanceID, err)
+	}
+	if err := db.Instances().Delete(instanceID); err != nil && !dberr.IsNotFound(err) {
+		return fmt.Errorf("while deleting instance %s: %w", instanceID, err)
+	}
+	return nil
+}
+
+func found[T any](_ T, err error) (bool, error) {
+	switch {
+	case err == nil:
+		return true, nil
+	case dberr.IsNotFound(err):
+		return false, nil
+	default:
+		return false, err
+	}
+}
@@ -0,0 +1,119 @@
+package changes
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	pkg "github.com/kyma-project/kyma-environment-broker/common/changes"
+	"github.com/kyma-project/kyma-environment-broker/internal/httputil"
+	"github.com/kyma-project/kyma-environment-broker/internal/storage"
+)
+
+type Config struct {
+	// MaxWait limits the time the request waits for new changes
+	MaxWait time.Duration `envconfig:"default=60s"`
+	// PollInterval is the interval in which the storage is checked for new changes while the request waits
+	PollInterval time.Duration `envconfig:"default=1s"`
+	// PageSize is the default and the maximal number of changes returned in one response
+	PageSize int `envconfig:"default=100"`
+}
+
+// Handler serves the feed of the changes of the instances and the operations. The client passes the cursor
+// of the last response, so it gets every change once and in the order of the commits.
+type Handler struct {
+	changes storage.Changes
+	cfg     Config
+	log     *slog.Logger
+}
+
+func NewHandler(changes storage.Changes, cfg Config, log *slog.Logger) *Handler {
+	return &Handler{
+		changes: changes,
+		cfg:     cfg,
+		log:     log.With("service", "ChangesEndpoint"),
+	}
+}
+
+func (h *Handler) AttachRoutes(router *httputil.Router) {
+	router.HandleFunc("GET /changes", h.listChanges)
+}
+
+func (h *Handler) listChanges(w http.ResponseWriter, req *http.Request) {
+	query := req.URL.Query()
+	since, err := pkg.ParseCursor(query.Get(pkg.SinceParam))
+	if err != nil {
+		httputil.WriteErrorResponse(w, http.StatusBadRequest, err)
+		return
+	}
+	limit, err := h.limit(query.Get(pkg.LimitParam))
+	if err != nil {
+		httputil.WriteErrorResponse(w, http.StatusBadRequest, err)
+		return
+	}
+	wait, err := h.wait(query.Get(pkg.WaitParam))
+	if err != nil {
+		httputil.WriteErrorResponse(w, http.StatusBadRequest, err)
+		return
+	}
+
+	deadline := time.NewTimer(wait)
+	defer deadline.Stop()
+	for {
+		changes, err := h.changes.List(since, limit)
+		if err != nil {
+			h.log.Error(fmt.Sprintf("unable to list changes: %s", err))
+			httputil.WriteErrorResponse(w, http.StatusInternalServerError, err)
+			return
+		}
+		if len(changes) > 0 || wait == 0 {
+			httputil.WriteResponse(w, http.StatusOK, page(changes, since))
+			return
+		}
+
+		select {
+		case <-req.Context().Done():
+			return
+		case <-deadline.C:
+			wait = 0
+		case <-time.After(h.cfg.PollInterval):
+		}
+	}
+}
+
+func (h *Handler) limit(value string) (int, error) {
+	if value == "" {
+		return h.cfg.PageSize, nil
+	}
+	limit, err := strconv.Atoi(value)
+	if err != nil || limit < 1 {
+		return 0, fmt.Errorf("invalid %s parameter %q", pkg.LimitParam, value)
+	}
+	return min(limit, h.cfg.PageSize), nil
+}
+
+func (h *Handler) wait(value string) (time.Duration, error) {
+	if value == "" {
+		return 0, nil
+	}
+	wait, err := time.ParseDuration(value)
+	if err != nil || wait < 0 {
+		return 0, fmt.Errorf("invalid %s parameter %q", pkg.WaitParam, value)
+	}
+	return min(wait, h.cfg.MaxWait), nil
+}
+
+func page(changes []pkg.ChangeDTO, since int64) pkg.ChangesPage {
+	if len(changes) > 0 {
+		since = changes[len(changes)-1].Sequence
+	}
+	if changes == nil {
+		changes = []pkg.ChangeDTO{}
+	}
+	return pkg.ChangesPage{
+		Data:   changes,
+		Cursor: pkg.Cursor(since),
+	}
+}
@@ -0,0 +1,109 @@
+package changes
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	pkg "github.com/kyma-project/kyma-environment-broker/common/changes"
+	"github.com/kyma-project/kyma-environment-broker/internal"
+	"github.com/kyma-project/kyma-environment-broker/internal/fixture"
+	"github.com/kyma-project/kyma-environment-broker/internal/httputil"
+	"github.com/kyma-project/kyma-environment-broker/internal/storage"
+	"github.com/pivotal-cf/brokerapi/v12/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler_ListChanges(t *testing.T) {
+	cfg := Config{MaxWait: time.Second, PollInterval: 10 * time.Millisecond, PageSize: 2}
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+
+	t.Run("should return the changes in pages", func(t *testing.T) {
+		// given
+		db := storage.NewMemoryStorage()
+		router := httputil.NewRouter()
+		NewHandler(db.Changes(), cfg, logger).AttachRoutes(router)
+
+		require.NoError(t, db.Instances().Insert(fixture.FixInstance("instance-01")))
+		operation := fixture.FixOperation("op-01", "instance-01", internal.OperationTypeProvision)
+		operation.State = domain.InProgress
+		require.NoError(t, db.Operations().InsertOperation(operation))
+		operation.State = domain.Succeeded
+		_, err := db.Operations().UpdateOperation(operation)
+		require.NoError(t, err)
+
+		// when
+		first := listChanges(t, router, "/changes")
+		second := listChanges(t, router, "/changes?since="+first.Cursor)
+
+		// then
+		require.Len(t, first.Data, 2)
+		assert.Equal(t, pkg.InstanceKind, first.Data[0].Kind)
+		assert.Equal(t, pkg.InstanceCreated, first.Data[0].State)
+		assert.Equal(t, "2", first.Cursor)
+		require.Len(t, second.Data, 1)
+		assert.Equal(t, "op-01", second.Data[0].OperationID)
+		assert.Equal(t, string(domain.Succeeded), second.Data[0].State)
+		assert.Equal(t, string(domain.InProgress), second.Data[0].PreviousState)
+		assert.Equal(t, "3", second.Cursor)
+	})
+
+	t.Run("should wait for the next change", func(t *testing.T) {
+		// given
+		db := storage.NewMemoryStorage()
+		router := httputil.NewRouter()
+		NewHandler(db.Changes(), cfg, logger).AttachRoutes(router)
+
+		go func() {
+			time.Sleep(50 * time.Millisecond)
+			_ = db.Instances().Insert(fixture.FixInstance("instance-01"))
+		}()
+
+		// when
+		page := listChanges(t, router, "/changes?wait=1s")
+
+		// then
+		require.Len(t, page.Data, 1)
+		assert.Equal(t, "instance-01", page.Data[0].InstanceID)
+	})
+
+	t.Run("should return the same cursor when there are no changes", func(t *testing.T) {
+		// given
+		db := storage.NewMemoryStorage()
+		router := httputil.NewRouter()
+		NewHandler(db.Changes(), cfg, logger).AttachRoutes(router)
+
+		// when
+		page := listChanges(t, router, "/changes?since=5&wait=20ms")
+
+		// then
+		assert.Empty(t, page.Data)
+		assert.Equal(t, "5", page.Cursor)
+	})
+
+	t.Run("should reject invalid parameters", func(t *testing.T) {
+		router := httputil.NewRouter()
+		NewHandler(storage.NewMemoryStorage().Changes(), cfg, logger).AttachRoutes(router)
+
+		for _, query := range []string{"since=abc", "limit=0", "wait=soon"} {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/changes?"+query, nil))
+			assert.Equal(t, http.StatusBadRequest, w.Code, query)
+		}
+	})
+}
+
+func listChanges(t *testing.T, router *httputil.Router, url string) pkg.ChangesPage {
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, url, nil))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var page pkg.ChangesPage
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &page))
+	return page
+}
@@ -2,8 +2,10 @@ package events
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/kyma-project/kyma-environment-broker/common/events"
 	"github.com/kyma-project/kyma-environment-broker/internal/storage"
@@ -27,11 +29,12 @@ func split(s string) []string {
 }
 
 func (h Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	instanceId := r.URL.Query().Get("instance_ids")
-	instanceIds := split(instanceId)
-	runtimeId := r.URL.Query().Get("runtime_ids")
-	operationId := r.URL.Query().Get("operation_ids")
-	operationIds := split(operationId)
+	filter, err := getFilter(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	runtimeId := r.URL.Query().Get(events.RuntimeIDsParam)
 	if runtimeId != "" {
 		instances, _, _, err := h.i.List(dbmodel.InstanceFilter{RuntimeIDs: split(runtimeId)})
 		if err != nil {
@@ -39,10 +42,10 @@ func (h Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 		for _, i := range instances {
-			instanceIds = append(instanceIds, i.InstanceID)
+			filter.InstanceIDs = append(filter.InstanceIDs, i.InstanceID)
 		}
 	}
-	events, err := h.e.ListEvents(events.EventFilter{InstanceIDs: instanceIds, OperationIDs: operationIds})
+	events, err := h.e.ListEvents(filter)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusServiceUnavailable)
 		return
@@ -56,3 +59,38 @@ func (h Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, err.Error(), http.StatusServiceUnavailable)
 	}
 }
+
+func getFilter(r *http.Request) (events.EventFilter, error) {
+	query := r.URL.Query()
+	filter := events.EventFilter{
+		InstanceIDs:  split(query.Get(events.InstanceIDsParam)),
+		OperationIDs: split(query.Get(events.OperationIDsParam)),
+		Categories:   split(query.Get(events.CategoriesParam)),
+		Text:         query.Get(events.TextParam),
+	}
+	for _, level := range split(query.Get(events.LevelsParam)) {
+		if !events.EventLevel(level).IsValid() {
+			return filter, fmt.Errorf("invalid level: %s", level)
+		}
+		filter.Levels = append(filter.Levels, events.EventLevel(level))
+	}
+	var err error
+	if filter.From, err = getTime(query.Get(events.FromParam), events.FromParam); err != nil {
+		return filter, err
+	}
+	if filter.To, err = getTime(query.Get(events.ToParam), events.ToParam); err != nil {
+		return filter, err
+	}
+	return filter, nil
+}
+
+func getTime(value, name string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid %s parameter, expected time in the RFC 3339 format: %s", name, value)
+	}
+	return t, nil
+}
@@ -0,0 +1,82 @@
+package events
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kyma-project/kyma-environment-broker/common/events"
+	"github.com/kyma-project/kyma-environment-broker/internal/fixture"
+	"github.com/kyma-project/kyma-environment-broker/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeEvents struct {
+	filter events.EventFilter
+}
+
+func (f *fakeEvents) InsertEvent(events.EventDTO) {}
+
+func (f *fakeEvents) ImportEvent(events.EventDTO) error { return nil }
+
+func (f *fakeEvents) ListEvents(filter events.EventFilter) ([]events.EventDTO, error) {
+	f.filter = filter
+	return []events.EventDTO{{Level: events.WarningEventLevel, Category: "check_runtime_resource"}}, nil
+}
+
+func TestHandler_ServeHTTP(t *testing.T) {
+	t.Run("should pass the filters to the storage", func(t *testing.T) {
+		// given
+		db := storage.NewMemoryStorage()
+		instance := fixture.FixInstance("instance-01")
+		require.NoError(t, db.Instances().Insert(instance))
+		fake := &fakeEvents{}
+		handler := NewHandler(fake, db.Instances())
+		req := httptest.NewRequest(http.MethodGet, "/events?runtime_ids="+instance.RuntimeID+"&levels=warning,error&categories=check_runtime_resource&from=2026-10-17T10:00:00Z&to=2026-10-17T11:00:00Z&text=quota", nil)
+		rr := httptest.NewRecorder()
+
+		// when
+		handler.ServeHTTP(rr, req)
+
+		// then
+		require.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, []string{instance.InstanceID}, fake.filter.InstanceIDs)
+		assert.Equal(t, []events.EventLevel{events.WarningEventLevel, events.ErrorEventLevel}, fake.filter.Levels)
+		assert.Equal(t, []string{"check_runtime_resource"}, fake.filter.Categories)
+		assert.Equal(t, time.Date(2026, 10, 17, 10, 0, 0, 0, time.UTC), fake.filter.From.UTC())
+		assert.Equal(t, time.Date(2026, 10, 17, 11, 0, 0, 0, time.UTC), fake.filter.To.UTC())
+		assert.Equal(t, "quota", fake.filter.Text)
+
+		var list []events.EventDTO
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &list))
+		require.Len(t, list, 1)
+		assert.Equal(t, events.WarningEventLevel, list[0].Level)
+	})
+
+	t.Run("should reject an invalid level", func(t *testing.T) {
+		// given
+		handler := NewHandler(&fakeEvents{}, storage.NewMemoryStorage().Instances())
+		rr := httptest.NewRecorder()
+
+		// when
+		handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/events?levels=fatal", nil))
+
+		// then
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+
+	t.Run("should reject an invalid time", func(t *testing.T) {
+		// given
+		handler := NewHandler(&fakeEvents{}, storage.NewMemoryStorage().Instances())
+		rr := httptest.NewRecorder()
+
+		// when
+		handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/events?from=yesterday", nil))
+
+		// then
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+}
@@ -21,7 +21,8 @@ var (
 
 type Interface interface {
 	ListEvents(filter events.EventFilter) ([]events.EventDTO, error)
-	InsertEvent(eventLevel events.EventLevel, message, instanceID, operationID string)
+	InsertEvent(event events.EventDTO)
+	ImportEvent(event events.EventDTO) error
 	RunGarbageCollection(pollingPeriod, retention time.Duration)
 }
 
@@ -38,16 +39,32 @@ func New(cfg Config, events Interface) Interface {
 	return ev
 }
 
+func Debugf(instanceID, operationID, format string, args ...any) {
+	Insert(events.DebugEventLevel, "", nil, instanceID, operationID, fmt.Sprintf(format, args...))
+}
+
 func Infof(instanceID, operationID, format string, args ...any) {
-	insertEvent(events.InfoEventLevel, fmt.Sprintf(format, args...), instanceID, operationID)
+	Insert(events.InfoEventLevel, "", nil, instanceID, operationID, fmt.Sprintf(format, args...))
+}
+
+func Warnf(instanceID, operationID, format string, args ...any) {
+	Insert(events.WarningEventLevel, "", nil, instanceID, operationID, fmt.Sprintf(format, args...))
 }
 
 func Errorf(instanceID, operationID string, err error, format string, args ...any) {
-	insertEvent(events.ErrorEventLevel, fmt.Sprintf("%v: %v", fmt.Sprintf(format, args...), err), instanceID, operationID)
+	Insert(events.ErrorEventLevel, "", nil, instanceID, operationID, fmt.Sprintf("%v: %v", fmt.Sprintf(format, args...), err))
 }
 
-func insertEvent(eventLevel events.EventLevel, msg, instanceID, operationID string) {
+// Insert records the event of the category with the structured attributes
+func Insert(level events.EventLevel, category string, attributes events.Attributes, instanceID, operationID, msg string) {
 	if ev != nil {
-		ev.InsertEvent(eventLevel, msg, instanceID, operationID)
+		ev.InsertEvent(events.EventDTO{
+			Level:       level,
+			InstanceID:  &instanceID,
+			OperationID: &operationID,
+			Category:    category,
+			Message:     msg,
+			Attributes:  attributes,
+		})
 	}
 }
@@ -69,7 +69,7 @@ func (h *handler) expireInstance(w http.ResponseWriter, req *http.Request) {
 	}
 	logger = logger.With("planName", instance.ServicePlanName)
 
-	if instance.ServicePlanID != broker.TrialPlanID && instance.ServicePlanID != broker.FreemiumPlanID {
+	if !broker.IsTrialPlan(instance.ServicePlanID) && !broker.IsFreemiumPlan(instance.ServicePlanID) {
 		msg := fmt.Sprintf("unsupported plan: %s", broker.AvailablePlans.GetPlanNameOrEmpty(broker.PlanIDType(instance.ServicePlanID)))
 		logger.Warn(msg)
 		httputil.WriteErrorResponse(w, http.StatusBadRequest, errors.New(msg))
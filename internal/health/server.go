@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"log/slog"
 	"net/http"
+	"sync/atomic"
 
 	"github.com/kyma-project/kyma-environment-broker/internal/httputil"
 )
@@ -11,6 +12,8 @@ import (
 type Server struct {
 	Address string
 	Log     *slog.Logger
+
+	notReady atomic.Bool
 }
 
 func NewServer(host, port string, log *slog.Logger) *Server {
@@ -21,18 +24,39 @@ func NewServer(host, port string, log *slog.Logger) *Server {
 }
 
 func (srv *Server) ServeAsync() {
-	healthRouter := httputil.NewRouter()
-	healthRouter.HandleFunc("/healthz", livenessHandler())
 	go func() {
-		err := http.ListenAndServe(srv.Address, healthRouter)
+		err := http.ListenAndServe(srv.Address, srv.Handler())
 		if err != nil {
 			srv.Log.Error(fmt.Sprintf("HTTP Health server ListenAndServe: %v", err))
 		}
 	}()
 }
 
+func (srv *Server) Handler() http.Handler {
+	healthRouter := httputil.NewRouter()
+	healthRouter.HandleFunc("/healthz", livenessHandler())
+	healthRouter.HandleFunc("/readyz", srv.readinessHandler())
+	return healthRouter
+}
+
+// SetNotReady makes the readiness probe fail, so the application stops receiving new requests while it shuts down
+func (srv *Server) SetNotReady() {
+	srv.notReady.Store(true)
+	srv.Log.Info("Readiness probe set to not ready")
+}
+
 func livenessHandler() func(w http.ResponseWriter, _ *http.Request) {
 	return func(w http.ResponseWriter, _ *http.Request) {
 		w.WriteHeader(http.StatusOK)
 	}
 }
+
+func (srv *Server) readinessHandler() func(w http.ResponseWriter, _ *http.Request) {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		if srv.notReady.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}
+}
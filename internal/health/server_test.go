@@ -0,0 +1,36 @@
+package health_test
+
+import (
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/kyma-project/kyma-environment-broker/internal/health"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServer_Probes(t *testing.T) {
+	// given
+	srv := health.NewServer("localhost", "8080", slog.New(slog.NewTextHandler(os.Stdout, nil)))
+	handler := srv.Handler()
+
+	probe := func(path string) int {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		return rec.Code
+	}
+
+	// then
+	assert.Equal(t, http.StatusOK, probe("/healthz"))
+	assert.Equal(t, http.StatusOK, probe("/readyz"))
+
+	// when
+	srv.SetNotReady()
+
+	// then
+	assert.Equal(t, http.StatusOK, probe("/healthz"))
+	assert.Equal(t, http.StatusServiceUnavailable, probe("/readyz"))
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"strconv"
 	"sync"
 	"time"
 
@@ -92,6 +93,37 @@ func (c *BindingCreationCollector) OnBindingCreated(ctx context.Context, ev inte
 	return nil
 }
 
+type BindingRotationCollector struct {
+	bindingRotated *prometheus.CounterVec
+}
+
+// NewBindingRotationCollector provides a counter which shows the total number of rotated bindings:
+// - kcp_keb_v2_binding_rotated_total{plan_id,auto_renew}
+func NewBindingRotationCollector() *BindingRotationCollector {
+	return &BindingRotationCollector{
+		bindingRotated: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: prometheusNamespaceV2,
+			Subsystem: prometheusSubsystemV2,
+			Name:      "binding_rotated_total",
+			Help:      "The total number of bindings whose credentials were rotated",
+		}, []string{"plan_id", "auto_renew"}),
+	}
+}
+
+func (c *BindingRotationCollector) Describe(ch chan<- *prometheus.Desc) {
+	c.bindingRotated.Describe(ch)
+}
+
+func (c *BindingRotationCollector) Collect(ch chan<- prometheus.Metric) {
+	c.bindingRotated.Collect(ch)
+}
+
+func (c *BindingRotationCollector) OnBindingRotated(ctx context.Context, ev interface{}) error {
+	obj := ev.(broker.BindingRotated)
+	c.bindingRotated.WithLabelValues(obj.PlanID, strconv.FormatBool(obj.AutoRenew)).Inc()
+	return nil
+}
+
 type BindingStatitics struct {
 	db     storage.Bindings
 	logger *slog.Logger
@@ -76,6 +76,9 @@ func Register(ctx context.Context, sub event.Subscriber, db storage.BrokerStorag
 	bindCrestedCollector := NewBindingCreationCollector()
 	prometheus.MustRegister(bindCrestedCollector)
 
+	bindRotatedCollector := NewBindingRotationCollector()
+	prometheus.MustRegister(bindRotatedCollector)
+
 	stepDurationCollector := NewStepDurationCollector()
 	prometheus.MustRegister(stepDurationCollector)
 
@@ -90,6 +93,7 @@ func Register(ctx context.Context, sub event.Subscriber, db storage.BrokerStorag
 	sub.Subscribe(broker.BindRequestProcessed{}, bindDurationCollector.OnBindingExecuted)
 	sub.Subscribe(broker.UnbindRequestProcessed{}, bindDurationCollector.OnUnbindingExecuted)
 	sub.Subscribe(broker.BindingCreated{}, bindCrestedCollector.OnBindingCreated)
+	sub.Subscribe(broker.BindingRotated{}, bindRotatedCollector.OnBindingRotated)
 
 	credentialsBindingsCollector := NewCredentialsBindingsCollector(db.Instances(), gardenerClient, cfg.CredentialsBindingsPollingInterval, cfg.AvailableCredentialsBindingsPollingInterval, logger)
 	credentialsBindingsCollector.StartCollector(ctx)
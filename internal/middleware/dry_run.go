@@ -0,0 +1,35 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+)
+
+// DryRunParam is the query parameter which makes the broker validate the request without applying it
+const DryRunParam = "dry_run"
+
+// The dryRunKey type is no exported to prevent collisions with context keys
+// defined in other packages.
+type dryRunKey int
+
+const (
+	// requestDryRunKey is the context key for the dry run flag from the request query.
+	requestDryRunKey dryRunKey = iota + 1
+)
+
+func AddDryRunToContext() MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			dryRun := req.URL.Query().Get(DryRunParam) == "true"
+
+			newCtx := context.WithValue(req.Context(), requestDryRunKey, dryRun)
+			next.ServeHTTP(w, req.WithContext(newCtx))
+		})
+	}
+}
+
+// DryRunFromContext returns true if the request associated with the context is a dry run.
+func DryRunFromContext(ctx context.Context) bool {
+	dryRun, ok := ctx.Value(requestDryRunKey).(bool)
+	return ok && dryRun
+}
@@ -0,0 +1,46 @@
+package middleware_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kyma-project/kyma-environment-broker/internal/httputil"
+	"github.com/kyma-project/kyma-environment-broker/internal/middleware"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestDryRunKey(t *testing.T) {
+	for name, tc := range map[string]struct {
+		query    string
+		expected bool
+	}{
+		"dry run":         {query: "?dry_run=true", expected: true},
+		"not a dry run":   {query: "?dry_run=false", expected: false},
+		"no query params": {query: "", expected: false},
+	} {
+		t.Run(name, func(t *testing.T) {
+			// given
+			req, err := http.NewRequest(http.MethodPut, "http://url.dev/endpoint"+tc.query, nil)
+			require.NoError(t, err)
+
+			var gotCtx context.Context
+			spyHandler := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				gotCtx = req.Context()
+			})
+
+			router := httputil.NewRouter()
+			router.Use(middleware.AddDryRunToContext())
+			router.HandleFunc("/endpoint", spyHandler)
+
+			// when
+			router.ServeHTTP(httptest.NewRecorder(), req)
+
+			// then
+			assert.Equal(t, tc.expected, middleware.DryRunFromContext(gotCtx))
+		})
+	}
+}
@@ -13,3 +13,7 @@ func AddRegionToCtx(ctx context.Context, region string) context.Context {
 func AddProviderToCtx(ctx context.Context, provider pkg.CloudProvider) context.Context {
 	return context.WithValue(ctx, requestProviderKey, provider)
 }
+
+func AddDryRunToCtx(ctx context.Context) context.Context {
+	return context.WithValue(ctx, requestDryRunKey, true)
+}
@@ -1,6 +1,7 @@
 package internal
 
 import (
+	"encoding/json"
 	"fmt"
 	"log/slog"
 	"time"
@@ -8,6 +9,7 @@ import (
 	"github.com/kyma-project/kyma-environment-broker/internal/euaccess"
 
 	"github.com/google/uuid"
+	eventsapi "github.com/kyma-project/kyma-environment-broker/common/events"
 	"github.com/kyma-project/kyma-environment-broker/common/gardener"
 	pkg "github.com/kyma-project/kyma-environment-broker/common/runtime"
 	kebError "github.com/kyma-project/kyma-environment-broker/internal/error"
@@ -66,6 +68,34 @@ func (i *Instance) GetSubscriptionGlobalAccoundID() string {
 	}
 }
 
+// ChangedFrom returns true if the instance differs from the stored version in other fields than the update time,
+// the version and the number of the empty updates, which are changed by every write
+func (i *Instance) ChangedFrom(stored Instance) bool {
+	if i.RuntimeID != stored.RuntimeID || i.GlobalAccountID != stored.GlobalAccountID ||
+		i.SubscriptionGlobalAccountID != stored.SubscriptionGlobalAccountID || i.SubAccountID != stored.SubAccountID ||
+		i.ServiceID != stored.ServiceID || i.ServiceName != stored.ServiceName ||
+		i.ServicePlanID != stored.ServicePlanID || i.ServicePlanName != stored.ServicePlanName ||
+		i.SubscriptionSecretName != stored.SubscriptionSecretName || i.DashboardURL != stored.DashboardURL ||
+		i.ProviderRegion != stored.ProviderRegion || i.Provider != stored.Provider {
+		return true
+	}
+	if !i.CreatedAt.Equal(stored.CreatedAt) || !i.DeletedAt.Equal(stored.DeletedAt) {
+		return true
+	}
+	if (i.ExpiredAt == nil) != (stored.ExpiredAt == nil) || (i.ExpiredAt != nil && !i.ExpiredAt.Equal(*stored.ExpiredAt)) {
+		return true
+	}
+	parameters, err := json.Marshal(i.Parameters)
+	if err != nil {
+		return true
+	}
+	storedParameters, err := json.Marshal(stored.Parameters)
+	if err != nil {
+		return true
+	}
+	return string(parameters) != string(storedParameters)
+}
+
 func (i *Instance) GetInstanceDetails() (InstanceDetails, error) {
 	result := i.InstanceDetails
 	// overwrite RuntimeID in InstanceDetails with Instance.RuntimeID
@@ -142,6 +172,9 @@ type Operation struct {
 	// UpdatedPlanID is used to store the plan ID if the plan has been changed, "" if not changed
 	UpdatedPlanID string `json:"updated_plan_id,omitempty"`
 
+	// RuntimeResourceUpdated is stored before the Runtime resource is patched, the update cannot be reverted by a cancellation after that
+	RuntimeResourceUpdated bool `json:"runtime_resource_updated,omitempty"`
+
 	// UPGRADE KYMA
 	RuntimeOperation            `json:"runtime_operation"`
 	ClusterConfigurationApplied bool `json:"cluster_configuration_applied"`
@@ -156,6 +189,25 @@ type Operation struct {
 
 	// PreviousParameters stores the instance parameters before the update
 	PreviousParameters ProvisioningParameters `json:"previous_parameters"`
+
+	// RetriedAt is set when a failed operation is retried manually, the operation timeout is counted from that moment
+	RetriedAt *time.Time `json:"retried_at,omitempty"`
+
+	// Lease is set by the broker instance which processes the operation, other instances skip the operation until the lease expires
+	Lease *OperationLease `json:"lease,omitempty"`
+
+	// StepRetries counts the failed executions of the steps with a retry policy, the entry is removed when the step succeeds
+	StepRetries map[string]StepRetry `json:"step_retries,omitempty"`
+}
+
+type StepRetry struct {
+	Attempts       int       `json:"attempts"`
+	FirstFailureAt time.Time `json:"first_failure_at"`
+}
+
+type OperationLease struct {
+	Owner     string    `json:"owner"`
+	ExpiresAt time.Time `json:"expires_at"`
 }
 
 // ProviderValues contains values which are specific to particular plans (and provisioning parameters)
@@ -180,6 +232,19 @@ type GroupedOperations struct {
 	UpdateOperations         []UpdatingOperation
 }
 
+// ProcessingStartedAt returns the time from which the operation timeout is measured
+func (o *Operation) ProcessingStartedAt() time.Time {
+	if o.RetriedAt != nil {
+		return *o.RetriedAt
+	}
+	return o.CreatedAt
+}
+
+// IsLeasedByOther returns true if the operation is processed by another broker instance
+func (o *Operation) IsLeasedByOther(owner string, now time.Time) bool {
+	return o.Lease != nil && o.Lease.Owner != owner && o.Lease.ExpiresAt.After(now)
+}
+
 func (o *Operation) IsFinished() bool {
 	return o.State != OperationStateInProgress && o.State != OperationStatePending && o.State != OperationStateCanceling && o.State != OperationStateRetrying
 }
@@ -192,7 +257,18 @@ func (o *Operation) EventErrorf(err error, fmt string, args ...any) {
 	events.Errorf(o.InstanceID, o.ID, err, fmt, args...)
 }
 
-func (o *Operation) Merge(operation *Operation) {
+// StepEventf records the event of the level in the category of the step, the type of the operation and the step are added to the attributes.
+// The event about an error of a dependency, given by the component attribute, is recorded in the category of the dependency.
+func (o *Operation) StepEventf(level eventsapi.EventLevel, step string, attributes eventsapi.Attributes, format string, args ...any) {
+	all := eventsapi.Attributes{"operationType": string(o.Type), "step": step}
+	for key, value := range attributes {
+		all[key] = value
+	}
+	category := step
+	if component := kebError.Component(all["component"]); component != "" && component != kebError.KEBDependency {
+		category = string(component)
+	}
+	events.Insert(level, category, all, o.InstanceID, o.ID, fmt.Sprintf(format, args...))
 }
 
 type InstanceDetails struct {
@@ -518,6 +594,22 @@ type SubaccountState struct {
 	ModifiedAt        int64  `json:"modifiedAt"`
 }
 
+// QueueItem is an operation scheduled in the persistent processing queue
+type QueueItem struct {
+	QueueName   string
+	OperationID string
+
+	// NextRunAt is the time when the operation can be processed
+	NextRunAt time.Time
+	// LeaseOwner identifies the queue instance which processes the operation, empty if not leased
+	LeaseOwner     string
+	LeaseExpiresAt time.Time
+
+	// Version is incremented every time the operation is added to the queue
+	Version   int
+	CreatedAt time.Time
+}
+
 type DeletedStats struct {
 	NumberOfDeletedInstances              int
 	NumberOfOperationsForDeletedInstances int
@@ -534,6 +626,24 @@ type Binding struct {
 	Kubeconfig        string
 	ExpirationSeconds int64
 	CreatedBy         string
+
+	// State is in progress until the credentials are created, the bindings created synchronously
+	// before the state was stored have the succeeded state
+	State       domain.LastOperationState
+	Description string
+	// OperationID identifies the asynchronous creation of the binding, it is empty for the bindings created synchronously
+	OperationID string
+
+	// CredentialsType is the kind of the kubeconfig, service_account or oidc
+	CredentialsType string
+	// Group is bound to the cluster role of the oidc binding
+	Group string
+	// AutoRenew makes KEB rotate the credentials of the binding before they expire
+	AutoRenew bool
+	// Role is the role template granted by the binding, the bindings without the role have full access
+	Role string
+	// Namespaces limit the access of the binding, the access is cluster-wide when the list is empty
+	Namespaces []string
 }
 
 type RetryTuple struct {
@@ -2,8 +2,14 @@ package internal
 
 import (
 	"testing"
+	"time"
+
+	eventsapi "github.com/kyma-project/kyma-environment-broker/common/events"
+	kebError "github.com/kyma-project/kyma-environment-broker/internal/error"
+	"github.com/kyma-project/kyma-environment-broker/internal/events"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestFinishStage(t *testing.T) {
@@ -45,6 +51,48 @@ func TestFinishStage(t *testing.T) {
 	})
 }
 
+func TestOperation_StepEventf(t *testing.T) {
+	// given
+	recorded := &recordingEvents{}
+	events.New(events.Config{Enabled: true, PollingPeriod: time.Hour}, recorded)
+	operation := Operation{ID: "op-id", InstanceID: "instance-id", Type: OperationTypeProvision}
+
+	// when
+	operation.StepEventf(eventsapi.InfoEventLevel, "create_runtime_resource", nil, "processing step")
+	operation.StepEventf(eventsapi.ErrorEventLevel, "create_runtime_resource", eventsapi.Attributes{"component": string(kebError.KEBDependency)}, "step failed")
+	operation.StepEventf(eventsapi.ErrorEventLevel, "check_runtime_resource", eventsapi.Attributes{"component": string(kebError.InfrastructureManagerDependency)}, "step failed")
+
+	// then
+	require.Len(t, recorded.events, 3)
+	assert.Equal(t, "create_runtime_resource", recorded.events[0].Category)
+	assert.Equal(t, "create_runtime_resource", recorded.events[1].Category)
+	assert.Equal(t, string(kebError.InfrastructureManagerDependency), recorded.events[2].Category)
+	assert.Equal(t, eventsapi.Attributes{
+		"operationType": string(OperationTypeProvision),
+		"step":          "check_runtime_resource",
+		"component":     string(kebError.InfrastructureManagerDependency),
+	}, recorded.events[2].Attributes)
+}
+
+type recordingEvents struct {
+	events []eventsapi.EventDTO
+}
+
+func (r *recordingEvents) ListEvents(eventsapi.EventFilter) ([]eventsapi.EventDTO, error) {
+	return r.events, nil
+}
+
+func (r *recordingEvents) InsertEvent(event eventsapi.EventDTO) {
+	r.events = append(r.events, event)
+}
+
+func (r *recordingEvents) ImportEvent(event eventsapi.EventDTO) error {
+	r.events = append(r.events, event)
+	return nil
+}
+
+func (r *recordingEvents) RunGarbageCollection(time.Duration, time.Duration) {}
+
 func countStageOccurrences(operation ProvisioningOperation, stage string) int {
 	foundStages := 0
 	for _, v := range operation.FinishedStages {
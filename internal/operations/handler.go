@@ -0,0 +1,268 @@
+package operations
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	pkg "github.com/kyma-project/kyma-environment-broker/common/runtime"
+	"github.com/kyma-project/kyma-environment-broker/internal"
+	kebError "github.com/kyma-project/kyma-environment-broker/internal/error"
+	"github.com/kyma-project/kyma-environment-broker/internal/httputil"
+	"github.com/kyma-project/kyma-environment-broker/internal/ptr"
+	"github.com/kyma-project/kyma-environment-broker/internal/storage"
+	"github.com/kyma-project/kyma-environment-broker/internal/storage/dberr"
+	"github.com/kyma-project/kyma-environment-broker/internal/suspension"
+	"github.com/pivotal-cf/brokerapi/v12/domain"
+)
+
+const updateAttempts = 3
+
+type router interface {
+	HandleFunc(pattern string, handler func(http.ResponseWriter, *http.Request))
+}
+
+type operationResponse struct {
+	OperationID string `json:"operation"`
+	State       string `json:"state"`
+}
+
+type timelineResponse struct {
+	OperationID string              `json:"operation"`
+	Type        string              `json:"type"`
+	State       string              `json:"state"`
+	CreatedAt   time.Time           `json:"createdAt"`
+	UpdatedAt   time.Time           `json:"updatedAt"`
+	Steps       []pkg.OperationStep `json:"steps"`
+}
+
+type Handler interface {
+	AttachRoutes(r router)
+}
+
+type handler struct {
+	operations        storage.Operations
+	actions           storage.Actions
+	steps             storage.OperationSteps
+	provisioningQueue suspension.Adder
+	updateQueue       suspension.Adder
+	log               *slog.Logger
+}
+
+func NewHandler(operationsStorage storage.Operations, actionsStorage storage.Actions, stepsStorage storage.OperationSteps, provisioningQueue, updateQueue suspension.Adder, log *slog.Logger) Handler {
+	return &handler{
+		operations:        operationsStorage,
+		actions:           actionsStorage,
+		steps:             stepsStorage,
+		provisioningQueue: provisioningQueue,
+		updateQueue:       updateQueue,
+		log:               log.With("service", "OperationsEndpoint"),
+	}
+}
+
+func (h *handler) AttachRoutes(r router) {
+	r.HandleFunc("POST /operations/{operation_id}/cancel", h.cancelOperation)
+	r.HandleFunc("POST /operations/{operation_id}/retry", h.retryOperation)
+	r.HandleFunc("GET /operations/{operation_id}/timeline", h.getTimeline)
+}
+
+func (h *handler) cancelOperation(w http.ResponseWriter, req *http.Request) {
+	operationID := req.PathValue("operation_id")
+
+	h.log.Info(fmt.Sprintf("Cancellation requested for operationID: %s", operationID))
+	logger := h.log.With("operationID", operationID)
+
+	for attempt := 1; ; attempt++ {
+		operation, err := h.operations.GetOperationByID(operationID)
+		if err != nil {
+			logger.Error(fmt.Sprintf("unable to get operation: %s", err.Error()))
+			switch {
+			case dberr.IsNotFound(err):
+				httputil.WriteErrorResponse(w, http.StatusNotFound, err)
+			default:
+				httputil.WriteErrorResponse(w, http.StatusInternalServerError, err)
+			}
+			return
+		}
+		opLogger := logger.With("instanceID", operation.InstanceID, "operationType", operation.Type)
+
+		// the canceled update restores the instance parameters, the canceled provisioning is reported as failed,
+		// so the platform deprovisions the instance and the deprovisioning removes the runtime
+		queue := h.queueFor(operation.Type)
+		if queue == nil {
+			msg := fmt.Sprintf("cancellation of %s operations is not supported", operation.Type)
+			opLogger.Warn(msg)
+			httputil.WriteErrorResponse(w, http.StatusBadRequest, errors.New(msg))
+			return
+		}
+
+		switch operation.State {
+		case internal.OperationStateCanceling:
+			opLogger.Info("operation cancellation already requested")
+			httputil.WriteResponse(w, http.StatusAccepted, operationResponse{OperationID: operation.ID, State: string(operation.State)})
+			return
+		case internal.OperationStateCanceled:
+			opLogger.Info("operation already canceled")
+			httputil.WriteResponse(w, http.StatusOK, operationResponse{OperationID: operation.ID, State: string(operation.State)})
+			return
+		}
+		if operation.IsFinished() {
+			msg := fmt.Sprintf("operation is already finished with state %s", operation.State)
+			opLogger.Warn(msg)
+			httputil.WriteErrorResponse(w, http.StatusConflict, errors.New(msg))
+			return
+		}
+
+		operation.State = internal.OperationStateCanceling
+		operation.Description = "Operation cancellation requested"
+		updated, err := h.operations.UpdateOperation(*operation)
+		switch {
+		case err == nil:
+		case dberr.IsConflict(err) && attempt < updateAttempts:
+			// the operation was modified in the meantime by the processing, read it again
+			opLogger.Info(fmt.Sprintf("operation modified in the meantime, retrying: %s", err.Error()))
+			continue
+		default:
+			opLogger.Error(fmt.Sprintf("unable to update the operation: %s", err.Error()))
+			httputil.WriteErrorResponse(w, http.StatusInternalServerError, err)
+			return
+		}
+
+		// the operation is processed by the queue which runs the cancellation steps
+		queue.Add(updated.ID)
+		opLogger.Info("operation marked for cancellation and added to the queue")
+
+		httputil.WriteResponse(w, http.StatusAccepted, operationResponse{OperationID: updated.ID, State: string(updated.State)})
+		return
+	}
+}
+
+func (h *handler) retryOperation(w http.ResponseWriter, req *http.Request) {
+	operationID := req.PathValue("operation_id")
+
+	h.log.Info(fmt.Sprintf("Retry requested for operationID: %s", operationID))
+	logger := h.log.With("operationID", operationID)
+
+	operation, err := h.operations.GetOperationByID(operationID)
+	if err != nil {
+		logger.Error(fmt.Sprintf("unable to get operation: %s", err.Error()))
+		switch {
+		case dberr.IsNotFound(err):
+			httputil.WriteErrorResponse(w, http.StatusNotFound, err)
+		default:
+			httputil.WriteErrorResponse(w, http.StatusInternalServerError, err)
+		}
+		return
+	}
+	logger = logger.With("instanceID", operation.InstanceID, "operationType", operation.Type)
+
+	queue := h.queueFor(operation.Type)
+	if queue == nil {
+		msg := fmt.Sprintf("retry of %s operations is not supported", operation.Type)
+		logger.Warn(msg)
+		httputil.WriteErrorResponse(w, http.StatusBadRequest, errors.New(msg))
+		return
+	}
+	if operation.State != domain.Failed {
+		msg := fmt.Sprintf("only failed operations can be retried, the operation is in state %s", operation.State)
+		logger.Warn(msg)
+		httputil.WriteErrorResponse(w, http.StatusConflict, errors.New(msg))
+		return
+	}
+
+	// retrying an operation which is not the last one would overwrite the changes made by the newer operation
+	lastOperation, err := h.operations.GetLastOperationWithAllStates(operation.InstanceID)
+	if err != nil {
+		logger.Error(fmt.Sprintf("unable to get the last operation of the instance: %s", err.Error()))
+		httputil.WriteErrorResponse(w, http.StatusInternalServerError, err)
+		return
+	}
+	if lastOperation.ID != operation.ID {
+		msg := fmt.Sprintf("the operation is not the last operation of the instance, the last one is %s", lastOperation.ID)
+		logger.Warn(msg)
+		httputil.WriteErrorResponse(w, http.StatusConflict, errors.New(msg))
+		return
+	}
+
+	failedStep := operation.LastError.Step
+	operation.State = domain.InProgress
+	operation.Description = "Operation retried manually"
+	operation.LastError = kebError.LastError{}
+	operation.RetriedAt = ptr.Time(time.Now())
+	// the failed step gets the full retry budget again, otherwise it fails on its first error after the retry
+	operation.StepRetries = nil
+	// the replica which failed the operation does not process it anymore
+	operation.Lease = nil
+	updated, err := h.operations.UpdateOperation(*operation)
+	if err != nil {
+		logger.Error(fmt.Sprintf("unable to update the operation: %s", err.Error()))
+		switch {
+		case dberr.IsConflict(err):
+			httputil.WriteErrorResponse(w, http.StatusConflict, err)
+		default:
+			httputil.WriteErrorResponse(w, http.StatusInternalServerError, err)
+		}
+		return
+	}
+
+	message := fmt.Sprintf("Operation %s (%s) retried manually, finished stages: %s, failed step: %s.", updated.ID, updated.Type, strings.Join(updated.FinishedStages, ", "), failedStep)
+	if err := h.actions.InsertAction(pkg.OperationRetryActionType, updated.InstanceID, message, string(domain.Failed), string(domain.InProgress)); err != nil {
+		logger.Error(fmt.Sprintf("while inserting action %q with message %s for instance ID %s: %v", pkg.OperationRetryActionType, message, updated.InstanceID, err))
+	}
+
+	// finished stages are kept, so the processing starts from the stage which failed
+	queue.Add(updated.ID)
+	logger.Info("operation retried and added to the queue")
+
+	httputil.WriteResponse(w, http.StatusAccepted, operationResponse{OperationID: updated.ID, State: string(updated.State)})
+}
+
+func (h *handler) getTimeline(w http.ResponseWriter, req *http.Request) {
+	operationID := req.PathValue("operation_id")
+	logger := h.log.With("operationID", operationID)
+
+	operation, err := h.operations.GetOperationByID(operationID)
+	if err != nil {
+		logger.Error(fmt.Sprintf("unable to get operation: %s", err.Error()))
+		switch {
+		case dberr.IsNotFound(err):
+			httputil.WriteErrorResponse(w, http.StatusNotFound, err)
+		default:
+			httputil.WriteErrorResponse(w, http.StatusInternalServerError, err)
+		}
+		return
+	}
+
+	steps, err := h.steps.ListByOperationIDs([]string{operationID})
+	if err != nil {
+		logger.Error(fmt.Sprintf("unable to list operation steps: %s", err.Error()))
+		httputil.WriteErrorResponse(w, http.StatusInternalServerError, err)
+		return
+	}
+	if steps == nil {
+		steps = []pkg.OperationStep{}
+	}
+
+	httputil.WriteResponse(w, http.StatusOK, timelineResponse{
+		OperationID: operation.ID,
+		Type:        string(operation.Type),
+		State:       string(operation.State),
+		CreatedAt:   operation.CreatedAt,
+		UpdatedAt:   operation.UpdatedAt,
+		Steps:       steps,
+	})
+}
+
+func (h *handler) queueFor(operationType internal.OperationType) suspension.Adder {
+	switch operationType {
+	case internal.OperationTypeProvision:
+		return h.provisioningQueue
+	case internal.OperationTypeUpdate:
+		return h.updateQueue
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,312 @@
+package operations_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	pkg "github.com/kyma-project/kyma-environment-broker/common/runtime"
+	"github.com/kyma-project/kyma-environment-broker/internal"
+	kebError "github.com/kyma-project/kyma-environment-broker/internal/error"
+	"github.com/kyma-project/kyma-environment-broker/internal/fixture"
+	"github.com/kyma-project/kyma-environment-broker/internal/httputil"
+	"github.com/kyma-project/kyma-environment-broker/internal/operations"
+	"github.com/kyma-project/kyma-environment-broker/internal/process"
+	"github.com/kyma-project/kyma-environment-broker/internal/storage"
+	"github.com/pivotal-cf/brokerapi/v12/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	cancelPathFormat   = "/operations/%s/cancel"
+	retryPathFormat    = "/operations/%s/retry"
+	timelinePathFormat = "/operations/%s/timeline"
+)
+
+func TestCancelOperation(t *testing.T) {
+	router := httputil.NewRouter()
+	db := storage.NewMemoryStorage()
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{
+		Level: slog.LevelInfo,
+	}))
+	handler := operations.NewHandler(db.Operations(), db.Actions(), db.OperationSteps(), process.NewFakeQueue(), process.NewFakeQueue(), logger)
+	handler.AttachRoutes(router)
+
+	t.Run("should receive 404 Not Found response", func(t *testing.T) {
+		// given
+		req := httptest.NewRequest(http.MethodPost, fmt.Sprintf(cancelPathFormat, "op-not-existing"), nil)
+		w := httptest.NewRecorder()
+
+		// when
+		router.ServeHTTP(w, req)
+
+		// then
+		assert.Equal(t, http.StatusNotFound, w.Result().StatusCode)
+	})
+
+	t.Run("should receive 400 Bad Request response for deprovisioning", func(t *testing.T) {
+		// given
+		operation := fixture.FixOperation("op-deprovisioning", "inst-01", internal.OperationTypeDeprovision)
+		operation.State = domain.InProgress
+		require.NoError(t, db.Operations().InsertOperation(operation))
+
+		req := httptest.NewRequest(http.MethodPost, fmt.Sprintf(cancelPathFormat, operation.ID), nil)
+		w := httptest.NewRecorder()
+
+		// when
+		router.ServeHTTP(w, req)
+
+		// then
+		assert.Equal(t, http.StatusBadRequest, w.Result().StatusCode)
+		assertOperationState(t, db, operation.ID, domain.InProgress)
+	})
+
+	t.Run("should mark the provisioning operation as canceling", func(t *testing.T) {
+		// given
+		operation := fixture.FixOperation("op-provisioning", "inst-04", internal.OperationTypeProvision)
+		operation.State = domain.InProgress
+		require.NoError(t, db.Operations().InsertOperation(operation))
+
+		req := httptest.NewRequest(http.MethodPost, fmt.Sprintf(cancelPathFormat, operation.ID), nil)
+		w := httptest.NewRecorder()
+
+		// when
+		router.ServeHTTP(w, req)
+
+		// then
+		assert.Equal(t, http.StatusAccepted, w.Result().StatusCode)
+		assertOperationState(t, db, operation.ID, internal.OperationStateCanceling)
+	})
+
+	t.Run("should receive 409 Conflict response when operation is finished", func(t *testing.T) {
+		// given
+		operation := fixture.FixOperation("op-succeeded", "inst-02", internal.OperationTypeUpdate)
+		require.NoError(t, db.Operations().InsertOperation(operation))
+
+		req := httptest.NewRequest(http.MethodPost, fmt.Sprintf(cancelPathFormat, operation.ID), nil)
+		w := httptest.NewRecorder()
+
+		// when
+		router.ServeHTTP(w, req)
+
+		// then
+		assert.Equal(t, http.StatusConflict, w.Result().StatusCode)
+		assertOperationState(t, db, operation.ID, domain.Succeeded)
+	})
+
+	t.Run("should mark the operation as canceling", func(t *testing.T) {
+		// given
+		operation := fixture.FixOperation("op-in-progress", "inst-03", internal.OperationTypeUpdate)
+		operation.State = domain.InProgress
+		require.NoError(t, db.Operations().InsertOperation(operation))
+
+		req := httptest.NewRequest(http.MethodPost, fmt.Sprintf(cancelPathFormat, operation.ID), nil)
+		w := httptest.NewRecorder()
+
+		// when
+		router.ServeHTTP(w, req)
+		resp := w.Result()
+
+		// then
+		assert.Equal(t, http.StatusAccepted, resp.StatusCode)
+		assertOperationState(t, db, operation.ID, internal.OperationStateCanceling)
+
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		var response map[string]string
+		require.NoError(t, json.Unmarshal(body, &response))
+		assert.Equal(t, operation.ID, response["operation"])
+		assert.Equal(t, string(internal.OperationStateCanceling), response["state"])
+
+		// when called again
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, fmt.Sprintf(cancelPathFormat, operation.ID), nil))
+
+		// then
+		assert.Equal(t, http.StatusAccepted, w.Result().StatusCode)
+		assertOperationState(t, db, operation.ID, internal.OperationStateCanceling)
+	})
+}
+
+func TestRetryOperation(t *testing.T) {
+	router := httputil.NewRouter()
+	db := storage.NewMemoryStorage()
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{
+		Level: slog.LevelInfo,
+	}))
+	handler := operations.NewHandler(db.Operations(), db.Actions(), db.OperationSteps(), process.NewFakeQueue(), process.NewFakeQueue(), logger)
+	handler.AttachRoutes(router)
+
+	t.Run("should receive 404 Not Found response", func(t *testing.T) {
+		// given
+		req := httptest.NewRequest(http.MethodPost, fmt.Sprintf(retryPathFormat, "op-not-existing"), nil)
+		w := httptest.NewRecorder()
+
+		// when
+		router.ServeHTTP(w, req)
+
+		// then
+		assert.Equal(t, http.StatusNotFound, w.Result().StatusCode)
+	})
+
+	t.Run("should receive 409 Conflict response when operation is not failed", func(t *testing.T) {
+		// given
+		operation := fixture.FixOperation("op-retry-in-progress", "inst-retry-01", internal.OperationTypeUpdate)
+		operation.State = domain.InProgress
+		require.NoError(t, db.Operations().InsertOperation(operation))
+
+		req := httptest.NewRequest(http.MethodPost, fmt.Sprintf(retryPathFormat, operation.ID), nil)
+		w := httptest.NewRecorder()
+
+		// when
+		router.ServeHTTP(w, req)
+
+		// then
+		assert.Equal(t, http.StatusConflict, w.Result().StatusCode)
+		assertOperationState(t, db, operation.ID, domain.InProgress)
+	})
+
+	t.Run("should receive 409 Conflict response when a newer operation exists", func(t *testing.T) {
+		// given
+		operation := fixture.FixOperation("op-retry-old", "inst-retry-02", internal.OperationTypeUpdate)
+		operation.State = domain.Failed
+		operation.CreatedAt = time.Now().Add(-time.Hour)
+		require.NoError(t, db.Operations().InsertOperation(operation))
+		newer := fixture.FixOperation("op-retry-new", "inst-retry-02", internal.OperationTypeUpdate)
+		require.NoError(t, db.Operations().InsertOperation(newer))
+
+		req := httptest.NewRequest(http.MethodPost, fmt.Sprintf(retryPathFormat, operation.ID), nil)
+		w := httptest.NewRecorder()
+
+		// when
+		router.ServeHTTP(w, req)
+
+		// then
+		assert.Equal(t, http.StatusConflict, w.Result().StatusCode)
+		assertOperationState(t, db, operation.ID, domain.Failed)
+	})
+
+	t.Run("should retry the failed operation", func(t *testing.T) {
+		// given
+		operation := fixture.FixOperation("op-retry-failed", "inst-retry-03", internal.OperationTypeUpdate)
+		operation.State = domain.Failed
+		operation.FinishedStages = []string{"cluster", "btp-operator"}
+		operation.LastError = kebError.LastError{Message: "step failed", Reason: kebError.KEBInternalCode, Step: "Update_Runtime"}
+		require.NoError(t, db.Operations().InsertOperation(operation))
+
+		req := httptest.NewRequest(http.MethodPost, fmt.Sprintf(retryPathFormat, operation.ID), nil)
+		w := httptest.NewRecorder()
+
+		// when
+		router.ServeHTTP(w, req)
+
+		// then
+		assert.Equal(t, http.StatusAccepted, w.Result().StatusCode)
+
+		actual, err := db.Operations().GetOperationByID(operation.ID)
+		require.NoError(t, err)
+		assert.Equal(t, domain.InProgress, actual.State)
+		assert.Equal(t, kebError.LastError{}, actual.LastError)
+		assert.Equal(t, []string{"cluster", "btp-operator"}, actual.FinishedStages)
+		assert.NotNil(t, actual.RetriedAt)
+
+		actions, err := db.Actions().ListActionsByInstanceID(operation.InstanceID)
+		require.NoError(t, err)
+		require.Len(t, actions, 1)
+		assert.Equal(t, pkg.OperationRetryActionType, actions[0].Type)
+		assert.Contains(t, actions[0].Message, "Update_Runtime")
+	})
+
+	t.Run("should reset the retries of the step which ran out of retries", func(t *testing.T) {
+		// given
+		operation := fixture.FixOperation("op-retry-exhausted", "inst-retry-04", internal.OperationTypeUpdate)
+		operation.State = domain.Failed
+		operation.LastError = kebError.LastError{Message: "step failed", Reason: kebError.KEBInternalCode, Step: "Check_Runtime_Resource"}
+		operation.StepRetries = map[string]internal.StepRetry{
+			"Check_Runtime_Resource": {Attempts: 5, FirstFailureAt: time.Now().Add(-time.Hour)},
+		}
+		require.NoError(t, db.Operations().InsertOperation(operation))
+
+		req := httptest.NewRequest(http.MethodPost, fmt.Sprintf(retryPathFormat, operation.ID), nil)
+		w := httptest.NewRecorder()
+
+		// when
+		router.ServeHTTP(w, req)
+
+		// then
+		assert.Equal(t, http.StatusAccepted, w.Result().StatusCode)
+
+		actual, err := db.Operations().GetOperationByID(operation.ID)
+		require.NoError(t, err)
+		assert.Equal(t, domain.InProgress, actual.State)
+		assert.Empty(t, actual.StepRetries)
+	})
+}
+
+func TestOperationTimeline(t *testing.T) {
+	router := httputil.NewRouter()
+	db := storage.NewMemoryStorage()
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{
+		Level: slog.LevelInfo,
+	}))
+	handler := operations.NewHandler(db.Operations(), db.Actions(), db.OperationSteps(), process.NewFakeQueue(), process.NewFakeQueue(), logger)
+	handler.AttachRoutes(router)
+
+	t.Run("should receive 404 Not Found response", func(t *testing.T) {
+		// given
+		req := httptest.NewRequest(http.MethodGet, fmt.Sprintf(timelinePathFormat, "op-not-existing"), nil)
+		w := httptest.NewRecorder()
+
+		// when
+		router.ServeHTTP(w, req)
+
+		// then
+		assert.Equal(t, http.StatusNotFound, w.Result().StatusCode)
+	})
+
+	t.Run("should return the executed steps in the order of execution", func(t *testing.T) {
+		// given
+		operation := fixture.FixOperation("op-timeline", "inst-timeline", internal.OperationTypeProvision)
+		operation.State = domain.InProgress
+		require.NoError(t, db.Operations().InsertOperation(operation))
+		started := time.Now().Add(-time.Hour)
+		require.NoError(t, db.OperationSteps().Insert(pkg.OperationStep{OperationID: operation.ID, Stage: "create_runtime", Step: "Check_Runtime_Resource", StartedAt: started.Add(time.Minute), FinishedAt: started.Add(2 * time.Minute), Outcome: pkg.StepRetried, RetryAfterSeconds: 10}))
+		require.NoError(t, db.OperationSteps().Insert(pkg.OperationStep{OperationID: operation.ID, Stage: "start", Step: "Starting", StartedAt: started, FinishedAt: started.Add(time.Second), Outcome: pkg.StepSucceeded}))
+		require.NoError(t, db.OperationSteps().Insert(pkg.OperationStep{OperationID: "other-operation", Step: "Starting", StartedAt: started, Outcome: pkg.StepSucceeded}))
+
+		req := httptest.NewRequest(http.MethodGet, fmt.Sprintf(timelinePathFormat, operation.ID), nil)
+		w := httptest.NewRecorder()
+
+		// when
+		router.ServeHTTP(w, req)
+
+		// then
+		require.Equal(t, http.StatusOK, w.Result().StatusCode)
+		var response struct {
+			OperationID string              `json:"operation"`
+			State       string              `json:"state"`
+			Steps       []pkg.OperationStep `json:"steps"`
+		}
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+		assert.Equal(t, operation.ID, response.OperationID)
+		assert.Equal(t, string(domain.InProgress), response.State)
+		require.Len(t, response.Steps, 2)
+		assert.Equal(t, "Starting", response.Steps[0].Step)
+		assert.Equal(t, "Check_Runtime_Resource", response.Steps[1].Step)
+		assert.Equal(t, pkg.StepRetried, response.Steps[1].Outcome)
+		assert.Equal(t, float64(10), response.Steps[1].RetryAfterSeconds)
+	})
+}
+
+func assertOperationState(t *testing.T, db storage.BrokerStorage, operationID string, expected domain.LastOperationState) {
+	operation, err := db.Operations().GetOperationByID(operationID)
+	require.NoError(t, err)
+	assert.Equal(t, expected, operation.State)
+}
@@ -11,11 +11,13 @@ import (
 
 type CleanStep struct {
 	operations storage.Operations
+	steps      storage.OperationSteps
 }
 
 func NewCleanStep(db storage.BrokerStorage) *CleanStep {
 	return &CleanStep{
 		operations: db.Operations(),
+		steps:      db.OperationSteps(),
 	}
 }
 
@@ -39,6 +41,11 @@ func (s *CleanStep) Run(operation internal.Operation, log *slog.Logger) (interna
 	}
 	for _, op := range operations {
 		log.Info(fmt.Sprintf("Removing operation %s", op.ID))
+		// the steps are deleted first, so they are deleted again on the retry if the deletion of the operation fails
+		if err := s.steps.DeleteByOperationID(op.ID); err != nil {
+			log.Error(fmt.Sprintf("unable to delete steps of operation %s: %s", op.ID, err.Error()))
+			return operation, dbRetryBackoff, nil
+		}
 		err := s.operations.DeleteByID(op.ID)
 		if err != nil {
 			log.Error(fmt.Sprintf("unable to delete operation %s: %s", op.ID, err.Error()))
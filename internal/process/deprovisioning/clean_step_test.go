@@ -3,6 +3,7 @@ package deprovisioning
 import (
 	"testing"
 
+	"github.com/kyma-project/kyma-environment-broker/common/runtime"
 	"github.com/kyma-project/kyma-environment-broker/internal/fixture"
 	"github.com/kyma-project/kyma-environment-broker/internal/storage"
 	"github.com/stretchr/testify/assert"
@@ -17,6 +18,7 @@ func TestCleanStep_Run(t *testing.T) {
 	assert.NoError(t, err)
 	err = db.Operations().InsertOperation(deprovisioning)
 	assert.NoError(t, err)
+	err = db.OperationSteps().Insert(runtime.OperationStep{OperationID: "prov-id", Step: "Start"})
 	assert.NoError(t, err)
 
 	step := NewCleanStep(db)
@@ -30,6 +32,9 @@ func TestCleanStep_Run(t *testing.T) {
 	ops, err := db.Operations().ListOperationsByInstanceID("inst-id")
 	assert.NoError(t, err)
 	assert.Emptyf(t, ops, "Operations should be empty")
+	steps, err := db.OperationSteps().ListByOperationIDs([]string{"prov-id"})
+	assert.NoError(t, err)
+	assert.Empty(t, steps)
 }
 
 func TestCleanStep_Run_TemporaryOperation(t *testing.T) {
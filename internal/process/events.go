@@ -7,10 +7,12 @@ import (
 )
 
 type StepProcessed struct {
-	StepName string
-	Duration time.Duration
-	When     time.Duration
-	Error    error
+	StepName  string
+	Stage     string
+	StartedAt time.Time
+	Duration  time.Duration
+	When      time.Duration
+	Error     error
 }
 
 type ProvisioningStepProcessed struct {
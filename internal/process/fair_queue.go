@@ -0,0 +1,220 @@
+package process
+
+import (
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/kyma-project/kyma-environment-broker/internal/storage"
+	"github.com/kyma-project/kyma-environment-broker/internal/storage/dberr"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+type QueueFairnessConfig struct {
+	// Enabled makes the queues track the operations of every global account and export them as metrics
+	Enabled bool `envconfig:"default=false"`
+	// MaxOperationsPerGlobalAccount limits the operations of one global account processed by the queue at the same time, 0 means no limit
+	MaxOperationsPerGlobalAccount int `envconfig:"default=0"`
+	// RequeueInterval is the time after which an operation of a global account which reached the limit, or whose global account
+	// could not be read, is taken again
+	RequeueInterval time.Duration `envconfig:"default=5s"`
+}
+
+// TenantResolver returns the tenant, which is the global account, of the operation
+type TenantResolver func(operationID string) (string, error)
+
+// GlobalAccountResolver returns the global account of the operation stored in the storage
+func GlobalAccountResolver(operations storage.Operations) TenantResolver {
+	return func(operationID string) (string, error) {
+		operation, err := operations.GetOperationByID(operationID)
+		if err != nil {
+			return "", err
+		}
+		return operation.ProvisioningParameters.ErsContext.GlobalAccountID, nil
+	}
+}
+
+var (
+	queueTenantOperationsMetric = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "kcp",
+		Subsystem: "keb_v2",
+		Name:      "queue_global_account_operations",
+		Help:      "Number of operations of the global account taken from the queue and not finished yet",
+	}, []string{"queue_name", "global_account_id"})
+	queueTenantOperationsInProgressMetric = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "kcp",
+		Subsystem: "keb_v2",
+		Name:      "queue_global_account_operations_in_progress",
+		Help:      "Number of operations of the global account currently processed by the queue workers",
+	}, []string{"queue_name", "global_account_id"})
+	queueTenantDeferralsMetric = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "kcp",
+		Subsystem: "keb_v2",
+		Name:      "queue_global_account_deferrals_total",
+		Help:      "Number of times an operation was put back to the queue because its global account reached the limit of operations in progress",
+	}, []string{"queue_name"})
+	queueTenantResolveFailuresMetric = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "kcp",
+		Subsystem: "keb_v2",
+		Name:      "queue_global_account_resolve_failures_total",
+		Help:      "Number of times an operation was put back to the queue because its global account could not be read",
+	}, []string{"queue_name"})
+)
+
+// fairQueue wraps a work queue and limits the number of items of one tenant processed at the same time.
+// An item of a tenant which reached the limit is put back to the queue, so the workers can take the items of other tenants.
+// The counters are kept in memory, every KEB replica applies the limit to the items it processes.
+// The tenant is resolved by the worker which takes the item for the first time and kept until the item is forgotten,
+// so adding an item to the queue does not read the storage.
+type fairQueue struct {
+	workQueue
+	resolve TenantResolver
+	name    string
+	config  QueueFairnessConfig
+	log     *slog.Logger
+
+	mu         sync.Mutex
+	tenants    map[string]string
+	processing map[string]string
+	queued     map[string]int
+	inProgress map[string]int
+}
+
+// UseFairness limits the number of operations of one global account processed at the same time and exports
+// the number of operations of every global account as metrics. It must be called before the queue is run.
+func (q *Queue) UseFairness(cfg QueueFairnessConfig, resolve TenantResolver) {
+	if !cfg.Enabled {
+		return
+	}
+	q.queue = newFairQueue(q.queue, resolve, q.name, cfg, q.log)
+}
+
+func newFairQueue(queue workQueue, resolve TenantResolver, name string, cfg QueueFairnessConfig, log *slog.Logger) *fairQueue {
+	if cfg.RequeueInterval <= 0 {
+		cfg.RequeueInterval = 5 * time.Second
+	}
+	return &fairQueue{
+		workQueue:  queue,
+		resolve:    resolve,
+		name:       name,
+		config:     cfg,
+		log:        log,
+		tenants:    make(map[string]string),
+		processing: make(map[string]string),
+		queued:     make(map[string]int),
+		inProgress: make(map[string]int),
+	}
+}
+
+func (q *fairQueue) Restore(item string) {
+	if r, ok := q.workQueue.(restorer); ok {
+		r.Restore(item)
+		return
+	}
+	q.workQueue.Add(item)
+}
+
+func (q *fairQueue) Get() (string, bool) {
+	for {
+		item, shutdown := q.workQueue.Get()
+		if shutdown {
+			return item, shutdown
+		}
+		tenant, err := q.track(item)
+		if err != nil {
+			// the limit cannot be checked, the item is taken again instead of being processed without the limit
+			q.log.Warn(fmt.Sprintf("unable to get the global account of item %s, item is deferred: %s", item, err))
+			queueTenantResolveFailuresMetric.WithLabelValues(q.name).Inc()
+			q.workQueue.AddAfter(item, q.config.RequeueInterval)
+			q.workQueue.Done(item)
+			continue
+		}
+
+		q.mu.Lock()
+		if tenant != "" && q.config.MaxOperationsPerGlobalAccount > 0 && q.inProgress[tenant] >= q.config.MaxOperationsPerGlobalAccount {
+			q.mu.Unlock()
+			q.log.Info(fmt.Sprintf("global account %s reached the limit of %d operations in progress, item %s is deferred", tenant, q.config.MaxOperationsPerGlobalAccount, item))
+			queueTenantDeferralsMetric.WithLabelValues(q.name).Inc()
+			q.workQueue.AddAfter(item, q.config.RequeueInterval)
+			q.workQueue.Done(item)
+			continue
+		}
+		q.processing[item] = tenant
+		q.inProgress[tenant]++
+		q.setMetric(queueTenantOperationsInProgressMetric, tenant, q.inProgress[tenant])
+		q.mu.Unlock()
+		return item, false
+	}
+}
+
+func (q *fairQueue) Done(item string) {
+	q.mu.Lock()
+	if tenant, found := q.processing[item]; found {
+		delete(q.processing, item)
+		q.inProgress[tenant]--
+		q.setMetric(queueTenantOperationsInProgressMetric, tenant, q.inProgress[tenant])
+		if q.inProgress[tenant] == 0 {
+			delete(q.inProgress, tenant)
+		}
+	}
+	q.mu.Unlock()
+	q.workQueue.Done(item)
+}
+
+// Forget is called when the item is processed without a retry, so the item is no longer counted for its tenant
+func (q *fairQueue) Forget(item string) {
+	q.mu.Lock()
+	if tenant, found := q.tenants[item]; found {
+		delete(q.tenants, item)
+		q.queued[tenant]--
+		q.setMetric(queueTenantOperationsMetric, tenant, q.queued[tenant])
+		if q.queued[tenant] <= 0 {
+			delete(q.queued, tenant)
+		}
+	}
+	q.mu.Unlock()
+	q.workQueue.Forget(item)
+}
+
+// track returns the tenant of the item, the tenant is resolved once for every item taken from the queue
+func (q *fairQueue) track(item string) (string, error) {
+	q.mu.Lock()
+	tenant, found := q.tenants[item]
+	q.mu.Unlock()
+	if found {
+		return tenant, nil
+	}
+
+	tenant, err := q.resolve(item)
+	switch {
+	case dberr.IsNotFound(err):
+		// the operation does not exist, the executor handles it without the limit
+		q.log.Info(fmt.Sprintf("operation %s not found, item is processed without the limit", item))
+		tenant = ""
+	case err != nil:
+		return "", err
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if _, found := q.tenants[item]; !found {
+		q.tenants[item] = tenant
+		q.queued[tenant]++
+		q.setMetric(queueTenantOperationsMetric, tenant, q.queued[tenant])
+	}
+	return tenant, nil
+}
+
+func (q *fairQueue) setMetric(metric *prometheus.GaugeVec, tenant string, value int) {
+	if tenant == "" {
+		return
+	}
+	if value <= 0 {
+		metric.DeleteLabelValues(q.name, tenant)
+		return
+	}
+	metric.WithLabelValues(q.name, tenant).Set(float64(value))
+}
@@ -0,0 +1,159 @@
+package process
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kyma-project/kyma-environment-broker/internal/storage/dberr"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// tenantExecutor records the highest number of operations of every tenant processed at the same time
+type tenantExecutor struct {
+	mu            sync.Mutex
+	running       map[string]int
+	maxRunning    map[string]int
+	finished      []string
+	blockedTenant string
+	unblock       chan struct{}
+}
+
+func (e *tenantExecutor) Execute(operationID string) (time.Duration, error) {
+	tenant := tenantOf(operationID)
+	e.mu.Lock()
+	e.running[tenant]++
+	e.maxRunning[tenant] = max(e.maxRunning[tenant], e.running[tenant])
+	e.mu.Unlock()
+
+	if tenant == e.blockedTenant {
+		<-e.unblock
+	} else {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	e.mu.Lock()
+	e.running[tenant]--
+	e.finished = append(e.finished, operationID)
+	e.mu.Unlock()
+	return 0, nil
+}
+
+func (e *tenantExecutor) finishedOperations() []string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return append([]string{}, e.finished...)
+}
+
+func (e *tenantExecutor) maxRunningOf(tenant string) int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.maxRunning[tenant]
+}
+
+func tenantOf(operationID string) string {
+	return strings.Split(operationID, "-")[0]
+}
+
+func TestQueue_UseFairness(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	resolver := func(operationID string) (string, error) {
+		if strings.HasPrefix(operationID, "unknown") {
+			return "", dberr.NotFound("operation %s not found", operationID)
+		}
+		return tenantOf(operationID), nil
+	}
+
+	t.Run("should limit the operations of a global account in progress", func(t *testing.T) {
+		// given
+		executor := &tenantExecutor{running: map[string]int{}, maxRunning: map[string]int{}, blockedTenant: "ga1", unblock: make(chan struct{})}
+		queue := NewQueue(executor, logger, "test")
+		queue.UseFairness(QueueFairnessConfig{Enabled: true, MaxOperationsPerGlobalAccount: 2, RequeueInterval: 10 * time.Millisecond}, resolver)
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		for i := 0; i < 5; i++ {
+			queue.Add(fmt.Sprintf("ga1-%d", i))
+		}
+		queue.Add("ga2-0")
+		queue.Add("unknown-0")
+
+		// when
+		queue.Run(ctx.Done(), 4)
+
+		// then the operations of other global accounts are processed while ga1 reached the limit
+		assert.NoError(t, wait.PollUntilContextTimeout(context.Background(), 10*time.Millisecond, 5*time.Second, true, func(ctx context.Context) (bool, error) {
+			return len(executor.finishedOperations()) == 2, nil
+		}))
+		assert.ElementsMatch(t, []string{"ga2-0", "unknown-0"}, executor.finishedOperations())
+
+		close(executor.unblock)
+		assert.NoError(t, wait.PollUntilContextTimeout(context.Background(), 10*time.Millisecond, 5*time.Second, true, func(ctx context.Context) (bool, error) {
+			return len(executor.finishedOperations()) == 7, nil
+		}))
+		assert.Equal(t, 2, executor.maxRunningOf("ga1"))
+		fair := queue.queue.(*fairQueue)
+		assert.NoError(t, wait.PollUntilContextTimeout(context.Background(), 10*time.Millisecond, 5*time.Second, true, func(ctx context.Context) (bool, error) {
+			fair.mu.Lock()
+			defer fair.mu.Unlock()
+			return len(fair.queued) == 0 && len(fair.inProgress) == 0, nil
+		}))
+	})
+
+	t.Run("should resolve the global account by the worker and defer the item when it cannot be read", func(t *testing.T) {
+		// given
+		var mu sync.Mutex
+		calls := map[string]int{}
+		failingResolver := func(operationID string) (string, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			calls[operationID]++
+			if calls[operationID] == 1 {
+				return "", dberr.Internal("connection refused")
+			}
+			return tenantOf(operationID), nil
+		}
+		executor := &tenantExecutor{running: map[string]int{}, maxRunning: map[string]int{}}
+		queue := NewQueue(executor, logger, "test")
+		queue.UseFairness(QueueFairnessConfig{Enabled: true, MaxOperationsPerGlobalAccount: 1, RequeueInterval: 10 * time.Millisecond}, failingResolver)
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		// when
+		queue.Add("ga1-0")
+		queue.AddAfter("ga1-1", time.Millisecond)
+
+		// then the storage is not read when the items are added
+		mu.Lock()
+		assert.Empty(t, calls)
+		mu.Unlock()
+
+		queue.Run(ctx.Done(), 2)
+		assert.NoError(t, wait.PollUntilContextTimeout(context.Background(), 10*time.Millisecond, 5*time.Second, true, func(ctx context.Context) (bool, error) {
+			return len(executor.finishedOperations()) == 2, nil
+		}))
+		assert.Equal(t, 1, executor.maxRunningOf("ga1"))
+		mu.Lock()
+		defer mu.Unlock()
+		assert.Equal(t, map[string]int{"ga1-0": 2, "ga1-1": 2}, calls)
+	})
+
+	t.Run("should not wrap the queue when disabled", func(t *testing.T) {
+		// given
+		queue := NewQueue(&tenantExecutor{}, logger, "test")
+
+		// when
+		queue.UseFairness(QueueFairnessConfig{}, resolver)
+
+		// then
+		_, wrapped := queue.queue.(*fairQueue)
+		assert.False(t, wrapped)
+	})
+}
@@ -0,0 +1,184 @@
+package hooks
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/kyma-project/kyma-environment-broker/internal"
+	"github.com/kyma-project/kyma-environment-broker/internal/broker"
+	"github.com/kyma-project/kyma-environment-broker/internal/process"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	ProvisioningProcess   = "provisioning"
+	UpdateProcess         = "update"
+	DeprovisioningProcess = "deprovisioning"
+)
+
+type FailurePolicy string
+
+const (
+	// FailurePolicyFail fails the operation when the webhook call fails
+	FailurePolicyFail FailurePolicy = "fail"
+	// FailurePolicyIgnore logs the failure and continues the operation
+	FailurePolicyIgnore FailurePolicy = "ignore"
+	// FailurePolicyRetry retries the webhook call until RetryTimeout passes, then fails the operation
+	FailurePolicyRetry FailurePolicy = "retry"
+)
+
+const (
+	defaultTimeout       = 10 * time.Second
+	defaultRetryInterval = 30 * time.Second
+	defaultRetryTimeout  = 10 * time.Minute
+)
+
+// Config holds the step hooks of all processes
+type Config struct {
+	Hooks []Hook `yaml:"hooks"`
+}
+
+// Hook describes a webhook called before or after a named step of a process
+type Hook struct {
+	// Name identifies the hook, it is used as the step name
+	Name string `yaml:"name"`
+	// Process is one of provisioning, update or deprovisioning
+	Process string `yaml:"process"`
+	// Before and After hold the name of the step next to which the hook is executed, exactly one of them must be set
+	Before string `yaml:"before"`
+	After  string `yaml:"after"`
+	// URL receives a POST request with the operation details, any 2xx response means success
+	URL           string        `yaml:"url"`
+	Timeout       time.Duration `yaml:"timeout"`
+	FailurePolicy FailurePolicy `yaml:"failurePolicy"`
+	RetryInterval time.Duration `yaml:"retryInterval"`
+	RetryTimeout  time.Duration `yaml:"retryTimeout"`
+	// Plans limits the hook to the operations of the given plans, the hook is executed for all plans if empty
+	Plans []string `yaml:"plans"`
+}
+
+// StepInserter is implemented by process.StagedManager
+type StepInserter interface {
+	AddStepBefore(stepName string, step process.Step, cnd process.StepCondition) error
+	AddStepAfter(stepName string, step process.Step, cnd process.StepCondition) error
+}
+
+// ReadFromFile loads the hooks from a YAML file, an empty file means no hooks:
+//
+//	hooks:
+//	  - name: cmdb-registration
+//	    process: provisioning
+//	    before: Create_Runtime_Resource
+//	    url: https://cmdb.example.com/hooks/kyma
+//	    failurePolicy: retry
+func ReadFromFile(path string) (Config, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return Config{}, fmt.Errorf("while reading step hooks: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	dec := yaml.NewDecoder(f)
+	dec.KnownFields(true)
+
+	var cfg Config
+	if err := dec.Decode(&cfg); err != nil {
+		if errors.Is(err, io.EOF) {
+			return Config{}, nil
+		}
+		return Config{}, fmt.Errorf("while reading step hooks: %w", err)
+	}
+	if err := cfg.validate(); err != nil {
+		return Config{}, fmt.Errorf("while validating step hooks: %w", err)
+	}
+	cfg.setDefaults()
+	return cfg, nil
+}
+
+func (c Config) validate() error {
+	names := map[string]struct{}{}
+	for _, h := range c.Hooks {
+		if h.Name == "" {
+			return fmt.Errorf("hook name must not be empty")
+		}
+		if _, found := names[h.Name]; found {
+			return fmt.Errorf("hook %s is defined more than once", h.Name)
+		}
+		names[h.Name] = struct{}{}
+
+		if !slices.Contains([]string{ProvisioningProcess, UpdateProcess, DeprovisioningProcess}, h.Process) {
+			return fmt.Errorf("hook %s: unknown process %q", h.Name, h.Process)
+		}
+		if (h.Before == "") == (h.After == "") {
+			return fmt.Errorf("hook %s: exactly one of before and after must be set", h.Name)
+		}
+		if u, err := url.Parse(h.URL); err != nil || u.Scheme == "" || u.Host == "" {
+			return fmt.Errorf("hook %s: invalid URL %q", h.Name, h.URL)
+		}
+		switch h.FailurePolicy {
+		case "", FailurePolicyFail, FailurePolicyIgnore, FailurePolicyRetry:
+		default:
+			return fmt.Errorf("hook %s: unknown failure policy %q", h.Name, h.FailurePolicy)
+		}
+		for _, plan := range h.Plans {
+			if !broker.AvailablePlans.IsPlanName(plan) {
+				return fmt.Errorf("hook %s: unknown plan %q", h.Name, plan)
+			}
+		}
+	}
+	return nil
+}
+
+func (c Config) setDefaults() {
+	for i := range c.Hooks {
+		h := &c.Hooks[i]
+		if h.Timeout == 0 {
+			h.Timeout = defaultTimeout
+		}
+		if h.FailurePolicy == "" {
+			h.FailurePolicy = FailurePolicyFail
+		}
+		if h.RetryInterval == 0 {
+			h.RetryInterval = defaultRetryInterval
+		}
+		if h.RetryTimeout == 0 {
+			h.RetryTimeout = defaultRetryTimeout
+		}
+	}
+}
+
+// Register adds the webhook steps of the given process to the manager
+func (c Config) Register(manager StepInserter, processName string) error {
+	for _, h := range c.Hooks {
+		if h.Process != processName {
+			continue
+		}
+		step := NewWebhookStep(h)
+		var err error
+		if h.Before != "" {
+			err = manager.AddStepBefore(h.Before, step, h.condition())
+		} else {
+			err = manager.AddStepAfter(h.After, step, h.condition())
+		}
+		if err != nil {
+			return fmt.Errorf("while registering hook %s: %w", h.Name, err)
+		}
+	}
+	return nil
+}
+
+func (h Hook) condition() process.StepCondition {
+	if len(h.Plans) == 0 {
+		return nil
+	}
+	return func(operation internal.Operation) bool {
+		planName := broker.AvailablePlans.GetPlanNameOrEmpty(broker.PlanIDType(operation.ProvisioningParameters.PlanID))
+		return slices.Contains(h.Plans, planName)
+	}
+}
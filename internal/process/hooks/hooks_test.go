@@ -0,0 +1,145 @@
+package hooks_test
+
+import (
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/kyma-project/kyma-environment-broker/internal"
+	"github.com/kyma-project/kyma-environment-broker/internal/broker"
+	"github.com/kyma-project/kyma-environment-broker/internal/process"
+	"github.com/kyma-project/kyma-environment-broker/internal/process/hooks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadFromFile(t *testing.T) {
+	t.Run("should read the hooks and set the defaults", func(t *testing.T) {
+		// given
+		path := writeYAML(t, `
+hooks:
+  - name: cmdb
+    process: provisioning
+    before: Create_Runtime_Resource
+    url: https://cmdb.example.com/hooks
+    timeout: 5s
+    plans: [aws, azure]
+  - name: compliance
+    process: deprovisioning
+    before: Remove_Instance
+    url: https://compliance.example.com/hooks
+    failurePolicy: retry
+`)
+
+		// when
+		cfg, err := hooks.ReadFromFile(path)
+
+		// then
+		require.NoError(t, err)
+		require.Len(t, cfg.Hooks, 2)
+		assert.Equal(t, 5*time.Second, cfg.Hooks[0].Timeout)
+		assert.Equal(t, hooks.FailurePolicyFail, cfg.Hooks[0].FailurePolicy)
+		assert.Equal(t, []string{"aws", "azure"}, cfg.Hooks[0].Plans)
+		assert.Equal(t, 10*time.Second, cfg.Hooks[1].Timeout)
+		assert.Equal(t, hooks.FailurePolicyRetry, cfg.Hooks[1].FailurePolicy)
+		assert.Equal(t, 30*time.Second, cfg.Hooks[1].RetryInterval)
+		assert.Equal(t, 10*time.Minute, cfg.Hooks[1].RetryTimeout)
+	})
+
+	t.Run("should accept an empty file", func(t *testing.T) {
+		// when
+		cfg, err := hooks.ReadFromFile(writeYAML(t, ""))
+
+		// then
+		require.NoError(t, err)
+		assert.Empty(t, cfg.Hooks)
+	})
+
+	for name, content := range map[string]string{
+		"unknown field":      "hooks:\n  - name: a\n    proces: provisioning\n",
+		"missing name":       "hooks:\n  - process: provisioning\n    before: a\n    url: https://a.com\n",
+		"duplicated name":    "hooks:\n  - {name: a, process: update, before: b, url: 'https://a.com'}\n  - {name: a, process: update, before: c, url: 'https://a.com'}\n",
+		"unknown process":    "hooks:\n  - {name: a, process: upgrade, before: b, url: 'https://a.com'}\n",
+		"before and after":   "hooks:\n  - {name: a, process: update, before: b, after: c, url: 'https://a.com'}\n",
+		"no position":        "hooks:\n  - {name: a, process: update, url: 'https://a.com'}\n",
+		"invalid url":        "hooks:\n  - {name: a, process: update, before: b, url: 'a.com'}\n",
+		"unknown policy":     "hooks:\n  - {name: a, process: update, before: b, url: 'https://a.com', failurePolicy: skip}\n",
+		"unknown plan":       "hooks:\n  - {name: a, process: update, before: b, url: 'https://a.com', plans: [trail]}\n",
+		"not a valid config": "hooks: a\n",
+	} {
+		t.Run("should reject "+name, func(t *testing.T) {
+			// when
+			_, err := hooks.ReadFromFile(writeYAML(t, content))
+
+			// then
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestConfig_Register(t *testing.T) {
+	// given
+	cfg := hooks.Config{Hooks: []hooks.Hook{
+		{Name: "cmdb", Process: hooks.ProvisioningProcess, Before: "Create_Runtime_Resource", Plans: []string{"aws"}},
+		{Name: "notify", Process: hooks.ProvisioningProcess, After: "Create_Runtime_Resource"},
+		{Name: "compliance", Process: hooks.DeprovisioningProcess, Before: "Remove_Instance"},
+	}}
+	inserter := &fakeStepInserter{}
+
+	// when
+	err := cfg.Register(inserter, hooks.ProvisioningProcess)
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, []string{"cmdb before Create_Runtime_Resource", "notify after Create_Runtime_Resource"}, inserter.registered)
+
+	aws := internal.Operation{ProvisioningParameters: internal.ProvisioningParameters{PlanID: broker.AWSPlanID}}
+	azure := internal.Operation{ProvisioningParameters: internal.ProvisioningParameters{PlanID: broker.AzurePlanID}}
+	assert.True(t, inserter.conditions[0](aws))
+	assert.False(t, inserter.conditions[0](azure))
+	assert.Nil(t, inserter.conditions[1])
+
+	t.Run("should return an error for a missing step", func(t *testing.T) {
+		// when
+		err := cfg.Register(&fakeStepInserter{missing: "Remove_Instance"}, hooks.DeprovisioningProcess)
+
+		// then
+		assert.EqualError(t, err, "while registering hook compliance: step Remove_Instance not defined")
+	})
+}
+
+type fakeStepInserter struct {
+	missing    string
+	registered []string
+	conditions []process.StepCondition
+}
+
+func (f *fakeStepInserter) AddStepBefore(stepName string, step process.Step, cnd process.StepCondition) error {
+	return f.add(stepName, "before", step, cnd)
+}
+
+func (f *fakeStepInserter) AddStepAfter(stepName string, step process.Step, cnd process.StepCondition) error {
+	return f.add(stepName, "after", step, cnd)
+}
+
+func (f *fakeStepInserter) add(stepName, position string, step process.Step, cnd process.StepCondition) error {
+	if stepName == f.missing {
+		return fmt.Errorf("step %s not defined", stepName)
+	}
+	f.registered = append(f.registered, step.Name()+" "+position+" "+stepName)
+	f.conditions = append(f.conditions, cnd)
+	return nil
+}
+
+func writeYAML(t *testing.T, content string) string {
+	t.Helper()
+	f, err := os.CreateTemp("", "hooks-*.yaml")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.Remove(f.Name()) })
+	_, err = f.WriteString(content)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+	return f.Name()
+}
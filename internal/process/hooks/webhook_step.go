@@ -0,0 +1,135 @@
+package hooks
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/kyma-project/kyma-environment-broker/internal"
+	"github.com/kyma-project/kyma-environment-broker/internal/broker"
+	kebError "github.com/kyma-project/kyma-environment-broker/internal/error"
+	"github.com/kyma-project/kyma-environment-broker/internal/process"
+)
+
+// maxResponseBody limits the part of the webhook response kept in the error message
+const maxResponseBody = 512
+
+// Request is the body of the POST request sent to the webhook
+type Request struct {
+	Hook      string           `json:"hook"`
+	Process   string           `json:"process"`
+	Step      string           `json:"step"`
+	Position  string           `json:"position"`
+	Operation OperationDetails `json:"operation"`
+}
+
+type OperationDetails struct {
+	ID              string `json:"id"`
+	Type            string `json:"type"`
+	InstanceID      string `json:"instanceID"`
+	RuntimeID       string `json:"runtimeID"`
+	GlobalAccountID string `json:"globalAccountID"`
+	SubAccountID    string `json:"subAccountID"`
+	PlanID          string `json:"planID"`
+	PlanName        string `json:"planName"`
+	PlatformRegion  string `json:"platformRegion"`
+	ShootName       string `json:"shootName"`
+}
+
+// WebhookStep calls the webhook of the hook, the result is handled according to the failure policy
+type WebhookStep struct {
+	hook   Hook
+	client *http.Client
+}
+
+var _ process.Step = &WebhookStep{}
+var _ process.StepWithRetryPolicy = &WebhookStep{}
+
+func NewWebhookStep(hook Hook) *WebhookStep {
+	return &WebhookStep{
+		hook:   hook,
+		client: &http.Client{Timeout: hook.Timeout},
+	}
+}
+
+func (s *WebhookStep) Name() string {
+	return s.hook.Name
+}
+
+func (s *WebhookStep) RetryPolicy() process.RetryPolicy {
+	if s.hook.FailurePolicy == FailurePolicyRetry {
+		return process.RetryPolicy{
+			InitialInterval: s.hook.RetryInterval,
+			Timeout:         s.hook.RetryTimeout,
+		}
+	}
+	return process.RetryPolicy{MaxAttempts: 1}
+}
+
+func (s *WebhookStep) Run(operation internal.Operation, log *slog.Logger) (internal.Operation, time.Duration, error) {
+	err := s.call(operation)
+	if err == nil {
+		log.Info(fmt.Sprintf("Webhook %s called successfully", s.hook.URL))
+		return operation, 0, nil
+	}
+	if s.hook.FailurePolicy == FailurePolicyIgnore {
+		log.Warn(fmt.Sprintf("Ignoring failed call of the webhook %s: %s", s.hook.URL, err))
+		return operation, 0, nil
+	}
+	return operation, 0, err
+}
+
+func (s *WebhookStep) call(operation internal.Operation) error {
+	body, err := json.Marshal(s.request(operation))
+	if err != nil {
+		return fmt.Errorf("while encoding the webhook request: %w", err)
+	}
+
+	response, err := s.client.Post(s.hook.URL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return kebError.LastError{
+			Message:   fmt.Sprintf("while calling the webhook %s: %s", s.hook.URL, err),
+			Component: kebError.StepHookDependency,
+		}
+	}
+	defer func() { _ = response.Body.Close() }()
+
+	if response.StatusCode < http.StatusOK || response.StatusCode >= http.StatusMultipleChoices {
+		responseBody, _ := io.ReadAll(io.LimitReader(response.Body, maxResponseBody))
+		return kebError.LastError{
+			Message:   fmt.Sprintf("webhook %s returned status %d: %s", s.hook.URL, response.StatusCode, responseBody),
+			Reason:    kebError.HttpStatusCode,
+			Component: kebError.StepHookDependency,
+		}
+	}
+	return nil
+}
+
+func (s *WebhookStep) request(operation internal.Operation) Request {
+	step, position := s.hook.Before, "before"
+	if s.hook.After != "" {
+		step, position = s.hook.After, "after"
+	}
+	return Request{
+		Hook:     s.hook.Name,
+		Process:  s.hook.Process,
+		Step:     step,
+		Position: position,
+		Operation: OperationDetails{
+			ID:              operation.ID,
+			Type:            string(operation.Type),
+			InstanceID:      operation.InstanceID,
+			RuntimeID:       operation.RuntimeID,
+			GlobalAccountID: operation.ProvisioningParameters.ErsContext.GlobalAccountID,
+			SubAccountID:    operation.ProvisioningParameters.ErsContext.SubAccountID,
+			PlanID:          operation.ProvisioningParameters.PlanID,
+			PlanName:        broker.AvailablePlans.GetPlanNameOrEmpty(broker.PlanIDType(operation.ProvisioningParameters.PlanID)),
+			PlatformRegion:  operation.ProvisioningParameters.PlatformRegion,
+			ShootName:       operation.ShootName,
+		},
+	}
+}
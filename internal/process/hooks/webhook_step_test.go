@@ -0,0 +1,117 @@
+package hooks_test
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/kyma-project/kyma-environment-broker/internal/broker"
+	"github.com/kyma-project/kyma-environment-broker/internal/fixture"
+	"github.com/kyma-project/kyma-environment-broker/internal/process"
+	"github.com/kyma-project/kyma-environment-broker/internal/process/hooks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookStep_Run(t *testing.T) {
+	t.Run("should send the operation details", func(t *testing.T) {
+		// given
+		var received hooks.Request
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.MethodPost, r.Method)
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer server.Close()
+		step := hooks.NewWebhookStep(fixHook(server.URL, hooks.FailurePolicyFail))
+		operation := fixture.FixProvisioningOperation("op-id", "inst-id")
+
+		// when
+		_, backoff, err := step.Run(operation, fixLogger())
+
+		// then
+		require.NoError(t, err)
+		assert.Zero(t, backoff)
+		assert.Equal(t, "cmdb", received.Hook)
+		assert.Equal(t, "Create_Runtime_Resource", received.Step)
+		assert.Equal(t, "before", received.Position)
+		assert.Equal(t, "op-id", received.Operation.ID)
+		assert.Equal(t, "inst-id", received.Operation.InstanceID)
+		assert.Equal(t, operation.ProvisioningParameters.ErsContext.GlobalAccountID, received.Operation.GlobalAccountID)
+		assert.Equal(t, broker.AvailablePlans.GetPlanNameOrEmpty(broker.PlanIDType(operation.ProvisioningParameters.PlanID)), received.Operation.PlanName)
+	})
+
+	t.Run("should return an error when the webhook fails", func(t *testing.T) {
+		// given
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte("not registered"))
+		}))
+		defer server.Close()
+		step := hooks.NewWebhookStep(fixHook(server.URL, hooks.FailurePolicyFail))
+
+		// when
+		_, _, err := step.Run(fixture.FixProvisioningOperation("op-id", "inst-id"), fixLogger())
+
+		// then
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "returned status 403: not registered")
+		assert.Equal(t, process.RetryPolicy{MaxAttempts: 1}, step.RetryPolicy())
+	})
+
+	t.Run("should ignore the failure", func(t *testing.T) {
+		// given
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer server.Close()
+		step := hooks.NewWebhookStep(fixHook(server.URL, hooks.FailurePolicyIgnore))
+
+		// when
+		_, backoff, err := step.Run(fixture.FixProvisioningOperation("op-id", "inst-id"), fixLogger())
+
+		// then
+		require.NoError(t, err)
+		assert.Zero(t, backoff)
+	})
+
+	t.Run("should return an error after the timeout", func(t *testing.T) {
+		// given
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			time.Sleep(200 * time.Millisecond)
+		}))
+		defer server.Close()
+		hook := fixHook(server.URL, hooks.FailurePolicyRetry)
+		hook.Timeout = 10 * time.Millisecond
+		step := hooks.NewWebhookStep(hook)
+
+		// when
+		_, _, err := step.Run(fixture.FixProvisioningOperation("op-id", "inst-id"), fixLogger())
+
+		// then
+		require.Error(t, err)
+		assert.Equal(t, process.RetryPolicy{InitialInterval: hook.RetryInterval, Timeout: hook.RetryTimeout}, step.RetryPolicy())
+	})
+}
+
+func fixHook(url string, policy hooks.FailurePolicy) hooks.Hook {
+	return hooks.Hook{
+		Name:          "cmdb",
+		Process:       hooks.ProvisioningProcess,
+		Before:        "Create_Runtime_Resource",
+		URL:           url,
+		Timeout:       time.Second,
+		FailurePolicy: policy,
+		RetryInterval: time.Second,
+		RetryTimeout:  time.Minute,
+	}
+}
+
+func fixLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(os.Stdout, nil))
+}
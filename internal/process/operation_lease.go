@@ -0,0 +1,108 @@
+package process
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/kyma-project/kyma-environment-broker/internal"
+	"github.com/kyma-project/kyma-environment-broker/internal/storage/dberr"
+)
+
+type OperationLeaseConfig struct {
+	// Enabled makes the managers claim an operation before processing it, required when more than one broker replica is running
+	Enabled bool `envconfig:"default=false"`
+	// Duration is the time after which an operation claimed by a not responding replica can be claimed by another one.
+	// It must be longer than the MaxStepProcessingTime of the managers.
+	Duration time.Duration `envconfig:"default=5m"`
+}
+
+// UseLease makes the manager claim the operation before processing it and renew the claim between the steps.
+// Operations claimed by other owners are skipped until their lease expires.
+func (m *StagedManager) UseLease(owner string, duration time.Duration) {
+	m.leaseOwner = owner
+	m.leaseDuration = duration
+}
+
+// ReleaseLeases removes the leases of the operations which wait in the queue, so another replica takes them over
+// without waiting for the leases to expire. It is called when the manager stops, after the queue is drained.
+// The operations still processed keep their leases, because their steps can save the results until the process exits.
+func (m *StagedManager) ReleaseLeases() {
+	if !m.leaseEnabled() {
+		return
+	}
+	m.leasesMu.Lock()
+	defer m.leasesMu.Unlock()
+	for operationID, processed := range m.leases {
+		if processed {
+			continue
+		}
+		log := m.log.With("operationID", operationID)
+		operation, err := m.operationStorage.GetOperationByID(operationID)
+		if err != nil {
+			log.Warn(fmt.Sprintf("Unable to get the operation to release its lease: %s", err))
+			continue
+		}
+		if operation.Lease == nil || operation.Lease.Owner != m.leaseOwner || operation.IsFinished() {
+			continue
+		}
+		expiresAt := operation.Lease.ExpiresAt
+		operation.Lease = nil
+		if _, err := m.operationStorage.UpdateOperation(*operation); err != nil {
+			log.Warn(fmt.Sprintf("Unable to release the lease of the operation, it expires at %s: %s", expiresAt.Format(time.RFC3339), err))
+			continue
+		}
+		log.Info("Lease of the operation released")
+	}
+}
+
+// trackLease remembers the operation while it is processed and while it waits in the queue for the next processing
+func (m *StagedManager) trackLease(operationID string, processed bool) {
+	m.leasesMu.Lock()
+	defer m.leasesMu.Unlock()
+	if m.leases == nil {
+		m.leases = make(map[string]bool)
+	}
+	m.leases[operationID] = processed
+}
+
+// forgetLease is called for the operation which is not returned to the queue
+func (m *StagedManager) forgetLease(operationID string) {
+	m.leasesMu.Lock()
+	defer m.leasesMu.Unlock()
+	delete(m.leases, operationID)
+}
+
+func (m *StagedManager) leaseEnabled() bool {
+	return m.leaseOwner != ""
+}
+
+// claim stores the lease in the operation. The update is protected by the optimistic lock on the operation version,
+// so only one of the replicas claiming the same operation at the same time succeeds.
+// The lease owned by the manager is renewed only when less than half of its duration is left.
+func (m *StagedManager) claim(operation internal.Operation, log *slog.Logger) (internal.Operation, time.Duration, bool) {
+	now := time.Now()
+	if operation.IsLeasedByOther(m.leaseOwner, now) {
+		log.Info(fmt.Sprintf("Operation is processed by %s until %s, skipping", operation.Lease.Owner, operation.Lease.ExpiresAt.Format(time.RFC3339)))
+		return operation, operation.Lease.ExpiresAt.Sub(now) + time.Second, false
+	}
+	if operation.Lease != nil && operation.Lease.Owner == m.leaseOwner && operation.Lease.ExpiresAt.Sub(now) > m.leaseDuration/2 {
+		return operation, 0, true
+	}
+
+	operation.Lease = &internal.OperationLease{Owner: m.leaseOwner, ExpiresAt: now.Add(m.leaseDuration)}
+	claimed, err := m.operationStorage.UpdateOperation(operation)
+	switch {
+	case err == nil:
+		return *claimed, 0, true
+	case dberr.IsNotFound(err):
+		// it is ok, when operation does not exist in the DB - it can happen at the end of a deprovisioning process
+		return operation, 0, true
+	case dberr.IsConflict(err):
+		log.Info("Operation was modified by another replica while claiming it, retrying")
+		return operation, time.Second, false
+	default:
+		log.Error(fmt.Sprintf("Unable to claim the operation: %s", err))
+		return operation, 3 * time.Second, false
+	}
+}
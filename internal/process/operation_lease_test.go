@@ -0,0 +1,249 @@
+package process_test
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/kyma-project/kyma-environment-broker/internal"
+	"github.com/kyma-project/kyma-environment-broker/internal/event"
+	"github.com/kyma-project/kyma-environment-broker/internal/process"
+	"github.com/kyma-project/kyma-environment-broker/internal/storage"
+
+	"github.com/pivotal-cf/brokerapi/v12/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOperationLease(t *testing.T) {
+	t.Run("should not process the operation claimed by another replica", func(t *testing.T) {
+		// given
+		operation := FixOperation("op-0001234")
+		db := storage.NewMemoryStorage()
+		require.NoError(t, db.Operations().InsertOperation(operation))
+		eventCollector := &CollectingEventHandler{}
+		replicaA := newLeasingStagedManager(db.Operations(), eventCollector, "pod-a", time.Hour)
+		require.NoError(t, replicaA.AddStep("stage-1", &waitingStep{name: "wait-a", eventPublisher: eventCollector}, nil))
+		replicaB := newLeasingStagedManager(db.Operations(), eventCollector, "pod-b", time.Hour)
+		require.NoError(t, replicaB.AddStep("stage-1", &testingStep{name: "run-b", eventPublisher: eventCollector}, nil))
+
+		// when
+		retryA, err := replicaA.Execute(operation.ID)
+		require.NoError(t, err)
+		retryB, err := replicaB.Execute(operation.ID)
+		require.NoError(t, err)
+
+		// then
+		assert.Equal(t, time.Minute, retryA)
+		assert.Greater(t, retryB, 59*time.Minute)
+		eventCollector.AssertProcessedSteps(t, []string{"wait-a"})
+		op, err := db.Operations().GetOperationByID(operation.ID)
+		require.NoError(t, err)
+		require.NotNil(t, op.Lease)
+		assert.Equal(t, "pod-a", op.Lease.Owner)
+		assert.Equal(t, domain.InProgress, op.State)
+	})
+
+	t.Run("should take over the operation after the replica restart", func(t *testing.T) {
+		// given
+		operation := FixOperation("op-0001234")
+		db := storage.NewMemoryStorage()
+		require.NoError(t, db.Operations().InsertOperation(operation))
+		eventCollector := &CollectingEventHandler{}
+		replicaA := newLeasingStagedManager(db.Operations(), eventCollector, "pod-a", 10*time.Millisecond)
+		require.NoError(t, replicaA.AddStep("stage-1", &waitingStep{name: "wait-a", eventPublisher: eventCollector}, nil))
+		_, err := replicaA.Execute(operation.ID)
+		require.NoError(t, err)
+
+		// the replica A is gone, its lease expires
+		time.Sleep(20 * time.Millisecond)
+		replicaB := newLeasingStagedManager(db.Operations(), eventCollector, "pod-b", time.Hour)
+		require.NoError(t, replicaB.AddStep("stage-1", &testingStep{name: "run-b", eventPublisher: eventCollector}, nil))
+
+		// when
+		retry, err := replicaB.Execute(operation.ID)
+
+		// then
+		require.NoError(t, err)
+		assert.Zero(t, retry)
+		eventCollector.AssertProcessedSteps(t, []string{"wait-a", "run-b"})
+		op, err := db.Operations().GetOperationByID(operation.ID)
+		require.NoError(t, err)
+		assert.Equal(t, domain.Succeeded, op.State)
+		assert.Equal(t, "pod-b", op.Lease.Owner)
+	})
+
+	t.Run("should skip the operation finished by another replica", func(t *testing.T) {
+		// given
+		operation := FixOperation("op-0001234")
+		operation.State = domain.Succeeded
+		operation.Lease = &internal.OperationLease{Owner: "pod-a", ExpiresAt: time.Now().Add(-time.Minute)}
+		db := storage.NewMemoryStorage()
+		require.NoError(t, db.Operations().InsertOperation(operation))
+		eventCollector := &CollectingEventHandler{}
+		replicaB := newLeasingStagedManager(db.Operations(), eventCollector, "pod-b", time.Hour)
+		require.NoError(t, replicaB.AddStep("stage-1", &testingStep{name: "run-b", eventPublisher: eventCollector}, nil))
+
+		// when
+		retry, err := replicaB.Execute(operation.ID)
+
+		// then
+		require.NoError(t, err)
+		assert.Zero(t, retry)
+		eventCollector.AssertProcessedSteps(t, []string{})
+		op, err := db.Operations().GetOperationByID(operation.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "pod-a", op.Lease.Owner)
+	})
+
+	t.Run("should let only one replica claim the operation loaded at the same time", func(t *testing.T) {
+		// given
+		operation := FixOperation("op-0001234")
+		db := storage.NewMemoryStorage()
+		require.NoError(t, db.Operations().InsertOperation(operation))
+		eventCollector := &CollectingEventHandler{}
+
+		// the replica A claims the operation after the replica B loaded it
+		claimed := operation
+		claimed.Lease = &internal.OperationLease{Owner: "pod-a", ExpiresAt: time.Now().Add(time.Hour)}
+		_, err := db.Operations().UpdateOperation(claimed)
+		require.NoError(t, err)
+		racing := &racingOperations{Operations: db.Operations(), loaded: operation}
+
+		// when
+		replicaB := newLeasingStagedManager(racing, eventCollector, "pod-b", time.Hour)
+		require.NoError(t, replicaB.AddStep("stage-1", &testingStep{name: "run-b", eventPublisher: eventCollector}, nil))
+		retry, err := replicaB.Execute(operation.ID)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, time.Second, retry)
+		eventCollector.AssertProcessedSteps(t, []string{})
+		op, err := db.Operations().GetOperationByID(operation.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "pod-a", op.Lease.Owner)
+	})
+}
+
+func TestOperationLease_Replicas(t *testing.T) {
+	log := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	t.Run("should process the operation once when two replicas run it", func(t *testing.T) {
+		// given
+		operation := FixOperation("op-0001234")
+		db := storage.NewMemoryStorage()
+		require.NoError(t, db.Operations().InsertOperation(operation))
+		step := &countingStep{name: "count", duration: 50 * time.Millisecond}
+		stop := make(chan struct{})
+		defer close(stop)
+
+		for _, owner := range []string{"pod-a", "pod-b"} {
+			replica := newLeasingStagedManager(db.Operations(), &CollectingEventHandler{}, owner, time.Hour)
+			require.NoError(t, replica.AddStep("stage-1", step, nil))
+			queue := process.NewQueue(replica, log, owner)
+			queue.Run(stop, 2)
+			queue.Add(operation.ID)
+		}
+
+		// then
+		waitForOperationState(t, db.Operations(), operation.ID, domain.Succeeded)
+		assert.Equal(t, int32(1), step.executions.Load())
+	})
+
+	t.Run("should let the restarted replica resume the operation at once", func(t *testing.T) {
+		// given
+		operation := FixOperation("op-0001234")
+		db := storage.NewMemoryStorage()
+		require.NoError(t, db.Operations().InsertOperation(operation))
+		eventCollector := &CollectingEventHandler{}
+		stop := make(chan struct{})
+		defer close(stop)
+
+		replicaA := newLeasingStagedManager(db.Operations(), eventCollector, "pod-a", time.Hour)
+		require.NoError(t, replicaA.AddStep("stage-1", &waitingStep{name: "wait-a", eventPublisher: eventCollector}, nil))
+		queueA := process.NewQueue(replicaA, log, "pod-a")
+		queueA.Run(stop, 1)
+		queueA.Add(operation.ID)
+		eventCollector.WaitForEvents(t, 1)
+
+		// the Pod is restarted and gets a new name
+		require.True(t, queueA.Drain(time.Second))
+		replicaA.ReleaseLeases()
+		replicaB := newLeasingStagedManager(db.Operations(), eventCollector, "pod-b", time.Hour)
+		require.NoError(t, replicaB.AddStep("stage-1", &testingStep{name: "run-b", eventPublisher: eventCollector}, nil))
+		queueB := process.NewQueue(replicaB, log, "pod-b")
+		queueB.Run(stop, 1)
+
+		// when
+		queueB.Add(operation.ID)
+
+		// then
+		waitForOperationState(t, db.Operations(), operation.ID, domain.Succeeded)
+		eventCollector.AssertProcessedSteps(t, []string{"wait-a", "run-b"})
+		op, err := db.Operations().GetOperationByID(operation.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "pod-b", op.Lease.Owner)
+	})
+}
+
+func waitForOperationState(t *testing.T, operations storage.Operations, operationID string, state domain.LastOperationState) {
+	assert.Eventually(t, func() bool {
+		op, err := operations.GetOperationByID(operationID)
+		return err == nil && op.State == state
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func newLeasingStagedManager(operations storage.Operations, eventCollector *CollectingEventHandler, owner string, leaseDuration time.Duration) *process.StagedManager {
+	l := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	mgr := process.NewStagedManager(operations, eventCollector, time.Hour, process.StagedManagerConfiguration{}, l)
+	mgr.SpeedUp(100000)
+	mgr.DefineStages([]string{"stage-1"})
+	mgr.UseLease(owner, leaseDuration)
+	return mgr
+}
+
+// racingOperations returns the operation loaded before it was claimed by another replica
+type racingOperations struct {
+	storage.Operations
+	loaded internal.Operation
+}
+
+func (r *racingOperations) GetOperationByID(string) (*internal.Operation, error) {
+	op := r.loaded
+	return &op, nil
+}
+
+type waitingStep struct {
+	name           string
+	eventPublisher event.Publisher
+}
+
+func (s *waitingStep) Name() string {
+	return s.name
+}
+
+func (s *waitingStep) Run(operation internal.Operation, logger *slog.Logger) (internal.Operation, time.Duration, error) {
+	s.eventPublisher.Publish(context.Background(), s.name)
+	logger.Info("Waiting")
+	return operation, time.Minute, nil
+}
+
+// countingStep counts its executions by all replicas
+type countingStep struct {
+	name       string
+	duration   time.Duration
+	executions atomic.Int32
+}
+
+func (s *countingStep) Name() string {
+	return s.name
+}
+
+func (s *countingStep) Run(operation internal.Operation, _ *slog.Logger) (internal.Operation, time.Duration, error) {
+	s.executions.Add(1)
+	time.Sleep(s.duration)
+	return operation, 0, nil
+}
@@ -6,6 +6,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/kyma-project/kyma-environment-broker/common/events"
 	"github.com/kyma-project/kyma-environment-broker/internal"
 	kebErr "github.com/kyma-project/kyma-environment-broker/internal/error"
 	"github.com/kyma-project/kyma-environment-broker/internal/storage"
@@ -95,7 +96,7 @@ func (om *OperationManager) OperationFailed(operation internal.Operation, descri
 	}
 
 	log.Error(fmt.Sprintf("Step execution failed: %v", retErr))
-	operation.EventErrorf(retErr, "operation failed")
+	operation.StepEventf(events.ErrorEventLevel, om.step, om.eventAttributes(description), "operation failed: %v", retErr)
 
 	return op, 0, retErr
 }
@@ -174,7 +175,7 @@ func (om *OperationManager) RetryOperationWithoutFail(operation internal.Operati
 		return op, repeat, err
 	}
 
-	op.EventErrorf(fmt.Errorf("%s", description), "step %s failed all retries: operation continues", stepName)
+	op.StepEventf(events.WarningEventLevel, stepName, om.eventAttributes(description), "step %s failed all retries: operation continues: %s", stepName, description)
 	if opErr != nil {
 		log.Error(fmt.Sprintf("quiting step after %s of failing retries, last error: %s", maxTime.String(), opErr.Error()))
 	} else {
@@ -190,27 +191,31 @@ func (om *OperationManager) RetryOperationOnce(operation internal.Operation, err
 
 // UpdateOperation updates a given operation and handles conflict situation
 // The DB update call must be done even if there is no any change in the operation - this is required to update the operation's `UpdatedAt` field.
+// When the operation was changed concurrently, the stored operation is reloaded and merged with the updated one:
+// the fields changed only concurrently keep the stored values, the fields changed by the update take the updated values.
+// The fields changed both by the update and concurrently are logged.
 func (om *OperationManager) UpdateOperation(operation internal.Operation, update func(operation *internal.Operation), log *slog.Logger) (internal.Operation, time.Duration, error) {
-	update(&operation)
+	base := operation
+	keepCanceling(update)(&operation)
 	op, err := om.storage.UpdateOperation(operation)
-	switch {
-	case dberr.IsConflict(err):
-		{
-			op, err = om.storage.GetOperationByID(operation.ID)
-			if err != nil {
-				log.Error(fmt.Sprintf("while getting operation: %v", err))
-				return operation, 1 * time.Minute, err
-			}
-			// do not optimize the flow by skipping the update call - it's required to update the `UpdatedAt` field
-			op.Merge(&operation)
-			update(op)
-			op, err = om.storage.UpdateOperation(*op)
-			if err != nil {
-				log.Error(fmt.Sprintf("while updating operation after conflict: %v", err))
-				return operation, 1 * time.Minute, err
-			}
+	for attempt := 1; dberr.IsConflict(err) && attempt <= maxConflictRetries; attempt++ {
+		op, err = om.storage.GetOperationByID(operation.ID)
+		if err != nil {
+			log.Error(fmt.Sprintf("while getting operation: %v", err))
+			return operation, 1 * time.Minute, err
 		}
-	case err != nil:
+		om.logMerge(newOperationMerge(base, operation, *op), operation.Version, op.Version, log)
+
+		// do not optimize the flow by skipping the update call - it's required to update the `UpdatedAt` field
+		ours, previousBase := operation, base
+		base = *op
+		operation = *op
+		keepCanceling(func(stored *internal.Operation) {
+			*stored = mergeOperations(previousBase, ours, *stored)
+		})(&operation)
+		op, err = om.storage.UpdateOperation(operation)
+	}
+	if err != nil {
 		log.Error(fmt.Sprintf("while updating operation: %v", err))
 		return operation, 1 * time.Minute, err
 	}
@@ -218,6 +223,31 @@ func (om *OperationManager) UpdateOperation(operation internal.Operation, update
 	return *op, 0, nil
 }
 
+// keepCanceling wraps the update, so it does not override the requested cancellation with a state of the running operation.
+// The cancellation is requested concurrently, so it is visible only in the operation reloaded after the conflict.
+func keepCanceling(update func(operation *internal.Operation)) func(operation *internal.Operation) {
+	return func(operation *internal.Operation) {
+		canceling := operation.State == internal.OperationStateCanceling
+		update(operation)
+		if canceling && !operation.IsFinished() {
+			operation.State = internal.OperationStateCanceling
+		}
+	}
+}
+
+// maxConflictRetries is the number of times the update is merged with the concurrently changed operation
+const maxConflictRetries = 3
+
+func (om *OperationManager) logMerge(merge operationMerge, expectedVersion, storedVersion int, log *slog.Logger) {
+	msg := fmt.Sprintf("operation changed concurrently (version %d, expected %d), merging the update with the stored operation, concurrently changed fields: %v",
+		storedVersion, expectedVersion, merge.concurrent)
+	if len(merge.collisions) > 0 {
+		log.Warn(fmt.Sprintf("%s, fields overridden by the update of step %q: %v", msg, om.step, merge.collisions))
+		return
+	}
+	log.Info(msg)
+}
+
 func (om *OperationManager) MarkStepAsExecutedButNotCompleted(operation internal.Operation, stepName string, msg string, log *slog.Logger) (internal.Operation, time.Duration, error) {
 	op, repeat, err := om.UpdateOperation(operation, func(operation *internal.Operation) {
 		operation.ExcutedButNotCompleted = append(operation.ExcutedButNotCompleted, stepName)
@@ -226,11 +256,16 @@ func (om *OperationManager) MarkStepAsExecutedButNotCompleted(operation internal
 		return op, repeat, err
 	}
 
-	op.EventErrorf(fmt.Errorf("%s", msg), "step %s failed: operation continues", stepName)
+	op.StepEventf(events.WarningEventLevel, stepName, om.eventAttributes(msg), "step %s failed: operation continues: %s", stepName, msg)
 	log.Error(msg)
 	return op, 0, nil
 }
 
+// eventAttributes returns the attributes of the events about the failures, the component is the dependency which caused the failure
+func (om *OperationManager) eventAttributes(reason string) events.Attributes {
+	return events.Attributes{"component": string(om.component), "reason": reason}
+}
+
 func (om *OperationManager) update(operation internal.Operation, state domain.LastOperationState, description string, log *slog.Logger) (internal.Operation, time.Duration, error) {
 	return om.UpdateOperation(operation, func(operation *internal.Operation) {
 		operation.State = state
@@ -13,6 +13,7 @@ import (
 	"github.com/kyma-project/kyma-environment-broker/internal"
 	kebErr "github.com/kyma-project/kyma-environment-broker/internal/error"
 	"github.com/kyma-project/kyma-environment-broker/internal/storage"
+	"github.com/pivotal-cf/brokerapi/v12/domain"
 )
 
 func Test_OperationManager_RetryOperationOnce(t *testing.T) {
@@ -151,6 +152,68 @@ func Test_OperationManager_LastError(t *testing.T) {
 	})
 }
 
+func Test_OperationManager_UpdateOperation(t *testing.T) {
+	t.Run("should apply the update on top of the concurrently changed operation", func(t *testing.T) {
+		// given
+		memory := storage.NewMemoryStorage()
+		operations := memory.Operations()
+		opManager := NewOperationManager(operations, "some_step", kebErr.KEBDependency)
+		op := internal.Operation{ID: "op-01", InstanceID: "instance-01", State: domain.InProgress}
+		require.NoError(t, operations.InsertOperation(op))
+
+		concurrent := op
+		concurrent.ProvisioningParameters.ErsContext.SubAccountID = "moved-subaccount"
+		concurrent.Description = "changed concurrently"
+		_, err := operations.UpdateOperation(concurrent)
+		require.NoError(t, err)
+
+		// when
+		updated, when, err := opManager.UpdateOperation(op, func(operation *internal.Operation) {
+			operation.Description = "step finished"
+			operation.ExcutedButNotCompleted = append(operation.ExcutedButNotCompleted, "some_step")
+		}, fixLogger())
+
+		// then
+		require.NoError(t, err)
+		assert.Zero(t, when)
+		assert.Equal(t, "moved-subaccount", updated.ProvisioningParameters.ErsContext.SubAccountID)
+		assert.Equal(t, "step finished", updated.Description)
+		assert.Equal(t, []string{"some_step"}, updated.ExcutedButNotCompleted)
+
+		stored, err := operations.GetOperationByID(op.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "moved-subaccount", stored.ProvisioningParameters.ErsContext.SubAccountID)
+		assert.Equal(t, "step finished", stored.Description)
+	})
+
+	t.Run("should keep the cancellation requested concurrently", func(t *testing.T) {
+		// given
+		memory := storage.NewMemoryStorage()
+		operations := memory.Operations()
+		opManager := NewOperationManager(operations, "some_step", kebErr.KEBDependency)
+		op := internal.Operation{ID: "op-02", InstanceID: "instance-02", State: domain.InProgress}
+		require.NoError(t, operations.InsertOperation(op))
+
+		canceling := op
+		canceling.State = internal.OperationStateCanceling
+		_, err := operations.UpdateOperation(canceling)
+		require.NoError(t, err)
+
+		// when
+		updated, when, err := opManager.RetryOperationWithoutFail(op, "some_step", "step failed", time.Second, -time.Second, fixLogger(), nil)
+
+		// then
+		require.NoError(t, err)
+		assert.Zero(t, when)
+		assert.Equal(t, internal.OperationStateCanceling, string(updated.State))
+		assert.Equal(t, []string{"some_step"}, updated.ExcutedButNotCompleted)
+
+		stored, err := operations.GetOperationByID(op.ID)
+		require.NoError(t, err)
+		assert.Equal(t, internal.OperationStateCanceling, string(stored.State))
+	})
+}
+
 func fixLogger() *slog.Logger {
 	return slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{
 		Level: slog.LevelInfo,
@@ -0,0 +1,209 @@
+package process
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/kyma-project/kyma-environment-broker/internal"
+)
+
+// mergeIgnoredFields are changed by every write of the operation, so they are never reported and the merged operation takes them from the stored one
+var mergeIgnoredFields = map[string]bool{
+	"Version":   true,
+	"UpdatedAt": true,
+}
+
+// operationMerge describes the three-way merge of an update which conflicted with a concurrent write.
+// The base is the operation passed to the update, ours is the operation after the mutation,
+// and theirs is the operation stored by the concurrent writer.
+type operationMerge struct {
+	// concurrent are the fields changed by the concurrent writer, the merged operation keeps their values
+	// unless the mutation changed them too
+	concurrent []string
+	// collisions are the fields changed both by the mutation and by the concurrent writer to different values,
+	// the merged operation takes their values from the mutation
+	collisions []string
+}
+
+func newOperationMerge(base, ours, theirs internal.Operation) operationMerge {
+	changedByUs := toSet(changedFields(base, ours))
+	differ := toSet(changedFields(ours, theirs))
+
+	merge := operationMerge{concurrent: changedFields(base, theirs)}
+	for _, field := range merge.concurrent {
+		if changedByUs[field] && differ[field] {
+			merge.collisions = append(merge.collisions, field)
+		}
+	}
+	return merge
+}
+
+// mergeOperations merges the operation changed by the mutation (ours) with the operation stored by the concurrent writer (theirs),
+// both changed from the base. The fields changed only by the concurrent writer keep its values, the fields changed by the mutation
+// take the values of the mutation. The structs, pointers to structs and maps changed by both are merged field by field.
+func mergeOperations(base, ours, theirs internal.Operation) internal.Operation {
+	merged := mergeValues("", reflect.ValueOf(base), reflect.ValueOf(ours), reflect.ValueOf(theirs)).Interface().(internal.Operation)
+	// the ignored fields are not compared, so the shortcuts for the unchanged operations could take them from ours
+	merged.Version = theirs.Version
+	merged.UpdatedAt = theirs.UpdatedAt
+	return merged
+}
+
+func mergeValues(path string, base, ours, theirs reflect.Value) reflect.Value {
+	switch {
+	case equalValues(path, base, ours):
+		return theirs
+	case equalValues(path, base, theirs), equalValues(path, ours, theirs):
+		return ours
+	}
+
+	switch {
+	case ours.Type() == timeType:
+		return ours
+	case ours.Kind() == reflect.Struct:
+		merged := reflect.New(ours.Type()).Elem()
+		merged.Set(ours)
+		for i := 0; i < ours.NumField(); i++ {
+			field := ours.Type().Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			fieldPath := joinPath(path, field.Name)
+			if field.Anonymous {
+				fieldPath = path
+			}
+			merged.Field(i).Set(mergeValues(fieldPath, base.Field(i), ours.Field(i), theirs.Field(i)))
+		}
+		return merged
+	case ours.Kind() == reflect.Pointer && ours.Type().Elem().Kind() == reflect.Struct:
+		if base.IsNil() || ours.IsNil() || theirs.IsNil() {
+			return ours
+		}
+		merged := reflect.New(ours.Type().Elem())
+		merged.Elem().Set(mergeValues(path, base.Elem(), ours.Elem(), theirs.Elem()))
+		return merged
+	case ours.Kind() == reflect.Map && ours.Type().Key().Kind() == reflect.String:
+		merged := reflect.MakeMap(ours.Type())
+		keys := map[string]reflect.Value{}
+		for _, key := range append(append(base.MapKeys(), ours.MapKeys()...), theirs.MapKeys()...) {
+			keys[key.String()] = key
+		}
+		for name, key := range keys {
+			keyPath := fmt.Sprintf("%s[%s]", path, name)
+			b, o, t := base.MapIndex(key), ours.MapIndex(key), theirs.MapIndex(key)
+			var value reflect.Value
+			switch {
+			case presentAndEqual(keyPath, b, o):
+				value = t
+			case presentAndEqual(keyPath, b, t), presentAndEqual(keyPath, o, t):
+				value = o
+			case b.IsValid() && o.IsValid() && t.IsValid():
+				value = mergeValues(keyPath, b, o, t)
+			default:
+				value = o
+			}
+			if value.IsValid() {
+				merged.SetMapIndex(key, value)
+			}
+		}
+		if merged.Len() == 0 && ours.IsNil() {
+			return ours
+		}
+		return merged
+	default:
+		return ours
+	}
+}
+
+func equalValues(path string, a, b reflect.Value) bool {
+	var fields []string
+	diffValues(path, a, b, &fields)
+	return len(fields) == 0
+}
+
+// presentAndEqual compares the map entries, the missing entries are equal to each other
+func presentAndEqual(path string, a, b reflect.Value) bool {
+	if !a.IsValid() || !b.IsValid() {
+		return a.IsValid() == b.IsValid()
+	}
+	return equalValues(path, a, b)
+}
+
+// changedFields returns the paths of the fields which differ between the operations. The nested structs,
+// pointers to structs and maps are compared field by field, the times are compared by the instant they represent.
+func changedFields(a, b internal.Operation) []string {
+	var fields []string
+	diffValues("", reflect.ValueOf(a), reflect.ValueOf(b), &fields)
+	sort.Strings(fields)
+	return fields
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+func diffValues(path string, a, b reflect.Value, fields *[]string) {
+	if mergeIgnoredFields[path] {
+		return
+	}
+	switch {
+	case a.Type() == timeType:
+		if !a.Interface().(time.Time).Equal(b.Interface().(time.Time)) {
+			*fields = append(*fields, path)
+		}
+	case a.Kind() == reflect.Struct:
+		for i := 0; i < a.NumField(); i++ {
+			field := a.Type().Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			fieldPath := joinPath(path, field.Name)
+			if field.Anonymous {
+				// the fields of the embedded structs are promoted to the operation
+				fieldPath = path
+			}
+			diffValues(fieldPath, a.Field(i), b.Field(i), fields)
+		}
+	case a.Kind() == reflect.Pointer && a.Type().Elem().Kind() == reflect.Struct:
+		if a.IsNil() || b.IsNil() {
+			if a.IsNil() != b.IsNil() {
+				*fields = append(*fields, path)
+			}
+			return
+		}
+		diffValues(path, a.Elem(), b.Elem(), fields)
+	case a.Kind() == reflect.Map && a.Type().Key().Kind() == reflect.String:
+		keys := map[string]bool{}
+		for _, key := range append(a.MapKeys(), b.MapKeys()...) {
+			keys[key.String()] = true
+		}
+		for key := range keys {
+			keyPath := fmt.Sprintf("%s[%s]", path, key)
+			av, bv := a.MapIndex(reflect.ValueOf(key).Convert(a.Type().Key())), b.MapIndex(reflect.ValueOf(key).Convert(b.Type().Key()))
+			if !av.IsValid() || !bv.IsValid() {
+				*fields = append(*fields, keyPath)
+				continue
+			}
+			diffValues(keyPath, av, bv, fields)
+		}
+	default:
+		if !reflect.DeepEqual(a.Interface(), b.Interface()) {
+			*fields = append(*fields, path)
+		}
+	}
+}
+
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+func toSet(values []string) map[string]bool {
+	set := make(map[string]bool, len(values))
+	for _, v := range values {
+		set[v] = true
+	}
+	return set
+}
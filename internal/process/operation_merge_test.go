@@ -0,0 +1,83 @@
+package process
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kyma-project/kyma-environment-broker/internal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOperationMerge(t *testing.T) {
+	// given
+	now := time.Now()
+	base := internal.Operation{
+		ID:          "op-01",
+		Version:     1,
+		Description: "in progress",
+		Lease:       &internal.OperationLease{Owner: "keb-0", ExpiresAt: now},
+		StepRetries: map[string]internal.StepRetry{"step-a": {Attempts: 1, FirstFailureAt: now}},
+	}
+	base.ProvisioningParameters.ErsContext.SubAccountID = "subaccount-01"
+
+	ours := base
+	ours.Description = "step finished"
+	ours.StepRetries = map[string]internal.StepRetry{"step-a": {Attempts: 2, FirstFailureAt: now}}
+	ours.RuntimeID = "runtime-01"
+
+	theirs := base
+	theirs.Version = 2
+	theirs.UpdatedAt = now
+	theirs.Description = "refreshed"
+	theirs.ProvisioningParameters.ErsContext.SubAccountID = "subaccount-02"
+	theirs.Lease = &internal.OperationLease{Owner: "keb-0", ExpiresAt: now.UTC()}
+	theirs.RuntimeID = "runtime-01"
+
+	// when
+	merge := newOperationMerge(base, ours, theirs)
+
+	// then
+	assert.Equal(t, []string{"Description", "ProvisioningParameters.ErsContext.SubAccountID", "RuntimeID"}, merge.concurrent)
+	assert.Equal(t, []string{"Description"}, merge.collisions)
+	assert.Equal(t, []string{"Description", "RuntimeID", "StepRetries[step-a].Attempts"}, changedFields(base, ours))
+}
+
+func TestMergeOperations(t *testing.T) {
+	// given
+	now := time.Now()
+	base := internal.Operation{
+		ID:          "op-01",
+		Version:     1,
+		Description: "in progress",
+		Lease:       &internal.OperationLease{Owner: "keb-0", ExpiresAt: now},
+		StepRetries: map[string]internal.StepRetry{"step-a": {Attempts: 1, FirstFailureAt: now}, "step-b": {Attempts: 1, FirstFailureAt: now}},
+	}
+	base.ProvisioningParameters.ErsContext.SubAccountID = "subaccount-01"
+
+	ours := base
+	ours.Description = "step finished"
+	ours.StepRetries = map[string]internal.StepRetry{"step-a": {Attempts: 2, FirstFailureAt: now}}
+	ours.InstanceDetails.ShootName = "shoot-01"
+
+	theirs := base
+	theirs.Version = 2
+	theirs.UpdatedAt = now
+	theirs.Description = "refreshed"
+	theirs.ProvisioningParameters.ErsContext.SubAccountID = "subaccount-02"
+	theirs.Lease = &internal.OperationLease{Owner: "keb-1", ExpiresAt: now}
+	theirs.StepRetries = map[string]internal.StepRetry{"step-a": {Attempts: 1, FirstFailureAt: now}, "step-b": {Attempts: 1, FirstFailureAt: now}, "step-c": {Attempts: 1, FirstFailureAt: now}}
+	theirs.InstanceDetails.ShootDomain = "shoot-01.example.com"
+
+	// when
+	merged := mergeOperations(base, ours, theirs)
+
+	// then
+	assert.Equal(t, 2, merged.Version)
+	assert.Equal(t, now, merged.UpdatedAt)
+	assert.Equal(t, "step finished", merged.Description)
+	assert.Equal(t, "subaccount-02", merged.ProvisioningParameters.ErsContext.SubAccountID)
+	assert.Equal(t, "keb-1", merged.Lease.Owner)
+	assert.Equal(t, "shoot-01", merged.InstanceDetails.ShootName)
+	assert.Equal(t, "shoot-01.example.com", merged.InstanceDetails.ShootDomain)
+	assert.Equal(t, map[string]internal.StepRetry{"step-a": {Attempts: 2, FirstFailureAt: now}, "step-c": {Attempts: 1, FirstFailureAt: now}}, merged.StepRetries)
+}
@@ -0,0 +1,269 @@
+package process
+
+import (
+	"fmt"
+	"log/slog"
+	"math/rand"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kyma-project/kyma-environment-broker/internal"
+	"github.com/kyma-project/kyma-environment-broker/internal/storage"
+	"github.com/kyma-project/kyma-environment-broker/internal/storage/dberr"
+)
+
+type PersistentQueueConfig struct {
+	// Enabled switches the processing queues from the in-memory workqueue to the queue stored in the database
+	Enabled bool `envconfig:"default=false"`
+	// LeaseDuration is the time after which an operation leased by a not responding instance can be taken by another one
+	LeaseDuration time.Duration `envconfig:"default=10m"`
+	// PollInterval is the time between checks for the operations which are due, the interval doubles while the queue is empty
+	PollInterval time.Duration `envconfig:"default=1s"`
+	// MaxPollInterval limits the time between checks while the queue is empty
+	MaxPollInterval time.Duration `envconfig:"default=10s"`
+	// RestoreSpread is the time range in which the operations restored on the application start are scheduled
+	RestoreSpread time.Duration `envconfig:"default=1m"`
+}
+
+type leasedItem struct {
+	item      internal.QueueItem
+	requeueAt *time.Time
+}
+
+// persistentQueue keeps the scheduled operations in the storage, so the schedule survives the application restart.
+// Every operation taken for processing is leased, the lease is extended as long as the operation is processed.
+// One poller leases the operations from the storage and hands them out to the workers, it leases an operation
+// only when a worker waits for it.
+type persistentQueue struct {
+	storage storage.QueueItems
+	name    string
+	owner   string
+	config  PersistentQueueConfig
+	log     *slog.Logger
+
+	mu     sync.Mutex
+	leased map[string]*leasedItem
+
+	// requests are sent by the workers waiting for an item, the poller sends the leased items to them
+	requests     chan struct{}
+	items        chan string
+	notify       chan struct{}
+	shutdown     chan struct{}
+	shutdownOnce sync.Once
+}
+
+func NewPersistentQueue(executor Executor, items storage.QueueItems, log *slog.Logger, name string, cfg PersistentQueueConfig) *Queue {
+	log = log.With("queueName", name)
+	return newQueue(newPersistentQueue(items, log, name, cfg), executor, log, name)
+}
+
+func newPersistentQueue(items storage.QueueItems, log *slog.Logger, name string, cfg PersistentQueueConfig) *persistentQueue {
+	if cfg.LeaseDuration <= 0 {
+		cfg.LeaseDuration = 10 * time.Minute
+	}
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = time.Second
+	}
+	if cfg.MaxPollInterval < cfg.PollInterval {
+		cfg.MaxPollInterval = cfg.PollInterval
+	}
+	hostname, _ := os.Hostname()
+	q := &persistentQueue{
+		storage:  items,
+		name:     name,
+		owner:    fmt.Sprintf("%s-%s", hostname, uuid.NewString()),
+		config:   cfg,
+		log:      log,
+		leased:   make(map[string]*leasedItem),
+		requests: make(chan struct{}),
+		items:    make(chan string),
+		notify:   make(chan struct{}, 1),
+		shutdown: make(chan struct{}),
+	}
+	go q.poll()
+	go q.extendLeases()
+	return q
+}
+
+func (q *persistentQueue) Add(item string) {
+	q.schedule(item, time.Now())
+}
+
+func (q *persistentQueue) AddAfter(item string, duration time.Duration) {
+	q.mu.Lock()
+	leased, found := q.leased[item]
+	if found && leased.requeueAt == nil {
+		// the item is processed by this queue, the new schedule is stored when the processing is done
+		requeueAt := time.Now().Add(duration)
+		leased.requeueAt = &requeueAt
+		q.mu.Unlock()
+		return
+	}
+	q.mu.Unlock()
+
+	q.schedule(item, time.Now().Add(duration))
+}
+
+func (q *persistentQueue) Restore(item string) {
+	runAt := time.Now()
+	if q.config.RestoreSpread > 0 {
+		runAt = runAt.Add(time.Duration(rand.Int63n(int64(q.config.RestoreSpread))))
+	}
+	if err := q.storage.ScheduleIfAbsent(q.name, item, runAt); err != nil {
+		q.log.Error(fmt.Sprintf("unable to restore item %s in the queue: %s", item, err))
+	}
+}
+
+func (q *persistentQueue) Get() (string, bool) {
+	select {
+	case <-q.shutdown:
+		return "", true
+	case q.requests <- struct{}{}:
+	}
+	select {
+	case <-q.shutdown:
+		return "", true
+	case item := <-q.items:
+		return item, false
+	}
+}
+
+// poll leases an item for every worker which waits for it
+func (q *persistentQueue) poll() {
+	for {
+		select {
+		case <-q.shutdown:
+			return
+		case <-q.requests:
+		}
+		item, ok := q.lease()
+		if !ok {
+			return
+		}
+
+		q.mu.Lock()
+		q.leased[item.OperationID] = &leasedItem{item: *item}
+		q.mu.Unlock()
+		select {
+		case q.items <- item.OperationID:
+		case <-q.shutdown:
+			// the worker stopped waiting, the item is returned, so another instance does not wait for the lease to expire
+			q.mu.Lock()
+			delete(q.leased, item.OperationID)
+			q.mu.Unlock()
+			if err := q.storage.Reschedule(*item, time.Now()); err != nil {
+				q.log.Warn(fmt.Sprintf("unable to return item %s to the queue: %s", item.OperationID, err))
+			}
+			return
+		}
+	}
+}
+
+// lease returns the next due item, the checks of the empty queue are spread by the growing interval.
+// The interval is reset when an item is scheduled or processed by this instance.
+func (q *persistentQueue) lease() (*internal.QueueItem, bool) {
+	interval := q.config.PollInterval
+	for {
+		item, err := q.storage.Lease(q.name, q.owner, q.config.LeaseDuration)
+		switch {
+		case err == nil:
+			return item, true
+		case !dberr.IsNotFound(err):
+			q.log.Error(fmt.Sprintf("unable to lease an item from the queue: %s", err))
+		}
+
+		select {
+		case <-q.shutdown:
+			return nil, false
+		case <-q.notify:
+			interval = q.config.PollInterval
+		case <-time.After(interval):
+			interval = min(2*interval, q.config.MaxPollInterval)
+		}
+	}
+}
+
+func (q *persistentQueue) Done(item string) {
+	q.mu.Lock()
+	leased, found := q.leased[item]
+	delete(q.leased, item)
+	q.mu.Unlock()
+	if !found {
+		return
+	}
+
+	var err error
+	if leased.requeueAt != nil {
+		err = q.storage.Reschedule(leased.item, *leased.requeueAt)
+		if delay := time.Until(*leased.requeueAt); err == nil && delay > 0 {
+			time.AfterFunc(delay, q.wakeUp)
+		}
+	} else {
+		err = q.storage.Complete(leased.item)
+	}
+	if err != nil {
+		// the lease expires and the item is processed again
+		q.log.Error(fmt.Sprintf("unable to store the result of processing item %s: %s", item, err))
+	}
+	q.wakeUp()
+}
+
+// Forget is a no-op, the item is removed from the storage when it is done without a requeue
+func (q *persistentQueue) Forget(string) {}
+
+func (q *persistentQueue) ShutDown() {
+	q.shutdownOnce.Do(func() {
+		close(q.shutdown)
+	})
+}
+
+// Len returns the number of the items processed by this instance. It does not count the items scheduled in the storage,
+// they are shared by all the instances and counting them on every logged queue change would query the storage.
+func (q *persistentQueue) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.leased)
+}
+
+func (q *persistentQueue) schedule(item string, runAt time.Time) {
+	if err := q.storage.Schedule(q.name, item, runAt); err != nil {
+		q.log.Error(fmt.Sprintf("unable to schedule item %s in the queue: %s", item, err))
+		return
+	}
+	// the item scheduled by this instance is leased when it is due, even if the interval of the empty queue has grown
+	if delay := time.Until(runAt); delay > 0 {
+		time.AfterFunc(delay, q.wakeUp)
+		return
+	}
+	q.wakeUp()
+}
+
+func (q *persistentQueue) wakeUp() {
+	select {
+	case q.notify <- struct{}{}:
+	default:
+	}
+}
+
+func (q *persistentQueue) extendLeases() {
+	ticker := time.NewTicker(q.config.LeaseDuration / 3)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-q.shutdown:
+			return
+		case <-ticker.C:
+			q.mu.Lock()
+			processing := len(q.leased)
+			q.mu.Unlock()
+			if processing == 0 {
+				continue
+			}
+			if err := q.storage.ExtendLease(q.name, q.owner, q.config.LeaseDuration); err != nil {
+				q.log.Warn(fmt.Sprintf("unable to extend the lease of the processed items: %s", err))
+			}
+		}
+	}
+}
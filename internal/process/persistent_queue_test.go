@@ -0,0 +1,184 @@
+package process
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/kyma-project/kyma-environment-broker/internal"
+	"github.com/kyma-project/kyma-environment-broker/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+type countingExecutor struct {
+	mu       sync.Mutex
+	executed map[string]int
+	retries  map[string]int
+}
+
+func (e *countingExecutor) Execute(operationID string) (time.Duration, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.executed[operationID]++
+	if e.executed[operationID] <= e.retries[operationID] {
+		return time.Millisecond, nil
+	}
+	return 0, nil
+}
+
+func (e *countingExecutor) executions(operationID string) int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.executed[operationID]
+}
+
+// countingQueueItems counts the checks of the queue
+type countingQueueItems struct {
+	storage.QueueItems
+	leases atomic.Int32
+}
+
+func (c *countingQueueItems) Lease(queueName, owner string, duration time.Duration) (*internal.QueueItem, error) {
+	c.leases.Add(1)
+	return c.QueueItems.Lease(queueName, owner, duration)
+}
+
+func TestPersistentQueue(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	cfg := PersistentQueueConfig{LeaseDuration: time.Minute, PollInterval: 10 * time.Millisecond}
+
+	t.Run("should process items and remove them from the storage", func(t *testing.T) {
+		// given
+		items := storage.NewMemoryStorage().QueueItems()
+		executor := &countingExecutor{executed: map[string]int{}, retries: map[string]int{"op-2": 2}}
+		queue := NewPersistentQueue(executor, items, logger, "test", cfg)
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		// when
+		queue.Add("op-1")
+		queue.AddAfter("op-2", 0)
+		queue.Run(ctx.Done(), 2)
+
+		// then
+		assert.NoError(t, wait.PollUntilContextTimeout(context.Background(), 10*time.Millisecond, 5*time.Second, true, func(ctx context.Context) (bool, error) {
+			count, err := items.Count("test")
+			return count == 0, err
+		}))
+		assert.Equal(t, 1, executor.executions("op-1"))
+		assert.Equal(t, 3, executor.executions("op-2"))
+
+		queue.ShutDown()
+		cancel()
+		queue.waitGroup.Wait()
+	})
+
+	t.Run("should keep the schedule of items for the next queue instance", func(t *testing.T) {
+		// given
+		items := storage.NewMemoryStorage().QueueItems()
+		require.NoError(t, items.Schedule("test", "op-scheduled", time.Now().Add(time.Hour)))
+		executor := &countingExecutor{executed: map[string]int{}}
+		queue := NewPersistentQueue(executor, items, logger, "test", cfg)
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		// when
+		queue.Restore("op-scheduled")
+		queue.Restore("op-new")
+		queue.Run(ctx.Done(), 1)
+
+		// then
+		assert.NoError(t, wait.PollUntilContextTimeout(context.Background(), 10*time.Millisecond, 5*time.Second, true, func(ctx context.Context) (bool, error) {
+			return executor.executions("op-new") == 1, nil
+		}))
+		assert.Equal(t, 0, executor.executions("op-scheduled"))
+		count, err := items.Count("test")
+		require.NoError(t, err)
+		assert.Equal(t, 1, count)
+
+		queue.ShutDown()
+		cancel()
+		queue.waitGroup.Wait()
+	})
+
+	t.Run("should count the items processed by the queue instance", func(t *testing.T) {
+		// given
+		items := storage.NewMemoryStorage().QueueItems()
+		require.NoError(t, items.Schedule("test", "op-1", time.Now()))
+		require.NoError(t, items.Schedule("test", "op-2", time.Now().Add(time.Hour)))
+		queue := newPersistentQueue(items, logger, "test", cfg)
+		defer queue.ShutDown()
+
+		// when
+		item, shutdown := queue.Get()
+
+		// then
+		require.False(t, shutdown)
+		assert.Equal(t, "op-1", item)
+		assert.Equal(t, 1, queue.Len())
+
+		// when
+		queue.Done(item)
+
+		// then
+		assert.Equal(t, 0, queue.Len())
+	})
+
+	t.Run("should not take the item leased by another queue instance", func(t *testing.T) {
+		// given
+		items := storage.NewMemoryStorage().QueueItems()
+		require.NoError(t, items.Schedule("test", "op-leased", time.Now()))
+		leased, err := items.Lease("test", "other-instance", time.Hour)
+		require.NoError(t, err)
+		require.Equal(t, "op-leased", leased.OperationID)
+
+		// when
+		_, err = items.Lease("test", "this-instance", time.Hour)
+
+		// then
+		assert.Error(t, err)
+
+		// when the other instance is done but the item was added again in the meantime
+		require.NoError(t, items.Schedule("test", "op-leased", time.Now()))
+		require.NoError(t, items.Complete(*leased))
+
+		// then
+		again, err := items.Lease("test", "this-instance", time.Hour)
+		require.NoError(t, err)
+		assert.Equal(t, "op-leased", again.OperationID)
+	})
+
+	t.Run("should check the empty queue with one poller and a growing interval", func(t *testing.T) {
+		// given
+		items := &countingQueueItems{QueueItems: storage.NewMemoryStorage().QueueItems()}
+		executor := &countingExecutor{executed: map[string]int{}}
+		queue := NewPersistentQueue(executor, items, logger, "test", PersistentQueueConfig{LeaseDuration: time.Minute, PollInterval: 10 * time.Millisecond, MaxPollInterval: 80 * time.Millisecond})
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		// when
+		queue.Run(ctx.Done(), 5)
+		time.Sleep(500 * time.Millisecond)
+
+		// then
+		assert.Less(t, items.leases.Load(), int32(15))
+
+		// when the item is due after the interval has grown
+		queue.AddAfter("op-1", 20*time.Millisecond)
+
+		// then
+		assert.NoError(t, wait.PollUntilContextTimeout(context.Background(), 5*time.Millisecond, time.Second, true, func(ctx context.Context) (bool, error) {
+			return executor.executions("op-1") == 1, nil
+		}))
+
+		queue.ShutDown()
+		cancel()
+		queue.waitGroup.Wait()
+	})
+}
@@ -134,6 +134,18 @@ func (s *CreateRuntimeResourceStep) Run(operation internal.Operation, log *slog.
 	}
 }
 
+// RenderRuntimeResource returns the Runtime resource which the step would create for the operation
+func (s *CreateRuntimeResourceStep) RenderRuntimeResource(operation internal.Operation, log *slog.Logger) (client.Object, error) {
+	cloudProvider := string(provider.ProviderToCloudProvider(operation.ProviderValues.ProviderType))
+	runtimeCR := &imv1.Runtime{}
+	runtimeCR.SetGroupVersionKind(imv1.GroupVersion.WithKind("Runtime"))
+	err := s.updateRuntimeResourceObject(log, *operation.ProviderValues, runtimeCR, operation, steps.KymaRuntimeResourceName(operation), cloudProvider)
+	if err != nil {
+		return nil, err
+	}
+	return runtimeCR, nil
+}
+
 func (s *CreateRuntimeResourceStep) updateRuntimeResourceObject(log *slog.Logger, values internal.ProviderValues, runtime *imv1.Runtime, operation internal.Operation, runtimeName, cloudProvider string) error {
 
 	runtime.ObjectMeta.Name = runtimeName
@@ -23,7 +23,7 @@ type ResolveCredentialsBindingStep struct {
 	gardenerClient     *gardener.Client
 	instanceStorage    storage.Instances
 	rulesService       *rules.RulesService
-	stepRetryTuple     internal.RetryTuple
+	retryPolicy        process.RetryPolicy
 	mu                 sync.Mutex
 	multiAccountConfig *multiaccount.MultiAccountConfig
 }
@@ -33,8 +33,11 @@ func NewResolveCredentialsBindingStep(brokerStorage storage.BrokerStorage, garde
 		instanceStorage:    brokerStorage.Instances(),
 		gardenerClient:     gardenerClient,
 		rulesService:       rulesService,
-		stepRetryTuple:     stepRetryTuple,
 		multiAccountConfig: multiAccountConfig,
+		retryPolicy: process.RetryPolicy{
+			InitialInterval: stepRetryTuple.Interval,
+			Timeout:         stepRetryTuple.Timeout,
+		},
 	}
 	step.operationManager = process.NewOperationManager(brokerStorage.Operations(), step.Name(), kebError.AccountPoolDependency)
 	return step
@@ -44,6 +47,10 @@ func (s *ResolveCredentialsBindingStep) Name() string {
 	return "Resolve_Credentials_Binding"
 }
 
+func (s *ResolveCredentialsBindingStep) RetryPolicy() process.RetryPolicy {
+	return s.retryPolicy
+}
+
 func (s *ResolveCredentialsBindingStep) Run(operation internal.Operation, log *slog.Logger) (internal.Operation, time.Duration, error) {
 	if operation.ProvisioningParameters.Parameters.TargetSecret != nil && *operation.ProvisioningParameters.Parameters.TargetSecret != "" {
 		log.Info("target secret is already set, skipping resolve step")
@@ -51,12 +58,11 @@ func (s *ResolveCredentialsBindingStep) Run(operation internal.Operation, log *s
 	}
 	targetSecretName, err := s.resolveSecretName(operation, log)
 	if err != nil {
-		msg := "resolving secret name"
 		// Case if there are no unassigned secrets, we want to use the error message defined in the step instead of the generic one from the error type
 		if lastErr, ok := err.(kebError.LastError); ok && lastErr.Component == kebError.AccountPoolDependency {
-			msg = lastErr.Message
+			return operation, 0, lastErr
 		}
-		return s.operationManager.RetryOperation(operation, msg, err, s.stepRetryTuple.Interval, s.stepRetryTuple.Timeout, log)
+		return operation, 0, fmt.Errorf("resolving secret name: %w", err)
 	}
 
 	if targetSecretName == "" {
@@ -67,7 +73,7 @@ func (s *ResolveCredentialsBindingStep) Run(operation internal.Operation, log *s
 	err = s.updateInstance(operation.InstanceID, targetSecretName)
 	if err != nil {
 		log.Error(fmt.Sprintf("failed to update instance with subscription secret name: %s", err.Error()))
-		return s.operationManager.RetryOperation(operation, "updating instance", err, s.stepRetryTuple.Interval, s.stepRetryTuple.Timeout, log)
+		return operation, 0, fmt.Errorf("updating instance: %w", err)
 	}
 
 	return s.operationManager.UpdateOperation(operation, func(op *internal.Operation) {
@@ -242,6 +242,8 @@ func TestResolveCredentialsBindingStep(t *testing.T) {
 		assert.Error(t, err)
 		assert.Zero(t, backoff)
 		assert.True(t, strings.Contains(err.Error(), "Currently, no unassigned provider accounts are available. Please contact us for further assistance."))
+		assert.True(t, step.RetryPolicy().Retryable(err))
+		assert.Equal(t, immediateTimeout.Timeout, step.RetryPolicy().Timeout)
 
 		updatedInstance, err := brokerStorage.Instances().GetByID(instanceID)
 		require.NoError(t, err)
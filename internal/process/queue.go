@@ -5,6 +5,7 @@ import (
 	"log/slog"
 	"runtime/debug"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
@@ -17,8 +18,29 @@ type Executor interface {
 	Execute(operationID string) (time.Duration, error)
 }
 
+// workQueue is the subset of the client-go workqueue used by the Queue, implemented also by the persistent queue
+type workQueue interface {
+	Add(item string)
+	AddAfter(item string, duration time.Duration)
+	Get() (item string, shutdown bool)
+	Done(item string)
+	Forget(item string)
+	ShutDown()
+	Len() int
+}
+
+// interruptible is implemented by executors which wait for the retries of a step, they stop waiting when the queue stops or drains
+type interruptible interface {
+	StopWaitingOn(stop <-chan struct{})
+}
+
+// restorer is implemented by queues which keep scheduled items between restarts
+type restorer interface {
+	Restore(item string)
+}
+
 type Queue struct {
-	queue     workqueue.TypedRateLimitingInterface[string]
+	queue     workQueue
 	executor  Executor
 	waitGroup sync.WaitGroup
 	log       *slog.Logger
@@ -26,6 +48,10 @@ type Queue struct {
 
 	speedFactor       int64
 	workersInUseGauge prometheus.Gauge
+
+	draining  atomic.Bool
+	drainOnce sync.Once
+	drained   chan struct{}
 }
 
 var queueWorkersInUseMetric = promauto.NewGaugeVec(prometheus.GaugeOpts{
@@ -37,14 +63,21 @@ var queueWorkersInUseMetric = promauto.NewGaugeVec(prometheus.GaugeOpts{
 
 func NewQueue(executor Executor, log *slog.Logger, name string) *Queue {
 	// add queue name field that could be logged later on
+	return newQueue(
+		workqueue.NewTypedRateLimitingQueueWithConfig(workqueue.DefaultTypedControllerRateLimiter[string](), workqueue.TypedRateLimitingQueueConfig[string]{Name: "operations"}),
+		executor, log.With("queueName", name), name)
+}
+
+func newQueue(queue workQueue, executor Executor, log *slog.Logger, name string) *Queue {
 	return &Queue{
-		queue:             workqueue.NewTypedRateLimitingQueueWithConfig(workqueue.DefaultTypedControllerRateLimiter[string](), workqueue.TypedRateLimitingQueueConfig[string]{Name: "operations"}),
+		queue:             queue,
 		executor:          executor,
 		waitGroup:         sync.WaitGroup{},
-		log:               log.With("queueName", name),
+		log:               log,
 		speedFactor:       1,
 		name:              name,
 		workersInUseGauge: queueWorkersInUseMetric.WithLabelValues(name),
+		drained:           make(chan struct{}),
 	}
 }
 
@@ -58,12 +91,55 @@ func (q *Queue) AddAfter(processId string, duration time.Duration) {
 	q.log.Info(fmt.Sprintf("item %s will be added to the queue %s after duration of %d, queue length is %d", processId, q.name, duration, q.queue.Len()))
 }
 
+// Restore adds the item to the queue unless the queue already keeps it scheduled.
+// It is used to resume processing of the operations in progress on the application start.
+func (q *Queue) Restore(processId string) {
+	r, ok := q.queue.(restorer)
+	if !ok {
+		q.Add(processId)
+		return
+	}
+	r.Restore(processId)
+	q.log.Info(fmt.Sprintf("restored item %s in the queue %s", processId, q.name))
+}
+
 func (q *Queue) ShutDown() {
 	q.log.Info(fmt.Sprintf("shutting down the queue, queue length is %d", q.queue.Len()))
 	q.queue.ShutDown()
 }
 
+// Drain stops taking items from the queue and waits until the workers finish processing the items they have taken.
+// It returns false if the workers did not finish before the timeout.
+// The items which were not taken stay in the persistent queue, the in-memory queue loses them,
+// they are restored from the operations in progress on the next start.
+func (q *Queue) Drain(timeout time.Duration) bool {
+	q.drainOnce.Do(func() {
+		q.log.Info(fmt.Sprintf("draining the queue, queue length is %d", q.queue.Len()))
+		q.draining.Store(true)
+		close(q.drained)
+		q.queue.ShutDown()
+	})
+
+	finished := make(chan struct{})
+	go func() {
+		q.waitGroup.Wait()
+		close(finished)
+	}()
+	select {
+	case <-finished:
+		q.log.Info("queue drained")
+		return true
+	case <-time.After(timeout):
+		q.log.Warn(fmt.Sprintf("workers did not finish processing within %s", timeout))
+		return false
+	}
+}
+
 func (q *Queue) Run(stop <-chan struct{}, workersAmount int) {
+	stop = q.stopOrDrain(stop)
+	if executor, ok := q.executor.(interruptible); ok {
+		executor.StopWaitingOn(stop)
+	}
 	for i := 0; i < workersAmount; i++ {
 		q.waitGroup.Add(1)
 
@@ -74,6 +150,19 @@ func (q *Queue) Run(stop <-chan struct{}, workersAmount int) {
 
 }
 
+// stopOrDrain returns a channel closed when the queue is stopped or drained
+func (q *Queue) stopOrDrain(stop <-chan struct{}) <-chan struct{} {
+	merged := make(chan struct{})
+	go func() {
+		select {
+		case <-stop:
+		case <-q.drained:
+		}
+		close(merged)
+	}()
+	return merged
+}
+
 // SpeedUp changes speedFactor parameter to reduce time between processing operations.
 // This method should only be used for testing purposes
 func (q *Queue) SpeedUp(speedFactor int64) {
@@ -81,14 +170,14 @@ func (q *Queue) SpeedUp(speedFactor int64) {
 	q.log.Info(fmt.Sprintf("queue speed factor set to %d", speedFactor))
 }
 
-func (q *Queue) createWorker(queue workqueue.TypedRateLimitingInterface[string], process func(id string) (time.Duration, error), stopCh <-chan struct{}, waitGroup *sync.WaitGroup, log *slog.Logger, nameId string) {
+func (q *Queue) createWorker(queue workQueue, process func(id string) (time.Duration, error), stopCh <-chan struct{}, waitGroup *sync.WaitGroup, log *slog.Logger, nameId string) {
 	go func() {
 		wait.Until(q.worker(queue, process, log, nameId), time.Second, stopCh)
 		waitGroup.Done()
 	}()
 }
 
-func (q *Queue) worker(queue workqueue.TypedRateLimitingInterface[string], process func(key string) (time.Duration, error), log *slog.Logger, workerNameId string) func() {
+func (q *Queue) worker(queue workQueue, process func(key string) (time.Duration, error), log *slog.Logger, workerNameId string) func() {
 	return func() {
 		exit := false
 		for !exit {
@@ -98,6 +187,13 @@ func (q *Queue) worker(queue workqueue.TypedRateLimitingInterface[string], proce
 					log.Info("shutting down")
 					return true
 				}
+				if q.draining.Load() {
+					// the item is not processed, the persistent queue keeps it scheduled, the in-memory queue ignores the requeue after the shutdown
+					log.Info(fmt.Sprintf("queue is draining, item %s is not processed", key))
+					queue.AddAfter(key, 0)
+					queue.Done(key)
+					return true
+				}
 
 				q.workersInUseGauge.Inc()
 				id := key
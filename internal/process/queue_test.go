@@ -9,6 +9,11 @@ import (
 	"testing"
 	"time"
 
+	"github.com/kyma-project/kyma-environment-broker/internal"
+	"github.com/kyma-project/kyma-environment-broker/internal/event"
+	"github.com/kyma-project/kyma-environment-broker/internal/storage"
+
+	"github.com/pivotal-cf/brokerapi/v12/domain"
 	"github.com/stretchr/testify/require"
 )
 
@@ -64,3 +69,110 @@ type captureWriter struct {
 func (c *captureWriter) Write(p []byte) (n int, err error) {
 	return c.buf.Write(p)
 }
+
+type blockingExecutor struct {
+	started  chan string
+	unblock  chan struct{}
+	mu       sync.Mutex
+	executed []string
+}
+
+func (e *blockingExecutor) Execute(operationID string) (time.Duration, error) {
+	e.started <- operationID
+	<-e.unblock
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.executed = append(e.executed, operationID)
+	return 0, nil
+}
+
+func TestQueue_Drain(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(&captureWriter{buf: &bytes.Buffer{}}, nil))
+
+	t.Run("should wait for the items in progress and not take new ones", func(t *testing.T) {
+		// given
+		executor := &blockingExecutor{started: make(chan string, 3), unblock: make(chan struct{})}
+		queue := NewQueue(executor, logger, "test")
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		queue.Add("op-1")
+		queue.Add("op-2")
+		queue.Add("op-3")
+		queue.Run(ctx.Done(), 2)
+		<-executor.started
+		<-executor.started
+
+		// when
+		go func() {
+			time.Sleep(50 * time.Millisecond)
+			close(executor.unblock)
+		}()
+		drained := queue.Drain(5 * time.Second)
+
+		// then
+		require.True(t, drained)
+		require.ElementsMatch(t, []string{"op-1", "op-2"}, executor.executed)
+	})
+
+	t.Run("should return false after the timeout", func(t *testing.T) {
+		// given
+		executor := &blockingExecutor{started: make(chan string, 1), unblock: make(chan struct{})}
+		defer close(executor.unblock)
+		queue := NewQueue(executor, logger, "test")
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		queue.Add("op-1")
+		queue.Run(ctx.Done(), 1)
+		<-executor.started
+
+		// when
+		drained := queue.Drain(10 * time.Millisecond)
+
+		// then
+		require.False(t, drained)
+	})
+
+	t.Run("should stop the step waiting for a retry", func(t *testing.T) {
+		// given
+		memoryStorage := storage.NewMemoryStorage()
+		operation := internal.Operation{ID: "op-1", InstanceID: "inst-1", State: domain.InProgress, CreatedAt: time.Now()}
+		require.NoError(t, memoryStorage.Operations().InsertOperation(operation))
+		manager := NewStagedManager(memoryStorage.Operations(), event.NewPubSub(logger), time.Hour, StagedManagerConfiguration{MaxStepProcessingTime: time.Hour}, logger)
+		manager.DefineStages([]string{"stage-1"})
+		step := &backingOffStep{started: make(chan struct{}, 1)}
+		require.NoError(t, manager.AddStep("stage-1", step, nil))
+
+		queue := NewQueue(manager, logger, "test")
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		queue.Add(operation.ID)
+		queue.Run(ctx.Done(), 1)
+		<-step.started
+
+		// when
+		drained := queue.Drain(5 * time.Second)
+
+		// then
+		require.True(t, drained)
+		stored, err := memoryStorage.Operations().GetOperationByID(operation.ID)
+		require.NoError(t, err)
+		require.Equal(t, domain.InProgress, stored.State)
+	})
+}
+
+// backingOffStep always needs a retry, so the staged manager waits for it in place
+type backingOffStep struct {
+	started chan struct{}
+}
+
+func (s *backingOffStep) Name() string {
+	return "backing-off"
+}
+
+func (s *backingOffStep) Run(operation internal.Operation, _ *slog.Logger) (internal.Operation, time.Duration, error) {
+	select {
+	case s.started <- struct{}{}:
+	default:
+	}
+	return operation, time.Minute, nil
+}
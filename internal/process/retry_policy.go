@@ -0,0 +1,157 @@
+package process
+
+import (
+	"fmt"
+	"log/slog"
+	"maps"
+	"math"
+	"math/rand"
+	"slices"
+	"time"
+
+	"github.com/kyma-project/kyma-environment-broker/internal"
+	kebError "github.com/kyma-project/kyma-environment-broker/internal/error"
+
+	"github.com/pivotal-cf/brokerapi/v12/domain"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// RetryPolicy describes how the StagedManager retries a step which returned an error
+type RetryPolicy struct {
+	// InitialInterval is the time before the first retry
+	InitialInterval time.Duration
+	// MaxInterval limits the time between retries, 0 means no limit
+	MaxInterval time.Duration
+	// Multiplier increases the interval after every retry, values lower than 1 mean a constant interval
+	Multiplier float64
+	// MaxAttempts is the number of step executions after which the operation fails, 0 means no limit
+	MaxAttempts int
+	// Timeout is the time since the first failure after which the operation fails, 0 means no limit
+	Timeout time.Duration
+	// Jitter is the maximum fraction of the interval added randomly to spread the retries
+	Jitter float64
+	// RetryableReasons and RetryableComponents select the errors which are retried, all errors are retried if both are empty
+	RetryableReasons    []kebError.Reason
+	RetryableComponents []kebError.Component
+}
+
+// StepWithRetryPolicy is implemented by steps which leave the retries to the StagedManager.
+// Such a step returns an error without failing the operation, the StagedManager retries the step or fails the operation
+// according to the policy. The number of retries is stored in the operation, so it is kept between restarts.
+type StepWithRetryPolicy interface {
+	RetryPolicy() RetryPolicy
+}
+
+var stepRetriesMetric = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "kcp",
+	Subsystem: "keb_v2",
+	Name:      "step_retries_total",
+	Help:      "Number of retries of the steps with a retry policy",
+}, []string{"step", "error_reason", "error_component"})
+
+// Retryable returns true if the error is retried according to the policy
+func (p RetryPolicy) Retryable(err error) bool {
+	if len(p.RetryableReasons) == 0 && len(p.RetryableComponents) == 0 {
+		return true
+	}
+	lastErr := kebError.ReasonForError(err, kebError.NotSet)
+	return slices.Contains(p.RetryableReasons, lastErr.GetReason()) || slices.Contains(p.RetryableComponents, lastErr.GetComponent())
+}
+
+// Interval returns the time to wait before the given retry, retries are counted from 1
+func (p RetryPolicy) Interval(retry int) time.Duration {
+	multiplier := math.Max(p.Multiplier, 1)
+	interval := float64(p.InitialInterval) * math.Pow(multiplier, float64(retry-1))
+	if p.MaxInterval > 0 {
+		interval = math.Min(interval, float64(p.MaxInterval))
+	}
+	if p.Jitter > 0 {
+		interval += interval * p.Jitter * rand.Float64()
+	}
+	return time.Duration(interval)
+}
+
+func retryPolicyOf(step Step) (RetryPolicy, bool) {
+	if s, ok := step.(StepWithCondition); ok {
+		step = s.Step
+	}
+	s, ok := step.(StepWithRetryPolicy)
+	if !ok {
+		return RetryPolicy{}, false
+	}
+	return s.RetryPolicy(), true
+}
+
+// applyRetryPolicy converts the error returned by the step into a retry or fails the operation when the policy does not allow the retry
+func (m *StagedManager) applyRetryPolicy(policy RetryPolicy, stepName string, operation internal.Operation, backoff time.Duration, err error, log *slog.Logger) (internal.Operation, time.Duration, error) {
+	if err == nil {
+		if _, found := operation.StepRetries[stepName]; !found {
+			return operation, backoff, nil
+		}
+		operation.StepRetries = maps.Clone(operation.StepRetries)
+		delete(operation.StepRetries, stepName)
+		if backoff == 0 {
+			// stored with the next update of the operation
+			return operation, backoff, nil
+		}
+		// the step is polled again, so the earlier failures must not count towards the timeout of the next error
+		updated, dbErr := m.operationStorage.UpdateOperation(operation)
+		if dbErr != nil {
+			log.Error(fmt.Sprintf("Unable to reset the number of retries: %s", dbErr))
+			return operation, backoff, nil
+		}
+		return *updated, backoff, nil
+	}
+	if operation.State == domain.Failed {
+		return operation, backoff, err
+	}
+
+	retry := operation.StepRetries[stepName]
+	if retry.Attempts == 0 {
+		retry.FirstFailureAt = time.Now()
+	}
+	retry.Attempts++
+	lastErr := kebError.ReasonForError(err, stepName)
+
+	switch {
+	case !policy.Retryable(err):
+		return m.failStep(operation, lastErr, fmt.Sprintf("step %s returned a not retryable error", stepName), log)
+	case policy.MaxAttempts > 0 && retry.Attempts >= policy.MaxAttempts:
+		return m.failStep(operation, lastErr, fmt.Sprintf("step %s failed %d times", stepName, retry.Attempts), log)
+	case policy.Timeout > 0 && time.Since(retry.FirstFailureAt) > policy.Timeout:
+		return m.failStep(operation, lastErr, fmt.Sprintf("step %s failed for %s", stepName, policy.Timeout), log)
+	}
+
+	interval := policy.Interval(retry.Attempts)
+	stepRetriesMetric.WithLabelValues(stepName, string(lastErr.GetReason()), string(lastErr.GetComponent())).Inc()
+	log.Warn(fmt.Sprintf("Step returned an error, retry %d in %s: %s", retry.Attempts, interval, err))
+
+	// the map is copied, because it can be shared with the operation kept by the caller
+	operation.StepRetries = maps.Clone(operation.StepRetries)
+	if operation.StepRetries == nil {
+		operation.StepRetries = make(map[string]internal.StepRetry)
+	}
+	operation.StepRetries[stepName] = retry
+	operation.LastError = lastErr
+	updated, dbErr := m.operationStorage.UpdateOperation(operation)
+	if dbErr != nil {
+		log.Error(fmt.Sprintf("Unable to save the number of retries: %s", dbErr))
+		return operation, interval, nil
+	}
+	return *updated, interval, nil
+}
+
+func (m *StagedManager) failStep(operation internal.Operation, lastErr kebError.LastError, description string, log *slog.Logger) (internal.Operation, time.Duration, error) {
+	log.Error(fmt.Sprintf("Failing operation: %s: %s", description, lastErr.Error()))
+	operation.State = domain.Failed
+	// the error message is a part of the description, because the description is returned by the last_operation endpoint
+	operation.Description = fmt.Sprintf("%s: %s", description, lastErr.Error())
+	operation.LastError = lastErr
+	updated, err := m.operationStorage.UpdateOperation(operation)
+	if err != nil {
+		log.Error(fmt.Sprintf("Unable to save the failed operation: %s", err))
+		return operation, time.Second, nil
+	}
+	return *updated, 0, fmt.Errorf("%s: %w", description, lastErr)
+}
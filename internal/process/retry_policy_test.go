@@ -0,0 +1,193 @@
+package process_test
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/kyma-project/kyma-environment-broker/internal"
+	kebError "github.com/kyma-project/kyma-environment-broker/internal/error"
+	"github.com/kyma-project/kyma-environment-broker/internal/event"
+	"github.com/kyma-project/kyma-environment-broker/internal/process"
+	"github.com/kyma-project/kyma-environment-broker/internal/storage"
+
+	"github.com/pivotal-cf/brokerapi/v12/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetryPolicy_Interval(t *testing.T) {
+	// given
+	policy := process.RetryPolicy{InitialInterval: time.Second, MaxInterval: 5 * time.Second, Multiplier: 2}
+
+	// then
+	assert.Equal(t, time.Second, policy.Interval(1))
+	assert.Equal(t, 2*time.Second, policy.Interval(2))
+	assert.Equal(t, 4*time.Second, policy.Interval(3))
+	assert.Equal(t, 5*time.Second, policy.Interval(4))
+
+	// when
+	policy.Jitter = 0.5
+
+	// then
+	for i := 0; i < 10; i++ {
+		interval := policy.Interval(1)
+		assert.GreaterOrEqual(t, interval, time.Second)
+		assert.LessOrEqual(t, interval, 1500*time.Millisecond)
+	}
+}
+
+func TestRetryPolicy_Retryable(t *testing.T) {
+	// given
+	policy := process.RetryPolicy{
+		RetryableReasons:    []kebError.Reason{kebError.KEBTimeOutCode},
+		RetryableComponents: []kebError.Component{kebError.InfrastructureManagerDependency},
+	}
+
+	// then
+	assert.True(t, process.RetryPolicy{}.Retryable(fmt.Errorf("any error")))
+	assert.True(t, policy.Retryable(kebError.TimeoutError("timeout", "step")))
+	assert.True(t, policy.Retryable(fmt.Errorf("wrapped: %w", kebError.LastError{Component: kebError.InfrastructureManagerDependency})))
+	assert.False(t, policy.Retryable(fmt.Errorf("internal error")))
+}
+
+func TestStagedManager_RetryPolicy(t *testing.T) {
+	t.Run("should retry the step and reset the retries when the step succeeds", func(t *testing.T) {
+		// given
+		operation := FixOperation("op-0001234")
+		mgr, operationStorage, eventCollector := setupRetryPolicyStagedManager(t, operation)
+		step := &policyStep{name: "policy", failures: 2, eventPublisher: eventCollector, policy: process.RetryPolicy{InitialInterval: time.Minute, Multiplier: 2, MaxAttempts: 3}}
+		require.NoError(t, mgr.AddStep("stage-1", step, nil))
+
+		// when
+		firstRetry, err := mgr.Execute(operation.ID)
+		require.NoError(t, err)
+		secondRetry, err := mgr.Execute(operation.ID)
+		require.NoError(t, err)
+
+		// then
+		assert.Equal(t, time.Minute, firstRetry)
+		assert.Equal(t, 2*time.Minute, secondRetry)
+		op, err := operationStorage.GetOperationByID(operation.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 2, op.StepRetries["policy"].Attempts)
+		assert.Equal(t, domain.InProgress, op.State)
+
+		// when
+		retry, err := mgr.Execute(operation.ID)
+
+		// then
+		require.NoError(t, err)
+		assert.Zero(t, retry)
+		op, err = operationStorage.GetOperationByID(operation.ID)
+		require.NoError(t, err)
+		assert.Empty(t, op.StepRetries)
+		assert.Equal(t, domain.Succeeded, op.State)
+	})
+
+	t.Run("should reset the retries when the step is polled again", func(t *testing.T) {
+		// given
+		operation := FixOperation("op-0001234")
+		mgr, operationStorage, eventCollector := setupRetryPolicyStagedManager(t, operation)
+		step := &policyStep{name: "policy", failures: 1, pollBackoff: time.Second, eventPublisher: eventCollector, policy: process.RetryPolicy{InitialInterval: time.Minute, MaxAttempts: 2}}
+		require.NoError(t, mgr.AddStep("stage-1", step, nil))
+
+		// when
+		_, err := mgr.Execute(operation.ID)
+		require.NoError(t, err)
+		retry, err := mgr.Execute(operation.ID)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, time.Second, retry)
+		op, err := operationStorage.GetOperationByID(operation.ID)
+		require.NoError(t, err)
+		assert.Empty(t, op.StepRetries)
+		assert.Equal(t, domain.InProgress, op.State)
+	})
+
+	t.Run("should fail the operation when the attempts are exhausted", func(t *testing.T) {
+		// given
+		operation := FixOperation("op-0001234")
+		mgr, operationStorage, eventCollector := setupRetryPolicyStagedManager(t, operation)
+		step := &policyStep{name: "policy", failures: 5, eventPublisher: eventCollector, policy: process.RetryPolicy{InitialInterval: time.Minute, MaxAttempts: 2}}
+		require.NoError(t, mgr.AddStep("stage-1", step, nil))
+
+		// when
+		_, err := mgr.Execute(operation.ID)
+		require.NoError(t, err)
+		retry, err := mgr.Execute(operation.ID)
+
+		// then
+		require.NoError(t, err)
+		assert.Zero(t, retry)
+		op, err := operationStorage.GetOperationByID(operation.ID)
+		require.NoError(t, err)
+		assert.Equal(t, domain.Failed, op.State)
+		assert.Equal(t, kebError.InfrastructureManagerDependency, op.LastError.GetComponent())
+		assert.Equal(t, "policy", op.LastError.GetStep())
+	})
+
+	t.Run("should fail the operation when the error is not retryable", func(t *testing.T) {
+		// given
+		operation := FixOperation("op-0001234")
+		mgr, operationStorage, eventCollector := setupRetryPolicyStagedManager(t, operation)
+		step := &policyStep{name: "policy", failures: 1, eventPublisher: eventCollector, policy: process.RetryPolicy{
+			InitialInterval:  time.Minute,
+			RetryableReasons: []kebError.Reason{kebError.KEBTimeOutCode},
+		}}
+		require.NoError(t, mgr.AddStep("stage-1", step, nil))
+
+		// when
+		retry, err := mgr.Execute(operation.ID)
+
+		// then
+		require.NoError(t, err)
+		assert.Zero(t, retry)
+		op, err := operationStorage.GetOperationByID(operation.ID)
+		require.NoError(t, err)
+		assert.Equal(t, domain.Failed, op.State)
+	})
+}
+
+// setupRetryPolicyStagedManager creates the manager which does not retry steps in place, so every retry is returned to the queue
+func setupRetryPolicyStagedManager(t *testing.T, op internal.Operation) (*process.StagedManager, storage.Operations, *CollectingEventHandler) {
+	memoryStorage := storage.NewMemoryStorage()
+	require.NoError(t, memoryStorage.Operations().InsertOperation(op))
+
+	eventCollector := &CollectingEventHandler{}
+	l := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	mgr := process.NewStagedManager(memoryStorage.Operations(), eventCollector, time.Hour, process.StagedManagerConfiguration{}, l)
+	mgr.DefineStages([]string{"stage-1"})
+
+	return mgr, memoryStorage.Operations(), eventCollector
+}
+
+type policyStep struct {
+	name           string
+	failures       int
+	pollBackoff    time.Duration
+	policy         process.RetryPolicy
+	eventPublisher event.Publisher
+}
+
+func (s *policyStep) Name() string {
+	return s.name
+}
+
+func (s *policyStep) RetryPolicy() process.RetryPolicy {
+	return s.policy
+}
+
+func (s *policyStep) Run(operation internal.Operation, logger *slog.Logger) (internal.Operation, time.Duration, error) {
+	s.eventPublisher.Publish(context.Background(), s.name)
+	if s.failures > 0 {
+		s.failures--
+		logger.Info("Failing")
+		return operation, 0, kebError.LastError{Message: "not ready", Component: kebError.InfrastructureManagerDependency}
+	}
+	return operation, s.pollBackoff, nil
+}
@@ -4,10 +4,13 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"slices"
+	"sync"
 	"time"
 
 	"github.com/kyma-project/kyma-environment-broker/internal/storage/dberr"
 
+	"github.com/kyma-project/kyma-environment-broker/common/events"
 	"github.com/kyma-project/kyma-environment-broker/internal"
 	kebError "github.com/kyma-project/kyma-environment-broker/internal/error"
 	"github.com/kyma-project/kyma-environment-broker/internal/event"
@@ -16,16 +19,29 @@ import (
 	"github.com/pivotal-cf/brokerapi/v12/domain"
 )
 
+// cancellationStage is the name under which the cancellation steps are reported
+const cancellationStage = "cancellation"
+
 type StagedManager struct {
 	log              *slog.Logger
 	operationStorage storage.Operations
 	publisher        event.Publisher
 
-	stages           []*stage
-	operationTimeout time.Duration
+	stages            []*stage
+	cancellationSteps []StepWithCondition
+	operationTimeout  time.Duration
 
 	speedFactor int64
 	cfg         StagedManagerConfiguration
+
+	leaseOwner    string
+	leaseDuration time.Duration
+	// leases holds the operations which can be leased by the manager, the value is true while the operation is processed
+	leases   map[string]bool
+	leasesMu sync.Mutex
+
+	// stop is closed when the queue stops or drains, the step waiting for a retry returns to the queue then
+	stop <-chan struct{}
 }
 
 type StagedManagerConfiguration struct {
@@ -79,6 +95,12 @@ func (m *StagedManager) SpeedUp(speedFactor int64) {
 	m.speedFactor = speedFactor
 }
 
+// StopWaitingOn makes the steps waiting for a retry return the backoff when the channel is closed,
+// so the retry is scheduled by the queue instead of blocking the worker during the shutdown
+func (m *StagedManager) StopWaitingOn(stop <-chan struct{}) {
+	m.stop = stop
+}
+
 func (m *StagedManager) DefineStages(names []string) {
 	m.stages = make([]*stage, len(names))
 	for i, n := range names {
@@ -96,6 +118,55 @@ func (m *StagedManager) AddStep(stageName string, step Step, cnd StepCondition)
 	return fmt.Errorf("stage %s not defined", stageName)
 }
 
+// AddStepBefore registers a step which is executed just before the step with the given name, in the same stage.
+// The step is executed only if the condition of the given step is also met.
+func (m *StagedManager) AddStepBefore(stepName string, step Step, cnd StepCondition) error {
+	return m.insertStep(stepName, 0, step, cnd)
+}
+
+// AddStepAfter registers a step which is executed just after the step with the given name, in the same stage.
+// The step is executed only if the condition of the given step is also met.
+func (m *StagedManager) AddStepAfter(stepName string, step Step, cnd StepCondition) error {
+	return m.insertStep(stepName, 1, step, cnd)
+}
+
+func (m *StagedManager) insertStep(stepName string, offset int, step Step, cnd StepCondition) error {
+	for _, s := range m.stages {
+		for i, existing := range s.steps {
+			if existing.Name() != stepName {
+				continue
+			}
+			s.steps = slices.Insert(s.steps, i+offset, StepWithCondition{
+				Step:      step,
+				condition: allConditions(existing.condition, cnd),
+			})
+			return nil
+		}
+	}
+	return fmt.Errorf("step %s not defined", stepName)
+}
+
+func allConditions(conditions ...StepCondition) StepCondition {
+	return func(operation internal.Operation) bool {
+		for _, cnd := range conditions {
+			if cnd != nil && !cnd(operation) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// AddCancellationStep registers a step which compensates the work done by the already processed steps.
+// Cancellation steps are executed in the order of registration when the operation is in the canceling state.
+// The whole list is executed again after a retry, that is why the steps must be idempotent.
+func (m *StagedManager) AddCancellationStep(step Step, cnd StepCondition) {
+	m.cancellationSteps = append(m.cancellationSteps, StepWithCondition{
+		Step:      step,
+		condition: cnd,
+	})
+}
+
 func (m *StagedManager) GetAllStages() []string {
 	var all []string
 	for _, s := range m.stages {
@@ -105,7 +176,20 @@ func (m *StagedManager) GetAllStages() []string {
 }
 
 func (m *StagedManager) Execute(operationID string) (time.Duration, error) {
+	if !m.leaseEnabled() {
+		return m.execute(operationID)
+	}
+	m.trackLease(operationID, true)
+	when, err := m.execute(operationID)
+	if when > 0 {
+		m.trackLease(operationID, false)
+	} else {
+		m.forgetLease(operationID)
+	}
+	return when, err
+}
 
+func (m *StagedManager) execute(operationID string) (time.Duration, error) {
 	operation, err := m.operationStorage.GetOperationByID(operationID)
 	if err != nil {
 		m.log.Error(fmt.Sprintf("Cannot fetch operation from storage: %s", err))
@@ -113,13 +197,32 @@ func (m *StagedManager) Execute(operationID string) (time.Duration, error) {
 	}
 
 	logOperation := m.log.With("operationID", operationID, "instanceID", operation.InstanceID, "planID", operation.ProvisioningParameters.PlanID)
+	if m.leaseEnabled() {
+		// the operation could be finished by another replica
+		if operation.IsFinished() {
+			logOperation.Info(fmt.Sprintf("Operation already in state %s, skipping", operation.State))
+			return 0, nil
+		}
+		claimed, retry, ok := m.claim(*operation, logOperation)
+		if !ok {
+			return retry, nil
+		}
+		operation = &claimed
+	}
+	switch operation.State {
+	case internal.OperationStateCanceled:
+		logOperation.Info("Operation already canceled, skipping")
+		return 0, nil
+	case internal.OperationStateCanceling:
+		return m.cancel(*operation, logOperation)
+	}
 	logOperation.Info(fmt.Sprintf("Start process operation steps for GlobalAccount=%s, ", operation.ProvisioningParameters.ErsContext.GlobalAccountID))
-	if time.Since(operation.CreatedAt) > m.operationTimeout {
+	if time.Since(operation.ProcessingStartedAt()) > m.operationTimeout {
 		timeoutErr := kebError.TimeoutError("operation has reached the time limit", string(kebError.KEBDependency))
 		operation.LastError = timeoutErr
 		defer m.publishEventOnFail(operation, err)
-		logOperation.Info(fmt.Sprintf("operation has reached the time limit: operation was created at: %s, timeout: %s elapsed %s",
-			operation.CreatedAt.Format(time.RFC3339Nano), m.operationTimeout.String(), time.Since(operation.CreatedAt).String()))
+		logOperation.Info(fmt.Sprintf("operation has reached the time limit: operation was created at: %s, processing started at: %s, timeout: %s elapsed %s",
+			operation.CreatedAt.Format(time.RFC3339Nano), operation.ProcessingStartedAt().Format(time.RFC3339Nano), m.operationTimeout.String(), time.Since(operation.ProcessingStartedAt()).String()))
 		operation.State = domain.Failed
 		_, err = m.operationStorage.UpdateOperation(*operation)
 		if err != nil {
@@ -147,12 +250,19 @@ func (m *StagedManager) Execute(operationID string) (time.Duration, error) {
 				logStep.Debug("Skipping")
 				continue
 			}
-			operation.EventInfof("processing step: %v", step.Name())
+			if m.leaseEnabled() {
+				var retry time.Duration
+				var claimed bool
+				if processedOperation, retry, claimed = m.claim(processedOperation, logStep); !claimed {
+					return retry, nil
+				}
+			}
+			operation.StepEventf(events.InfoEventLevel, step.Name(), nil, "processing step: %v", step.Name())
 
-			processedOperation, when, err = m.runStep(step, processedOperation, logStep)
+			processedOperation, when, err = m.runStep(step, stage.name, processedOperation, logStep)
 			if err != nil {
 				logStep.Error(fmt.Sprintf("Process operation failed: %s", err))
-				operation.EventErrorf(err, "step %v processing returned error", step.Name())
+				operation.StepEventf(events.ErrorEventLevel, step.Name(), lastErrorAttributes(processedOperation), "step %v processing returned error: %v", step.Name(), err)
 				return 0, err
 			}
 			if processedOperation.State == domain.Failed || processedOperation.State == domain.Succeeded {
@@ -162,6 +272,10 @@ func (m *StagedManager) Execute(operationID string) (time.Duration, error) {
 				m.publishDeprovisioningSucceeded(&processedOperation)
 				return 0, nil
 			}
+			// the operation saved by the step is reloaded on a conflict, so the cancellation requested in the meantime is visible here
+			if processedOperation.State == internal.OperationStateCanceling {
+				return m.cancel(processedOperation, logOperation)
+			}
 
 			// the step needs a retry
 			if when > 0 {
@@ -172,6 +286,11 @@ func (m *StagedManager) Execute(operationID string) (time.Duration, error) {
 		}
 
 		processedOperation, err = m.saveFinishedStage(processedOperation, stage, logOperation)
+		if err != nil && dberr.IsConflict(err) {
+			if canceling, ok := m.cancellationRequested(processedOperation.ID, logOperation); ok {
+				return m.cancel(canceling, logOperation)
+			}
+		}
 
 		// it is ok, when operation does not exist in the DB - it can happen at the end of a deprovisioning process
 		if err != nil && !dberr.IsNotFound(err) {
@@ -196,6 +315,67 @@ func (m *StagedManager) Execute(operationID string) (time.Duration, error) {
 	return 0, nil
 }
 
+// cancellationRequested reloads the operation to check if the cancellation was requested while the stage was processed.
+// It is called only when saving the finished stage failed with a conflict, the steps which did not save the operation do not see the cancellation.
+func (m *StagedManager) cancellationRequested(operationID string, log *slog.Logger) (internal.Operation, bool) {
+	operation, err := m.operationStorage.GetOperationByID(operationID)
+	if err != nil {
+		log.Warn(fmt.Sprintf("Unable to check if the operation was canceled: %s", err))
+		return internal.Operation{}, false
+	}
+	if operation.State != internal.OperationStateCanceling {
+		return internal.Operation{}, false
+	}
+	return *operation, true
+}
+
+func (m *StagedManager) cancel(operation internal.Operation, logOperation *slog.Logger) (time.Duration, error) {
+	logOperation.Info("Operation cancellation requested, running cancellation steps")
+	operation.EventInfof("operation canceling")
+
+	var when time.Duration
+	var err error
+	processedOperation := operation
+	for _, step := range m.cancellationSteps {
+		logStep := logOperation.With("step", step.Name()).
+			With("stage", cancellationStage)
+		if step.condition != nil && !step.condition(processedOperation) {
+			logStep.Debug("Skipping")
+			continue
+		}
+		operation.StepEventf(events.InfoEventLevel, step.Name(), nil, "processing cancellation step: %v", step.Name())
+
+		processedOperation, when, err = m.runStep(step, cancellationStage, processedOperation, logStep)
+		if err != nil {
+			logStep.Error(fmt.Sprintf("Cancellation of the operation failed: %s", err))
+			operation.StepEventf(events.ErrorEventLevel, step.Name(), lastErrorAttributes(processedOperation), "cancellation step %v processing returned error: %v", step.Name(), err)
+			return 0, err
+		}
+		if processedOperation.State == domain.Failed || processedOperation.State == domain.Succeeded {
+			logStep.Info(fmt.Sprintf("Operation %q got status %s during cancellation. Process finished.", operation.ID, processedOperation.State))
+			m.publishOperationFinishedEvent(processedOperation)
+			return 0, nil
+		}
+		if when > 0 {
+			logStep.Warn(fmt.Sprintf("retrying cancellation step %s by restarting the operation in %d s", step.Name(), int64(when.Seconds())))
+			return when, nil
+		}
+	}
+
+	processedOperation.State = internal.OperationStateCanceled
+	processedOperation.Description = "Operation canceled"
+	_, err = m.operationStorage.UpdateOperation(processedOperation)
+	if err != nil && !dberr.IsNotFound(err) {
+		logOperation.Info(fmt.Sprintf("Unable to save canceled operation: %s", err))
+		return time.Second, nil
+	}
+
+	logOperation.Info("Operation canceled")
+	operation.EventInfof("operation processing %v", processedOperation.State)
+	m.publishOperationFinishedEvent(processedOperation)
+	return 0, nil
+}
+
 func (m *StagedManager) saveFinishedStage(operation internal.Operation, s *stage, log *slog.Logger) (internal.Operation, error) {
 	operation.FinishStage(s.name)
 	op, err := m.operationStorage.UpdateOperation(operation)
@@ -208,7 +388,7 @@ func (m *StagedManager) saveFinishedStage(operation internal.Operation, s *stage
 	return *op, nil
 }
 
-func (m *StagedManager) runStep(step Step, operation internal.Operation, logger *slog.Logger) (processedOperation internal.Operation, backoff time.Duration, err error) {
+func (m *StagedManager) runStep(step Step, stageName string, operation internal.Operation, logger *slog.Logger) (processedOperation internal.Operation, backoff time.Duration, err error) {
 	var start time.Time
 	defer func() {
 		if pErr := recover(); pErr != nil {
@@ -226,6 +406,10 @@ func (m *StagedManager) runStep(step Step, operation internal.Operation, logger
 		logger.Info("Start step")
 		stepLogger := logger.With("step", step.Name(), "operationID", processedOperation.ID)
 		processedOperation, backoff, err = step.Run(processedOperation, stepLogger)
+		if policy, ok := retryPolicyOf(step); ok {
+			processedOperation, backoff, err = m.applyRetryPolicy(policy, step.Name(), processedOperation, backoff, err, stepLogger)
+		}
+		stepErr := err
 		if err != nil {
 			logOperation := stepLogger.With("error_component", processedOperation.LastError.GetComponent(), "error_reason", processedOperation.LastError.GetReason())
 			logOperation.Warn(fmt.Sprintf("Last error from step: %s", processedOperation.LastError.Error()))
@@ -238,10 +422,12 @@ func (m *StagedManager) runStep(step Step, operation internal.Operation, logger
 
 		m.publisher.Publish(context.TODO(), OperationStepProcessed{
 			StepProcessed: StepProcessed{
-				StepName: step.Name(),
-				Duration: time.Since(start),
-				When:     backoff,
-				Error:    err,
+				StepName:  step.Name(),
+				Stage:     stageName,
+				StartedAt: start,
+				Duration:  time.Since(start),
+				When:      backoff,
+				Error:     stepErr,
 			},
 			Operation:    processedOperation,
 			OldOperation: operation,
@@ -258,8 +444,13 @@ func (m *StagedManager) runStep(step Step, operation internal.Operation, logger
 			}
 			return processedOperation, backoff, err
 		}
-		operation.EventInfof("step %v sleeping for %v", step.Name(), backoff)
-		time.Sleep(backoff / time.Duration(m.speedFactor))
+		operation.StepEventf(events.DebugEventLevel, step.Name(), nil, "step %v sleeping for %v", step.Name(), backoff)
+		select {
+		case <-time.After(backoff / time.Duration(m.speedFactor)):
+		case <-m.stop:
+			logger.Info(fmt.Sprintf("Queue is stopping, the step will be retried in %s by the queue", backoff))
+			return processedOperation, backoff, nil
+		}
 	}
 }
 
@@ -305,3 +496,15 @@ func (m *StagedManager) publishDeprovisioningSucceeded(operation *internal.Opera
 		)
 	}
 }
+
+// lastErrorAttributes describes the last error of the operation in the attributes of the event
+func lastErrorAttributes(operation internal.Operation) events.Attributes {
+	attributes := events.Attributes{}
+	if component := operation.LastError.GetComponent(); component != "" {
+		attributes["component"] = string(component)
+	}
+	if reason := operation.LastError.GetReason(); reason != "" {
+		attributes["reason"] = string(reason)
+	}
+	return attributes
+}
@@ -20,6 +20,7 @@ import (
 
 	pkg "github.com/kyma-project/kyma-environment-broker/common/runtime"
 	"github.com/kyma-project/kyma-environment-broker/internal"
+	kebErr "github.com/kyma-project/kyma-environment-broker/internal/error"
 	"github.com/kyma-project/kyma-environment-broker/internal/event"
 	"github.com/kyma-project/kyma-environment-broker/internal/storage"
 	"github.com/stretchr/testify/assert"
@@ -82,6 +83,31 @@ func TestHappyPathWithStepCondition(t *testing.T) {
 	assert.True(t, op.IsStageFinished("stage-2"))
 }
 
+func TestStepsAddedBeforeAndAfter(t *testing.T) {
+	// given
+	operation := FixOperation("op-0001234")
+	mgr, _, eventCollector := SetupStagedManager(t, operation)
+	skipped := func(_ internal.Operation) bool {
+		return false
+	}
+	assert.NoError(t, mgr.AddStep("stage-1", &testingStep{name: "first", eventPublisher: eventCollector}, nil))
+	assert.NoError(t, mgr.AddStep("stage-1", &testingStep{name: "second", eventPublisher: eventCollector}, skipped))
+	assert.NoError(t, mgr.AddStep("stage-2", &testingStep{name: "first-2", eventPublisher: eventCollector}, nil))
+
+	// when
+	assert.NoError(t, mgr.AddStepBefore("first", &testingStep{name: "before-first", eventPublisher: eventCollector}, nil))
+	assert.NoError(t, mgr.AddStepAfter("first", &testingStep{name: "after-first", eventPublisher: eventCollector}, nil))
+	assert.NoError(t, mgr.AddStepAfter("second", &testingStep{name: "after-second", eventPublisher: eventCollector}, nil))
+	assert.NoError(t, mgr.AddStepBefore("first-2", &testingStep{name: "before-first-2", eventPublisher: eventCollector}, skipped))
+	assert.EqualError(t, mgr.AddStepBefore("missing", &testingStep{name: "before-missing", eventPublisher: eventCollector}, nil), "step missing not defined")
+
+	_, err := mgr.Execute(operation.ID)
+	assert.NoError(t, err)
+
+	// then
+	eventCollector.AssertProcessedSteps(t, []string{"before-first", "first", "after-first", "first-2"})
+}
+
 func TestWithRetry(t *testing.T) {
 	// given
 	operation := FixOperation("op-0001234")
@@ -160,6 +186,54 @@ func TestSkipFinishedStage(t *testing.T) {
 	assert.True(t, op.IsStageFinished("stage-2"))
 }
 
+func TestCancellation(t *testing.T) {
+	// given
+	operation := FixOperation("op-0001234")
+	mgr, operationStorage, eventCollector := SetupStagedManager(t, operation)
+	err := mgr.AddStep("stage-1", &testingStep{name: "first", eventPublisher: eventCollector}, nil)
+	assert.NoError(t, err)
+	err = mgr.AddStep("stage-1", &cancelingStep{name: "second", operations: operationStorage, eventPublisher: eventCollector}, nil)
+	assert.NoError(t, err)
+	err = mgr.AddStep("stage-2", &testingStep{name: "first-2", eventPublisher: eventCollector}, nil)
+	assert.NoError(t, err)
+	mgr.AddCancellationStep(&testingStep{name: "compensate", eventPublisher: eventCollector}, nil)
+	mgr.AddCancellationStep(&testingStep{name: "skipped", eventPublisher: eventCollector}, func(_ internal.Operation) bool {
+		return false
+	})
+
+	// when
+	retry, err := mgr.Execute(operation.ID)
+
+	// then
+	assert.NoError(t, err)
+	assert.Zero(t, retry)
+	eventCollector.AssertProcessedSteps(t, []string{"first", "second", "compensate"})
+	op, _ := operationStorage.GetOperationByID(operation.ID)
+	assert.Equal(t, domain.LastOperationState(internal.OperationStateCanceled), op.State)
+	assert.False(t, op.IsStageFinished("stage-1"))
+	assert.False(t, op.IsStageFinished("stage-2"))
+}
+
+func TestCancellationOfCanceledOperation(t *testing.T) {
+	// given
+	operation := FixOperation("op-0001234")
+	operation.State = internal.OperationStateCanceled
+	mgr, operationStorage, eventCollector := SetupStagedManager(t, operation)
+	err := mgr.AddStep("stage-1", &testingStep{name: "first", eventPublisher: eventCollector}, nil)
+	assert.NoError(t, err)
+	mgr.AddCancellationStep(&testingStep{name: "compensate", eventPublisher: eventCollector}, nil)
+
+	// when
+	retry, err := mgr.Execute(operation.ID)
+
+	// then
+	assert.NoError(t, err)
+	assert.Zero(t, retry)
+	eventCollector.AssertProcessedSteps(t, []string{})
+	op, _ := operationStorage.GetOperationByID(operation.ID)
+	assert.Equal(t, domain.LastOperationState(internal.OperationStateCanceled), op.State)
+}
+
 func SetupStagedManager(t *testing.T, op internal.Operation) (*process.StagedManager, storage.Operations, *CollectingEventHandler) {
 	memoryStorage := storage.NewMemoryStorage()
 	err := memoryStorage.Operations().InsertOperation(op)
@@ -209,6 +283,68 @@ func (s *onceRetryingStep) Run(operation internal.Operation, logger *slog.Logger
 	return operation, 0, nil
 }
 
+func TestCancellationDetectedFromSavedOperation(t *testing.T) {
+	// given
+	operation := FixOperation("op-0001234")
+	mgr, operationStorage, eventCollector := SetupStagedManager(t, operation)
+	err := mgr.AddStep("stage-1", &cancelingStep{name: "first", operations: operationStorage, eventPublisher: eventCollector}, nil)
+	assert.NoError(t, err)
+	err = mgr.AddStep("stage-1", &savingStep{name: "second", operations: operationStorage, eventPublisher: eventCollector}, nil)
+	assert.NoError(t, err)
+	err = mgr.AddStep("stage-1", &testingStep{name: "third", eventPublisher: eventCollector}, nil)
+	assert.NoError(t, err)
+	mgr.AddCancellationStep(&testingStep{name: "compensate", eventPublisher: eventCollector}, nil)
+
+	// when
+	retry, err := mgr.Execute(operation.ID)
+
+	// then
+	assert.NoError(t, err)
+	assert.Zero(t, retry)
+	eventCollector.AssertProcessedSteps(t, []string{"first", "second", "compensate"})
+	op, _ := operationStorage.GetOperationByID(operation.ID)
+	assert.Equal(t, domain.LastOperationState(internal.OperationStateCanceled), op.State)
+}
+
+type cancelingStep struct {
+	name           string
+	operations     storage.Operations
+	eventPublisher event.Publisher
+}
+
+func (s *cancelingStep) Name() string {
+	return s.name
+}
+
+// Run simulates the cancellation requested by the user while the step is processed
+func (s *cancelingStep) Run(operation internal.Operation, logger *slog.Logger) (internal.Operation, time.Duration, error) {
+	s.eventPublisher.Publish(context.Background(), s.name)
+	canceling := operation
+	canceling.State = internal.OperationStateCanceling
+	if _, err := s.operations.UpdateOperation(canceling); err != nil {
+		return operation, 0, err
+	}
+	logger.Info("Cancellation requested")
+	return operation, 0, nil
+}
+
+type savingStep struct {
+	name           string
+	operations     storage.Operations
+	eventPublisher event.Publisher
+}
+
+func (s *savingStep) Name() string {
+	return s.name
+}
+
+func (s *savingStep) Run(operation internal.Operation, logger *slog.Logger) (internal.Operation, time.Duration, error) {
+	s.eventPublisher.Publish(context.Background(), s.name)
+	return process.NewOperationManager(s.operations, s.name, kebErr.KEBDependency).UpdateOperation(operation, func(operation *internal.Operation) {
+		operation.Description = "saved"
+	}, logger)
+}
+
 type panicStep struct {
 	name           string
 	eventPublisher event.Publisher
@@ -278,6 +414,8 @@ func (h *CollectingEventHandler) Publish(ctx context.Context, ev interface{}) {
 
 func (h *CollectingEventHandler) WaitForEvents(t *testing.T, count int) {
 	assert.NoError(t, wait.PollUntilContextTimeout(context.Background(), time.Millisecond, time.Second, true, func(ctx context.Context) (bool, error) {
+		h.mu.Lock()
+		defer h.mu.Unlock()
 		return len(h.StepsProcessed) == count, nil
 	}))
 }
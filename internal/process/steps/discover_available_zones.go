@@ -37,10 +37,24 @@ func NewDiscoverAvailableZonesStep(db storage.BrokerStorage, providerSpec *confi
 	return step
 }
 
+// zonesDiscoveryRetryPolicy retries the failed calls to Gardener and AWS for about a minute
+var zonesDiscoveryRetryPolicy = process.RetryPolicy{
+	InitialInterval: 5 * time.Second,
+	MaxInterval:     20 * time.Second,
+	Multiplier:      2,
+	MaxAttempts:     6,
+	Timeout:         time.Minute,
+	Jitter:          0.2,
+}
+
 func (s *DiscoverAvailableZonesStep) Name() string {
 	return "Discover_Available_Zones"
 }
 
+func (s *DiscoverAvailableZonesStep) RetryPolicy() process.RetryPolicy {
+	return zonesDiscoveryRetryPolicy
+}
+
 func (s *DiscoverAvailableZonesStep) Run(operation internal.Operation, log *slog.Logger) (internal.Operation, time.Duration, error) {
 	if !s.providerSpec.ZonesDiscovery(runtime.CloudProviderFromString(operation.ProviderValues.ProviderType)) {
 		log.Info(fmt.Sprintf("Zones discovery disabled for provider %s, skipping", runtime.CloudProviderFromString(operation.ProviderValues.ProviderType)))
@@ -56,7 +70,7 @@ func (s *DiscoverAvailableZonesStep) Run(operation internal.Operation, log *slog
 		if dberr.IsNotFound(err) {
 			return s.operationManager.OperationFailed(operation, fmt.Sprintf("instance %s does not exists", operation.InstanceID), err, log)
 		}
-		return s.operationManager.RetryOperation(operation, fmt.Sprintf("unable to get instance %s", operation.InstanceID), err, 10*time.Second, time.Minute, log)
+		return operation, 0, fmt.Errorf("unable to get instance %s: %w", operation.InstanceID, err)
 	}
 
 	subscriptionSecretName := instance.SubscriptionSecretName
@@ -69,12 +83,12 @@ func (s *DiscoverAvailableZonesStep) Run(operation internal.Operation, log *slog
 
 	secretBinding, err := s.gardenerClient.GetSecretBinding(subscriptionSecretName)
 	if err != nil {
-		return s.operationManager.RetryOperation(operation, fmt.Sprintf("unable to get secret binding %s", subscriptionSecretName), err, 10*time.Second, time.Minute, log)
+		return operation, 0, fmt.Errorf("unable to get secret binding %s: %w", subscriptionSecretName, err)
 	}
 
 	secret, err := s.gardenerClient.GetSecret(secretBinding.GetSecretRefNamespace(), secretBinding.GetSecretRefName())
 	if err != nil {
-		return s.operationManager.RetryOperation(operation, fmt.Sprintf("unable to get secret %s/%s", secretBinding.GetSecretRefNamespace(), secretBinding.GetSecretRefName()), err, 10*time.Second, time.Minute, log)
+		return operation, 0, fmt.Errorf("unable to get secret %s/%s: %w", secretBinding.GetSecretRefNamespace(), secretBinding.GetSecretRefName(), err)
 	}
 	accessKeyID, secretAccessKey, err := aws.ExtractCredentials(secret)
 	if err != nil {
@@ -83,7 +97,7 @@ func (s *DiscoverAvailableZonesStep) Run(operation internal.Operation, log *slog
 
 	client, err := s.awsClientFactory.New(context.Background(), accessKeyID, secretAccessKey, operation.ProviderValues.Region)
 	if err != nil {
-		return s.operationManager.RetryOperation(operation, "unable to create AWS client", err, 10*time.Second, time.Minute, log)
+		return operation, 0, fmt.Errorf("unable to create AWS client: %w", err)
 	}
 
 	discoveredZones := make(map[string][]string)
@@ -102,7 +116,7 @@ func (s *DiscoverAvailableZonesStep) Run(operation internal.Operation, log *slog
 	for machineType := range discoveredZones {
 		zones, err := client.AvailableZones(context.Background(), machineType)
 		if err != nil {
-			return s.operationManager.RetryOperation(operation, fmt.Sprintf("unable to get available zones for machine type %s", machineType), err, 10*time.Second, time.Minute, log)
+			return operation, 0, fmt.Errorf("unable to get available zones for machine type %s: %w", machineType, err)
 		}
 		rand.Shuffle(len(zones), func(i, j int) { zones[i], zones[j] = zones[j], zones[i] })
 		log.Info(fmt.Sprintf("Available zones for machine type %s: %v", machineType, zones))
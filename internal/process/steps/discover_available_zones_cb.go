@@ -41,6 +41,10 @@ func (s *DiscoverAvailableZonesCBStep) Name() string {
 	return "Discover_Available_Zones_CredentialsBinding"
 }
 
+func (s *DiscoverAvailableZonesCBStep) RetryPolicy() process.RetryPolicy {
+	return zonesDiscoveryRetryPolicy
+}
+
 func (s *DiscoverAvailableZonesCBStep) Run(operation internal.Operation, log *slog.Logger) (internal.Operation, time.Duration, error) {
 	if !s.providerSpec.ZonesDiscovery(runtime.CloudProviderFromString(operation.ProviderValues.ProviderType)) {
 		log.Info(fmt.Sprintf("Zones discovery disabled for provider %s, skipping", runtime.CloudProviderFromString(operation.ProviderValues.ProviderType)))
@@ -56,7 +60,7 @@ func (s *DiscoverAvailableZonesCBStep) Run(operation internal.Operation, log *sl
 		if dberr.IsNotFound(err) {
 			return s.operationManager.OperationFailed(operation, fmt.Sprintf("instance %s does not exists", operation.InstanceID), err, log)
 		}
-		return s.operationManager.RetryOperation(operation, fmt.Sprintf("unable to get instance %s", operation.InstanceID), err, 10*time.Second, time.Minute, log)
+		return operation, 0, fmt.Errorf("unable to get instance %s: %w", operation.InstanceID, err)
 	}
 
 	subscriptionSecretName := instance.SubscriptionSecretName
@@ -69,12 +73,12 @@ func (s *DiscoverAvailableZonesCBStep) Run(operation internal.Operation, log *sl
 
 	credentialsBinding, err := s.gardenerClient.GetCredentialsBinding(subscriptionSecretName)
 	if err != nil {
-		return s.operationManager.RetryOperation(operation, fmt.Sprintf("unable to get credentials binding %s", subscriptionSecretName), err, 10*time.Second, time.Minute, log)
+		return operation, 0, fmt.Errorf("unable to get credentials binding %s: %w", subscriptionSecretName, err)
 	}
 
 	secret, err := s.gardenerClient.GetSecret(credentialsBinding.GetSecretRefNamespace(), credentialsBinding.GetSecretRefName())
 	if err != nil {
-		return s.operationManager.RetryOperation(operation, fmt.Sprintf("unable to get secret %s/%s", credentialsBinding.GetSecretRefNamespace(), credentialsBinding.GetSecretRefName()), err, 10*time.Second, time.Minute, log)
+		return operation, 0, fmt.Errorf("unable to get secret %s/%s: %w", credentialsBinding.GetSecretRefNamespace(), credentialsBinding.GetSecretRefName(), err)
 	}
 	accessKeyID, secretAccessKey, err := aws.ExtractCredentials(secret)
 	if err != nil {
@@ -83,7 +87,7 @@ func (s *DiscoverAvailableZonesCBStep) Run(operation internal.Operation, log *sl
 
 	client, err := s.awsClientFactory.New(context.Background(), accessKeyID, secretAccessKey, operation.ProviderValues.Region)
 	if err != nil {
-		return s.operationManager.RetryOperation(operation, "unable to create AWS client", err, 10*time.Second, time.Minute, log)
+		return operation, 0, fmt.Errorf("unable to create AWS client: %w", err)
 	}
 
 	discoveredZones := make(map[string][]string)
@@ -102,7 +106,7 @@ func (s *DiscoverAvailableZonesCBStep) Run(operation internal.Operation, log *sl
 	for machineType := range discoveredZones {
 		zones, err := client.AvailableZones(context.Background(), machineType)
 		if err != nil {
-			return s.operationManager.RetryOperation(operation, fmt.Sprintf("unable to get available zones for machine type %s", machineType), err, 10*time.Second, time.Minute, log)
+			return operation, 0, fmt.Errorf("unable to get available zones for machine type %s: %w", machineType, err)
 		}
 		rand.Shuffle(len(zones), func(i, j int) { zones[i], zones[j] = zones[j], zones[i] })
 		log.Info(fmt.Sprintf("Available zones for machine type %s: %v", machineType, zones))
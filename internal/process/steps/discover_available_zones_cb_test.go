@@ -5,7 +5,6 @@ import (
 	"log/slog"
 	"os"
 	"testing"
-	"time"
 
 	pkg "github.com/kyma-project/kyma-environment-broker/common/runtime"
 	"github.com/kyma-project/kyma-environment-broker/internal"
@@ -279,8 +278,9 @@ func TestDiscoverAvailableZonesCBStep_RepeatWhenAWSError(t *testing.T) {
 	operation, repeat, err := step.Run(operation, fixLogger())
 
 	// then
-	assert.NoError(t, err)
-	assert.Equal(t, 10*time.Second, repeat)
+	assert.ErrorContains(t, err, "AWS error")
+	assert.Zero(t, repeat)
+	assert.True(t, step.RetryPolicy().Retryable(err))
 }
 
 func TestDiscoverAvailableZonesCBStep_ProvisioningHappyPath(t *testing.T) {
@@ -3,7 +3,6 @@ package steps
 import (
 	"fmt"
 	"testing"
-	"time"
 
 	pkg "github.com/kyma-project/kyma-environment-broker/common/runtime"
 	"github.com/kyma-project/kyma-environment-broker/internal"
@@ -268,8 +267,9 @@ func TestDiscoverAvailableZonesStep_RepeatWhenAWSError(t *testing.T) {
 	operation, repeat, err := step.Run(operation, fixLogger())
 
 	// then
-	assert.NoError(t, err)
-	assert.Equal(t, 10*time.Second, repeat)
+	assert.ErrorContains(t, err, "AWS error")
+	assert.Zero(t, repeat)
+	assert.True(t, step.RetryPolicy().Retryable(err))
 }
 
 func TestDiscoverAvailableZonesStep_ProvisioningHappyPath(t *testing.T) {
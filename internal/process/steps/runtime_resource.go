@@ -21,6 +21,10 @@ func NewCheckRuntimeResourceStep(os storage.Operations, k8sClient client.Client,
 	step := &checkRuntimeResource{
 		k8sClient:                 k8sClient,
 		runtimeResourceStateRetry: runtimeResourceStateRetry,
+		retryPolicy: process.RetryPolicy{
+			InitialInterval: kcpRetryInterval,
+			Timeout:         kcpRetryTimeout,
+		},
 	}
 	step.operationManager = process.NewOperationManager(os, step.Name(), kebError.InfrastructureManagerDependency)
 	return step
@@ -40,6 +44,7 @@ type checkRuntimeResource struct {
 	k8sClient                 client.Client
 	operationManager          *process.OperationManager
 	runtimeResourceStateRetry internal.RetryTuple
+	retryPolicy               process.RetryPolicy
 }
 
 type checkRuntimeResourceProvisioning struct {
@@ -58,11 +63,16 @@ func (*checkRuntimeResource) Name() string {
 	return "Check_RuntimeResource_Update"
 }
 
+// RetryPolicy retries the errors of getting the Runtime resource, a Runtime resource which is not ready yet is polled without the policy
+func (s *checkRuntimeResource) RetryPolicy() process.RetryPolicy {
+	return s.retryPolicy
+}
+
 func (s *checkRuntimeResource) Run(operation internal.Operation, log *slog.Logger) (internal.Operation, time.Duration, error) {
 	runtime, err := s.GetRuntimeResource(operation.RuntimeID, operation.KymaResourceNamespace)
 	if err != nil {
 		log.Error(fmt.Sprintf("unable to get Runtime resource %s/%s", operation.KymaResourceNamespace, operation.RuntimeID))
-		return s.operationManager.RetryOperation(operation, "unable to get Runtime resource", err, kcpRetryInterval, kcpRetryTimeout, log)
+		return operation, 0, fmt.Errorf("unable to get Runtime resource: %w", err)
 	}
 
 	// check status
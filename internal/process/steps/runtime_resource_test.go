@@ -84,6 +84,27 @@ func TestCheckRuntimeResourceStep(t *testing.T) {
 		assert.NotZero(t, backoff)
 	})
 
+	t.Run("return retryable error when Runtime CR does not exist", func(t *testing.T) {
+		// given
+		operation := createFakeProvisioningOp("3a")
+		err = os.InsertOperation(operation)
+		assert.NoError(t, err)
+
+		k8sClient := fake.NewClientBuilder().Build()
+
+		step := NewCheckRuntimeResourceStep(os, k8sClient, internal.RetryTuple{Timeout: 2 * time.Second, Interval: time.Second})
+
+		// when
+		op, backoff, err := step.Run(operation, fixLogger())
+
+		// then
+		assert.ErrorContains(t, err, "unable to get Runtime resource")
+		assert.Zero(t, backoff)
+		assert.NotEqual(t, domain.Failed, op.State)
+		assert.True(t, step.RetryPolicy().Retryable(err))
+		assert.Equal(t, kcpRetryTimeout, step.RetryPolicy().Timeout)
+	})
+
 	t.Run("fail operation when failed Runtime CR", func(t *testing.T) {
 		// given
 		operation := createFakeProvisioningOp("4")
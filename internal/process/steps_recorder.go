@@ -0,0 +1,68 @@
+package process
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/kyma-project/kyma-environment-broker/common/runtime"
+	kebError "github.com/kyma-project/kyma-environment-broker/internal/error"
+	"github.com/kyma-project/kyma-environment-broker/internal/storage"
+
+	"github.com/pivotal-cf/brokerapi/v12/domain"
+)
+
+// StepsRecorder stores every step execution reported by the StagedManager, the stored executions build the operation timeline
+type StepsRecorder struct {
+	steps storage.OperationSteps
+	log   *slog.Logger
+}
+
+func NewStepsRecorder(steps storage.OperationSteps, log *slog.Logger) *StepsRecorder {
+	return &StepsRecorder{
+		steps: steps,
+		log:   log.With("service", "StepsRecorder"),
+	}
+}
+
+func (r *StepsRecorder) OnOperationStepProcessed(_ context.Context, ev interface{}) error {
+	stepProcessed, ok := ev.(OperationStepProcessed)
+	if !ok {
+		return fmt.Errorf("expected process.OperationStepProcessed in OnOperationStepProcessed but got %+v", ev)
+	}
+	// the event published when the operation reached the time limit does not describe a step
+	if stepProcessed.StepName == "" {
+		return nil
+	}
+
+	step := runtime.OperationStep{
+		OperationID: stepProcessed.Operation.ID,
+		Stage:       stepProcessed.Stage,
+		Step:        stepProcessed.StepName,
+		StartedAt:   stepProcessed.StartedAt,
+		FinishedAt:  stepProcessed.StartedAt.Add(stepProcessed.Duration),
+		Outcome:     runtime.StepSucceeded,
+	}
+	switch {
+	case stepProcessed.Error != nil || stepProcessed.Operation.State == domain.Failed:
+		step.Outcome = runtime.StepFailed
+	case stepProcessed.When > 0:
+		step.Outcome = runtime.StepRetried
+		step.RetryAfterSeconds = stepProcessed.When.Seconds()
+	}
+	if step.Outcome != runtime.StepSucceeded {
+		lastErr := stepProcessed.Operation.LastError
+		if lastErr.GetStep() != stepProcessed.StepName {
+			lastErr = kebError.ReasonForError(stepProcessed.Error, stepProcessed.StepName)
+		}
+		step.ErrorReason = string(lastErr.GetReason())
+		step.ErrorComponent = string(lastErr.GetComponent())
+		step.ErrorMessage = lastErr.Error()
+	}
+
+	if err := r.steps.Insert(step); err != nil {
+		r.log.Warn(fmt.Sprintf("unable to store the execution of step %s for operation %s: %s", step.Step, step.OperationID, err))
+		return err
+	}
+	return nil
+}
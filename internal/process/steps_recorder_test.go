@@ -0,0 +1,67 @@
+package process_test
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	pkg "github.com/kyma-project/kyma-environment-broker/common/runtime"
+	kebError "github.com/kyma-project/kyma-environment-broker/internal/error"
+	"github.com/kyma-project/kyma-environment-broker/internal/process"
+	"github.com/kyma-project/kyma-environment-broker/internal/storage"
+
+	"github.com/pivotal-cf/brokerapi/v12/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStepsRecorder(t *testing.T) {
+	// given
+	db := storage.NewMemoryStorage()
+	recorder := process.NewStepsRecorder(db.OperationSteps(), slog.New(slog.NewTextHandler(os.Stdout, nil)))
+	started := time.Now().Add(-time.Minute)
+
+	operation := FixOperation("op-0001234")
+	failedOperation := operation
+	failedOperation.State = domain.Failed
+	failedOperation.LastError = kebError.LastError{Message: "runtime failed", Reason: "provisioning failed", Component: kebError.InfrastructureManagerDependency, Step: "check"}
+
+	// when
+	require.NoError(t, recorder.OnOperationStepProcessed(context.Background(), process.OperationStepProcessed{
+		StepProcessed: process.StepProcessed{StepName: "create", Stage: "stage-1", StartedAt: started, Duration: time.Second},
+		Operation:     operation,
+	}))
+	require.NoError(t, recorder.OnOperationStepProcessed(context.Background(), process.OperationStepProcessed{
+		StepProcessed: process.StepProcessed{StepName: "check", Stage: "stage-2", StartedAt: started.Add(time.Second), Duration: time.Second, When: 30 * time.Second},
+		Operation:     operation,
+	}))
+	require.NoError(t, recorder.OnOperationStepProcessed(context.Background(), process.OperationStepProcessed{
+		StepProcessed: process.StepProcessed{StepName: "check", Stage: "stage-2", StartedAt: started.Add(time.Minute), Duration: time.Second, Error: fmt.Errorf("runtime failed")},
+		Operation:     failedOperation,
+	}))
+	require.NoError(t, recorder.OnOperationStepProcessed(context.Background(), process.OperationStepProcessed{
+		StepProcessed: process.StepProcessed{Duration: time.Hour},
+		Operation:     failedOperation,
+	}))
+
+	// then
+	steps, err := db.OperationSteps().ListByOperationIDs([]string{operation.ID})
+	require.NoError(t, err)
+	require.Len(t, steps, 3)
+
+	assert.Equal(t, "create", steps[0].Step)
+	assert.Equal(t, pkg.StepSucceeded, steps[0].Outcome)
+	assert.Equal(t, started.Add(time.Second), steps[0].FinishedAt)
+	assert.Empty(t, steps[0].ErrorMessage)
+
+	assert.Equal(t, pkg.StepRetried, steps[1].Outcome)
+	assert.Equal(t, float64(30), steps[1].RetryAfterSeconds)
+
+	assert.Equal(t, pkg.StepFailed, steps[2].Outcome)
+	assert.Equal(t, "provisioning failed", steps[2].ErrorReason)
+	assert.Equal(t, string(kebError.InfrastructureManagerDependency), steps[2].ErrorComponent)
+	assert.Equal(t, "runtime failed", steps[2].ErrorMessage)
+}
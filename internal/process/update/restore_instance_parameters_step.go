@@ -0,0 +1,61 @@
+package update
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/kyma-project/kyma-environment-broker/internal"
+	"github.com/kyma-project/kyma-environment-broker/internal/broker"
+	"github.com/kyma-project/kyma-environment-broker/internal/storage"
+	"github.com/kyma-project/kyma-environment-broker/internal/storage/dberr"
+)
+
+// RestoreInstanceParametersStep is a cancellation step which brings back the instance parameters stored before the update.
+// The update endpoint saves the new parameters in the instance before the operation is processed, so canceling the update
+// must revert them to not show parameters which were never applied to the runtime.
+type RestoreInstanceParametersStep struct {
+	instanceStorage storage.Instances
+}
+
+func NewRestoreInstanceParametersStep(db storage.BrokerStorage) *RestoreInstanceParametersStep {
+	return &RestoreInstanceParametersStep{
+		instanceStorage: db.Instances(),
+	}
+}
+
+func (s *RestoreInstanceParametersStep) Name() string {
+	return "Update_Restore_Instance_Parameters"
+}
+
+func (s *RestoreInstanceParametersStep) Run(operation internal.Operation, log *slog.Logger) (internal.Operation, time.Duration, error) {
+	if operation.PreviousParameters.PlanID == "" {
+		log.Info("previous parameters not stored in the operation, skipping")
+		return operation, 0, nil
+	}
+
+	instance, err := s.instanceStorage.GetByID(operation.InstanceID)
+	if err != nil {
+		if dberr.IsNotFound(err) {
+			log.Info("the instance does not exist, skipping")
+			return operation, 0, nil
+		}
+		log.Error(fmt.Sprintf("unable to get the instance: %s", err))
+		return operation, 10 * time.Second, nil
+	}
+
+	instance.Parameters.Parameters = operation.PreviousParameters.Parameters
+	if operation.UpdatedPlanID != "" {
+		instance.Parameters.PlanID = operation.PreviousParameters.PlanID
+		instance.ServicePlanID = operation.PreviousParameters.PlanID
+		instance.ServicePlanName = broker.AvailablePlans.GetPlanNameOrEmpty(broker.PlanIDType(operation.PreviousParameters.PlanID))
+	}
+
+	if _, err := s.instanceStorage.Update(*instance); err != nil {
+		log.Error(fmt.Sprintf("unable to restore the instance parameters: %s", err))
+		return operation, 10 * time.Second, nil
+	}
+	log.Info("instance parameters restored")
+
+	return operation, 0, nil
+}
@@ -96,6 +96,17 @@ func (s *UpdateRuntimeStep) Run(operation internal.Operation, log *slog.Logger)
 
 	s.applyMaxPodsConfig(operation, &runtime)
 
+	// the marker is stored before the patch, so a cancellation never reverts the instance parameters of an updated runtime
+	if !operation.RuntimeResourceUpdated {
+		var backoff time.Duration
+		operation, backoff, err = s.operationManager.UpdateOperation(operation, func(op *internal.Operation) {
+			op.RuntimeResourceUpdated = true
+		}, log)
+		if backoff > 0 {
+			return operation, backoff, nil
+		}
+	}
+
 	err = s.k8sClient.Update(context.Background(), &runtime)
 	if err != nil {
 		return s.operationManager.RetryOperation(operation, fmt.Sprintf("unable to update Runtime Resource %s", operation.GetRuntimeResourceName()), err, 10*time.Second, 1*time.Minute, log)
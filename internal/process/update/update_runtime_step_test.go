@@ -78,6 +78,8 @@ func TestUpdateRuntimeStep_RunUpdateMachineType(t *testing.T) {
 	}
 	operation.ProviderValues = &internal.ProviderValues{}
 
+	operation = storeOperation(t, operation)
+
 	// when
 	_, backoff, err := step.Run(operation, fixLogger())
 
@@ -89,6 +91,10 @@ func TestUpdateRuntimeStep_RunUpdateMachineType(t *testing.T) {
 	err = kcpClient.Get(context.Background(), client.ObjectKey{Name: operation.RuntimeResourceName, Namespace: kcpSystemNamespace}, &gotRuntime)
 	require.NoError(t, err)
 	assert.Equal(t, "new-machine-type", gotRuntime.Spec.Shoot.Provider.Workers[0].Machine.Type)
+
+	storedOperation, err := memoryStorage.Operations().GetOperationByID(operation.ID)
+	require.NoError(t, err)
+	assert.True(t, storedOperation.RuntimeResourceUpdated)
 }
 
 func TestUpdateRuntimeStep_RunUpdateACL(t *testing.T) {
@@ -108,6 +114,8 @@ func TestUpdateRuntimeStep_RunUpdateACL(t *testing.T) {
 	}
 	operation.ProviderValues = &internal.ProviderValues{}
 
+	operation = storeOperation(t, operation)
+
 	// when
 	_, backoff, err := step.Run(operation, fixLogger())
 
@@ -138,6 +146,8 @@ func TestUpdateRuntimeStep_RunDeleteACL(t *testing.T) {
 	}
 	operation.ProviderValues = &internal.ProviderValues{}
 
+	operation = storeOperation(t, operation)
+
 	// when
 	_, backoff, err := step.Run(operation, fixLogger())
 
@@ -180,6 +190,8 @@ func TestUpdateRuntimeStep_RunUpdateEmptyOIDCConfigWithOIDCObject(t *testing.T)
 	err = kcpClient.Get(context.Background(), client.ObjectKey{Name: operation.RuntimeResourceName, Namespace: kcpSystemNamespace}, &gotRuntime)
 	require.NoError(t, err)
 	t.Logf("gotRuntime: %+v", gotRuntime)
+	operation = storeOperation(t, operation)
+
 	// when
 	_, backoff, err := step.Run(operation, fixLogger())
 
@@ -229,6 +241,8 @@ func TestUpdateRuntimeStep_RunUpdateRemoveJWKSConfig(t *testing.T) {
 	err = kcpClient.Get(context.Background(), client.ObjectKey{Name: operation.RuntimeResourceName, Namespace: kcpSystemNamespace}, &gotRuntime)
 	require.NoError(t, err)
 	t.Logf("gotRuntime: %+v", gotRuntime)
+	operation = storeOperation(t, operation)
+
 	// when
 	_, backoff, err := step.Run(operation, fixLogger())
 
@@ -277,6 +291,8 @@ func TestUpdateRuntimeStep_RunUpdateOIDCWithOIDCObject(t *testing.T) {
 	err = kcpClient.Get(context.Background(), client.ObjectKey{Name: operation.RuntimeResourceName, Namespace: kcpSystemNamespace}, &gotRuntime)
 	require.NoError(t, err)
 	t.Logf("gotRuntime: %+v", gotRuntime)
+	operation = storeOperation(t, operation)
+
 	// when
 	_, backoff, err := step.Run(operation, fixLogger())
 
@@ -361,6 +377,8 @@ func TestUpdateRuntimeStep_RunUpdateEmptyAdditionalOIDCWithMultipleAdditionalOID
 	err = kcpClient.Get(context.Background(), client.ObjectKey{Name: operation.RuntimeResourceName, Namespace: kcpSystemNamespace}, &gotRuntime)
 	require.NoError(t, err)
 	t.Logf("gotRuntime: %+v", gotRuntime)
+	operation = storeOperation(t, operation)
+
 	// when
 	_, backoff, err := step.Run(operation, fixLogger())
 
@@ -486,6 +504,8 @@ func TestUpdateRuntimeStep_RunUpdateMultipleAdditionalOIDCWithMultipleAdditional
 	err = kcpClient.Get(context.Background(), client.ObjectKey{Name: operation.RuntimeResourceName, Namespace: kcpSystemNamespace}, &gotRuntime)
 	require.NoError(t, err)
 	t.Logf("gotRuntime: %+v", gotRuntime)
+	operation = storeOperation(t, operation)
+
 	// when
 	_, backoff, err := step.Run(operation, fixLogger())
 
@@ -528,6 +548,8 @@ func TestUpdateRuntimeStep_RunUpdateMultipleAdditionalOIDCWitEmptyAdditionalOIDC
 	err = kcpClient.Get(context.Background(), client.ObjectKey{Name: operation.RuntimeResourceName, Namespace: kcpSystemNamespace}, &gotRuntime)
 	require.NoError(t, err)
 	t.Logf("gotRuntime: %+v", gotRuntime)
+	operation = storeOperation(t, operation)
+
 	// when
 	_, backoff, err := step.Run(operation, fixLogger())
 
@@ -599,6 +621,8 @@ func TestUpdateRuntimeStep_NetworkFilter(t *testing.T) {
 			kcpClient := fake.NewClientBuilder().WithRuntimeObjects(fixRuntimeResourceWithNetworkFilter(runtimeResourceName, testCase.initialIngressFiltering, testCase.initialEgressFiltering)).Build()
 			step := NewUpdateRuntimeStep(memoryStorage, kcpClient, 0, inputConfig, &workers.Provider{}, fixValuesProvider(), whitelist.Set{}, &configuration.ProviderSpec{}, nil)
 
+			operation = storeOperation(t, operation)
+
 			// when
 			_, backoff, err := step.Run(operation, fixLogger())
 
@@ -648,6 +672,8 @@ func TestUpdateRuntimeStep_RunUpdateSingleOIDCRequiredClaimsDash(t *testing.T) {
 	assert.NotNil(t, gotRuntime.Spec.Shoot.Kubernetes.KubeAPIServer.AdditionalOidcConfig)
 	assert.NotEmpty(t, (*gotRuntime.Spec.Shoot.Kubernetes.KubeAPIServer.AdditionalOidcConfig)[0].RequiredClaims)
 
+	operation = storeOperation(t, operation)
+
 	// when
 	_, backoff, err := step.Run(operation, fixLogger())
 
@@ -695,6 +721,8 @@ func TestUpdateRuntimeStep_ZonesDiscovery(t *testing.T) {
 		},
 	}
 
+	operation = storeOperation(t, operation)
+
 	// when
 	_, backoff, err := step.Run(operation, fixLogger())
 
@@ -762,6 +790,8 @@ func TestUpdateRuntimeStep_GvisorOnMainWorker(t *testing.T) {
 				Gvisor: tc.gvisor,
 			}
 
+			operation = storeOperation(t, operation)
+
 			// when
 			_, backoff, err := step.Run(operation, fixLogger())
 
@@ -815,6 +845,8 @@ func TestUpdateRuntimeStep_GvisorOnMainAndAdditionalWorkers(t *testing.T) {
 		},
 	}
 
+	operation = storeOperation(t, operation)
+
 	// when
 	_, backoff, err := step.Run(operation, fixLogger())
 
@@ -916,6 +948,8 @@ aws:
 		},
 	}
 
+	operation = storeOperation(t, operation)
+
 	// when
 	_, backoff, err := step.Run(operation, fixLogger())
 
@@ -1009,6 +1043,8 @@ aws:
 		},
 	}
 
+	operation = storeOperation(t, operation)
+
 	// when
 	_, backoff, err := step.Run(operation, fixLogger())
 
@@ -1040,6 +1076,8 @@ func TestUpdateRuntimeStep_SkipMachineTypeUpdateWhenMachineTypeParameterIsNil(t
 	}
 	operation.ProviderValues = &internal.ProviderValues{}
 
+	operation = storeOperation(t, operation)
+
 	// when
 	_, backoff, err := step.Run(operation, fixLogger())
 
@@ -1338,6 +1376,19 @@ func fixRuntimeResourceWithCRI(name string, cri *gardener.CRI) runtime.Object {
 	}
 }
 
+// storeOperation saves the operation in the shared memory storage, so the step can update it
+func storeOperation(t *testing.T, operation internal.Operation) internal.Operation {
+	stored, err := memoryStorage.Operations().GetOperationByID(operation.ID)
+	if err != nil {
+		require.NoError(t, memoryStorage.Operations().InsertOperation(operation))
+		return operation
+	}
+	operation.Version = stored.Version
+	updated, err := memoryStorage.Operations().UpdateOperation(operation)
+	require.NoError(t, err)
+	return *updated
+}
+
 func fixLogger() *slog.Logger {
 	return slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{
 		Level: slog.LevelInfo,
@@ -1,13 +1,43 @@
 package configuration
 
 import (
+	"fmt"
 	"io"
 	"os"
+	"sort"
 	"strings"
 
+	"github.com/kyma-project/kyma-environment-broker/common/runtime"
+
 	"gopkg.in/yaml.v3"
 )
 
+// The kinds of the provisioning parameters schema which can be set for a plan
+const (
+	RegularSchema   = "regular"
+	AzureLiteSchema = "azure_lite"
+	FreeSchema      = "free"
+	TrialSchema     = "trial"
+)
+
+var schemaKinds = map[string]bool{
+	RegularSchema:   true,
+	AzureLiteSchema: true,
+	FreeSchema:      true,
+	TrialSchema:     true,
+}
+
+// PlanDefinition is the identity and the semantics of a plan offered in the catalog
+type PlanDefinition struct {
+	ID       string
+	Name     string
+	Provider runtime.CloudProvider
+	Trial    bool
+	Free     bool
+	Bindable bool
+	Schema   string
+}
+
 type PlanSpecifications struct {
 	plans map[string]planSpecificationDTO
 }
@@ -35,6 +65,12 @@ func NewPlanSpecifications(r io.Reader) (*PlanSpecifications, error) {
 
 	for key, plan := range dto {
 		planNames := strings.Split(key, ",")
+		if plan.ID != "" && len(planNames) > 1 {
+			return nil, fmt.Errorf("plan ID %s must be defined for a single plan, not for %s", plan.ID, key)
+		}
+		if plan.Schema != "" && !schemaKinds[plan.Schema] {
+			return nil, fmt.Errorf("unknown schema %s of the plan %s", plan.Schema, key)
+		}
 		for _, planName := range planNames {
 			spec.plans[planName] = plan
 		}
@@ -46,6 +82,14 @@ func NewPlanSpecifications(r io.Reader) (*PlanSpecifications, error) {
 type PlanSpecificationsDTO map[string]planSpecificationDTO
 
 type planSpecificationDTO struct {
+	// the plan identity and semantics, set only for the plans defined by the configuration
+	ID       string `yaml:"id,omitempty"`
+	Provider string `yaml:"provider,omitempty"`
+	Trial    bool   `yaml:"trial,omitempty"`
+	Free     bool   `yaml:"free,omitempty"`
+	Bindable bool   `yaml:"bindable,omitempty"`
+	Schema   string `yaml:"schema,omitempty"`
+
 	// platform region -> list of hyperscaler regions
 	Regions map[string][]string `yaml:"regions"`
 
@@ -145,3 +189,29 @@ func (p *PlanSpecifications) DefaultMachineType(planName string) string {
 	}
 	return regularMachines[0]
 }
+
+// PlanDefinitions returns the plans which have the ID defined in the configuration, sorted by name
+func (p *PlanSpecifications) PlanDefinitions() []PlanDefinition {
+	definitions := make([]PlanDefinition, 0)
+	for planName, plan := range p.plans {
+		if plan.ID == "" {
+			continue
+		}
+		definition := PlanDefinition{
+			ID:       plan.ID,
+			Name:     planName,
+			Trial:    plan.Trial,
+			Free:     plan.Free,
+			Bindable: plan.Bindable,
+			Schema:   plan.Schema,
+		}
+		if plan.Provider != "" {
+			definition.Provider = runtime.CloudProviderFromString(plan.Provider)
+		}
+		definitions = append(definitions, definition)
+	}
+	sort.Slice(definitions, func(i, j int) bool {
+		return definitions[i].Name < definitions[j].Name
+	})
+	return definitions
+}
@@ -4,6 +4,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/kyma-project/kyma-environment-broker/common/runtime"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -52,3 +53,50 @@ sap-converged-cloud:
 	assert.False(t, spec.IsUpgradableBetween("plan1", "plan3-bis"))
 	assert.False(t, spec.IsUpgradableBetween("plan1-not-existing", "plan2"))
 }
+
+func TestPlanConfiguration_PlanDefinitions(t *testing.T) {
+	// given
+	spec, err := NewPlanSpecifications(strings.NewReader(`
+aws,build-runtime-aws:
+        regions:
+            default:
+                - eu-central-1
+build-runtime-sap-converged-cloud:
+        id: 5e6b1b2a-6a3c-4b1f-9d5e-0c4f1a2b3c4d
+        provider: openstack
+        bindable: true
+        regions:
+            default:
+                - eu-de-1
+`))
+	require.NoError(t, err)
+
+	// when
+	definitions := spec.PlanDefinitions()
+
+	// then
+	assert.Equal(t, []PlanDefinition{{
+		ID:       "5e6b1b2a-6a3c-4b1f-9d5e-0c4f1a2b3c4d",
+		Name:     "build-runtime-sap-converged-cloud",
+		Provider: runtime.SapConvergedCloud,
+		Bindable: true,
+	}}, definitions)
+}
+
+func TestPlanConfiguration_InvalidPlanDefinitions(t *testing.T) {
+	for name, config := range map[string]string{
+		"ID for many plans": `
+plan1,plan2:
+        id: 5e6b1b2a-6a3c-4b1f-9d5e-0c4f1a2b3c4d
+`,
+		"unknown schema": `
+plan1:
+        schema: custom
+`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := NewPlanSpecifications(strings.NewReader(config))
+			assert.Error(t, err)
+		})
+	}
+}
@@ -59,47 +59,45 @@ func NewFakePlanSpecFromFile() (*configuration.PlanSpecifications, error) {
 }
 
 func (s *PlanSpecificValuesProvider) ValuesForPlanAndParameters(provisioningParameters internal.ProvisioningParameters) (internal.ProviderValues, error) {
+	plan, found := broker.AvailablePlans.GetDefinition(broker.PlanIDType(provisioningParameters.PlanID))
+	if !found {
+		return internal.ProviderValues{}, fmt.Errorf("plan %s not supported", provisioningParameters.PlanID)
+	}
+
 	var p Provider
-	switch provisioningParameters.PlanID {
-	case broker.AWSPlanID, broker.BuildRuntimeAWSPlanID:
-		p = &AWSInputProvider{
-			Purpose:                s.defaultPurpose,
-			MultiZone:              s.multiZoneCluster,
-			ProvisioningParameters: provisioningParameters,
-			FailureTolerance:       s.commercialFailureTolerance,
-			ZonesProvider:          s.zonesProvider,
-		}
-	case broker.PreviewPlanID:
-		p = &AWSInputProvider{
-			Purpose:                s.defaultPurpose,
-			MultiZone:              s.multiZoneCluster,
-			ProvisioningParameters: provisioningParameters,
-			FailureTolerance:       s.commercialFailureTolerance,
-			ZonesProvider:          s.zonesProvider,
-		}
-	case broker.AzurePlanID, broker.BuildRuntimeAzurePlanID:
-		p = &AzureInputProvider{
-			Purpose:                s.defaultPurpose,
-			MultiZone:              s.multiZoneCluster,
-			ProvisioningParameters: provisioningParameters,
-			FailureTolerance:       s.commercialFailureTolerance,
-			ZonesProvider:          s.zonesProvider,
-		}
-	case broker.AzureLitePlanID:
-		p = &AzureLiteInputProvider{
-			Purpose:                s.defaultPurpose,
-			ProvisioningParameters: provisioningParameters,
-			ZonesProvider:          s.zonesProvider,
+	switch {
+	case plan.Trial:
+		var trialProvider pkg.CloudProvider
+		if provisioningParameters.Parameters.Provider == nil {
+			trialProvider = s.defaultTrialProvider
+		} else {
+			trialProvider = *provisioningParameters.Parameters.Provider
 		}
-	case broker.GCPPlanID, broker.BuildRuntimeGCPPlanID:
-		p = &GCPInputProvider{
-			Purpose:                s.defaultPurpose,
-			MultiZone:              s.multiZoneCluster,
-			ProvisioningParameters: provisioningParameters,
-			FailureTolerance:       s.commercialFailureTolerance,
-			ZonesProvider:          s.zonesProvider,
+		switch trialProvider {
+		case pkg.AWS:
+			p = &AWSTrialInputProvider{
+				PlatformRegionMapping:  s.trialPlatformRegionMapping,
+				UseSmallerMachineTypes: s.useSmallerMachineTypes,
+				ProvisioningParameters: provisioningParameters,
+				ZonesProvider:          s.zonesProvider,
+			}
+		case pkg.GCP:
+			p = &GCPTrialInputProvider{
+				PlatformRegionMapping:  s.trialPlatformRegionMapping,
+				ProvisioningParameters: provisioningParameters,
+				ZonesProvider:          s.zonesProvider,
+			}
+		case pkg.Azure:
+			p = &AzureTrialInputProvider{
+				PlatformRegionMapping:  s.trialPlatformRegionMapping,
+				UseSmallerMachineTypes: s.useSmallerMachineTypes,
+				ProvisioningParameters: provisioningParameters,
+				ZonesProvider:          s.zonesProvider,
+			}
+		default:
+			return internal.ProviderValues{}, fmt.Errorf("trial provider for %s not yet implemented", trialProvider)
 		}
-	case broker.FreemiumPlanID:
+	case plan.Free:
 		switch provisioningParameters.PlatformProvider {
 		case pkg.AWS:
 			p = &AWSFreemiumInputProvider{
@@ -116,60 +114,61 @@ func (s *PlanSpecificValuesProvider) ValuesForPlanAndParameters(provisioningPara
 		default:
 			return internal.ProviderValues{}, fmt.Errorf("freemium provider for '%s' is not supported", provisioningParameters.PlatformProvider)
 		}
-	case broker.SapConvergedCloudPlanID:
-		p = &SapConvergedCloudInputProvider{
-			Purpose:                s.defaultPurpose,
-			MultiZone:              s.multiZoneCluster,
-			ProvisioningParameters: provisioningParameters,
-			FailureTolerance:       s.commercialFailureTolerance,
-			ZonesProvider:          s.zonesProvider,
-		}
-	case broker.AlicloudPlanID, broker.BuildRuntimeAlicloudPlanID:
-		p = &AlicloudInputProvider{
+	case plan.Schema == configuration.AzureLiteSchema:
+		p = &AzureLiteInputProvider{
 			Purpose:                s.defaultPurpose,
-			MultiZone:              s.multiZoneCluster,
 			ProvisioningParameters: provisioningParameters,
-			FailureTolerance:       s.commercialFailureTolerance,
 			ZonesProvider:          s.zonesProvider,
 		}
-	case broker.TrialPlanID:
-		var trialProvider pkg.CloudProvider
-		if provisioningParameters.Parameters.Provider == nil {
-			trialProvider = s.defaultTrialProvider
-		} else {
-			trialProvider = *provisioningParameters.Parameters.Provider
-		}
-		switch trialProvider {
+	default:
+		switch plan.Provider {
 		case pkg.AWS:
-			p = &AWSTrialInputProvider{
-				PlatformRegionMapping:  s.trialPlatformRegionMapping,
-				UseSmallerMachineTypes: s.useSmallerMachineTypes,
+			p = &AWSInputProvider{
+				Purpose:                s.defaultPurpose,
+				MultiZone:              s.multiZoneCluster,
+				ProvisioningParameters: provisioningParameters,
+				FailureTolerance:       s.commercialFailureTolerance,
+				ZonesProvider:          s.zonesProvider,
+			}
+		case pkg.Azure:
+			p = &AzureInputProvider{
+				Purpose:                s.defaultPurpose,
+				MultiZone:              s.multiZoneCluster,
 				ProvisioningParameters: provisioningParameters,
+				FailureTolerance:       s.commercialFailureTolerance,
 				ZonesProvider:          s.zonesProvider,
 			}
 		case pkg.GCP:
-			p = &GCPTrialInputProvider{
-				PlatformRegionMapping:  s.trialPlatformRegionMapping,
+			p = &GCPInputProvider{
+				Purpose:                s.defaultPurpose,
+				MultiZone:              s.multiZoneCluster,
 				ProvisioningParameters: provisioningParameters,
+				FailureTolerance:       s.commercialFailureTolerance,
 				ZonesProvider:          s.zonesProvider,
 			}
-		case pkg.Azure:
-			p = &AzureTrialInputProvider{
-				PlatformRegionMapping:  s.trialPlatformRegionMapping,
-				UseSmallerMachineTypes: s.useSmallerMachineTypes,
+		case pkg.SapConvergedCloud:
+			p = &SapConvergedCloudInputProvider{
+				Purpose:                s.defaultPurpose,
+				MultiZone:              s.multiZoneCluster,
+				ProvisioningParameters: provisioningParameters,
+				FailureTolerance:       s.commercialFailureTolerance,
+				ZonesProvider:          s.zonesProvider,
+			}
+		case pkg.Alicloud:
+			p = &AlicloudInputProvider{
+				Purpose:                s.defaultPurpose,
+				MultiZone:              s.multiZoneCluster,
 				ProvisioningParameters: provisioningParameters,
+				FailureTolerance:       s.commercialFailureTolerance,
 				ZonesProvider:          s.zonesProvider,
 			}
 		default:
-			return internal.ProviderValues{}, fmt.Errorf("trial provider for %s not yet implemented", trialProvider)
+			return internal.ProviderValues{}, fmt.Errorf("plan %s not supported", provisioningParameters.PlanID)
 		}
-
-	default:
-		return internal.ProviderValues{}, fmt.Errorf("plan %s not supported", provisioningParameters.PlanID)
 	}
 
 	values := p.Provide()
-	planeName := broker.AvailablePlans.GetPlanNameOrEmpty(broker.PlanIDType(provisioningParameters.PlanID))
+	planeName := plan.Name
 	volumeSize, found := s.planSpec.DefaultVolumeSizeGb(planeName)
 	if found {
 		values.VolumeSizeGb = volumeSize
@@ -0,0 +1,38 @@
+package reencryption
+
+import "github.com/prometheus/client_golang/prometheus"
+
+const (
+	resultReencrypted = "reencrypted"
+	resultUpToDate    = "up_to_date"
+	resultChanged     = "changed"
+	resultFailed      = "failed"
+)
+
+type Metrics struct {
+	rows    *prometheus.CounterVec
+	batches *prometheus.CounterVec
+	dryRun  prometheus.Gauge
+}
+
+func NewMetrics(reg prometheus.Registerer, namespace string) *Metrics {
+	m := &Metrics{
+		rows: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "rows_total",
+			Help:      "Rows with encrypted data processed by the job, by the source column and the result.",
+		}, []string{"source", "result"}),
+		batches: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "batches_total",
+			Help:      "Batches of rows processed by the job, by the source column.",
+		}, []string{"source"}),
+		dryRun: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "dry_run",
+			Help:      "Rows are not updated.",
+		}),
+	}
+	reg.MustRegister(m.rows, m.batches, m.dryRun)
+	return m
+}
@@ -0,0 +1,187 @@
+package reencryption
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+
+	"github.com/kyma-project/kyma-environment-broker/internal"
+	"github.com/kyma-project/kyma-environment-broker/internal/storage"
+	"github.com/kyma-project/kyma-environment-broker/internal/storage/dberr"
+	"github.com/kyma-project/kyma-environment-broker/internal/storage/dbmodel"
+)
+
+type Cipher interface {
+	NeedsReencryption(ciphertext []byte) bool
+	Encrypt(data []byte) ([]byte, error)
+	DecryptUsingMode(data []byte) ([]byte, error)
+	EncryptSMCredentials(pp *internal.ProvisioningParameters) error
+	DecryptSMCredentialsUsingMode(pp *internal.ProvisioningParameters) error
+	EncryptKubeconfig(pp *internal.ProvisioningParameters) error
+	DecryptKubeconfigUsingMode(pp *internal.ProvisioningParameters) error
+}
+
+var sources = []dbmodel.EncryptedDataSource{
+	dbmodel.InstanceProvisioningParameters,
+	dbmodel.OperationProvisioningParameters,
+	dbmodel.BindingKubeconfig,
+}
+
+// Service encrypts again with the active key the SM credentials and the kubeconfigs stored in the provisioning parameters
+// of instances and operations, and the kubeconfigs of bindings. The rows are processed in batches ordered by the ID.
+type Service struct {
+	dryRun    bool
+	batchSize int
+	cipher    Cipher
+	storage   storage.EncryptedData
+	metrics   *Metrics
+}
+
+func NewService(dryRun bool, batchSize int, cipher Cipher, storage storage.EncryptedData, metrics *Metrics) *Service {
+	return &Service{
+		dryRun:    dryRun,
+		batchSize: batchSize,
+		cipher:    cipher,
+		storage:   storage,
+		metrics:   metrics,
+	}
+}
+
+func (s *Service) Run() error {
+	if s.dryRun {
+		s.metrics.dryRun.Set(1)
+	}
+	var failedCount int
+	for _, source := range sources {
+		failed, err := s.reencrypt(source)
+		if err != nil {
+			return fmt.Errorf("while re-encrypting %s: %w", source, err)
+		}
+		failedCount += failed
+	}
+	if failedCount > 0 {
+		return fmt.Errorf("unable to re-encrypt %d row(s)", failedCount)
+	}
+	return nil
+}
+
+func (s *Service) reencrypt(source dbmodel.EncryptedDataSource) (int, error) {
+	log := slog.With("source", source)
+	counts := make(map[string]int)
+	var after dbmodel.EncryptedDataDTO
+	for {
+		items, err := s.storage.List(source, after, s.batchSize)
+		if err != nil {
+			return 0, err
+		}
+		for _, item := range items {
+			result := s.reencryptItem(log, source, item)
+			counts[result]++
+			s.metrics.rows.WithLabelValues(string(source), result).Inc()
+		}
+		s.metrics.batches.WithLabelValues(string(source)).Inc()
+		if len(items) < s.batchSize {
+			break
+		}
+		after = items[len(items)-1]
+	}
+	if s.dryRun {
+		log.Info(fmt.Sprintf("Rows to re-encrypt: %d, up to date: %d, failed: %d", counts[resultReencrypted], counts[resultUpToDate], counts[resultFailed]))
+	} else {
+		log.Info(fmt.Sprintf("Re-encrypted rows: %d, up to date: %d, changed during the job: %d, failed: %d",
+			counts[resultReencrypted], counts[resultUpToDate], counts[resultChanged], counts[resultFailed]))
+	}
+	return counts[resultFailed], nil
+}
+
+func (s *Service) reencryptItem(log *slog.Logger, source dbmodel.EncryptedDataSource, item dbmodel.EncryptedDataDTO) string {
+	var value string
+	var needed bool
+	var err error
+	switch source {
+	case dbmodel.BindingKubeconfig:
+		value, needed, err = s.reencryptValue(item.Value)
+	default:
+		value, needed, err = s.reencryptProvisioningParameters(item.Value)
+	}
+	if err != nil {
+		log.Error(fmt.Sprintf("unable to re-encrypt the row with ID %s: %s", item.ID, err))
+		return resultFailed
+	}
+	if !needed {
+		return resultUpToDate
+	}
+	if s.dryRun {
+		return resultReencrypted
+	}
+
+	err = s.storage.Update(source, item, value)
+	switch {
+	case dberr.IsConflict(err):
+		// the row was saved by KEB after it had been listed, so it is already encrypted with the active key
+		log.Info(fmt.Sprintf("the row with ID %s was changed, skipping", item.ID))
+		return resultChanged
+	case err != nil:
+		log.Error(fmt.Sprintf("unable to update the row with ID %s: %s", item.ID, err))
+		return resultFailed
+	}
+	return resultReencrypted
+}
+
+func (s *Service) reencryptValue(value string) (string, bool, error) {
+	if !s.cipher.NeedsReencryption([]byte(value)) {
+		return value, false, nil
+	}
+	decrypted, err := s.cipher.DecryptUsingMode([]byte(value))
+	if err != nil {
+		return "", false, fmt.Errorf("while decrypting: %w", err)
+	}
+	encrypted, err := s.cipher.Encrypt(decrypted)
+	if err != nil {
+		return "", false, fmt.Errorf("while encrypting: %w", err)
+	}
+	return string(encrypted), true, nil
+}
+
+func (s *Service) reencryptProvisioningParameters(value string) (string, bool, error) {
+	var params internal.ProvisioningParameters
+	if err := json.Unmarshal([]byte(value), &params); err != nil {
+		return "", false, fmt.Errorf("while unmarshalling provisioning parameters: %w", err)
+	}
+	if !s.provisioningParametersNeedReencryption(params) {
+		return value, false, nil
+	}
+
+	if err := s.cipher.DecryptSMCredentialsUsingMode(&params); err != nil {
+		return "", false, err
+	}
+	if err := s.cipher.DecryptKubeconfigUsingMode(&params); err != nil {
+		return "", false, err
+	}
+	if err := s.cipher.EncryptSMCredentials(&params); err != nil {
+		return "", false, err
+	}
+	if err := s.cipher.EncryptKubeconfig(&params); err != nil {
+		return "", false, err
+	}
+
+	encrypted, err := json.Marshal(params)
+	if err != nil {
+		return "", false, fmt.Errorf("while marshalling provisioning parameters: %w", err)
+	}
+	return string(encrypted), true, nil
+}
+
+func (s *Service) provisioningParametersNeedReencryption(params internal.ProvisioningParameters) bool {
+	var encrypted []string
+	if creds := params.ErsContext.SMOperatorCredentials; creds != nil {
+		encrypted = append(encrypted, creds.ClientID, creds.ClientSecret)
+	}
+	encrypted = append(encrypted, params.Parameters.Kubeconfig)
+	for _, value := range encrypted {
+		if value != "" && s.cipher.NeedsReencryption([]byte(value)) {
+			return true
+		}
+	}
+	return false
+}
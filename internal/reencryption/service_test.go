@@ -0,0 +1,208 @@
+package reencryption_test
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/kyma-project/kyma-environment-broker/common/runtime"
+	"github.com/kyma-project/kyma-environment-broker/internal"
+	"github.com/kyma-project/kyma-environment-broker/internal/reencryption"
+	"github.com/kyma-project/kyma-environment-broker/internal/storage"
+	"github.com/kyma-project/kyma-environment-broker/internal/storage/dberr"
+	"github.com/kyma-project/kyma-environment-broker/internal/storage/dbmodel"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/util/rand"
+)
+
+func TestService_Run(t *testing.T) {
+	// given
+	secretKey := rand.String(32)
+	newKey := rand.String(32)
+	oldCipher := storage.NewEncrypter(secretKey)
+	newCipher, err := storage.NewEncrypterFromConfig(storage.Config{SecretKey: secretKey, EncryptionKeys: "v2=" + newKey, EncryptionKeyID: "v2"})
+	require.NoError(t, err)
+
+	db := newFakeEncryptedData()
+	for _, id := range []string{"i1", "i2", "i3"} {
+		db.add(dbmodel.InstanceProvisioningParameters, dbmodel.EncryptedDataDTO{ID: id, InstanceID: id, Value: encryptedParameters(t, oldCipher)})
+	}
+	db.add(dbmodel.InstanceProvisioningParameters, dbmodel.EncryptedDataDTO{ID: "i4", InstanceID: "i4", Value: encryptedParameters(t, newCipher)})
+	db.add(dbmodel.OperationProvisioningParameters, dbmodel.EncryptedDataDTO{ID: "o1", InstanceID: "i1", Value: encryptedParameters(t, oldCipher)})
+	db.add(dbmodel.OperationProvisioningParameters, dbmodel.EncryptedDataDTO{ID: "o2", InstanceID: "i1", Value: `{"plan_id":"plan"}`})
+	kubeconfig, err := oldCipher.Encrypt([]byte("kubeconfig"))
+	require.NoError(t, err)
+	db.add(dbmodel.BindingKubeconfig, dbmodel.EncryptedDataDTO{ID: "b1", InstanceID: "i1", Value: string(kubeconfig)})
+
+	t.Run("should only count the rows in dry run mode", func(t *testing.T) {
+		// given
+		metrics := reencryption.NewMetrics(prometheus.NewRegistry(), "test")
+		before := db.snapshot()
+
+		// when
+		err := reencryption.NewService(true, 2, newCipher, db, metrics).Run()
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, before, db.snapshot())
+	})
+
+	t.Run("should re-encrypt the rows with the active key", func(t *testing.T) {
+		// given
+		reg := prometheus.NewRegistry()
+		metrics := reencryption.NewMetrics(reg, "test")
+
+		// when
+		err := reencryption.NewService(false, 2, newCipher, db, metrics).Run()
+
+		// then
+		require.NoError(t, err)
+		for _, id := range []string{"i1", "i2", "i3", "i4"} {
+			params := decryptedParameters(t, newCipher, db.get(dbmodel.InstanceProvisioningParameters, id))
+			assert.Equal(t, "client-id", params.ErsContext.SMOperatorCredentials.ClientID)
+			assert.Equal(t, "client-secret", params.ErsContext.SMOperatorCredentials.ClientSecret)
+			assert.Equal(t, "kubeconfig", params.Parameters.Kubeconfig)
+		}
+		params := decryptedParameters(t, newCipher, db.get(dbmodel.OperationProvisioningParameters, "o1"))
+		assert.Equal(t, "client-id", params.ErsContext.SMOperatorCredentials.ClientID)
+		assert.Equal(t, `{"plan_id":"plan"}`, db.get(dbmodel.OperationProvisioningParameters, "o2"))
+		assert.False(t, newCipher.NeedsReencryption([]byte(db.get(dbmodel.BindingKubeconfig, "b1"))))
+		decrypted, err := newCipher.DecryptUsingMode([]byte(db.get(dbmodel.BindingKubeconfig, "b1")))
+		require.NoError(t, err)
+		assert.Equal(t, "kubeconfig", string(decrypted))
+
+		expected := `
+# HELP test_rows_total Rows with encrypted data processed by the job, by the source column and the result.
+# TYPE test_rows_total counter
+test_rows_total{result="reencrypted",source="bindings.kubeconfig"} 1
+test_rows_total{result="reencrypted",source="instances.provisioning_parameters"} 3
+test_rows_total{result="reencrypted",source="operations.provisioning_parameters"} 1
+test_rows_total{result="up_to_date",source="instances.provisioning_parameters"} 1
+test_rows_total{result="up_to_date",source="operations.provisioning_parameters"} 1
+# HELP test_batches_total Batches of rows processed by the job, by the source column.
+# TYPE test_batches_total counter
+test_batches_total{source="bindings.kubeconfig"} 1
+test_batches_total{source="instances.provisioning_parameters"} 3
+test_batches_total{source="operations.provisioning_parameters"} 2
+`
+		assert.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(expected), "test_rows_total", "test_batches_total"))
+	})
+
+	t.Run("should skip the rows changed during the job", func(t *testing.T) {
+		// given
+		db.add(dbmodel.BindingKubeconfig, dbmodel.EncryptedDataDTO{ID: "b2", InstanceID: "i2", Value: string(kubeconfig)})
+		db.conflicts = map[string]bool{"b2": true}
+		defer func() { db.conflicts = nil }()
+
+		// when
+		err := reencryption.NewService(false, 2, newCipher, db, reencryption.NewMetrics(prometheus.NewRegistry(), "test")).Run()
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, string(kubeconfig), db.get(dbmodel.BindingKubeconfig, "b2"))
+	})
+
+	t.Run("should fail when the data cannot be decrypted", func(t *testing.T) {
+		// given
+		unknown, err := storage.NewEncrypterFromConfig(storage.Config{EncryptionKeys: "v3=" + rand.String(32), EncryptionKeyID: "v3"})
+		require.NoError(t, err)
+		value, err := unknown.Encrypt([]byte("kubeconfig"))
+		require.NoError(t, err)
+		db.add(dbmodel.BindingKubeconfig, dbmodel.EncryptedDataDTO{ID: "b3", InstanceID: "i3", Value: string(value)})
+
+		// when
+		err = reencryption.NewService(false, 2, newCipher, db, reencryption.NewMetrics(prometheus.NewRegistry(), "test")).Run()
+
+		// then
+		assert.ErrorContains(t, err, "unable to re-encrypt 1 row(s)")
+	})
+}
+
+func encryptedParameters(t *testing.T, cipher *storage.Encrypter) string {
+	params := internal.ProvisioningParameters{
+		PlanID: "plan",
+		ErsContext: internal.ERSContext{
+			SMOperatorCredentials: &internal.ServiceManagerOperatorCredentials{
+				ClientID:     "client-id",
+				ClientSecret: "client-secret",
+				URL:          "https://sm.example.com",
+			},
+		},
+		Parameters: runtime.ProvisioningParametersDTO{
+			Kubeconfig: "kubeconfig",
+		},
+	}
+	require.NoError(t, cipher.EncryptSMCredentials(&params))
+	require.NoError(t, cipher.EncryptKubeconfig(&params))
+	value, err := json.Marshal(params)
+	require.NoError(t, err)
+	return string(value)
+}
+
+func decryptedParameters(t *testing.T, cipher *storage.Encrypter, value string) internal.ProvisioningParameters {
+	var params internal.ProvisioningParameters
+	require.NoError(t, json.Unmarshal([]byte(value), &params))
+	assert.False(t, cipher.NeedsReencryption([]byte(params.ErsContext.SMOperatorCredentials.ClientID)))
+	require.NoError(t, cipher.DecryptSMCredentialsUsingMode(&params))
+	require.NoError(t, cipher.DecryptKubeconfigUsingMode(&params))
+	return params
+}
+
+type fakeEncryptedData struct {
+	rows      map[dbmodel.EncryptedDataSource][]dbmodel.EncryptedDataDTO
+	conflicts map[string]bool
+}
+
+func newFakeEncryptedData() *fakeEncryptedData {
+	return &fakeEncryptedData{rows: map[dbmodel.EncryptedDataSource][]dbmodel.EncryptedDataDTO{}}
+}
+
+func (f *fakeEncryptedData) add(source dbmodel.EncryptedDataSource, item dbmodel.EncryptedDataDTO) {
+	f.rows[source] = append(f.rows[source], item)
+	sort.Slice(f.rows[source], func(i, j int) bool { return f.rows[source][i].ID < f.rows[source][j].ID })
+}
+
+func (f *fakeEncryptedData) get(source dbmodel.EncryptedDataSource, id string) string {
+	for _, item := range f.rows[source] {
+		if item.ID == id {
+			return item.Value
+		}
+	}
+	return ""
+}
+
+func (f *fakeEncryptedData) snapshot() map[dbmodel.EncryptedDataSource][]dbmodel.EncryptedDataDTO {
+	snapshot := map[dbmodel.EncryptedDataSource][]dbmodel.EncryptedDataDTO{}
+	for source, items := range f.rows {
+		snapshot[source] = append([]dbmodel.EncryptedDataDTO{}, items...)
+	}
+	return snapshot
+}
+
+func (f *fakeEncryptedData) List(source dbmodel.EncryptedDataSource, after dbmodel.EncryptedDataDTO, limit int) ([]dbmodel.EncryptedDataDTO, error) {
+	items := make([]dbmodel.EncryptedDataDTO, 0)
+	for _, item := range f.rows[source] {
+		if item.ID > after.ID && len(items) < limit {
+			items = append(items, item)
+		}
+	}
+	return items, nil
+}
+
+func (f *fakeEncryptedData) Update(source dbmodel.EncryptedDataSource, item dbmodel.EncryptedDataDTO, value string) error {
+	if f.conflicts[item.ID] {
+		return dberr.Conflict("the value was changed")
+	}
+	for i, row := range f.rows[source] {
+		if row.ID == item.ID && row.Value == item.Value {
+			f.rows[source][i].Value = value
+			return nil
+		}
+	}
+	return dberr.Conflict("the value was changed")
+}
@@ -0,0 +1,48 @@
+package retention
+
+import "github.com/prometheus/client_golang/prometheus"
+
+const (
+	resultDeleted = "deleted"
+	resultFailed  = "failed"
+)
+
+type Metrics struct {
+	instances  prometheus.Counter
+	operations *prometheus.CounterVec
+	events     *prometheus.CounterVec
+	changes    prometheus.Counter
+	dryRun     prometheus.Gauge
+}
+
+func NewMetrics(reg prometheus.Registerer, namespace string) *Metrics {
+	m := &Metrics{
+		instances: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "instances_total",
+			Help:      "Instances which operations were checked by the job.",
+		}),
+		operations: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "operations_total",
+			Help:      "Operations purged by the job, by the operation type and the result.",
+		}, []string{"type", "result"}),
+		events: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "events_total",
+			Help:      "Events purged by the job, by the event level.",
+		}, []string{"level"}),
+		changes: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "changes_total",
+			Help:      "Changes purged by the job from the change feed.",
+		}),
+		dryRun: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "dry_run",
+			Help:      "Operations, events, and changes are not deleted.",
+		}),
+	}
+	reg.MustRegister(m.instances, m.operations, m.events, m.changes, m.dryRun)
+	return m
+}
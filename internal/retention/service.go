@@ -0,0 +1,221 @@
+package retention
+
+import (
+	"fmt"
+	"log/slog"
+	"sort"
+	"time"
+
+	"github.com/kyma-project/kyma-environment-broker/common/events"
+	"github.com/kyma-project/kyma-environment-broker/internal"
+	"github.com/kyma-project/kyma-environment-broker/internal/storage"
+)
+
+type Config struct {
+	// UpdateOperationsToKeep is the number of the latest update operations kept for every instance, 0 disables the limit
+	UpdateOperationsToKeep int `envconfig:"default=20"`
+	// OperationsMaxAge is the time after which the finished operations are deleted, 0 keeps the operations regardless of their age
+	OperationsMaxAge time.Duration `envconfig:"default=2160h"` // 90 days
+	// DebugEventsTTL, InfoEventsTTL, WarningEventsTTL and ErrorEventsTTL are the times after which the events
+	// of the level are deleted, 0 keeps the events
+	DebugEventsTTL   time.Duration `envconfig:"default=24h"`
+	InfoEventsTTL    time.Duration `envconfig:"default=168h"`
+	WarningEventsTTL time.Duration `envconfig:"default=720h"`
+	ErrorEventsTTL   time.Duration `envconfig:"default=720h"`
+	// ChangesTTL is the time after which the changes are deleted from the change feed, 0 keeps the changes
+	ChangesTTL time.Duration `envconfig:"default=336h"`
+}
+
+// Service purges the history of the existing instances. The provisioning operation, the operations in progress
+// and the last operation of every instance are always kept, because they describe the current state of the instance.
+// The latest UpdateOperationsToKeep update operations are kept regardless of their age, the older update operations
+// are deleted. The other finished operations are deleted when they are older than OperationsMaxAge.
+// The operations of deleted instances are removed by the archiving job.
+type Service struct {
+	cfg        Config
+	dryRun     bool
+	batchSize  int
+	operations storage.Operations
+	steps      storage.OperationSteps
+	retention  storage.Retention
+	metrics    *Metrics
+}
+
+func NewService(cfg Config, dryRun bool, batchSize int, db storage.BrokerStorage, metrics *Metrics) *Service {
+	return &Service{
+		cfg:        cfg,
+		dryRun:     dryRun,
+		batchSize:  batchSize,
+		operations: db.Operations(),
+		steps:      db.OperationSteps(),
+		retention:  db.Retention(),
+		metrics:    metrics,
+	}
+}
+
+func (s *Service) Run() error {
+	if s.dryRun {
+		s.metrics.dryRun.Set(1)
+	}
+	now := time.Now()
+
+	failed, err := s.purgeOperations(now)
+	if err != nil {
+		return fmt.Errorf("while purging operations: %w", err)
+	}
+	if err := s.purgeEvents(now); err != nil {
+		return fmt.Errorf("while purging events: %w", err)
+	}
+	if err := s.purgeChanges(now); err != nil {
+		return fmt.Errorf("while purging changes: %w", err)
+	}
+	if failed > 0 {
+		return fmt.Errorf("unable to delete %d operation(s)", failed)
+	}
+	return nil
+}
+
+func (s *Service) purgeOperations(now time.Time) (int, error) {
+	var instances, deleted, failed int
+	after := ""
+	for {
+		instanceIDs, err := s.retention.ListInstanceIDs(after, s.batchSize)
+		if err != nil {
+			return 0, err
+		}
+		for _, instanceID := range instanceIDs {
+			log := slog.With("instanceID", instanceID)
+			operations, err := s.operations.ListOperationsByInstanceID(instanceID)
+			if err != nil {
+				log.Error(fmt.Sprintf("unable to get operations: %s", err))
+				failed++
+				continue
+			}
+			for _, operation := range s.operationsToPurge(operations, now) {
+				result := s.deleteOperation(log, operation)
+				if result == resultFailed {
+					failed++
+				} else {
+					deleted++
+				}
+				s.metrics.operations.WithLabelValues(string(operation.Type), result).Inc()
+			}
+			instances++
+			s.metrics.instances.Inc()
+		}
+		if len(instanceIDs) < s.batchSize {
+			break
+		}
+		after = instanceIDs[len(instanceIDs)-1]
+	}
+	if s.dryRun {
+		slog.Info(fmt.Sprintf("Instances checked: %d, operations to delete: %d", instances, deleted))
+	} else {
+		slog.Info(fmt.Sprintf("Instances checked: %d, deleted operations: %d, failed: %d", instances, deleted, failed))
+	}
+	return failed, nil
+}
+
+// operationsToPurge returns the operations of the instance which are not kept by the retention policy
+func (s *Service) operationsToPurge(operations []internal.Operation, now time.Time) []internal.Operation {
+	sort.SliceStable(operations, func(i, j int) bool {
+		return operations[i].CreatedAt.After(operations[j].CreatedAt)
+	})
+
+	var purge []internal.Operation
+	updates := 0
+	for i, operation := range operations {
+		if operation.Type == internal.OperationTypeUpdate {
+			updates++
+		}
+		if i == 0 || operation.Type == internal.OperationTypeProvision || !operation.IsFinished() {
+			continue
+		}
+		if operation.Type == internal.OperationTypeUpdate && s.cfg.UpdateOperationsToKeep > 0 {
+			if updates > s.cfg.UpdateOperationsToKeep {
+				purge = append(purge, operation)
+			}
+			continue
+		}
+		if s.cfg.OperationsMaxAge > 0 && operation.UpdatedAt.Before(now.Add(-s.cfg.OperationsMaxAge)) {
+			purge = append(purge, operation)
+		}
+	}
+	return purge
+}
+
+func (s *Service) deleteOperation(log *slog.Logger, operation internal.Operation) string {
+	log = log.With("operationID", operation.ID, "type", operation.Type)
+	if s.dryRun {
+		log.Debug(fmt.Sprintf("DryRun: operation finished at %s would be deleted", operation.UpdatedAt))
+		return resultDeleted
+	}
+
+	// the steps are deleted first, so the operation is found again by the next run if the deletion fails
+	if err := s.steps.DeleteByOperationID(operation.ID); err != nil {
+		log.Error(fmt.Sprintf("unable to delete steps of the operation: %s", err))
+		return resultFailed
+	}
+	if err := s.operations.DeleteByID(operation.ID); err != nil {
+		log.Error(fmt.Sprintf("unable to delete the operation: %s", err))
+		return resultFailed
+	}
+	log.Debug("operation deleted")
+	return resultDeleted
+}
+
+func (s *Service) purgeEvents(now time.Time) error {
+	ttls := map[events.EventLevel]time.Duration{
+		events.DebugEventLevel:   s.cfg.DebugEventsTTL,
+		events.InfoEventLevel:    s.cfg.InfoEventsTTL,
+		events.WarningEventLevel: s.cfg.WarningEventsTTL,
+		events.ErrorEventLevel:   s.cfg.ErrorEventsTTL,
+	}
+	for level, ttl := range ttls {
+		if ttl == 0 {
+			continue
+		}
+		until := now.Add(-ttl)
+		var count int
+		var err error
+		if s.dryRun {
+			count, err = s.retention.CountEvents(level, until)
+		} else {
+			count, err = s.retention.DeleteEvents(level, until)
+		}
+		if err != nil {
+			return err
+		}
+		s.metrics.events.WithLabelValues(string(level)).Add(float64(count))
+		if s.dryRun {
+			slog.Info(fmt.Sprintf("%s events to delete: %d", level, count))
+		} else {
+			slog.Info(fmt.Sprintf("Deleted %s events created before %s: %d", level, until.Format(time.RFC3339), count))
+		}
+	}
+	return nil
+}
+
+func (s *Service) purgeChanges(now time.Time) error {
+	if s.cfg.ChangesTTL == 0 {
+		return nil
+	}
+	until := now.Add(-s.cfg.ChangesTTL)
+	var count int
+	var err error
+	if s.dryRun {
+		count, err = s.retention.CountChanges(until)
+	} else {
+		count, err = s.retention.DeleteChanges(until)
+	}
+	if err != nil {
+		return err
+	}
+	s.metrics.changes.Add(float64(count))
+	if s.dryRun {
+		slog.Info(fmt.Sprintf("Changes to delete: %d", count))
+	} else {
+		slog.Info(fmt.Sprintf("Deleted changes created before %s: %d", until.Format(time.RFC3339), count))
+	}
+	return nil
+}
@@ -0,0 +1,179 @@
+package retention
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/kyma-project/kyma-environment-broker/common/events"
+	"github.com/kyma-project/kyma-environment-broker/common/runtime"
+	"github.com/kyma-project/kyma-environment-broker/internal"
+	"github.com/kyma-project/kyma-environment-broker/internal/fixture"
+	"github.com/kyma-project/kyma-environment-broker/internal/storage"
+	"github.com/pivotal-cf/brokerapi/v12/domain"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const instanceID = "instance-01"
+
+func TestService_Run(t *testing.T) {
+	cfg := Config{
+		UpdateOperationsToKeep: 2,
+		OperationsMaxAge:       30 * 24 * time.Hour,
+	}
+
+	t.Run("should delete old update operations and operations older than the max age", func(t *testing.T) {
+		// given
+		db := storage.NewMemoryStorage()
+		fixInstanceHistory(t, db)
+		require.NoError(t, db.OperationSteps().Insert(runtime.OperationStep{OperationID: "update-1"}))
+		metrics := NewMetrics(prometheus.NewRegistry(), "test")
+		svc := NewService(cfg, false, 10, db, metrics)
+
+		// when
+		err := svc.Run()
+
+		// then
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"provision", "update-3", "update-4", "update-in-progress", "suspension-recent", "deprovision-last"}, operationIDs(t, db))
+		assert.Equal(t, float64(2), testutil.ToFloat64(metrics.operations.WithLabelValues(string(internal.OperationTypeUpdate), resultDeleted)))
+		assert.Equal(t, float64(1), testutil.ToFloat64(metrics.operations.WithLabelValues(string(internal.OperationTypeDeprovision), resultDeleted)))
+		assert.Equal(t, float64(1), testutil.ToFloat64(metrics.instances))
+
+		steps, err := db.OperationSteps().ListByOperationIDs([]string{"update-1"})
+		require.NoError(t, err)
+		assert.Empty(t, steps)
+	})
+
+	t.Run("should not delete operations in the dry run", func(t *testing.T) {
+		// given
+		db := storage.NewMemoryStorage()
+		fixInstanceHistory(t, db)
+		metrics := NewMetrics(prometheus.NewRegistry(), "test")
+		svc := NewService(cfg, true, 10, db, metrics)
+
+		// when
+		err := svc.Run()
+
+		// then
+		require.NoError(t, err)
+		assert.Len(t, operationIDs(t, db), 9)
+		assert.Equal(t, float64(1), testutil.ToFloat64(metrics.dryRun))
+		assert.Equal(t, float64(2), testutil.ToFloat64(metrics.operations.WithLabelValues(string(internal.OperationTypeUpdate), resultDeleted)))
+	})
+
+	t.Run("should process all instances in batches", func(t *testing.T) {
+		// given
+		db := storage.NewMemoryStorage()
+		for i := 0; i < 5; i++ {
+			id := fmt.Sprintf("instance-%d", i)
+			require.NoError(t, db.Instances().Insert(fixture.FixInstance(id)))
+		}
+		metrics := NewMetrics(prometheus.NewRegistry(), "test")
+		svc := NewService(cfg, false, 2, db, metrics)
+
+		// when
+		err := svc.Run()
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, float64(5), testutil.ToFloat64(metrics.instances))
+	})
+}
+
+func TestService_PurgeEvents(t *testing.T) {
+	// given
+	retention := &fakeRetention{}
+	svc := &Service{
+		cfg:       Config{DebugEventsTTL: 10 * time.Minute, InfoEventsTTL: time.Hour, WarningEventsTTL: 0, ErrorEventsTTL: 0},
+		retention: retention,
+		metrics:   NewMetrics(prometheus.NewRegistry(), "test"),
+	}
+	now := time.Now()
+
+	// when
+	err := svc.purgeEvents(now)
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, map[events.EventLevel]time.Time{
+		events.DebugEventLevel: now.Add(-10 * time.Minute),
+		events.InfoEventLevel:  now.Add(-time.Hour),
+	}, retention.deleted)
+	assert.Equal(t, float64(3), testutil.ToFloat64(svc.metrics.events.WithLabelValues(string(events.InfoEventLevel))))
+}
+
+func TestService_PurgeChanges(t *testing.T) {
+	// given
+	retention := &fakeRetention{}
+	svc := &Service{
+		cfg:       Config{ChangesTTL: time.Hour},
+		retention: retention,
+		metrics:   NewMetrics(prometheus.NewRegistry(), "test"),
+	}
+	now := time.Now()
+
+	// when
+	err := svc.purgeChanges(now)
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, now.Add(-time.Hour), retention.changesDeletedUntil)
+	assert.Equal(t, float64(5), testutil.ToFloat64(svc.metrics.changes))
+}
+
+func fixInstanceHistory(t *testing.T, db storage.BrokerStorage) {
+	old := time.Now().Add(-60 * 24 * time.Hour)
+	created := old
+	insert := func(id string, operationType internal.OperationType, state domain.LastOperationState, updatedAt time.Time) {
+		created = created.Add(time.Minute)
+		operation := fixture.FixOperation(id, instanceID, operationType)
+		operation.State = state
+		operation.CreatedAt = created
+		operation.UpdatedAt = updatedAt
+		require.NoError(t, db.Operations().InsertOperation(operation))
+	}
+
+	require.NoError(t, db.Instances().Insert(fixture.FixInstance(instanceID)))
+	insert("provision", internal.OperationTypeProvision, domain.Succeeded, old)
+	insert("update-1", internal.OperationTypeUpdate, domain.Failed, old)
+	insert("suspension-old", internal.OperationTypeDeprovision, domain.Succeeded, old)
+	insert("update-2", internal.OperationTypeUpdate, domain.Succeeded, time.Now())
+	insert("suspension-recent", internal.OperationTypeDeprovision, domain.Succeeded, time.Now())
+	insert("update-in-progress", internal.OperationTypeUpdate, domain.InProgress, old)
+	insert("update-3", internal.OperationTypeUpdate, domain.Succeeded, old)
+	insert("update-4", internal.OperationTypeUpdate, domain.Succeeded, time.Now())
+	insert("deprovision-last", internal.OperationTypeDeprovision, domain.Failed, old)
+}
+
+func operationIDs(t *testing.T, db storage.BrokerStorage) []string {
+	operations, err := db.Operations().ListOperationsByInstanceID(instanceID)
+	require.NoError(t, err)
+	var ids []string
+	for _, operation := range operations {
+		ids = append(ids, operation.ID)
+	}
+	return ids
+}
+
+type fakeRetention struct {
+	storage.Retention
+	deleted             map[events.EventLevel]time.Time
+	changesDeletedUntil time.Time
+}
+
+func (f *fakeRetention) DeleteEvents(level events.EventLevel, until time.Time) (int, error) {
+	if f.deleted == nil {
+		f.deleted = make(map[events.EventLevel]time.Time)
+	}
+	f.deleted[level] = until
+	return 3, nil
+}
+
+func (f *fakeRetention) DeleteChanges(until time.Time) (int, error) {
+	f.changesDeletedUntil = until
+	return 5, nil
+}
@@ -40,6 +40,7 @@ type Handler struct {
 	bindingsDb          storage.Bindings
 	instancesArchivedDb storage.InstancesArchived
 	actionsDb           storage.Actions
+	operationStepsDb    storage.OperationSteps
 	converter           Converter
 	defaultMaxPage      int
 	k8sClient           client.Client
@@ -54,6 +55,7 @@ func NewHandler(storage storage.BrokerStorage, defaultMaxPage int, defaultReques
 		bindingsDb:          storage.Bindings(),
 		instancesArchivedDb: storage.InstancesArchived(),
 		actionsDb:           storage.Actions(),
+		operationStepsDb:    storage.OperationSteps(),
 		converter:           NewConverter(defaultRequestRegion),
 		defaultMaxPage:      defaultMaxPage,
 		k8sClient:           k8sClient,
@@ -183,13 +185,25 @@ func (h *Handler) GetRuntimes(w http.ResponseWriter, req *http.Request) {
 		httputil.WriteErrorResponse(w, http.StatusBadRequest, fmt.Errorf("while getting query parameters: %w", err))
 		return
 	}
+	cursor, err := pagination.ExtractCursorFromRequest(req)
+	if err != nil {
+		h.logger.Warn(fmt.Sprintf("unable to extract cursor: %s", err.Error()))
+		httputil.WriteErrorResponse(w, http.StatusBadRequest, fmt.Errorf("while getting query parameters: %w", err))
+		return
+	}
 	filter := h.getFilters(req)
 	filter.PageSize = pageSize
 	filter.Page = page
+	filter.After = cursor
+	if cursor != nil && slices.Contains(filter.States, dbmodel.InstanceDeprovisioned) {
+		httputil.WriteErrorResponse(w, http.StatusBadRequest, fmt.Errorf("cursor cannot be used with the %s state", dbmodel.InstanceDeprovisioned))
+		return
+	}
 	opDetail := getOpDetail(req)
 	runtimeResourceConfig := getBoolParam(pkg.RuntimeConfigParam, req)
 	bindings := getBoolParam(pkg.BindingsParam, req)
 	actions := getBoolParam(pkg.ActionsParam, req)
+	timeline := getBoolParam(pkg.TimelineParam, req)
 
 	instances, count, totalCount, err := h.listInstances(filter)
 	if err != nil {
@@ -203,6 +217,9 @@ func (h *Handler) GetRuntimes(w http.ResponseWriter, req *http.Request) {
 		switch opDetail {
 		case pkg.AllOperation:
 			err = h.addAllOperationsToRuntime(&dto)
+			if err == nil && timeline {
+				err = h.addTimeline(&dto)
+			}
 		case
 			pkg.LastOperation:
 			err = h.addLastOperationToRuntime(&dto)
@@ -276,6 +293,11 @@ func (h *Handler) GetRuntimes(w http.ResponseWriter, req *http.Request) {
 		Count:      count,
 		TotalCount: totalCount,
 	}
+	// the deprovisioned runtimes come also from the archive, which cannot be paged with the cursor
+	if len(toReturn) == pageSize && !slices.Contains(filter.States, dbmodel.InstanceDeprovisioned) {
+		last := toReturn[len(toReturn)-1]
+		runtimePage.NextCursor = pagination.NewCursor(last.Status.CreatedAt, last.InstanceID).Encode()
+	}
 	httputil.WriteResponse(w, http.StatusOK, runtimePage)
 }
 
@@ -359,6 +381,47 @@ func (h *Handler) addAllOperationsToRuntime(dto *pkg.RuntimeDTO) error {
 	return nil
 }
 
+// addTimeline adds the executed steps to every operation of the runtime
+func (h *Handler) addTimeline(dto *pkg.RuntimeDTO) error {
+	operations := make([]*pkg.Operation, 0)
+	if dto.Status.Provisioning != nil {
+		operations = append(operations, dto.Status.Provisioning)
+	}
+	if dto.Status.Deprovisioning != nil {
+		operations = append(operations, dto.Status.Deprovisioning)
+	}
+	for _, data := range []*pkg.OperationsData{dto.Status.UpgradingCluster, dto.Status.Suspension, dto.Status.Unsuspension} {
+		if data == nil {
+			continue
+		}
+		for i := range data.Data {
+			operations = append(operations, &data.Data[i])
+		}
+	}
+	if dto.Status.Update != nil {
+		for i := range dto.Status.Update.Data {
+			operations = append(operations, &dto.Status.Update.Data[i])
+		}
+	}
+
+	ids := make([]string, 0, len(operations))
+	for _, op := range operations {
+		ids = append(ids, op.OperationID)
+	}
+	steps, err := h.operationStepsDb.ListByOperationIDs(ids)
+	if err != nil {
+		return fmt.Errorf("while fetching operation steps for instance %s: %w", dto.InstanceID, err)
+	}
+	stepsByOperation := make(map[string][]pkg.OperationStep)
+	for _, step := range steps {
+		stepsByOperation[step.OperationID] = append(stepsByOperation[step.OperationID], step)
+	}
+	for _, op := range operations {
+		op.Timeline = stepsByOperation[op.OperationID]
+	}
+	return nil
+}
+
 func (h *Handler) addLastOperationToRuntime(dto *pkg.RuntimeDTO) error {
 	lastOp, err := h.operationsDb.GetLastOperation(dto.InstanceID)
 	if err != nil {
@@ -487,6 +550,8 @@ func (h *Handler) addBindings(p *pkg.RuntimeDTO) error {
 			ExpiresAt:         b.ExpiresAt,
 			CreatedBy:         b.CreatedBy,
 			KubeconfigExists:  len(b.Kubeconfig) > 0,
+			Role:              b.Role,
+			Namespaces:        b.Namespaces,
 		})
 	}
 
@@ -111,6 +111,63 @@ func TestRuntimeHandler(t *testing.T) {
 
 	})
 
+	t.Run("test cursor pagination should work", func(t *testing.T) {
+		// given
+		db := storage.NewMemoryStorage()
+		instances := db.Instances()
+		createdAt := time.Now()
+		for _, id := range []string{"instance-c", "instance-a", "instance-b"} {
+			err := instances.Insert(internal.Instance{InstanceID: id, CreatedAt: createdAt})
+			require.NoError(t, err)
+		}
+
+		runtimeHandler := runtime.NewHandler(db, 2, "", k8sClient, log)
+		router := httputil.NewRouter()
+		runtimeHandler.AttachRoutes(router)
+
+		getPage := func(query string) pkg.RuntimesPage {
+			req, err := http.NewRequest(http.MethodGet, "/runtimes?"+query, nil)
+			require.NoError(t, err)
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+			require.Equal(t, http.StatusOK, rr.Code)
+
+			var out pkg.RuntimesPage
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &out))
+			return out
+		}
+
+		// when
+		first := getPage("page_size=2")
+
+		// then
+		assert.Equal(t, 3, first.TotalCount)
+		require.Equal(t, 2, first.Count)
+		assert.Equal(t, "instance-a", first.Data[0].InstanceID)
+		assert.Equal(t, "instance-b", first.Data[1].InstanceID)
+		require.NotEmpty(t, first.NextCursor)
+
+		// when
+		err := instances.Insert(internal.Instance{InstanceID: "instance-0", CreatedAt: createdAt.Add(-time.Minute)})
+		require.NoError(t, err)
+		second := getPage("page_size=2&cursor=" + first.NextCursor)
+
+		// then
+		assert.Equal(t, 4, second.TotalCount)
+		require.Equal(t, 1, second.Count)
+		assert.Equal(t, "instance-c", second.Data[0].InstanceID)
+		assert.Empty(t, second.NextCursor)
+
+		// when
+		req, err := http.NewRequest(http.MethodGet, "/runtimes?page=2&cursor="+first.NextCursor, nil)
+		require.NoError(t, err)
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		// then
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+
 	t.Run("test validation should work", func(t *testing.T) {
 		// given
 
@@ -924,6 +981,8 @@ func TestRuntimeHandler_WithKimOnlyDrivenInstances(t *testing.T) {
 
 		binding := fixture.FixBinding("abcd")
 		binding.InstanceID = testInstance.InstanceID
+		binding.Role = "viewer"
+		binding.Namespaces = []string{"team-a"}
 		err = bindings.Insert(&binding)
 		require.NoError(t, err)
 
@@ -947,7 +1006,9 @@ func TestRuntimeHandler_WithKimOnlyDrivenInstances(t *testing.T) {
 		require.NoError(t, err)
 
 		assert.Equal(t, testID1, out.Data[0].InstanceID)
-		assert.NotNil(t, out.Data[0].Bindings)
+		require.Len(t, out.Data[0].Bindings, 1)
+		assert.Equal(t, "viewer", out.Data[0].Bindings[0].Role)
+		assert.Equal(t, []string{"team-a"}, out.Data[0].Bindings[0].Namespaces)
 	})
 
 	t.Run("test params sent by the platform are set", func(t *testing.T) {
@@ -1109,6 +1170,41 @@ func TestRuntimeHandler_WithKimOnlyDrivenInstances(t *testing.T) {
 		assert.Equal(t, out.Data[0].Actions[0].Type, pkg.SubaccountMovementActionType)
 		assert.Equal(t, out.Data[0].Actions[1].Type, pkg.PlanUpdateActionType)
 	})
+
+	t.Run("test timeline", func(t *testing.T) {
+		// given
+		db := storage.NewMemoryStorage()
+		testTime := time.Now()
+		testInstance := fixInstanceForPreview(testID1, testTime)
+		require.NoError(t, db.Instances().Insert(testInstance))
+
+		provOp := fixture.FixProvisioningOperation(fixRandomID(), testID1)
+		require.NoError(t, db.Operations().InsertOperation(provOp))
+		require.NoError(t, db.OperationSteps().Insert(pkg.OperationStep{OperationID: provOp.ID, Stage: "start", Step: "Starting", StartedAt: testTime, FinishedAt: testTime, Outcome: pkg.StepSucceeded}))
+
+		runtimeHandler := runtime.NewHandler(db, 2, "", k8sClient, log)
+
+		rr := httptest.NewRecorder()
+		router := httputil.NewRouter()
+		runtimeHandler.AttachRoutes(router)
+
+		// when
+		req, err := http.NewRequest("GET", fmt.Sprintf("/runtimes?op_detail=%s&timeline=true", pkg.AllOperation), nil)
+		require.NoError(t, err)
+		router.ServeHTTP(rr, req)
+
+		// then
+		require.Equal(t, http.StatusOK, rr.Code)
+
+		var out pkg.RuntimesPage
+
+		err = json.Unmarshal(rr.Body.Bytes(), &out)
+		require.NoError(t, err)
+		require.Len(t, out.Data, 1)
+		require.NotNil(t, out.Data[0].Status.Provisioning)
+		require.Len(t, out.Data[0].Status.Provisioning.Timeline, 1)
+		assert.Equal(t, "Starting", out.Data[0].Status.Provisioning.Timeline[0].Step)
+	})
 }
 
 func fixInstance(id string, t time.Time) internal.Instance {
@@ -19,10 +19,20 @@ type Config struct {
 	SSLRootCert string `envconfig:"optional"`
 
 	SecretKey string `envconfig:"optional"`
+	// EncryptionKeys is the keyring in the format "keyID=key,keyID=key", the keys are used to decrypt the data encrypted with them
+	EncryptionKeys string `envconfig:"optional"`
+	// EncryptionKeyID is the ID of the key from the keyring used to encrypt the data, if empty the data is encrypted with SecretKey
+	EncryptionKeyID string `envconfig:"optional"`
 
 	MaxOpenConns    int           `envconfig:"default=8"`
 	MaxIdleConns    int           `envconfig:"default=2"`
 	ConnMaxLifetime time.Duration `envconfig:"default=30m"`
+
+	// ReadReplicaURL is the connection string of the read-only replica used by the reporting queries, if empty the queries use the primary database
+	ReadReplicaURL string `envconfig:"optional"`
+	// ReadReplicaMaxStaleness is the replication lag above which the reporting queries fall back to the primary database, 0 turns off the limit.
+	// The queries also fall back to the primary database when the replica is not available.
+	ReadReplicaMaxStaleness time.Duration `envconfig:"default=30s"`
 }
 
 func (cfg *Config) ConnectionURL() string {
@@ -14,6 +14,17 @@ type BindingDTO struct {
 	Kubeconfig        string
 	ExpirationSeconds int64
 	CreatedBy         string
+
+	State       string
+	Description string
+	OperationID string
+
+	CredentialsType string
+	Group           string `db:"oidc_group"`
+	AutoRenew       bool
+
+	Role       string
+	Namespaces string
 }
 
 type BindingStatsDTO struct {
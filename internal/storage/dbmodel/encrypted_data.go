@@ -0,0 +1,17 @@
+package dbmodel
+
+// EncryptedDataSource is the column which contains the encrypted data
+type EncryptedDataSource string
+
+const (
+	InstanceProvisioningParameters  EncryptedDataSource = "instances.provisioning_parameters"
+	OperationProvisioningParameters EncryptedDataSource = "operations.provisioning_parameters"
+	BindingKubeconfig               EncryptedDataSource = "bindings.kubeconfig"
+)
+
+// EncryptedDataDTO is the value of the column with the encrypted data, the InstanceID identifies the binding together with the ID
+type EncryptedDataDTO struct {
+	ID         string
+	InstanceID string
+	Value      string
+}
@@ -2,6 +2,8 @@ package dbmodel
 
 import (
 	"time"
+
+	"github.com/kyma-project/kyma-environment-broker/common/pagination"
 )
 
 type InstanceState string
@@ -22,6 +24,7 @@ const (
 type InstanceFilter struct {
 	PageSize                     int
 	Page                         int
+	After                        *pagination.Cursor // used instead of Page, the instances created after the cursor are returned
 	GlobalAccountIDs             []string
 	SubscriptionGlobalAccountIDs []string
 	SubAccountIDs                []string
@@ -36,6 +39,11 @@ type InstanceFilter struct {
 	DeletionAttempted            *bool
 	BindingExists                *bool
 	Suspended                    *bool
+	// Providers and the deprovisioning time range are supported only by the archived instances,
+	// the range includes DeprovisionedFrom and excludes DeprovisionedTo, the zero time is not set
+	Providers         []string
+	DeprovisionedFrom time.Time
+	DeprovisionedTo   time.Time
 }
 
 type InstanceDTO struct {
@@ -4,6 +4,7 @@ import (
 	"database/sql"
 	"time"
 
+	"github.com/kyma-project/kyma-environment-broker/common/pagination"
 	"github.com/kyma-project/kyma-environment-broker/internal"
 )
 
@@ -12,6 +13,7 @@ type OperationFilter struct {
 	InstanceFilter *InstanceFilter
 	Page           int
 	PageSize       int
+	After          *pagination.Cursor // used instead of Page, the operations created after the cursor are returned
 	States         []string
 }
 
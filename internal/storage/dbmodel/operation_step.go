@@ -0,0 +1,20 @@
+package dbmodel
+
+import "time"
+
+type OperationStepDTO struct {
+	ID          string
+	OperationID string
+	Stage       string
+	Step        string
+
+	StartedAt  time.Time
+	FinishedAt time.Time
+
+	Outcome           string
+	RetryAfterSeconds float64
+
+	ErrorReason    string
+	ErrorComponent string
+	ErrorMessage   string
+}
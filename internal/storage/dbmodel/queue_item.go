@@ -0,0 +1,15 @@
+package dbmodel
+
+import "time"
+
+type QueueItemDTO struct {
+	QueueName   string
+	OperationID string
+
+	NextRunAt      time.Time
+	LeaseOwner     string
+	LeaseExpiresAt time.Time
+
+	Version   int
+	CreatedAt time.Time
+}
@@ -32,6 +32,11 @@ func (a *Action) InsertAction(actionType runtime.ActionType, instanceID, message
 	return nil
 }
 
+func (a *Action) ImportAction(action runtime.Action) error {
+	a.actions = append(a.actions, action)
+	return nil
+}
+
 func (a *Action) ListActionsByInstanceID(instanceID string) ([]runtime.Action, error) {
 	filtered := make([]runtime.Action, 0)
 	for _, action := range a.actions {
@@ -44,3 +49,14 @@ func (a *Action) ListActionsByInstanceID(instanceID string) ([]runtime.Action, e
 	})
 	return filtered, nil
 }
+
+func (a *Action) DeleteByInstanceID(instanceID string) error {
+	kept := make([]runtime.Action, 0, len(a.actions))
+	for _, action := range a.actions {
+		if action.InstanceID != instanceID {
+			kept = append(kept, action)
+		}
+	}
+	a.actions = kept
+	return nil
+}
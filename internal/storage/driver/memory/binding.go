@@ -2,11 +2,13 @@ package memory
 
 import (
 	"fmt"
+	"sort"
 	"sync"
 	"time"
 
 	"github.com/kyma-project/kyma-environment-broker/internal"
 	"github.com/kyma-project/kyma-environment-broker/internal/storage/dberr"
+	"github.com/pivotal-cf/brokerapi/v12/domain"
 )
 
 type Binding struct {
@@ -105,6 +107,49 @@ func (s *Binding) ListExpired() ([]internal.Binding, error) {
 	return bindings, nil
 }
 
+func (s *Binding) GetByOperationID(operationID string) (*internal.Binding, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, binding := range s.data {
+		if binding.OperationID != "" && binding.OperationID == operationID {
+			return &binding, nil
+		}
+	}
+	return nil, dberr.NotFound("binding for the operation %s does not exist", operationID)
+}
+
+func (s *Binding) ListInProgress() ([]internal.Binding, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var bindings []internal.Binding
+	for _, binding := range s.data {
+		if binding.State == domain.InProgress && binding.OperationID != "" {
+			bindings = append(bindings, binding)
+		}
+	}
+
+	return bindings, nil
+}
+
+func (s *Binding) ListToRenew(expiringBefore time.Time) ([]internal.Binding, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var bindings []internal.Binding
+	for _, binding := range s.data {
+		if binding.AutoRenew && binding.State == domain.Succeeded && binding.ExpiresAt.After(time.Now()) && !binding.ExpiresAt.After(expiringBefore) {
+			bindings = append(bindings, binding)
+		}
+	}
+	sort.Slice(bindings, func(i, j int) bool {
+		return bindings[i].ExpiresAt.Before(bindings[j].ExpiresAt)
+	})
+
+	return bindings, nil
+}
+
 func (s *Binding) GetStatistics() (internal.BindingStats, error) {
 	return internal.BindingStats{}, fmt.Errorf("not implemented")
 }
@@ -0,0 +1,67 @@
+package memory
+
+import (
+	"sync"
+	"time"
+
+	"github.com/kyma-project/kyma-environment-broker/common/changes"
+	"github.com/kyma-project/kyma-environment-broker/internal"
+)
+
+// Changes keeps the changes recorded by the operations and the instances of the memory storage
+type Changes struct {
+	mu      sync.Mutex
+	changes []changes.ChangeDTO
+}
+
+func NewChanges() *Changes {
+	return &Changes{}
+}
+
+func (s *Changes) List(since int64, limit int) ([]changes.ChangeDTO, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result := make([]changes.ChangeDTO, 0)
+	for _, change := range s.changes {
+		if change.Sequence <= since {
+			continue
+		}
+		if len(result) == limit {
+			break
+		}
+		result = append(result, change)
+	}
+	return result, nil
+}
+
+func (s *Changes) add(change changes.ChangeDTO) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	change.Sequence = int64(len(s.changes) + 1)
+	change.CreatedAt = time.Now()
+	s.changes = append(s.changes, change)
+}
+
+func (s *Changes) addInstance(instanceID, state string) {
+	s.add(changes.ChangeDTO{Kind: changes.InstanceKind, InstanceID: instanceID, State: state})
+}
+
+// addOperation records the state of the operation, the change is not recorded if the state is not changed
+func (s *Changes) addOperation(operation internal.Operation, previous *internal.Operation) {
+	change := changes.ChangeDTO{
+		Kind:          changes.OperationKind,
+		InstanceID:    operation.InstanceID,
+		OperationID:   operation.ID,
+		OperationType: string(operation.Type),
+		State:         string(operation.State),
+	}
+	if previous != nil {
+		if previous.State == operation.State {
+			return
+		}
+		change.PreviousState = string(previous.State)
+	}
+	s.add(change)
+}
@@ -0,0 +1,21 @@
+package memory
+
+import (
+	"github.com/kyma-project/kyma-environment-broker/internal/storage/dberr"
+	"github.com/kyma-project/kyma-environment-broker/internal/storage/dbmodel"
+)
+
+// EncryptedData is empty, the memory storage keeps the data not encrypted
+type EncryptedData struct{}
+
+func NewEncryptedData() *EncryptedData {
+	return &EncryptedData{}
+}
+
+func (s *EncryptedData) List(_ dbmodel.EncryptedDataSource, _ dbmodel.EncryptedDataDTO, _ int) ([]dbmodel.EncryptedDataDTO, error) {
+	return []dbmodel.EncryptedDataDTO{}, nil
+}
+
+func (s *EncryptedData) Update(source dbmodel.EncryptedDataSource, item dbmodel.EncryptedDataDTO, _ string) error {
+	return dberr.NotFound("%s with ID %s does not exist", source, item.ID)
+}
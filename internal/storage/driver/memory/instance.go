@@ -6,6 +6,7 @@ import (
 	"sort"
 	"sync"
 
+	"github.com/kyma-project/kyma-environment-broker/common/changes"
 	"github.com/kyma-project/kyma-environment-broker/common/pagination"
 	"github.com/kyma-project/kyma-environment-broker/internal"
 	"github.com/kyma-project/kyma-environment-broker/internal/storage/dberr"
@@ -160,6 +161,7 @@ func (s *instances) Delete(instanceID string) error {
 	defer s.mu.Unlock()
 
 	delete(s.instances, instanceID)
+	s.operationsStorage.changes.addInstance(instanceID, changes.InstanceDeleted)
 	return nil
 }
 
@@ -167,6 +169,7 @@ func (s *instances) Insert(instance internal.Instance) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	s.instances[instance.InstanceID] = instance
+	s.operationsStorage.changes.addInstance(instance.InstanceID, changes.InstanceCreated)
 
 	return nil
 }
@@ -183,6 +186,9 @@ func (s *instances) Update(instance internal.Instance) (*internal.Instance, erro
 	}
 	instance.Version = instance.Version + 1
 	s.instances[instance.InstanceID] = instance
+	if instance.ChangedFrom(oldInst) {
+		s.operationsStorage.changes.addInstance(instance.InstanceID, changes.InstanceUpdated)
+	}
 
 	return &instance, nil
 }
@@ -227,6 +233,11 @@ func (s *instances) List(filter dbmodel.InstanceFilter) ([]internal.Instance, in
 
 	instances := s.filterInstances(filter)
 	sortInstancesByCreatedAt(instances)
+	total := len(instances)
+	if filter.After != nil {
+		instances = instancesAfter(instances, *filter.After)
+		offset = 0
+	}
 
 	for i := offset; (filter.PageSize < 1 || i < offset+filter.PageSize) && i < len(instances); i++ {
 		toReturn = append(toReturn, s.instances[instances[i].InstanceID])
@@ -234,7 +245,7 @@ func (s *instances) List(filter dbmodel.InstanceFilter) ([]internal.Instance, in
 
 	return toReturn,
 		len(toReturn),
-		len(instances),
+		total,
 		nil
 }
 
@@ -246,9 +257,12 @@ func (s *instances) ListWithSubaccountState(filter dbmodel.InstanceFilter) ([]in
 	offset := pagination.ConvertPageAndPageSizeToOffset(filter.PageSize, filter.Page)
 
 	instances := s.filterInstances(filter)
-	sort.Slice(instances, func(i, j int) bool {
-		return instances[i].CreatedAt.Before(instances[j].CreatedAt)
-	})
+	sortInstancesByCreatedAt(instances)
+	total := len(instances)
+	if filter.After != nil {
+		instances = instancesAfter(instances, *filter.After)
+		offset = 0
+	}
 
 	for i := offset; (filter.PageSize < 1 || i < offset+filter.PageSize) && i < len(instances); i++ {
 		instanceToReturn := s.instances[instances[i].InstanceID]
@@ -264,16 +278,28 @@ func (s *instances) ListWithSubaccountState(filter dbmodel.InstanceFilter) ([]in
 
 	return toReturn,
 		len(toReturn),
-		len(instances),
+		total,
 		nil
 }
 
 func sortInstancesByCreatedAt(instances []internal.Instance) {
 	sort.Slice(instances, func(i, j int) bool {
+		if instances[i].CreatedAt.Equal(instances[j].CreatedAt) {
+			return instances[i].InstanceID < instances[j].InstanceID
+		}
 		return instances[i].CreatedAt.Before(instances[j].CreatedAt)
 	})
 }
 
+func instancesAfter(instances []internal.Instance, cursor pagination.Cursor) []internal.Instance {
+	for i, instance := range instances {
+		if cursor.Precedes(instance.CreatedAt, instance.InstanceID) {
+			return instances[i:]
+		}
+	}
+	return nil
+}
+
 func (s *instances) filterInstances(filter dbmodel.InstanceFilter) []internal.Instance {
 	inst := make([]internal.Instance, 0, len(s.instances))
 	var ok bool
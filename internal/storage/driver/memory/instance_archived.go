@@ -111,6 +111,18 @@ func (s *InstanceArchivedInMemoryStorage) filterInstancesArchived(filter dbmodel
 		if ok = matchFilter(i.ShootName, filter.Shoots, equal); !ok {
 			continue
 		}
+		if ok = matchFilter(i.PlanID, filter.PlanIDs, equal); !ok {
+			continue
+		}
+		if ok = matchFilter(i.Provider, filter.Providers, equal); !ok {
+			continue
+		}
+		if !filter.DeprovisionedFrom.IsZero() && i.LastDeprovisioningFinishedAt.Before(filter.DeprovisionedFrom) {
+			continue
+		}
+		if !filter.DeprovisionedTo.IsZero() && !i.LastDeprovisioningFinishedAt.Before(filter.DeprovisionedTo) {
+			continue
+		}
 
 		instancesArchived = append(instancesArchived, i)
 	}
@@ -2,6 +2,7 @@ package memory
 
 import (
 	"fmt"
+	"slices"
 	"sort"
 	"sync"
 	"time"
@@ -20,6 +21,7 @@ type operations struct {
 	operations               map[string]internal.Operation
 	upgradeClusterOperations map[string]internal.UpgradeClusterOperation
 	updateOperations         map[string]internal.UpdatingOperation
+	changes                  *Changes
 }
 
 // NewOperation creates in-memory storage for OSB operations.
@@ -28,9 +30,15 @@ func NewOperation() *operations {
 		operations:               make(map[string]internal.Operation, 0),
 		upgradeClusterOperations: make(map[string]internal.UpgradeClusterOperation, 0),
 		updateOperations:         make(map[string]internal.UpdatingOperation, 0),
+		changes:                  NewChanges(),
 	}
 }
 
+// Changes returns the changes recorded by the operations and the instances using the storage
+func (s *operations) Changes() *Changes {
+	return s.changes
+}
+
 func (s *operations) DeleteByID(operationID string) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -49,6 +57,7 @@ func (s *operations) InsertProvisioningOperation(operation internal.Provisioning
 	}
 
 	s.operations[id] = operation.Operation
+	s.changes.addOperation(operation.Operation, nil)
 	return nil
 }
 
@@ -62,6 +71,7 @@ func (s *operations) InsertOperation(operation internal.Operation) error {
 	}
 
 	s.operations[id] = operation
+	s.changes.addOperation(operation, nil)
 	return nil
 }
 
@@ -127,6 +137,7 @@ func (s *operations) UpdateProvisioningOperation(op internal.ProvisioningOperati
 	}
 	op.Version = op.Version + 1
 	s.operations[op.ID] = op.Operation
+	s.changes.addOperation(op.Operation, &oldOp)
 
 	return &op, nil
 }
@@ -144,6 +155,7 @@ func (s *operations) UpdateOperation(op internal.Operation) (*internal.Operation
 	}
 	op.Version = op.Version + 1
 	s.operations[op.ID] = op
+	s.changes.addOperation(op, &oldOp)
 
 	return &op, nil
 }
@@ -249,6 +261,7 @@ func (s *operations) InsertDeprovisioningOperation(operation internal.Deprovisio
 	}
 
 	s.operations[id] = operation.Operation
+	s.changes.addOperation(operation.Operation, nil)
 	return nil
 }
 
@@ -295,6 +308,7 @@ func (s *operations) UpdateDeprovisioningOperation(op internal.DeprovisioningOpe
 	}
 	op.Version = op.Version + 1
 	s.operations[op.ID] = op.Operation
+	s.changes.addOperation(op.Operation, &oldOp)
 
 	return &op, nil
 }
@@ -309,6 +323,7 @@ func (s *operations) InsertUpgradeClusterOperation(operation internal.UpgradeClu
 	}
 
 	s.upgradeClusterOperations[id] = operation
+	s.changes.addOperation(operation.Operation, nil)
 	return nil
 }
 
@@ -336,6 +351,7 @@ func (s *operations) UpdateUpgradeClusterOperation(op internal.UpgradeClusterOpe
 	}
 	op.Version = op.Version + 1
 	s.upgradeClusterOperations[op.Operation.ID] = op
+	s.changes.addOperation(op.Operation, &oldOp.Operation)
 
 	return &op, nil
 }
@@ -491,13 +507,13 @@ func (s *operations) GetNotFinishedOperationsByType(opType internal.OperationTyp
 	switch opType {
 	case internal.OperationTypeProvision:
 		for _, op := range s.operations {
-			if op.State == domain.InProgress {
+			if op.State == domain.InProgress || op.State == internal.OperationStateCanceling {
 				ops = append(ops, op)
 			}
 		}
 	case internal.OperationTypeDeprovision:
 		for _, op := range s.operations {
-			if op.State == domain.InProgress {
+			if op.State == domain.InProgress || op.State == internal.OperationStateCanceling {
 				ops = append(ops, op)
 			}
 		}
@@ -610,14 +626,21 @@ func (s *operations) ListOperations(filter dbmodel.OperationFilter) ([]internal.
 		return nil, 0, 0, fmt.Errorf("while listing operations: %w", err)
 	}
 	s.sortByCreatedAt(operations)
+	total := len(operations)
+	if filter.After != nil {
+		operations = slices.DeleteFunc(operations, func(op internal.Operation) bool {
+			return !filter.After.Precedes(op.CreatedAt, op.ID)
+		})
+		offset = 0
+	}
 
-	for i := offset; (filter.PageSize < 1 || i < offset+filter.PageSize) && i < len(operations)+offset; i++ {
+	for i := offset; (filter.PageSize < 1 || i < offset+filter.PageSize) && i < len(operations); i++ {
 		result = append(result, operations[i])
 	}
 
 	return result,
 		len(result),
-		len(operations),
+		total,
 		nil
 }
 
@@ -646,6 +669,7 @@ func (s *operations) InsertUpdatingOperation(operation internal.UpdatingOperatio
 	}
 
 	s.updateOperations[id] = operation
+	s.changes.addOperation(operation.Operation, nil)
 	return nil
 }
 
@@ -679,6 +703,7 @@ func (s *operations) UpdateUpdatingOperation(op internal.UpdatingOperation) (*in
 	}
 	op.Version = op.Version + 1
 	s.updateOperations[op.ID] = op
+	s.changes.addOperation(op.Operation, &oldOp.Operation)
 
 	return &op, nil
 }
@@ -741,6 +766,9 @@ func (s *operations) sortDeprovisioningByCreatedAtDesc(operations []internal.Dep
 
 func (s *operations) sortByCreatedAt(operations []internal.Operation) {
 	sort.Slice(operations, func(i, j int) bool {
+		if operations[i].CreatedAt.Equal(operations[j].CreatedAt) {
+			return operations[i].ID < operations[j].ID
+		}
 		return operations[i].CreatedAt.Before(operations[j].CreatedAt)
 	})
 }
@@ -0,0 +1,57 @@
+package memory
+
+import (
+	"slices"
+	"sort"
+	"sync"
+
+	"github.com/kyma-project/kyma-environment-broker/common/runtime"
+
+	"github.com/google/uuid"
+)
+
+type OperationSteps struct {
+	mu    sync.Mutex
+	steps []runtime.OperationStep
+}
+
+func NewOperationSteps() *OperationSteps {
+	return &OperationSteps{
+		steps: make([]runtime.OperationStep, 0),
+	}
+}
+
+func (s *OperationSteps) Insert(step runtime.OperationStep) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	step.ID = uuid.NewString()
+	s.steps = append(s.steps, step)
+	return nil
+}
+
+func (s *OperationSteps) ListByOperationIDs(operationIDs []string) ([]runtime.OperationStep, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	filtered := make([]runtime.OperationStep, 0)
+	for _, step := range s.steps {
+		if slices.Contains(operationIDs, step.OperationID) {
+			filtered = append(filtered, step)
+		}
+	}
+	sort.SliceStable(filtered, func(i, j int) bool {
+		return filtered[i].StartedAt.Before(filtered[j].StartedAt)
+	})
+	return filtered, nil
+}
+
+func (s *OperationSteps) DeleteByOperationID(operationID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.steps = slices.DeleteFunc(s.steps, func(step runtime.OperationStep) bool {
+		return step.OperationID == operationID
+	})
+	return nil
+}
@@ -0,0 +1,156 @@
+package memory
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/kyma-project/kyma-environment-broker/internal"
+	"github.com/kyma-project/kyma-environment-broker/internal/storage/dberr"
+)
+
+type queueItemKey struct {
+	queueName   string
+	operationID string
+}
+
+type QueueItems struct {
+	mu    sync.Mutex
+	items map[queueItemKey]internal.QueueItem
+}
+
+func NewQueueItems() *QueueItems {
+	return &QueueItems{
+		items: make(map[queueItemKey]internal.QueueItem),
+	}
+}
+
+func (q *QueueItems) Schedule(queueName, operationID string, runAt time.Time) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	key := queueItemKey{queueName: queueName, operationID: operationID}
+	item, exists := q.items[key]
+	if !exists {
+		q.items[key] = internal.QueueItem{
+			QueueName:   queueName,
+			OperationID: operationID,
+			NextRunAt:   runAt,
+			CreatedAt:   time.Now(),
+		}
+		return nil
+	}
+	if runAt.Before(item.NextRunAt) {
+		item.NextRunAt = runAt
+	}
+	item.Version = item.Version + 1
+	q.items[key] = item
+	return nil
+}
+
+func (q *QueueItems) ScheduleIfAbsent(queueName, operationID string, runAt time.Time) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	key := queueItemKey{queueName: queueName, operationID: operationID}
+	if _, exists := q.items[key]; exists {
+		return nil
+	}
+	q.items[key] = internal.QueueItem{
+		QueueName:   queueName,
+		OperationID: operationID,
+		NextRunAt:   runAt,
+		CreatedAt:   time.Now(),
+	}
+	return nil
+}
+
+func (q *QueueItems) Lease(queueName, owner string, leaseDuration time.Duration) (*internal.QueueItem, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	now := time.Now()
+	var due []internal.QueueItem
+	for _, item := range q.items {
+		if item.QueueName == queueName && !item.NextRunAt.After(now) && item.LeaseExpiresAt.Before(now) {
+			due = append(due, item)
+		}
+	}
+	if len(due) == 0 {
+		return nil, dberr.NotFound("no queue items to process in queue %s", queueName)
+	}
+	sort.Slice(due, func(i, j int) bool {
+		return due[i].NextRunAt.Before(due[j].NextRunAt)
+	})
+
+	item := due[0]
+	item.LeaseOwner = owner
+	item.LeaseExpiresAt = now.Add(leaseDuration)
+	q.items[queueItemKey{queueName: item.QueueName, operationID: item.OperationID}] = item
+	return &item, nil
+}
+
+func (q *QueueItems) ExtendLease(queueName, owner string, leaseDuration time.Duration) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for key, item := range q.items {
+		if item.QueueName == queueName && item.LeaseOwner == owner {
+			item.LeaseExpiresAt = time.Now().Add(leaseDuration)
+			q.items[key] = item
+		}
+	}
+	return nil
+}
+
+func (q *QueueItems) Complete(item internal.QueueItem) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	key := queueItemKey{queueName: item.QueueName, operationID: item.OperationID}
+	stored, exists := q.items[key]
+	if !exists || stored.LeaseOwner != item.LeaseOwner {
+		return nil
+	}
+	if stored.Version == item.Version {
+		delete(q.items, key)
+		return nil
+	}
+	q.items[key] = released(stored)
+	return nil
+}
+
+func (q *QueueItems) Reschedule(item internal.QueueItem, runAt time.Time) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	key := queueItemKey{queueName: item.QueueName, operationID: item.OperationID}
+	stored, exists := q.items[key]
+	if !exists || stored.LeaseOwner != item.LeaseOwner {
+		return nil
+	}
+	if stored.Version == item.Version {
+		stored.NextRunAt = runAt
+	}
+	q.items[key] = released(stored)
+	return nil
+}
+
+func (q *QueueItems) Count(queueName string) (int, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	count := 0
+	for _, item := range q.items {
+		if item.QueueName == queueName {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func released(item internal.QueueItem) internal.QueueItem {
+	item.LeaseOwner = ""
+	item.LeaseExpiresAt = time.Time{}
+	return item
+}
@@ -0,0 +1,56 @@
+package memory
+
+import (
+	"sort"
+	"time"
+
+	"github.com/kyma-project/kyma-environment-broker/common/events"
+)
+
+// Retention lists the instances of the memory storage, the memory storage does not keep the events and the changes
+type Retention struct {
+	instances *instances
+}
+
+func NewRetention(instances *instances) *Retention {
+	return &Retention{
+		instances: instances,
+	}
+}
+
+func (s *Retention) ListInstanceIDs(after string, limit int) ([]string, error) {
+	s.instances.mu.Lock()
+	defer s.instances.mu.Unlock()
+
+	ids := make([]string, 0)
+	for id := range s.instances.instances {
+		if id > after {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	if len(ids) > limit {
+		ids = ids[:limit]
+	}
+	return ids, nil
+}
+
+func (s *Retention) CountEvents(_ events.EventLevel, _ time.Time) (int, error) {
+	return 0, nil
+}
+
+func (s *Retention) DeleteEvents(_ events.EventLevel, _ time.Time) (int, error) {
+	return 0, nil
+}
+
+func (s *Retention) DeleteInstanceEvents(_ string) error {
+	return nil
+}
+
+func (s *Retention) CountChanges(_ time.Time) (int, error) {
+	return 0, nil
+}
+
+func (s *Retention) DeleteChanges(_ time.Time) (int, error) {
+	return 0, nil
+}
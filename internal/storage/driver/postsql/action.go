@@ -19,6 +19,14 @@ func (a *Action) InsertAction(actionType runtime.ActionType, instanceID, message
 	return a.Factory.NewWriteSession().InsertAction(actionType, instanceID, message, oldValue, newValue)
 }
 
+func (a *Action) ImportAction(action runtime.Action) error {
+	return a.Factory.NewWriteSession().ImportAction(action)
+}
+
 func (a *Action) ListActionsByInstanceID(instanceID string) ([]runtime.Action, error) {
 	return a.Factory.NewReadSession().ListActions(instanceID)
 }
+
+func (a *Action) DeleteByInstanceID(instanceID string) error {
+	return a.Factory.NewWriteSession().DeleteActions(instanceID)
+}
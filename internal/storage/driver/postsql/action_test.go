@@ -2,6 +2,7 @@ package postsql_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/kyma-project/kyma-environment-broker/common/runtime"
 	"github.com/kyma-project/kyma-environment-broker/internal/fixture"
@@ -61,3 +62,33 @@ func TestAction(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Len(t, actions, 2)
 }
+
+func TestAction_Import(t *testing.T) {
+	storageCleanup, brokerStorage, err := GetStorageForDatabaseTests()
+	require.NoError(t, err)
+	require.NotNil(t, brokerStorage)
+	defer func() {
+		err := storageCleanup()
+		assert.NoError(t, err)
+	}()
+	instanceID := "instance-id"
+	createdAt := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	err = brokerStorage.Actions().ImportAction(runtime.Action{
+		ID:         "action-id",
+		Type:       runtime.PlanUpdateActionType,
+		InstanceID: instanceID,
+		Message:    "test-message",
+		OldValue:   "old-value",
+		NewValue:   "new-value",
+		CreatedAt:  createdAt,
+	})
+	require.NoError(t, err)
+
+	actions, err := brokerStorage.Actions().ListActionsByInstanceID(instanceID)
+	require.NoError(t, err)
+	require.Len(t, actions, 1)
+	assert.Equal(t, "action-id", actions[0].ID)
+	assert.Equal(t, "test-message", actions[0].Message)
+	assert.True(t, createdAt.Equal(actions[0].CreatedAt))
+}
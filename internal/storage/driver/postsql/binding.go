@@ -2,11 +2,14 @@ package postsql
 
 import (
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/kyma-project/kyma-environment-broker/internal"
 	"github.com/kyma-project/kyma-environment-broker/internal/storage/dberr"
 	"github.com/kyma-project/kyma-environment-broker/internal/storage/dbmodel"
 	"github.com/kyma-project/kyma-environment-broker/internal/storage/postsql"
+	"github.com/pivotal-cf/brokerapi/v12/domain"
 )
 
 type Binding struct {
@@ -80,6 +83,57 @@ func (s *Binding) Delete(instanceID, bindingID string) error {
 	return sess.DeleteBinding(instanceID, bindingID)
 }
 
+func (s *Binding) GetByOperationID(operationID string) (*internal.Binding, error) {
+	bindingDTO, dbErr := s.Factory.NewReadSession().GetBindingByOperationID(operationID)
+	if dbErr != nil {
+		if dberr.IsNotFound(dbErr) {
+			return nil, dberr.NotFound("Binding for the operation %s does not exist", operationID)
+		}
+		return nil, fmt.Errorf("while getting bindingDTO by operation ID %s: %w", operationID, dbErr)
+	}
+
+	binding, err := s.toBinding(bindingDTO)
+	if err != nil {
+		return nil, err
+	}
+
+	return &binding, nil
+}
+
+func (s *Binding) ListInProgress() ([]internal.Binding, error) {
+	dtos, err := s.Factory.NewReadSession().ListBindingsInProgress()
+	if err != nil {
+		return []internal.Binding{}, err
+	}
+	var bindings []internal.Binding
+	for _, dto := range dtos {
+		binding, err := s.toBinding(dto)
+		if err != nil {
+			return []internal.Binding{}, err
+		}
+
+		bindings = append(bindings, binding)
+	}
+	return bindings, nil
+}
+
+func (s *Binding) ListToRenew(expiringBefore time.Time) ([]internal.Binding, error) {
+	dtos, err := s.Factory.NewReadSession().ListBindingsToRenew(expiringBefore)
+	if err != nil {
+		return []internal.Binding{}, err
+	}
+	var bindings []internal.Binding
+	for _, dto := range dtos {
+		binding, err := s.toBinding(dto)
+		if err != nil {
+			return []internal.Binding{}, err
+		}
+
+		bindings = append(bindings, binding)
+	}
+	return bindings, nil
+}
+
 func (s *Binding) ListByInstanceID(instanceID string) ([]internal.Binding, error) {
 	dtos, err := s.Factory.NewReadSession().ListBindings(instanceID)
 	if err != nil {
@@ -137,6 +191,14 @@ func (s *Binding) toBindingDTO(binding *internal.Binding) (dbmodel.BindingDTO, e
 		ExpirationSeconds: binding.ExpirationSeconds,
 		CreatedBy:         binding.CreatedBy,
 		ExpiresAt:         binding.ExpiresAt,
+		State:             string(binding.State),
+		Description:       binding.Description,
+		OperationID:       binding.OperationID,
+		CredentialsType:   binding.CredentialsType,
+		Group:             binding.Group,
+		AutoRenew:         binding.AutoRenew,
+		Role:              binding.Role,
+		Namespaces:        strings.Join(binding.Namespaces, ","),
 	}, nil
 }
 
@@ -146,6 +208,13 @@ func (s *Binding) toBinding(dto dbmodel.BindingDTO) (internal.Binding, error) {
 		return internal.Binding{}, fmt.Errorf("while decrypting kubeconfig: %w", err)
 	}
 
+	namespaces := make([]string, 0)
+	for _, namespace := range strings.Split(dto.Namespaces, ",") {
+		if namespace != "" {
+			namespaces = append(namespaces, namespace)
+		}
+	}
+
 	return internal.Binding{
 		Kubeconfig:        string(decrypted),
 		ID:                dto.ID,
@@ -154,6 +223,14 @@ func (s *Binding) toBinding(dto dbmodel.BindingDTO) (internal.Binding, error) {
 		ExpirationSeconds: dto.ExpirationSeconds,
 		CreatedBy:         dto.CreatedBy,
 		ExpiresAt:         dto.ExpiresAt,
+		State:             domain.LastOperationState(dto.State),
+		Description:       dto.Description,
+		OperationID:       dto.OperationID,
+		CredentialsType:   dto.CredentialsType,
+		Group:             dto.Group,
+		AutoRenew:         dto.AutoRenew,
+		Role:              dto.Role,
+		Namespaces:        namespaces,
 	}, nil
 }
 
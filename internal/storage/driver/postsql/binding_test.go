@@ -6,7 +6,9 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/kyma-project/kyma-environment-broker/internal"
 	"github.com/kyma-project/kyma-environment-broker/internal/fixture"
+	"github.com/pivotal-cf/brokerapi/v12/domain"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -298,3 +300,77 @@ func TestBinding_ModeGCM(t *testing.T) {
 	assert.NotNil(t, retrievedBinding)
 	assert.Equal(t, fixedBinding.Kubeconfig, retrievedBinding.Kubeconfig)
 }
+
+func TestBinding_ListToRenew(t *testing.T) {
+	storageCleanup, brokerStorage, err := GetStorageForDatabaseTests()
+	require.NoError(t, err)
+	require.NotNil(t, brokerStorage)
+	defer func() {
+		err := storageCleanup()
+		assert.NoError(t, err)
+	}()
+
+	// given
+	expiring := fixture.FixBinding("expiring")
+	expiring.AutoRenew = true
+	expiring.State = domain.Succeeded
+	expiring.ExpiresAt = time.Now().Add(2 * time.Minute)
+	notMarked := fixture.FixBinding("not-marked")
+	notMarked.State = domain.Succeeded
+	notMarked.ExpiresAt = time.Now().Add(2 * time.Minute)
+	notExpiring := fixture.FixBinding("not-expiring")
+	notExpiring.AutoRenew = true
+	notExpiring.State = domain.Succeeded
+	notExpiring.ExpiresAt = time.Now().Add(time.Hour)
+	expired := fixture.FixBinding("expired")
+	expired.AutoRenew = true
+	expired.State = domain.Succeeded
+	expired.ExpiresAt = time.Now().Add(-time.Minute)
+	inProgress := fixture.FixBinding("in-progress")
+	inProgress.AutoRenew = true
+	inProgress.State = domain.InProgress
+	inProgress.ExpiresAt = time.Now().Add(2 * time.Minute)
+
+	for _, binding := range []internal.Binding{expiring, notMarked, notExpiring, expired, inProgress} {
+		require.NoError(t, brokerStorage.Bindings().Insert(&binding))
+	}
+
+	// when
+	got, err := brokerStorage.Bindings().ListToRenew(time.Now().Add(5 * time.Minute))
+
+	// then
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	assert.Equal(t, "expiring", got[0].ID)
+	assert.True(t, got[0].AutoRenew)
+}
+
+func TestBinding_RBAC(t *testing.T) {
+	storageCleanup, brokerStorage, err := GetStorageForDatabaseTests()
+	require.NoError(t, err)
+	require.NotNil(t, brokerStorage)
+	defer func() {
+		err := storageCleanup()
+		assert.NoError(t, err)
+	}()
+
+	// given
+	scoped := fixture.FixBinding("scoped")
+	scoped.Role = "namespace-admin"
+	scoped.Namespaces = []string{"team-a", "team-b"}
+	clusterWide := fixture.FixBinding("cluster-wide")
+	require.NoError(t, brokerStorage.Bindings().Insert(&scoped))
+	require.NoError(t, brokerStorage.Bindings().Insert(&clusterWide))
+
+	// when
+	gotScoped, err := brokerStorage.Bindings().Get(scoped.InstanceID, scoped.ID)
+	require.NoError(t, err)
+	gotClusterWide, err := brokerStorage.Bindings().Get(clusterWide.InstanceID, clusterWide.ID)
+	require.NoError(t, err)
+
+	// then
+	assert.Equal(t, "namespace-admin", gotScoped.Role)
+	assert.Equal(t, []string{"team-a", "team-b"}, gotScoped.Namespaces)
+	assert.Empty(t, gotClusterWide.Role)
+	assert.Empty(t, gotClusterWide.Namespaces)
+}
@@ -0,0 +1,20 @@
+package postsql
+
+import (
+	"github.com/kyma-project/kyma-environment-broker/common/changes"
+	"github.com/kyma-project/kyma-environment-broker/internal/storage/postsql"
+)
+
+type Changes struct {
+	postsql.Factory
+}
+
+func NewChanges(sess postsql.Factory) *Changes {
+	return &Changes{
+		Factory: sess,
+	}
+}
+
+func (s *Changes) List(since int64, limit int) ([]changes.ChangeDTO, error) {
+	return s.Factory.NewReadSession().ListChanges(since, limit)
+}
@@ -0,0 +1,100 @@
+package postsql_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kyma-project/kyma-environment-broker/common/changes"
+	"github.com/kyma-project/kyma-environment-broker/internal"
+	"github.com/kyma-project/kyma-environment-broker/internal/fixture"
+	"github.com/pivotal-cf/brokerapi/v12/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChanges(t *testing.T) {
+	storageCleanup, brokerStorage, err := GetStorageForDatabaseTests()
+	require.NoError(t, err)
+	require.NotNil(t, brokerStorage)
+	defer func() {
+		err := storageCleanup()
+		assert.NoError(t, err)
+	}()
+
+	t.Run("should record the state transitions of the instance and the operation", func(t *testing.T) {
+		// given
+		instance := fixture.FixInstance("instance-01")
+		require.NoError(t, brokerStorage.Instances().Insert(instance))
+		operation := fixture.FixOperation("op-01", instance.InstanceID, internal.OperationTypeProvision)
+		operation.State = domain.InProgress
+		require.NoError(t, brokerStorage.Operations().InsertOperation(operation))
+
+		// when
+		updated, err := brokerStorage.Operations().UpdateOperation(operation)
+		require.NoError(t, err)
+		updated.State = domain.Succeeded
+		_, err = brokerStorage.Operations().UpdateOperation(*updated)
+		require.NoError(t, err)
+		_, err = brokerStorage.Operations().UpdateOperation(operation)
+		require.Error(t, err)
+		require.NoError(t, brokerStorage.Instances().Delete(instance.InstanceID))
+
+		// then
+		list, err := brokerStorage.Changes().List(0, 10)
+		require.NoError(t, err)
+		require.Len(t, list, 4)
+		assert.Equal(t, changes.InstanceCreated, list[0].State)
+		assert.Equal(t, string(domain.InProgress), list[1].State)
+		assert.Equal(t, "op-01", list[2].OperationID)
+		assert.Equal(t, string(internal.OperationTypeProvision), list[2].OperationType)
+		assert.Equal(t, string(domain.Succeeded), list[2].State)
+		assert.Equal(t, string(domain.InProgress), list[2].PreviousState)
+		assert.Equal(t, changes.InstanceDeleted, list[3].State)
+		for i := 1; i < len(list); i++ {
+			assert.Greater(t, list[i].Sequence, list[i-1].Sequence)
+		}
+
+		next, err := brokerStorage.Changes().List(list[1].Sequence, 1)
+		require.NoError(t, err)
+		require.Len(t, next, 1)
+		assert.Equal(t, list[2].Sequence, next[0].Sequence)
+	})
+
+	t.Run("should count and delete the changes", func(t *testing.T) {
+		// when
+		count, err := brokerStorage.Retention().CountChanges(time.Now())
+		require.NoError(t, err)
+		deleted, err := brokerStorage.Retention().DeleteChanges(time.Now())
+		require.NoError(t, err)
+
+		// then
+		assert.Equal(t, 4, count)
+		assert.Equal(t, 4, deleted)
+	})
+
+	t.Run("should record the update of the instance only when the instance is changed", func(t *testing.T) {
+		// given
+		instance := fixture.FixInstance("instance-02")
+		require.NoError(t, brokerStorage.Instances().Insert(instance))
+		created, err := brokerStorage.Changes().List(0, 10)
+		require.NoError(t, err)
+		require.Len(t, created, 1)
+
+		// when
+		updated, err := brokerStorage.Instances().Update(instance)
+		require.NoError(t, err)
+		updated.UpdatedAt = time.Now()
+		updated, err = brokerStorage.Instances().Update(*updated)
+		require.NoError(t, err)
+		updated.ServicePlanName = "other-plan"
+		_, err = brokerStorage.Instances().Update(*updated)
+		require.NoError(t, err)
+
+		// then
+		list, err := brokerStorage.Changes().List(created[0].Sequence, 10)
+		require.NoError(t, err)
+		require.Len(t, list, 1)
+		assert.Equal(t, changes.InstanceUpdated, list[0].State)
+	})
+}
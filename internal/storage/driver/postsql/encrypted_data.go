@@ -0,0 +1,32 @@
+package postsql
+
+import (
+	"github.com/kyma-project/kyma-environment-broker/internal/storage/dberr"
+	"github.com/kyma-project/kyma-environment-broker/internal/storage/dbmodel"
+	"github.com/kyma-project/kyma-environment-broker/internal/storage/postsql"
+)
+
+type EncryptedData struct {
+	postsql.Factory
+}
+
+func NewEncryptedData(sess postsql.Factory) *EncryptedData {
+	return &EncryptedData{
+		Factory: sess,
+	}
+}
+
+func (s *EncryptedData) List(source dbmodel.EncryptedDataSource, after dbmodel.EncryptedDataDTO, limit int) ([]dbmodel.EncryptedDataDTO, error) {
+	return s.Factory.NewReadSession().ListEncryptedData(source, after, limit)
+}
+
+func (s *EncryptedData) Update(source dbmodel.EncryptedDataSource, item dbmodel.EncryptedDataDTO, value string) error {
+	updated, err := s.Factory.NewWriteSession().UpdateEncryptedData(source, item, value)
+	if err != nil {
+		return err
+	}
+	if !updated {
+		return dberr.Conflict("the value of %s with ID %s was changed", source, item.ID)
+	}
+	return nil
+}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	eventsapi "github.com/kyma-project/kyma-environment-broker/common/events"
+	"github.com/kyma-project/kyma-environment-broker/internal/ptr"
 	"github.com/kyma-project/kyma-environment-broker/internal/storage/postsql"
 )
 
@@ -25,16 +26,27 @@ func (e *events) ListEvents(filter eventsapi.EventFilter) ([]eventsapi.EventDTO,
 	return sess.ListEvents(filter)
 }
 
-func (e *events) InsertEvent(eventLevel eventsapi.EventLevel, message, instanceID, operationID string) {
+func (e *events) InsertEvent(event eventsapi.EventDTO) {
 	if e == nil {
 		return
 	}
 	sess := e.Factory.NewWriteSession()
-	if err := sess.InsertEvent(eventLevel, message, instanceID, operationID); err != nil {
-		slog.Error(fmt.Sprintf("failed to insert event [%v] instanceID=%v/operationID=%v %q", eventLevel, instanceID, operationID, message))
+	if err := sess.InsertEvent(event); err != nil {
+		slog.Error(fmt.Sprintf("failed to insert event [%v] instanceID=%v/operationID=%v %q: %v", event.Level, ptr.ToString(event.InstanceID), ptr.ToString(event.OperationID), event.Message, err))
 	}
 }
 
+func (e *events) ImportEvent(event eventsapi.EventDTO) error {
+	if e == nil {
+		return fmt.Errorf("events are disabled")
+	}
+	sess := e.Factory.NewWriteSession()
+	if err := sess.ImportEvent(event); err != nil {
+		return fmt.Errorf("while importing event %s: %w", event.ID, err)
+	}
+	return nil
+}
+
 func (e *events) RunGarbageCollection(pollingPeriod, retention time.Duration) {
 	if e == nil {
 		return
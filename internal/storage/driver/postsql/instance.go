@@ -6,9 +6,11 @@ import (
 	"fmt"
 	"log/slog"
 	"strings"
+	"time"
 
 	"github.com/pivotal-cf/brokerapi/v12/domain"
 
+	"github.com/kyma-project/kyma-environment-broker/common/changes"
 	pkg "github.com/kyma-project/kyma-environment-broker/common/runtime"
 	"github.com/kyma-project/kyma-environment-broker/internal"
 	"github.com/kyma-project/kyma-environment-broker/internal/storage/dberr"
@@ -273,9 +275,10 @@ func (s *Instance) Insert(instance internal.Instance) error {
 		return err
 	}
 
-	sess := s.Factory.NewWriteSession()
 	return wait.PollUntilContextTimeout(context.Background(), defaultRetryInterval, defaultRetryTimeout, true, func(ctx context.Context) (bool, error) {
-		err := sess.InsertInstance(dto)
+		err := s.writeWithChange(instance.InstanceID, changes.InstanceCreated, func(sess postsql.WriteSession) dberr.Error {
+			return sess.InsertInstance(dto)
+		})
 		if err != nil {
 			return false, nil
 		}
@@ -284,14 +287,21 @@ func (s *Instance) Insert(instance internal.Instance) error {
 }
 
 func (s *Instance) Update(instance internal.Instance) (*internal.Instance, error) {
-	sess := s.Factory.NewWriteSession()
+	// the parameters are compared before they are encrypted
+	changed := s.changed(instance)
 	dto, err := s.toInstanceDTO(instance)
 	if err != nil {
 		return nil, err
 	}
 	var lastErr dberr.Error
 	err = wait.PollUntilContextTimeout(context.Background(), defaultRetryInterval, defaultRetryTimeout, true, func(ctx context.Context) (bool, error) {
-		lastErr = sess.UpdateInstance(dto)
+		if changed {
+			lastErr = s.writeWithChange(instance.InstanceID, changes.InstanceUpdated, func(sess postsql.WriteSession) dberr.Error {
+				return sess.UpdateInstance(dto)
+			})
+		} else {
+			lastErr = s.Factory.NewWriteSession().UpdateInstance(dto)
+		}
 
 		switch {
 		case dberr.IsNotFound(lastErr):
@@ -318,6 +328,19 @@ func (s *Instance) Update(instance internal.Instance) (*internal.Instance, error
 	return &instance, nil
 }
 
+// changed returns true if the update modifies the stored instance, the change is recorded when the stored instance cannot be read
+func (s *Instance) changed(instance internal.Instance) bool {
+	dto, dbErr := s.Factory.NewReadSession().GetInstanceByID(instance.InstanceID)
+	if dbErr != nil {
+		return true
+	}
+	stored, err := s.toInstance(dto)
+	if err != nil {
+		return true
+	}
+	return instance.ChangedFrom(stored)
+}
+
 func (s *Instance) toInstanceDTO(instance internal.Instance) (dbmodel.InstanceDTO, error) {
 	err := s.cipher.EncryptSMCredentials(&instance.Parameters)
 	if err != nil {
@@ -357,8 +380,32 @@ func (s *Instance) toInstanceDTO(instance internal.Instance) (dbmodel.InstanceDT
 }
 
 func (s *Instance) Delete(instanceID string) error {
-	sess := s.Factory.NewWriteSession()
-	return sess.DeleteInstance(instanceID)
+	return s.writeWithChange(instanceID, changes.InstanceDeleted, func(sess postsql.WriteSession) dberr.Error {
+		return sess.DeleteInstance(instanceID)
+	})
+}
+
+// writeWithChange runs the write of the instance and records the change in one transaction
+func (s *Instance) writeWithChange(instanceID, state string, write func(sess postsql.WriteSession) dberr.Error) dberr.Error {
+	sess, err := s.Factory.NewSessionWithinTransaction()
+	if err != nil {
+		return err
+	}
+	defer sess.RollbackUnlessCommitted()
+
+	err = sess.InsertChange(changes.ChangeDTO{
+		Kind:       changes.InstanceKind,
+		InstanceID: instanceID,
+		State:      state,
+		CreatedAt:  time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+	if err := write(sess); err != nil {
+		return err
+	}
+	return sess.Commit()
 }
 
 func (s *Instance) GetUpdatesStats() (internal.UpdateStats, internal.UpdateStats, error) {
@@ -388,6 +388,49 @@ func TestInstanceArchived(t *testing.T) {
 
 		assertInstanceArchived(t, givenInstance5, out[0])
 	})
+
+	t.Run("Should list instances based on provider and deprovisioning time", func(t *testing.T) {
+		// given
+		givenInstance1 := fixInstanceArchive(instanceArchiveData{InstanceID: "instance-id1", GlobalAccountID: "gaidA"})
+		givenInstance2 := fixInstanceArchive(instanceArchiveData{InstanceID: "instance-id2", GlobalAccountID: "gaidA"})
+		givenInstance2.Provider = "aws"
+		givenInstance3 := fixInstanceArchive(instanceArchiveData{InstanceID: "instance-id3", GlobalAccountID: "gaidA"})
+		givenInstance3.LastDeprovisioningFinishedAt = givenInstance3.LastDeprovisioningFinishedAt.Add(30 * 24 * time.Hour)
+
+		storageCleanup, brokerStorage, err := GetStorageForDatabaseTests()
+		require.NoError(t, err)
+		require.NotNil(t, brokerStorage)
+		defer func() {
+			err := storageCleanup()
+			assert.NoError(t, err)
+		}()
+		db := brokerStorage.InstancesArchived()
+		require.NoError(t, db.Insert(givenInstance1))
+		require.NoError(t, db.Insert(givenInstance2))
+		require.NoError(t, db.Insert(givenInstance3))
+
+		// when
+		out, count, totalCount, err := db.List(dbmodel.InstanceFilter{Providers: []string{"aws"}})
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, 1, count)
+		require.Equal(t, 1, totalCount)
+		assertInstanceArchived(t, givenInstance2, out[0])
+
+		// when
+		out, count, totalCount, err = db.List(dbmodel.InstanceFilter{
+			Providers:         []string{"azure"},
+			DeprovisionedFrom: givenInstance1.LastDeprovisioningFinishedAt,
+			DeprovisionedTo:   givenInstance3.LastDeprovisioningFinishedAt,
+		})
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, 1, count)
+		require.Equal(t, 1, totalCount)
+		assertInstanceArchived(t, givenInstance1, out[0])
+	})
 }
 
 func assertInstanceArchived(t *testing.T, expected internal.InstanceArchived, got internal.InstanceArchived) {
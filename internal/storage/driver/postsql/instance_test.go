@@ -6,6 +6,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/kyma-project/kyma-environment-broker/common/pagination"
 	pkg "github.com/kyma-project/kyma-environment-broker/common/runtime"
 	"github.com/kyma-project/kyma-environment-broker/internal/broker"
 	"github.com/kyma-project/kyma-environment-broker/internal/events"
@@ -374,6 +375,62 @@ func TestInstance_UsingLastOperationID(t *testing.T) {
 		assert.Equal(t, fixInstances[2].InstanceID, out[0].InstanceID)
 	})
 
+	t.Run("Should list instances after the cursor", func(t *testing.T) {
+		storageCleanup, brokerStorage, err := storage.GetStorageForTests(cfg)
+		require.NoError(t, err)
+		require.NotNil(t, brokerStorage)
+		defer func() {
+			err := storageCleanup()
+			assert.NoError(t, err)
+		}()
+
+		// populate database with samples, two instances are created at the same time
+		createdAt := time.Now().Truncate(time.Millisecond)
+		for _, id := range []string{"2", "1", "3"} {
+			instance := fixInstance(instanceData{val: id})
+			instance.CreatedAt = createdAt
+			if id == "3" {
+				instance.CreatedAt = createdAt.Add(time.Minute)
+			}
+			err = brokerStorage.Instances().Insert(*instance)
+			require.NoError(t, err)
+			operation := fixture.FixProvisioningOperation("op"+id, id)
+			err = brokerStorage.Operations().InsertOperation(operation)
+			require.NoError(t, err)
+			err = brokerStorage.Instances().UpdateInstanceLastOperation(id, operation.ID)
+			require.NoError(t, err)
+		}
+
+		// when
+		out, count, totalCount, err := brokerStorage.Instances().List(dbmodel.InstanceFilter{PageSize: 1, Page: 1})
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, 1, count)
+		require.Equal(t, 3, totalCount)
+		assert.Equal(t, "1", out[0].InstanceID)
+
+		// when
+		cursor := pagination.NewCursor(out[0].CreatedAt, out[0].InstanceID)
+		out, count, totalCount, err = brokerStorage.Instances().List(dbmodel.InstanceFilter{PageSize: 5, After: &cursor})
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, 2, count)
+		require.Equal(t, 3, totalCount)
+		assert.Equal(t, "2", out[0].InstanceID)
+		assert.Equal(t, "3", out[1].InstanceID)
+
+		// when
+		cursor = pagination.NewCursor(out[1].CreatedAt, out[1].InstanceID)
+		withStates, count, _, err := brokerStorage.Instances().ListWithSubaccountState(dbmodel.InstanceFilter{PageSize: 5, After: &cursor})
+
+		// then
+		require.NoError(t, err)
+		assert.Zero(t, count)
+		assert.Empty(t, withStates)
+	})
+
 	t.Run("Should list instances based on filters", func(t *testing.T) {
 		storageCleanup, brokerStorage, err := storage.GetStorageForTests(cfg)
 		require.NoError(t, err)
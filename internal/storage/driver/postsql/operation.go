@@ -8,6 +8,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/kyma-project/kyma-environment-broker/common/changes"
 	"github.com/kyma-project/kyma-environment-broker/common/storage"
 	"github.com/kyma-project/kyma-environment-broker/internal"
 	"github.com/kyma-project/kyma-environment-broker/internal/storage/dberr"
@@ -505,7 +506,6 @@ func (s *operations) GetUpdatingOperationByID(operationID string) (*internal.Upd
 }
 
 func (s *operations) UpdateUpdatingOperation(operation internal.UpdatingOperation) (*internal.UpdatingOperation, error) {
-	session := s.Factory.NewWriteSession()
 	operation.UpdatedAt = time.Now()
 	dto, err := s.updateOperationToDTO(&operation)
 	if err != nil {
@@ -514,7 +514,7 @@ func (s *operations) UpdateUpdatingOperation(operation internal.UpdatingOperatio
 
 	var lastErr error
 	_ = wait.PollUntilContextTimeout(context.Background(), defaultRetryInterval, defaultRetryTimeout, true, func(ctx context.Context) (bool, error) {
-		lastErr = session.UpdateOperation(dto)
+		lastErr = s.updateWithChange(dto)
 		if lastErr != nil && dberr.IsNotFound(lastErr) {
 			_, lastErr = s.Factory.NewReadSession().GetOperationByID(operation.Operation.ID)
 			if lastErr != nil {
@@ -566,7 +566,6 @@ func (s *operations) InsertUpgradeClusterOperation(operation internal.UpgradeClu
 
 // UpdateUpgradeClusterOperation updates UpgradeClusterOperation, fails if not exists or optimistic locking failure occurs.
 func (s *operations) UpdateUpgradeClusterOperation(operation internal.UpgradeClusterOperation) (*internal.UpgradeClusterOperation, error) {
-	session := s.Factory.NewWriteSession()
 	operation.UpdatedAt = time.Now()
 	dto, err := s.upgradeClusterOperationToDTO(&operation)
 	if err != nil {
@@ -575,7 +574,7 @@ func (s *operations) UpdateUpgradeClusterOperation(operation internal.UpgradeClu
 
 	var lastErr error
 	_ = wait.PollUntilContextTimeout(context.Background(), defaultRetryInterval, defaultRetryTimeout, true, func(ctx context.Context) (bool, error) {
-		lastErr = session.UpdateOperation(dto)
+		lastErr = s.updateWithChange(dto)
 		if lastErr != nil && dberr.IsNotFound(lastErr) {
 			_, lastErr = s.Factory.NewReadSession().GetOperationByID(operation.Operation.ID)
 			if lastErr != nil {
@@ -958,10 +957,9 @@ func (s *operations) getByID(id string) (*dbmodel.OperationDTO, error) {
 }
 
 func (s *operations) insert(dto dbmodel.OperationDTO) error {
-	session := s.Factory.NewWriteSession()
 	var lastErr error
 	_ = wait.PollUntilContextTimeout(context.Background(), defaultRetryInterval, defaultRetryTimeout, true, func(ctx context.Context) (bool, error) {
-		lastErr = session.InsertOperation(dto)
+		lastErr = s.insertWithChange(dto)
 		if lastErr != nil {
 			return false, nil
 		}
@@ -970,6 +968,49 @@ func (s *operations) insert(dto dbmodel.OperationDTO) error {
 	return lastErr
 }
 
+// insertWithChange inserts the operation and its state to the changes in one transaction
+func (s *operations) insertWithChange(dto dbmodel.OperationDTO) dberr.Error {
+	session, err := s.Factory.NewSessionWithinTransaction()
+	if err != nil {
+		return err
+	}
+	defer session.RollbackUnlessCommitted()
+
+	err = session.InsertChange(changes.ChangeDTO{
+		Kind:          changes.OperationKind,
+		InstanceID:    dto.InstanceID,
+		OperationID:   dto.ID,
+		OperationType: string(dto.Type),
+		State:         dto.State,
+		CreatedAt:     time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+	if err := session.InsertOperation(dto); err != nil {
+		return err
+	}
+	return session.Commit()
+}
+
+// updateWithChange updates the operation and writes the change of its state in one transaction,
+// the change is rolled back when the version of the operation does not match
+func (s *operations) updateWithChange(dto dbmodel.OperationDTO) dberr.Error {
+	session, err := s.Factory.NewSessionWithinTransaction()
+	if err != nil {
+		return err
+	}
+	defer session.RollbackUnlessCommitted()
+
+	if err := session.InsertOperationChange(dto); err != nil {
+		return err
+	}
+	if err := session.UpdateOperation(dto); err != nil {
+		return err
+	}
+	return session.Commit()
+}
+
 func (s *operations) getByTypeAndInstanceID(id string, opType internal.OperationType) (*dbmodel.OperationDTO, error) {
 	session := s.Factory.NewReadSession()
 	operation := dbmodel.OperationDTO{}
@@ -990,11 +1031,9 @@ func (s *operations) getByTypeAndInstanceID(id string, opType internal.Operation
 }
 
 func (s *operations) update(operation dbmodel.OperationDTO) error {
-	session := s.Factory.NewWriteSession()
-
 	var lastErr error
 	_ = wait.PollUntilContextTimeout(context.Background(), defaultRetryInterval, defaultRetryTimeout, true, func(ctx context.Context) (bool, error) {
-		lastErr = session.UpdateOperation(operation)
+		lastErr = s.updateWithChange(operation)
 		if lastErr != nil && dberr.IsNotFound(lastErr) {
 			_, lastErr = s.Factory.NewReadSession().GetOperationByID(operation.ID)
 			if dberr.IsNotFound(lastErr) {
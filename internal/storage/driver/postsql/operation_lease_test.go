@@ -0,0 +1,138 @@
+package postsql_test
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/kyma-project/kyma-environment-broker/internal"
+	"github.com/kyma-project/kyma-environment-broker/internal/event"
+	"github.com/kyma-project/kyma-environment-broker/internal/fixture"
+	"github.com/kyma-project/kyma-environment-broker/internal/process"
+	"github.com/kyma-project/kyma-environment-broker/internal/storage"
+
+	"github.com/pivotal-cf/brokerapi/v12/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOperationLease(t *testing.T) {
+	log := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	t.Run("should process the operation once when two replicas run it", func(t *testing.T) {
+		// given
+		storageCleanup, brokerStorage, err := GetStorageForDatabaseTests()
+		require.NoError(t, err)
+		require.NotNil(t, brokerStorage)
+		defer func() {
+			err := storageCleanup()
+			assert.NoError(t, err)
+		}()
+		operation := fixLeasedOperation("op-to-lease")
+		require.NoError(t, brokerStorage.Operations().InsertOperation(operation))
+		step := &leaseTestStep{name: "count", duration: 50 * time.Millisecond}
+		stop := make(chan struct{})
+		defer close(stop)
+
+		for _, owner := range []string{"pod-a", "pod-b"} {
+			replica := newLeasingManager(brokerStorage, owner, log)
+			require.NoError(t, replica.AddStep("stage-1", step, nil))
+			queue := process.NewQueue(replica, log, owner)
+			queue.Run(stop, 2)
+			queue.Add(operation.ID)
+		}
+
+		// then
+		waitForOperationState(t, brokerStorage, operation.ID, domain.Succeeded)
+		assert.Equal(t, int32(1), step.executions.Load())
+	})
+
+	t.Run("should let the restarted replica resume the operation at once", func(t *testing.T) {
+		// given
+		storageCleanup, brokerStorage, err := GetStorageForDatabaseTests()
+		require.NoError(t, err)
+		require.NotNil(t, brokerStorage)
+		defer func() {
+			err := storageCleanup()
+			assert.NoError(t, err)
+		}()
+		operation := fixLeasedOperation("op-to-lease")
+		require.NoError(t, brokerStorage.Operations().InsertOperation(operation))
+		stop := make(chan struct{})
+		defer close(stop)
+
+		waiting := &leaseTestStep{name: "wait", retry: time.Hour}
+		replicaA := newLeasingManager(brokerStorage, "pod-a", log)
+		require.NoError(t, replicaA.AddStep("stage-1", waiting, nil))
+		queueA := process.NewQueue(replicaA, log, "pod-a")
+		queueA.Run(stop, 1)
+		queueA.Add(operation.ID)
+		assert.Eventually(t, func() bool { return waiting.executions.Load() == 1 }, 5*time.Second, 10*time.Millisecond)
+
+		// the Pod is restarted and gets a new name
+		require.True(t, queueA.Drain(time.Second))
+		replicaA.ReleaseLeases()
+		replicaB := newLeasingManager(brokerStorage, "pod-b", log)
+		require.NoError(t, replicaB.AddStep("stage-1", &leaseTestStep{name: "run"}, nil))
+		queueB := process.NewQueue(replicaB, log, "pod-b")
+		queueB.Run(stop, 1)
+
+		// when
+		queueB.Add(operation.ID)
+
+		// then
+		waitForOperationState(t, brokerStorage, operation.ID, domain.Succeeded)
+		op, err := brokerStorage.Operations().GetOperationByID(operation.ID)
+		require.NoError(t, err)
+		require.NotNil(t, op.Lease)
+		assert.Equal(t, "pod-b", op.Lease.Owner)
+	})
+}
+
+func fixLeasedOperation(id string) internal.Operation {
+	operation := fixture.FixProvisioningOperation(id, "inst1")
+	operation.State = domain.InProgress
+	operation.FinishedStages = nil
+	return operation
+}
+
+func newLeasingManager(brokerStorage storage.BrokerStorage, owner string, log *slog.Logger) *process.StagedManager {
+	mgr := process.NewStagedManager(brokerStorage.Operations(), noopPublisher{}, time.Hour, process.StagedManagerConfiguration{}, log)
+	mgr.DefineStages([]string{"stage-1"})
+	mgr.UseLease(owner, time.Hour)
+	return mgr
+}
+
+func waitForOperationState(t *testing.T, brokerStorage storage.BrokerStorage, operationID string, state domain.LastOperationState) {
+	assert.Eventually(t, func() bool {
+		op, err := brokerStorage.Operations().GetOperationByID(operationID)
+		return err == nil && op.State == state
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+// leaseTestStep counts its executions by all replicas and returns the retry
+type leaseTestStep struct {
+	name       string
+	duration   time.Duration
+	retry      time.Duration
+	executions atomic.Int32
+}
+
+func (s *leaseTestStep) Name() string {
+	return s.name
+}
+
+func (s *leaseTestStep) Run(operation internal.Operation, _ *slog.Logger) (internal.Operation, time.Duration, error) {
+	s.executions.Add(1)
+	time.Sleep(s.duration)
+	return operation, s.retry, nil
+}
+
+type noopPublisher struct{}
+
+func (noopPublisher) Publish(context.Context, interface{}) {}
+
+var _ event.Publisher = noopPublisher{}
@@ -0,0 +1,72 @@
+package postsql
+
+import (
+	"github.com/google/uuid"
+	"github.com/kyma-project/kyma-environment-broker/common/runtime"
+	"github.com/kyma-project/kyma-environment-broker/internal/storage/dbmodel"
+	"github.com/kyma-project/kyma-environment-broker/internal/storage/postsql"
+)
+
+type OperationSteps struct {
+	postsql.Factory
+}
+
+func NewOperationSteps(sess postsql.Factory) *OperationSteps {
+	return &OperationSteps{
+		Factory: sess,
+	}
+}
+
+func (s *OperationSteps) Insert(step runtime.OperationStep) error {
+	dto := toOperationStepDTO(step)
+	dto.ID = uuid.NewString()
+	return s.Factory.NewWriteSession().InsertOperationStep(dto)
+}
+
+func (s *OperationSteps) ListByOperationIDs(operationIDs []string) ([]runtime.OperationStep, error) {
+	dtos, err := s.Factory.NewReadSession().ListOperationSteps(operationIDs)
+	if err != nil {
+		return nil, err
+	}
+	steps := make([]runtime.OperationStep, 0, len(dtos))
+	for _, dto := range dtos {
+		steps = append(steps, toOperationStep(dto))
+	}
+	return steps, nil
+}
+
+func (s *OperationSteps) DeleteByOperationID(operationID string) error {
+	return s.Factory.NewWriteSession().DeleteOperationSteps(operationID)
+}
+
+func toOperationStepDTO(step runtime.OperationStep) dbmodel.OperationStepDTO {
+	return dbmodel.OperationStepDTO{
+		ID:                step.ID,
+		OperationID:       step.OperationID,
+		Stage:             step.Stage,
+		Step:              step.Step,
+		StartedAt:         step.StartedAt,
+		FinishedAt:        step.FinishedAt,
+		Outcome:           string(step.Outcome),
+		RetryAfterSeconds: step.RetryAfterSeconds,
+		ErrorReason:       step.ErrorReason,
+		ErrorComponent:    step.ErrorComponent,
+		ErrorMessage:      step.ErrorMessage,
+	}
+}
+
+func toOperationStep(dto dbmodel.OperationStepDTO) runtime.OperationStep {
+	return runtime.OperationStep{
+		ID:                dto.ID,
+		OperationID:       dto.OperationID,
+		Stage:             dto.Stage,
+		Step:              dto.Step,
+		StartedAt:         dto.StartedAt,
+		FinishedAt:        dto.FinishedAt,
+		Outcome:           runtime.StepOutcome(dto.Outcome),
+		RetryAfterSeconds: dto.RetryAfterSeconds,
+		ErrorReason:       dto.ErrorReason,
+		ErrorComponent:    dto.ErrorComponent,
+		ErrorMessage:      dto.ErrorMessage,
+	}
+}
@@ -6,10 +6,12 @@ import (
 	"testing"
 	"time"
 
+	"github.com/kyma-project/kyma-environment-broker/common/pagination"
 	"github.com/kyma-project/kyma-environment-broker/internal"
 	"github.com/kyma-project/kyma-environment-broker/internal/broker"
 	"github.com/kyma-project/kyma-environment-broker/internal/fixture"
 	"github.com/kyma-project/kyma-environment-broker/internal/storage"
+	"github.com/kyma-project/kyma-environment-broker/internal/storage/dberr"
 	"github.com/kyma-project/kyma-environment-broker/internal/storage/dbmodel"
 	"github.com/pivotal-cf/brokerapi/v12/domain"
 	"github.com/stretchr/testify/assert"
@@ -50,6 +52,40 @@ func TestOperation(t *testing.T) {
 		assert.Equal(t, "op-to-keep", ops[0].ID)
 	})
 
+	t.Run("should let only one replica claim the operation", func(t *testing.T) {
+		// given
+		storageCleanup, brokerStorage, err := GetStorageForDatabaseTests()
+		require.NoError(t, err)
+		require.NotNil(t, brokerStorage)
+		defer func() {
+			err := storageCleanup()
+			assert.NoError(t, err)
+		}()
+		operation := fixture.FixProvisioningOperation("op-to-claim", "inst1")
+		operation.State = domain.InProgress
+		require.NoError(t, brokerStorage.Operations().InsertOperation(operation))
+		loadedByA, err := brokerStorage.Operations().GetOperationByID(operation.ID)
+		require.NoError(t, err)
+		loadedByB, err := brokerStorage.Operations().GetOperationByID(operation.ID)
+		require.NoError(t, err)
+
+		// when
+		loadedByA.Lease = &internal.OperationLease{Owner: "pod-a", ExpiresAt: time.Now().Add(time.Minute)}
+		_, errA := brokerStorage.Operations().UpdateOperation(*loadedByA)
+		loadedByB.Lease = &internal.OperationLease{Owner: "pod-b", ExpiresAt: time.Now().Add(time.Minute)}
+		_, errB := brokerStorage.Operations().UpdateOperation(*loadedByB)
+
+		// then
+		require.NoError(t, errA)
+		assert.True(t, dberr.IsConflict(errB))
+		claimed, err := brokerStorage.Operations().GetOperationByID(operation.ID)
+		require.NoError(t, err)
+		require.NotNil(t, claimed.Lease)
+		assert.Equal(t, "pod-a", claimed.Lease.Owner)
+		assert.True(t, claimed.IsLeasedByOther("pod-b", time.Now()))
+		assert.False(t, claimed.IsLeasedByOther("pod-b", time.Now().Add(2*time.Minute)))
+	})
+
 	t.Run("Provisioning in Shanghai", func(t *testing.T) {
 		storageCleanup, brokerStorage, err := storage.GetStorageForTests(cfg,
 			storage.WithConnectionURL(fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s timezone=%s", cfg.Host, cfg.Port, cfg.User, cfg.Password, cfg.Name, cfg.SSLMode, "'Asia/Shanghai'")))
@@ -495,6 +531,34 @@ func TestOperation(t *testing.T) {
 		require.Equal(t, 1, totalCount)
 	})
 
+	t.Run("List operations after the cursor", func(t *testing.T) {
+		storageCleanup, brokerStorage, err := GetStorageForDatabaseTests()
+		require.NoError(t, err)
+		require.NotNil(t, brokerStorage)
+		defer func() {
+			err := storageCleanup()
+			assert.NoError(t, err)
+		}()
+
+		createdAt := time.Now().Truncate(time.Millisecond)
+		for _, id := range []string{"op2", "op1", "op3"} {
+			op := fixture.FixOperation(id, "inst-"+id, internal.OperationTypeProvision)
+			op.CreatedAt = createdAt
+			err = brokerStorage.Operations().InsertOperation(op)
+			require.NoError(t, err)
+		}
+		cursor := pagination.NewCursor(createdAt, "op1")
+
+		// when
+		operations, count, totalCount, err := brokerStorage.Operations().ListOperations(dbmodel.OperationFilter{PageSize: 1, After: &cursor})
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, 1, count)
+		require.Equal(t, 3, totalCount)
+		assert.Equal(t, "op2", operations[0].ID)
+	})
+
 	t.Run("Last operation based on types", func(t *testing.T) {
 		storageCleanup, brokerStorage, err := GetStorageForDatabaseTests()
 		require.NoError(t, err)
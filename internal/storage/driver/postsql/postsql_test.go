@@ -8,6 +8,8 @@ import (
 
 	"github.com/kyma-project/kyma-environment-broker/internal"
 	"github.com/kyma-project/kyma-environment-broker/internal/storage"
+
+	_ "modernc.org/sqlite"
 )
 
 func brokerStorageDatabaseTestConfig() storage.Config {
@@ -31,6 +33,12 @@ func TestMain(m *testing.M) {
 		os.Exit(exitVal)
 	}()
 
+	// the tests run against the SQLite database in memory, no container is needed
+	if storage.SQLiteForStorageTests() {
+		exitVal = m.Run()
+		return
+	}
+
 	config := brokerStorageDatabaseTestConfig()
 
 	docker, err := internal.NewDockerHandler()
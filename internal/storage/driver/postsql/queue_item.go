@@ -0,0 +1,127 @@
+package postsql
+
+import (
+	"time"
+
+	"github.com/kyma-project/kyma-environment-broker/internal"
+	"github.com/kyma-project/kyma-environment-broker/internal/storage/dbmodel"
+	"github.com/kyma-project/kyma-environment-broker/internal/storage/postsql"
+)
+
+type QueueItems struct {
+	postsql.Factory
+}
+
+func NewQueueItems(sess postsql.Factory) *QueueItems {
+	return &QueueItems{
+		Factory: sess,
+	}
+}
+
+func (q *QueueItems) Schedule(queueName, operationID string, runAt time.Time) error {
+	err := q.Factory.NewWriteSession().UpsertQueueItem(dbmodel.QueueItemDTO{
+		QueueName:   queueName,
+		OperationID: operationID,
+		NextRunAt:   runAt,
+		CreatedAt:   time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (q *QueueItems) ScheduleIfAbsent(queueName, operationID string, runAt time.Time) error {
+	err := q.Factory.NewWriteSession().InsertQueueItemIfAbsent(dbmodel.QueueItemDTO{
+		QueueName:   queueName,
+		OperationID: operationID,
+		NextRunAt:   runAt,
+		CreatedAt:   time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (q *QueueItems) Lease(queueName, owner string, leaseDuration time.Duration) (*internal.QueueItem, error) {
+	now := time.Now()
+	dto, err := q.Factory.NewWriteSession().LeaseQueueItem(queueName, owner, now, now.Add(leaseDuration))
+	if err != nil {
+		return nil, err
+	}
+	item := toQueueItem(dto)
+	return &item, nil
+}
+
+func (q *QueueItems) ExtendLease(queueName, owner string, leaseDuration time.Duration) error {
+	err := q.Factory.NewWriteSession().ExtendQueueItemsLease(queueName, owner, time.Now().Add(leaseDuration))
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// Complete removes the processed item. If the item was added to the queue during the processing, only the lease is released.
+func (q *QueueItems) Complete(item internal.QueueItem) error {
+	sess := q.Factory.NewWriteSession()
+	dto := toQueueItemDTO(item)
+	deleted, err := sess.DeleteQueueItem(dto)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		if err := sess.ReleaseQueueItem(dto); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Reschedule sets the time of the next processing. If the item was added to the queue during the processing, only the lease is released.
+func (q *QueueItems) Reschedule(item internal.QueueItem, runAt time.Time) error {
+	sess := q.Factory.NewWriteSession()
+	dto := toQueueItemDTO(item)
+	rescheduled, err := sess.RescheduleQueueItem(dto, runAt)
+	if err != nil {
+		return err
+	}
+	if !rescheduled {
+		if err := sess.ReleaseQueueItem(dto); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (q *QueueItems) Count(queueName string) (int, error) {
+	count, err := q.Factory.NewReadSession().CountQueueItems(queueName)
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func toQueueItem(dto dbmodel.QueueItemDTO) internal.QueueItem {
+	return internal.QueueItem{
+		QueueName:      dto.QueueName,
+		OperationID:    dto.OperationID,
+		NextRunAt:      dto.NextRunAt,
+		LeaseOwner:     dto.LeaseOwner,
+		LeaseExpiresAt: dto.LeaseExpiresAt,
+		Version:        dto.Version,
+		CreatedAt:      dto.CreatedAt,
+	}
+}
+
+func toQueueItemDTO(item internal.QueueItem) dbmodel.QueueItemDTO {
+	return dbmodel.QueueItemDTO{
+		QueueName:      item.QueueName,
+		OperationID:    item.OperationID,
+		NextRunAt:      item.NextRunAt,
+		LeaseOwner:     item.LeaseOwner,
+		LeaseExpiresAt: item.LeaseExpiresAt,
+		Version:        item.Version,
+		CreatedAt:      item.CreatedAt,
+	}
+}
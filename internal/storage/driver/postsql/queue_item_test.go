@@ -0,0 +1,107 @@
+package postsql_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kyma-project/kyma-environment-broker/internal/storage/dberr"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueueItems(t *testing.T) {
+	storageCleanup, brokerStorage, err := GetStorageForDatabaseTests()
+	require.NoError(t, err)
+	require.NotNil(t, brokerStorage)
+	defer func() {
+		err := storageCleanup()
+		assert.NoError(t, err)
+	}()
+	items := brokerStorage.QueueItems()
+
+	t.Run("should lease the due items in the order of the schedule", func(t *testing.T) {
+		// given
+		require.NoError(t, items.Schedule("provisioning", "op-2", time.Now().Add(-time.Minute)))
+		require.NoError(t, items.Schedule("provisioning", "op-1", time.Now().Add(-time.Hour)))
+		require.NoError(t, items.Schedule("provisioning", "op-future", time.Now().Add(time.Hour)))
+		require.NoError(t, items.Schedule("update", "op-3", time.Now().Add(-time.Hour)))
+
+		// when
+		first, err := items.Lease("provisioning", "owner-a", time.Hour)
+		require.NoError(t, err)
+		second, err := items.Lease("provisioning", "owner-b", time.Hour)
+		require.NoError(t, err)
+		_, err = items.Lease("provisioning", "owner-c", time.Hour)
+
+		// then
+		assert.Equal(t, "op-1", first.OperationID)
+		assert.Equal(t, "owner-a", first.LeaseOwner)
+		assert.Equal(t, "op-2", second.OperationID)
+		assert.True(t, dberr.IsNotFound(err))
+
+		count, err := items.Count("provisioning")
+		require.NoError(t, err)
+		assert.Equal(t, 3, count)
+
+		// when
+		require.NoError(t, items.Complete(*first))
+		require.NoError(t, items.Reschedule(*second, time.Now().Add(-time.Second)))
+
+		// then
+		count, err = items.Count("provisioning")
+		require.NoError(t, err)
+		assert.Equal(t, 2, count)
+		again, err := items.Lease("provisioning", "owner-c", time.Hour)
+		require.NoError(t, err)
+		assert.Equal(t, "op-2", again.OperationID)
+	})
+
+	t.Run("should keep the item added during the processing", func(t *testing.T) {
+		// given
+		require.NoError(t, items.Schedule("deprovisioning", "op-4", time.Now()))
+		leased, err := items.Lease("deprovisioning", "owner-a", time.Hour)
+		require.NoError(t, err)
+
+		// when
+		require.NoError(t, items.Schedule("deprovisioning", "op-4", time.Now()))
+		require.NoError(t, items.Complete(*leased))
+
+		// then
+		again, err := items.Lease("deprovisioning", "owner-b", time.Hour)
+		require.NoError(t, err)
+		assert.Equal(t, "op-4", again.OperationID)
+		assert.Equal(t, leased.Version+1, again.Version)
+	})
+
+	t.Run("should not overwrite the existing schedule when restoring", func(t *testing.T) {
+		// given
+		require.NoError(t, items.Schedule("restore", "op-5", time.Now().Add(time.Hour)))
+
+		// when
+		require.NoError(t, items.ScheduleIfAbsent("restore", "op-5", time.Now()))
+		require.NoError(t, items.ScheduleIfAbsent("restore", "op-6", time.Now()))
+
+		// then
+		leased, err := items.Lease("restore", "owner-a", time.Hour)
+		require.NoError(t, err)
+		assert.Equal(t, "op-6", leased.OperationID)
+		_, err = items.Lease("restore", "owner-a", time.Hour)
+		assert.True(t, dberr.IsNotFound(err))
+	})
+
+	t.Run("should take the item with the expired lease", func(t *testing.T) {
+		// given
+		require.NoError(t, items.Schedule("expired", "op-7", time.Now()))
+		_, err := items.Lease("expired", "owner-a", time.Millisecond)
+		require.NoError(t, err)
+		time.Sleep(10 * time.Millisecond)
+
+		// when
+		leased, err := items.Lease("expired", "owner-b", time.Hour)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "owner-b", leased.LeaseOwner)
+	})
+}
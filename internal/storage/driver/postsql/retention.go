@@ -0,0 +1,50 @@
+package postsql
+
+import (
+	"time"
+
+	"github.com/kyma-project/kyma-environment-broker/common/events"
+	"github.com/kyma-project/kyma-environment-broker/internal/storage/postsql"
+)
+
+type Retention struct {
+	postsql.Factory
+}
+
+func NewRetention(sess postsql.Factory) *Retention {
+	return &Retention{
+		Factory: sess,
+	}
+}
+
+func (s *Retention) ListInstanceIDs(after string, limit int) ([]string, error) {
+	return s.Factory.NewReadSession().ListInstanceIDs(after, limit)
+}
+
+func (s *Retention) CountEvents(level events.EventLevel, until time.Time) (int, error) {
+	return s.Factory.NewReadSession().CountEvents(level, until)
+}
+
+func (s *Retention) DeleteEvents(level events.EventLevel, until time.Time) (int, error) {
+	deleted, err := s.Factory.NewWriteSession().DeleteEventsByLevel(level, until)
+	if err != nil {
+		return 0, err
+	}
+	return deleted, nil
+}
+
+func (s *Retention) DeleteInstanceEvents(instanceID string) error {
+	return s.Factory.NewWriteSession().DeleteInstanceEvents(instanceID)
+}
+
+func (s *Retention) CountChanges(until time.Time) (int, error) {
+	return s.Factory.NewReadSession().CountChanges(until)
+}
+
+func (s *Retention) DeleteChanges(until time.Time) (int, error) {
+	deleted, err := s.Factory.NewWriteSession().DeleteChanges(until)
+	if err != nil {
+		return 0, err
+	}
+	return deleted, nil
+}
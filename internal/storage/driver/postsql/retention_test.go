@@ -0,0 +1,70 @@
+package postsql_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kyma-project/kyma-environment-broker/common/events"
+	"github.com/kyma-project/kyma-environment-broker/common/runtime"
+	"github.com/kyma-project/kyma-environment-broker/internal/fixture"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetention(t *testing.T) {
+	storageCleanup, brokerStorage, err := GetStorageForDatabaseTests()
+	require.NoError(t, err)
+	require.NotNil(t, brokerStorage)
+	defer func() {
+		err := storageCleanup()
+		assert.NoError(t, err)
+	}()
+	retention := brokerStorage.Retention()
+
+	t.Run("should list the instance IDs in pages", func(t *testing.T) {
+		// given
+		for _, id := range []string{"inst-3", "inst-1", "inst-2"} {
+			require.NoError(t, brokerStorage.Instances().Insert(fixture.FixInstance(id)))
+		}
+
+		// when
+		first, err := retention.ListInstanceIDs("", 2)
+		require.NoError(t, err)
+		second, err := retention.ListInstanceIDs(first[len(first)-1], 2)
+		require.NoError(t, err)
+
+		// then
+		assert.Equal(t, []string{"inst-1", "inst-2"}, first)
+		assert.Equal(t, []string{"inst-3"}, second)
+	})
+
+	t.Run("should count and delete the events of the level", func(t *testing.T) {
+		// when
+		count, err := retention.CountEvents(events.InfoEventLevel, time.Now())
+		require.NoError(t, err)
+		deleted, err := retention.DeleteEvents(events.InfoEventLevel, time.Now())
+		require.NoError(t, err)
+
+		// then
+		assert.Zero(t, count)
+		assert.Zero(t, deleted)
+	})
+
+	t.Run("should delete the steps of the operation", func(t *testing.T) {
+		// given
+		steps := brokerStorage.OperationSteps()
+		require.NoError(t, steps.Insert(runtime.OperationStep{OperationID: "op-1", Step: "step", StartedAt: time.Now(), FinishedAt: time.Now(), Outcome: runtime.StepSucceeded}))
+		require.NoError(t, steps.Insert(runtime.OperationStep{OperationID: "op-2", Step: "step", StartedAt: time.Now(), FinishedAt: time.Now(), Outcome: runtime.StepSucceeded}))
+
+		// when
+		err := steps.DeleteByOperationID("op-1")
+
+		// then
+		require.NoError(t, err)
+		remaining, err := steps.ListByOperationIDs([]string{"op-1", "op-2"})
+		require.NoError(t, err)
+		require.Len(t, remaining, 1)
+		assert.Equal(t, "op-2", remaining[0].OperationID)
+	})
+}
@@ -0,0 +1,68 @@
+package postsql_test
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/gocraft/dbr"
+	"github.com/kyma-project/kyma-environment-broker/internal/storage"
+	"github.com/kyma-project/kyma-environment-broker/internal/storage/postsql"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// legacyTables are created by the migrations, but they are not used anymore, so the SQLite schema does not have them
+var legacyTables = []string{"lms_tenants", "cls_instances", "cls_instance_references"}
+
+// TestSQLiteSchema checks that the SQLite schema, which is maintained by hand, has the tables and the columns
+// created by the PostgreSQL migrations
+func TestSQLiteSchema(t *testing.T) {
+	if storage.SQLiteForStorageTests() {
+		t.Skip("the schema is compared with the PostgreSQL database")
+	}
+
+	// given
+	storageCleanup, _, err := GetStorageForDatabaseTests()
+	require.NoError(t, err)
+	defer func() {
+		err := storageCleanup()
+		assert.NoError(t, err)
+	}()
+	cfg := brokerStorageDatabaseTestConfig()
+	postgres, err := dbr.Open("postgres", cfg.ConnectionURL(), nil)
+	require.NoError(t, err)
+	defer postgres.Close()
+	sqlite, err := postsql.InitializeSQLiteDatabase(":memory:")
+	require.NoError(t, err)
+	defer sqlite.Close()
+
+	// when
+	migrated := loadColumns(t, postgres, `SELECT table_name, column_name FROM information_schema.columns WHERE table_schema = 'public'`)
+	maintained := loadColumns(t, sqlite, `SELECT m.name, p.name FROM sqlite_master m JOIN pragma_table_info(m.name) p WHERE m.type = 'table' AND m.name NOT LIKE 'sqlite_%'`)
+	for _, table := range legacyTables {
+		delete(migrated, table)
+	}
+
+	// then
+	assert.Equal(t, migrated, maintained, "internal/storage/postsql/sqlite_schema.sql differs from the migrations in resources/keb/migrations")
+}
+
+// loadColumns returns the sorted column names by the table name
+func loadColumns(t *testing.T, connection *dbr.Connection, query string) map[string][]string {
+	rows, err := connection.Query(query)
+	require.NoError(t, err)
+	defer rows.Close()
+
+	columns := make(map[string][]string)
+	for rows.Next() {
+		var table, column string
+		require.NoError(t, rows.Scan(&table, &column))
+		columns[table] = append(columns[table], column)
+	}
+	require.NoError(t, rows.Err())
+	for _, names := range columns {
+		sort.Strings(names)
+	}
+	return columns
+}
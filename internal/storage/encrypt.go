@@ -5,16 +5,99 @@ import (
 	"crypto/cipher"
 	"encoding/base64"
 	"fmt"
+	"strings"
 
 	"github.com/kyma-project/kyma-environment-broker/internal"
 )
 
+// keyIDSeparator separates the key ID from the encrypted data, it is not a part of the base64 alphabet,
+// so the data encrypted with the secret key before the keyring was introduced has no key ID
+const keyIDSeparator = ":"
+
 func NewEncrypter(secretKey string) *Encrypter {
 	return &Encrypter{key: []byte(secretKey)}
 }
 
+// NewEncrypterFromConfig creates the encrypter with the keyring defined in the config.
+// The data is encrypted with the active key and prefixed with its ID, the data without the key ID is decrypted with the secret key.
+func NewEncrypterFromConfig(cfg Config) (*Encrypter, error) {
+	e := NewEncrypter(cfg.SecretKey)
+	keys, err := parseEncryptionKeys(cfg.EncryptionKeys)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.EncryptionKeyID != "" {
+		if _, found := keys[cfg.EncryptionKeyID]; !found {
+			return nil, fmt.Errorf("encryption key %q is not defined in the keyring", cfg.EncryptionKeyID)
+		}
+	}
+	e.keys = keys
+	e.activeKeyID = cfg.EncryptionKeyID
+	return e, nil
+}
+
+func parseEncryptionKeys(keyring string) (map[string][]byte, error) {
+	keys := make(map[string][]byte)
+	for _, entry := range strings.Split(keyring, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		id, key, found := strings.Cut(entry, "=")
+		if !found || id == "" {
+			return nil, fmt.Errorf("invalid encryption keyring entry, expected the format keyID=key")
+		}
+		if strings.Contains(id, keyIDSeparator) {
+			return nil, fmt.Errorf("encryption key ID %q must not contain %q", id, keyIDSeparator)
+		}
+		if _, duplicated := keys[id]; duplicated {
+			return nil, fmt.Errorf("encryption key %q is defined more than once", id)
+		}
+		switch len(key) {
+		case 16, 24, 32:
+		default:
+			return nil, fmt.Errorf("encryption key %q must be 16, 24 or 32 bytes long", id)
+		}
+		keys[id] = []byte(key)
+	}
+	return keys, nil
+}
+
 type Encrypter struct {
 	key []byte
+
+	keys        map[string][]byte
+	activeKeyID string
+}
+
+// ActiveKeyID returns the ID of the key used to encrypt the data, empty if the data is encrypted with the secret key
+func (e *Encrypter) ActiveKeyID() string {
+	return e.activeKeyID
+}
+
+// NeedsReencryption returns true if the data is not encrypted with the active key
+func (e *Encrypter) NeedsReencryption(ciphertext []byte) bool {
+	keyID, _ := splitKeyID(ciphertext)
+	return keyID != e.activeKeyID
+}
+
+func splitKeyID(ciphertext []byte) (string, []byte) {
+	keyID, data, found := strings.Cut(string(ciphertext), keyIDSeparator)
+	if !found {
+		return "", ciphertext
+	}
+	return keyID, []byte(data)
+}
+
+func (e *Encrypter) keyByID(keyID string) ([]byte, error) {
+	if keyID == "" {
+		return e.key, nil
+	}
+	key, found := e.keys[keyID]
+	if !found {
+		return nil, fmt.Errorf("encryption key %q is not defined in the keyring", keyID)
+	}
+	return key, nil
 }
 
 func (e *Encrypter) Encrypt(data []byte) ([]byte, error) {
@@ -69,7 +152,11 @@ func (e *Encrypter) EncryptKubeconfig(provisioningParameters *internal.Provision
 }
 
 func (e *Encrypter) encryptGCM(data []byte) ([]byte, error) {
-	aes, err := aes.NewCipher(e.key)
+	key, err := e.keyByID(e.activeKeyID)
+	if err != nil {
+		return nil, err
+	}
+	aes, err := aes.NewCipher(key)
 	if err != nil {
 		return nil, err
 	}
@@ -77,19 +164,27 @@ func (e *Encrypter) encryptGCM(data []byte) ([]byte, error) {
 	if err != nil {
 		return nil, err
 	}
-	encoded := gcm.Seal(nil, make([]byte, gcm.NonceSize()), data, nil)
-	return []byte(base64.StdEncoding.EncodeToString(encoded)), nil
+	encoded := base64.StdEncoding.EncodeToString(gcm.Seal(nil, make([]byte, gcm.NonceSize()), data, nil))
+	if e.activeKeyID == "" {
+		return []byte(encoded), nil
+	}
+	return []byte(e.activeKeyID + keyIDSeparator + encoded), nil
 }
 
 // DecryptFunc decrypts a byte slice.
 type DecryptFunc func(data []byte) ([]byte, error)
 
 func (e *Encrypter) decryptGCM(ciphertext []byte) ([]byte, error) {
-	ciphertext, err := base64.StdEncoding.DecodeString(string(ciphertext))
+	keyID, ciphertext := splitKeyID(ciphertext)
+	key, err := e.keyByID(keyID)
+	if err != nil {
+		return nil, err
+	}
+	ciphertext, err = base64.StdEncoding.DecodeString(string(ciphertext))
 	if err != nil {
 		return nil, err
 	}
-	aes, err := aes.NewCipher(e.key)
+	aes, err := aes.NewCipher(key)
 	if err != nil {
 		return nil, err
 	}
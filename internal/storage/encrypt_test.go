@@ -2,6 +2,7 @@ package storage
 
 import (
 	"encoding/json"
+	"strings"
 	"testing"
 
 	"github.com/kyma-project/kyma-environment-broker/common/runtime"
@@ -143,3 +144,76 @@ func TestDecryptKubeconfigUsingModeWithEmptyKubeconfig(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, "", params.Parameters.Kubeconfig)
 }
+
+func TestEncrypterWithKeyring(t *testing.T) {
+	secretKey := rand.String(32)
+	oldKey := rand.String(32)
+	newKey := rand.String(16)
+	data := []byte("data encrypted with the keyring")
+
+	legacy := NewEncrypter(secretKey)
+	legacyEncrypted, err := legacy.Encrypt(data)
+	require.NoError(t, err)
+
+	old, err := NewEncrypterFromConfig(Config{SecretKey: secretKey, EncryptionKeys: "v1=" + oldKey, EncryptionKeyID: "v1"})
+	require.NoError(t, err)
+	oldEncrypted, err := old.Encrypt(data)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(oldEncrypted), "v1:"))
+
+	e, err := NewEncrypterFromConfig(Config{SecretKey: secretKey, EncryptionKeys: "v1=" + oldKey + ",v2=" + newKey, EncryptionKeyID: "v2"})
+	require.NoError(t, err)
+
+	t.Run("should encrypt with the active key", func(t *testing.T) {
+		encrypted, err := e.Encrypt(data)
+		require.NoError(t, err)
+		assert.True(t, strings.HasPrefix(string(encrypted), "v2:"))
+		assert.False(t, e.NeedsReencryption(encrypted))
+
+		decrypted, err := e.DecryptUsingMode(encrypted)
+		require.NoError(t, err)
+		assert.Equal(t, data, decrypted)
+	})
+
+	t.Run("should decrypt with the inactive key", func(t *testing.T) {
+		assert.True(t, e.NeedsReencryption(oldEncrypted))
+
+		decrypted, err := e.DecryptUsingMode(oldEncrypted)
+		require.NoError(t, err)
+		assert.Equal(t, data, decrypted)
+	})
+
+	t.Run("should decrypt data without key ID with the secret key", func(t *testing.T) {
+		assert.True(t, e.NeedsReencryption(legacyEncrypted))
+		assert.False(t, legacy.NeedsReencryption(legacyEncrypted))
+
+		decrypted, err := e.DecryptUsingMode(legacyEncrypted)
+		require.NoError(t, err)
+		assert.Equal(t, data, decrypted)
+	})
+
+	t.Run("should fail for the key removed from the keyring", func(t *testing.T) {
+		encrypted, err := e.Encrypt(data)
+		require.NoError(t, err)
+
+		_, err = old.DecryptUsingMode(encrypted)
+		assert.ErrorContains(t, err, `encryption key "v2" is not defined`)
+	})
+}
+
+func TestNewEncrypterFromConfig_InvalidKeyring(t *testing.T) {
+	for name, cfg := range map[string]Config{
+		"missing active key":   {EncryptionKeys: "v1=" + rand.String(32), EncryptionKeyID: "v2"},
+		"invalid key length":   {EncryptionKeys: "v1=short", EncryptionKeyID: "v1"},
+		"missing key ID":       {EncryptionKeys: "=" + rand.String(32)},
+		"missing separator":    {EncryptionKeys: rand.String(32)},
+		"key ID with colon":    {EncryptionKeys: "v:1=" + rand.String(32)},
+		"duplicated key ID":    {EncryptionKeys: "v1=" + rand.String(32) + ",v1=" + rand.String(32)},
+		"active key not given": {EncryptionKeyID: "v1"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := NewEncrypterFromConfig(cfg)
+			assert.Error(t, err)
+		})
+	}
+}
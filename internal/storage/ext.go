@@ -3,6 +3,7 @@ package storage
 import (
 	"time"
 
+	"github.com/kyma-project/kyma-environment-broker/common/changes"
 	"github.com/kyma-project/kyma-environment-broker/common/events"
 	"github.com/kyma-project/kyma-environment-broker/common/runtime"
 	"github.com/kyma-project/kyma-environment-broker/internal"
@@ -98,7 +99,10 @@ type Updating interface {
 }
 
 type Events interface {
-	InsertEvent(level events.EventLevel, message, instanceID, operationID string)
+	// InsertEvent stores the event, the ID and the creation time of the event are assigned by the storage
+	InsertEvent(event events.EventDTO)
+	// ImportEvent stores the event with its ID and creation time, for example, when the exported instance is imported
+	ImportEvent(event events.EventDTO) error
 	ListEvents(filter events.EventFilter) ([]events.EventDTO, error)
 }
 
@@ -115,12 +119,63 @@ type Bindings interface {
 	Delete(instanceID, bindingID string) error
 	ListByInstanceID(instanceID string) ([]internal.Binding, error)
 	ListExpired() ([]internal.Binding, error)
+	GetByOperationID(operationID string) (*internal.Binding, error)
+	ListInProgress() ([]internal.Binding, error)
+	ListToRenew(expiringBefore time.Time) ([]internal.Binding, error)
 	GetStatistics() (internal.BindingStats, error)
 }
 
 type Actions interface {
 	InsertAction(actionType runtime.ActionType, instanceID, message, oldValue, newValue string) error
+	// ImportAction stores the action with its ID and creation time, for example, when the exported instance is imported
+	ImportAction(action runtime.Action) error
 	ListActionsByInstanceID(instanceID string) ([]runtime.Action, error)
+	DeleteByInstanceID(instanceID string) error
+}
+
+type OperationSteps interface {
+	Insert(step runtime.OperationStep) error
+	ListByOperationIDs(operationIDs []string) ([]runtime.OperationStep, error)
+	DeleteByOperationID(operationID string) error
+}
+
+// Retention gives access to the data purged by the retention job
+type Retention interface {
+	// ListInstanceIDs returns the page of the IDs of the existing instances, ordered by the ID
+	ListInstanceIDs(after string, limit int) ([]string, error)
+	CountEvents(level events.EventLevel, until time.Time) (int, error)
+	// DeleteEvents deletes the events of the level created until the given time and returns the number of deleted events
+	DeleteEvents(level events.EventLevel, until time.Time) (int, error)
+	// DeleteInstanceEvents deletes all the events of the instance
+	DeleteInstanceEvents(instanceID string) error
+	CountChanges(until time.Time) (int, error)
+	// DeleteChanges deletes the changes created until the given time and returns the number of deleted changes
+	DeleteChanges(until time.Time) (int, error)
+}
+
+// Changes is the outbox of the state transitions of the instances and the operations, every change is written
+// in the same transaction as the instance or the operation
+type Changes interface {
+	// List returns up to limit changes with the sequence numbers greater than since, ordered by the sequence number
+	List(since int64, limit int) ([]changes.ChangeDTO, error)
+}
+
+// EncryptedData gives access to the columns with the encrypted data, so they can be encrypted again with a new key
+type EncryptedData interface {
+	List(source dbmodel.EncryptedDataSource, after dbmodel.EncryptedDataDTO, limit int) ([]dbmodel.EncryptedDataDTO, error)
+	// Update replaces the value, it returns the conflict error if the value was changed after it had been listed
+	Update(source dbmodel.EncryptedDataSource, item dbmodel.EncryptedDataDTO, value string) error
+}
+
+// QueueItems stores operations scheduled for processing, every operation is leased by one queue instance at a time
+type QueueItems interface {
+	Schedule(queueName, operationID string, runAt time.Time) error
+	ScheduleIfAbsent(queueName, operationID string, runAt time.Time) error
+	Lease(queueName, owner string, leaseDuration time.Duration) (*internal.QueueItem, error)
+	ExtendLease(queueName, owner string, leaseDuration time.Duration) error
+	Complete(item internal.QueueItem) error
+	Reschedule(item internal.QueueItem, runAt time.Time) error
+	Count(queueName string) (int, error)
 }
 
 type TimeZones interface {
@@ -0,0 +1,154 @@
+package postsql
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gocraft/dbr"
+	"github.com/gocraft/dbr/dialect"
+	"github.com/lib/pq"
+)
+
+// sqlDialect provides the parts of the queries which differ between PostgreSQL and SQLite,
+// the rest of the SQL is shared by both databases
+type sqlDialect interface {
+	// timeZone is the expression which returns the time zone of the database
+	timeZone() string
+	// jsonText returns the JSON column as text
+	jsonText(column string) string
+	// ersContextActive returns the "active" flag of the ERS context from the provisioning parameters column
+	ersContextActive(column string) string
+	// ersContextLicenseType returns the license type of the ERS context from the provisioning parameters column
+	ersContextLicenseType(column string) string
+	// shootNameIn matches the shoot name kept in the data of the operation
+	shootNameIn(table string, shoots []string) dbr.Builder
+	// secondsSinceEarliestExpiration returns the number of seconds since the earliest binding expired
+	secondsSinceEarliestExpiration() string
+	// least is the function which returns the smaller of two values
+	least() string
+	// skipLocked is the locking clause of the query which takes a row not locked by other sessions
+	skipLocked() string
+	// lockChanges is the statement which serializes the transactions writing the changes, so the sequence numbers
+	// of the changes are in the order of the commits, the empty statement is not executed
+	lockChanges() string
+	// caseInsensitiveLike is the operator which matches the pattern regardless of the case
+	caseInsensitiveLike() string
+	isUniqueViolation(err error) bool
+}
+
+type postgresDialect struct{}
+
+func (postgresDialect) timeZone() string {
+	return "current_setting('TIMEZONE')"
+}
+
+func (postgresDialect) jsonText(column string) string {
+	return fmt.Sprintf("%s::text", column)
+}
+
+func (postgresDialect) ersContextActive(column string) string {
+	return fmt.Sprintf("((%s::JSONB->>'ers_context')::JSONB->>'active')::BOOLEAN", column)
+}
+
+func (postgresDialect) ersContextLicenseType(column string) string {
+	return fmt.Sprintf("(%s -> 'ers_context' -> 'license_type')::VARCHAR", column)
+}
+
+func (postgresDialect) shootNameIn(table string, shoots []string) dbr.Builder {
+	shootNameMatch := fmt.Sprintf(`^(%s)$`, strings.Join(shoots, "|"))
+	return dbr.Expr(fmt.Sprintf("%s.data::json->>'shoot_name' ~ ?", table), shootNameMatch)
+}
+
+func (postgresDialect) secondsSinceEarliestExpiration() string {
+	return "max(extract(epoch from AGE(now(), expires_at)))"
+}
+
+func (postgresDialect) least() string {
+	return "LEAST"
+}
+
+func (postgresDialect) skipLocked() string {
+	return "FOR UPDATE SKIP LOCKED"
+}
+
+// changesLockID is the key of the advisory lock held by the transaction which writes a change
+const changesLockID = 7412086
+
+func (postgresDialect) lockChanges() string {
+	return fmt.Sprintf("SELECT pg_advisory_xact_lock(%d)", changesLockID)
+}
+
+func (postgresDialect) caseInsensitiveLike() string {
+	return "ILIKE"
+}
+
+func (postgresDialect) isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == UniqueViolationErrorCode
+}
+
+// sqliteDialect keeps the JSON columns as text and uses the JSON functions of SQLite.
+// SQLite allows only one writer at a time, so the rows do not have to be locked.
+type sqliteDialect struct{}
+
+func (sqliteDialect) timeZone() string {
+	return "'UTC'"
+}
+
+func (sqliteDialect) jsonText(column string) string {
+	return column
+}
+
+func (sqliteDialect) ersContextActive(column string) string {
+	return fmt.Sprintf("json_extract(%s, '$.ers_context.active')", column)
+}
+
+func (sqliteDialect) ersContextLicenseType(column string) string {
+	return fmt.Sprintf("(%s -> '$.ers_context.license_type')", column)
+}
+
+func (sqliteDialect) shootNameIn(table string, shoots []string) dbr.Builder {
+	return dbr.Expr(fmt.Sprintf("json_extract(%s.data, '$.shoot_name') IN ?", table), shoots)
+}
+
+func (sqliteDialect) secondsSinceEarliestExpiration() string {
+	return "max((julianday('now') - julianday(expires_at)) * 86400)"
+}
+
+func (sqliteDialect) least() string {
+	return "MIN"
+}
+
+func (sqliteDialect) skipLocked() string {
+	return ""
+}
+
+func (sqliteDialect) lockChanges() string {
+	return ""
+}
+
+// caseInsensitiveLike returns LIKE, which ignores the case of the ASCII letters in SQLite
+func (sqliteDialect) caseInsensitiveLike() string {
+	return "LIKE"
+}
+
+func (sqliteDialect) isUniqueViolation(err error) bool {
+	return err != nil && strings.Contains(err.Error(), "UNIQUE constraint failed")
+}
+
+// sqliteTimeFormat keeps the times as UTC text with the same width, so they are compared and sorted as strings.
+// The zero time is equal to the literal used in the queries for the instances which are not deleted.
+const sqliteTimeFormat = "2006-01-02T15:04:05.000Z"
+
+// sqliteEncoding is the SQLite dialect of dbr which encodes the times with sqliteTimeFormat
+type sqliteEncoding struct {
+	dbr.Dialect
+}
+
+func (sqliteEncoding) EncodeTime(t time.Time) string {
+	return `'` + t.UTC().Format(sqliteTimeFormat) + `'`
+}
+
+var _ dbr.Dialect = sqliteEncoding{Dialect: dialect.SQLite3}
@@ -1,9 +1,11 @@
 package postsql
 
 import (
+	"database/sql"
 	"time"
 
 	"github.com/gocraft/dbr"
+	"github.com/kyma-project/kyma-environment-broker/common/changes"
 	"github.com/kyma-project/kyma-environment-broker/common/events"
 	"github.com/kyma-project/kyma-environment-broker/common/runtime"
 	"github.com/kyma-project/kyma-environment-broker/internal"
@@ -16,6 +18,8 @@ type Factory interface {
 	NewReadSession() ReadSession
 	NewWriteSession() WriteSession
 	NewSessionWithinTransaction() (WriteSessionWithinTransaction, dberr.Error)
+	// BeginTransaction starts the transaction and returns the factory of the sessions within it
+	BeginTransaction() (TransactionFactory, dberr.Error)
 }
 
 //go:generate mockery --name=ReadSession
@@ -61,9 +65,19 @@ type ReadSession interface {
 	GetBinding(instanceID string, bindingID string) (dbmodel.BindingDTO, dberr.Error)
 	ListBindings(instanceID string) ([]dbmodel.BindingDTO, error)
 	ListExpiredBindings() ([]dbmodel.BindingDTO, error)
+	GetBindingByOperationID(operationID string) (dbmodel.BindingDTO, dberr.Error)
+	ListBindingsInProgress() ([]dbmodel.BindingDTO, error)
+	ListBindingsToRenew(expiringBefore time.Time) ([]dbmodel.BindingDTO, error)
 	GetBindingsStatistics() (dbmodel.BindingStatsDTO, error)
 	ListActions(instanceID string) ([]runtime.Action, error)
 	GetTimeZone() (string, dberr.Error)
+	CountQueueItems(queueName string) (int, dberr.Error)
+	ListOperationSteps(operationIDs []string) ([]dbmodel.OperationStepDTO, error)
+	ListEncryptedData(source dbmodel.EncryptedDataSource, after dbmodel.EncryptedDataDTO, limit int) ([]dbmodel.EncryptedDataDTO, error)
+	ListInstanceIDs(after string, limit int) ([]string, error)
+	CountEvents(level events.EventLevel, until time.Time) (int, error)
+	ListChanges(since int64, limit int) ([]changes.ChangeDTO, error)
+	CountChanges(until time.Time) (int, error)
 }
 
 //go:generate mockery --name=WriteSession
@@ -73,8 +87,11 @@ type WriteSession interface {
 	DeleteInstance(instanceID string) dberr.Error
 	InsertOperation(dto dbmodel.OperationDTO) dberr.Error
 	UpdateOperation(dto dbmodel.OperationDTO) dberr.Error
-	InsertEvent(level events.EventLevel, message, instanceID, operationID string) dberr.Error
+	InsertEvent(event events.EventDTO) dberr.Error
+	ImportEvent(event events.EventDTO) dberr.Error
 	DeleteEvents(until time.Time) dberr.Error
+	DeleteEventsByLevel(level events.EventLevel, until time.Time) (int, dberr.Error)
+	DeleteInstanceEvents(instanceID string) dberr.Error
 	UpsertSubaccountState(state dbmodel.SubaccountStateDTO) dberr.Error
 	DeleteState(id string) dberr.Error
 	DeleteOperationByID(operationID string) dberr.Error
@@ -84,6 +101,21 @@ type WriteSession interface {
 	DeleteBinding(instanceID, bindingID string) dberr.Error
 	UpdateInstanceLastOperation(instanceID, operationID string) error
 	InsertAction(actionType runtime.ActionType, instanceID, message, oldValue, newValue string) dberr.Error
+	ImportAction(action runtime.Action) dberr.Error
+	DeleteActions(instanceID string) dberr.Error
+	UpsertQueueItem(item dbmodel.QueueItemDTO) dberr.Error
+	InsertQueueItemIfAbsent(item dbmodel.QueueItemDTO) dberr.Error
+	LeaseQueueItem(queueName, owner string, now, leaseExpiresAt time.Time) (dbmodel.QueueItemDTO, dberr.Error)
+	ExtendQueueItemsLease(queueName, owner string, leaseExpiresAt time.Time) dberr.Error
+	DeleteQueueItem(item dbmodel.QueueItemDTO) (bool, dberr.Error)
+	RescheduleQueueItem(item dbmodel.QueueItemDTO, nextRunAt time.Time) (bool, dberr.Error)
+	ReleaseQueueItem(item dbmodel.QueueItemDTO) dberr.Error
+	InsertOperationStep(step dbmodel.OperationStepDTO) dberr.Error
+	DeleteOperationSteps(operationID string) dberr.Error
+	UpdateEncryptedData(source dbmodel.EncryptedDataSource, item dbmodel.EncryptedDataDTO, value string) (bool, dberr.Error)
+	InsertChange(change changes.ChangeDTO) dberr.Error
+	InsertOperationChange(op dbmodel.OperationDTO) dberr.Error
+	DeleteChanges(until time.Time) (int, dberr.Error)
 }
 
 type Transaction interface {
@@ -97,25 +129,49 @@ type WriteSessionWithinTransaction interface {
 	Transaction
 }
 
+// TransactionFactory opens all the sessions within one transaction, their writes are saved together by Commit
+type TransactionFactory interface {
+	Factory
+	Transaction
+}
+
+// sessionRunner runs the queries of the read sessions on the connection or within the transaction
+type sessionRunner interface {
+	dbr.SessionRunner
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+}
+
 type factory struct {
 	connection *dbr.Connection
+	dialect    sqlDialect
 }
 
 func NewFactory(connection *dbr.Connection) Factory {
 	return &factory{
 		connection: connection,
+		dialect:    postgresDialect{},
+	}
+}
+
+// NewSQLiteFactory creates the factory of the sessions for the SQLite database opened with InitializeSQLiteDatabase
+func NewSQLiteFactory(connection *dbr.Connection) Factory {
+	return &factory{
+		connection: connection,
+		dialect:    sqliteDialect{},
 	}
 }
 
 func (sf *factory) NewReadSession() ReadSession {
 	return readSession{
 		session: sf.connection.NewSession(nil),
+		dialect: sf.dialect,
 	}
 }
 
 func (sf *factory) NewWriteSession() WriteSession {
 	return writeSession{
 		session: sf.connection.NewSession(nil),
+		dialect: sf.dialect,
 	}
 }
 
@@ -130,5 +186,76 @@ func (sf *factory) NewSessionWithinTransaction() (WriteSessionWithinTransaction,
 	return writeSession{
 		session:     dbSession,
 		transaction: dbTransaction,
+		dialect:     sf.dialect,
+	}, nil
+}
+
+func (sf *factory) BeginTransaction() (TransactionFactory, dberr.Error) {
+	dbTransaction, err := sf.connection.NewSession(nil).Begin()
+	if err != nil {
+		return nil, dberr.Internal("Failed to start transaction: %s", err)
+	}
+
+	return &transactionFactory{
+		transaction: dbTransaction,
+		dialect:     sf.dialect,
+	}, nil
+}
+
+// transactionFactory opens the sessions within the transaction started by BeginTransaction,
+// the transactions of the sessions are a part of it and they are committed together with it
+type transactionFactory struct {
+	transaction *dbr.Tx
+	dialect     sqlDialect
+}
+
+func (tf *transactionFactory) NewReadSession() ReadSession {
+	return readSession{
+		session: tf.transaction,
+		dialect: tf.dialect,
+	}
+}
+
+func (tf *transactionFactory) NewWriteSession() WriteSession {
+	return writeSession{
+		transaction: tf.transaction,
+		dialect:     tf.dialect,
+	}
+}
+
+func (tf *transactionFactory) NewSessionWithinTransaction() (WriteSessionWithinTransaction, dberr.Error) {
+	return nestedTransactionSession{
+		writeSession: writeSession{
+			transaction: tf.transaction,
+			dialect:     tf.dialect,
+		},
 	}, nil
 }
+
+func (tf *transactionFactory) BeginTransaction() (TransactionFactory, dberr.Error) {
+	return nil, dberr.Internal("Failed to start transaction: the transaction is already started")
+}
+
+func (tf *transactionFactory) Commit() dberr.Error {
+	if err := tf.transaction.Commit(); err != nil {
+		return dberr.Internal("Failed to commit transaction: %s", err)
+	}
+
+	return nil
+}
+
+func (tf *transactionFactory) RollbackUnlessCommitted() {
+	tf.transaction.RollbackUnlessCommitted()
+}
+
+// nestedTransactionSession is the session within the transaction of the transactionFactory, its writes are committed
+// or rolled back with the whole transaction
+type nestedTransactionSession struct {
+	writeSession
+}
+
+func (nestedTransactionSession) Commit() dberr.Error {
+	return nil
+}
+
+func (nestedTransactionSession) RollbackUnlessCommitted() {}
@@ -20,6 +20,9 @@ const (
 	InstancesArchivedTableName = "instances_archived"
 	BindingsTableName          = "bindings"
 	ActionsTableName           = "actions"
+	QueueItemsTableName        = "queue_items"
+	OperationStepsTableName    = "operation_steps"
+	ChangesTableName           = "changes"
 )
 
 // InitializeDatabase opens database connection and initializes schema if it does not exist
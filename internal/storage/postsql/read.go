@@ -6,6 +6,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/kyma-project/kyma-environment-broker/common/changes"
 	"github.com/kyma-project/kyma-environment-broker/common/events"
 	"github.com/kyma-project/kyma-environment-broker/common/runtime"
 	"github.com/kyma-project/kyma-environment-broker/internal"
@@ -18,14 +19,15 @@ import (
 )
 
 type readSession struct {
-	session *dbr.Session
+	session sessionRunner
+	dialect sqlDialect
 }
 
 func (r readSession) GetTimeZone() (string, dberr.Error) {
 	var timeZone string
 
 	err := r.session.
-		Select("current_setting('TIMEZONE')").
+		Select(r.dialect.timeZone()).
 		LoadOne(&timeZone)
 
 	if err != nil {
@@ -58,6 +60,61 @@ func (r readSession) GetBinding(instanceID string, bindingID string) (dbmodel.Bi
 	return binding, nil
 }
 
+func (r readSession) GetBindingByOperationID(operationID string) (dbmodel.BindingDTO, dberr.Error) {
+	var binding dbmodel.BindingDTO
+
+	err := r.session.
+		Select("*").
+		From(BindingsTableName).
+		Where(dbr.Eq("operation_id", operationID)).
+		LoadOne(&binding)
+
+	if err != nil {
+		if errors.Is(err, dbr.ErrNotFound) {
+			return dbmodel.BindingDTO{}, dberr.NotFound("Cannot find the Binding for operationId:'%s'", operationID)
+		}
+		return dbmodel.BindingDTO{}, dberr.Internal("Failed to get the Binding: %s", err)
+	}
+
+	return binding, nil
+}
+
+func (r readSession) ListBindingsInProgress() ([]dbmodel.BindingDTO, error) {
+	var bindings []dbmodel.BindingDTO
+	_, err := r.session.
+		Select("*").
+		From(BindingsTableName).
+		Where(dbr.Eq("state", string(domain.InProgress))).
+		Where(dbr.Neq("operation_id", "")).
+		OrderBy("created_at").
+		Load(&bindings)
+
+	if err != nil {
+		return nil, fmt.Errorf("while getting bindings in progress: %w", err)
+	}
+
+	return bindings, nil
+}
+
+func (r readSession) ListBindingsToRenew(expiringBefore time.Time) ([]dbmodel.BindingDTO, error) {
+	var bindings []dbmodel.BindingDTO
+	_, err := r.session.
+		Select("*").
+		From(BindingsTableName).
+		Where(dbr.Eq("auto_renew", true)).
+		Where(dbr.Eq("state", string(domain.Succeeded))).
+		Where(dbr.Gt("expires_at", time.Now().UTC())).
+		Where(dbr.Lte("expires_at", expiringBefore.UTC())).
+		OrderBy("expires_at").
+		Load(&bindings)
+
+	if err != nil {
+		return nil, fmt.Errorf("while getting bindings to renew: %w", err)
+	}
+
+	return bindings, nil
+}
+
 func (r readSession) ListBindings(instanceID string) ([]dbmodel.BindingDTO, error) {
 	var bindings []dbmodel.BindingDTO
 	if len(instanceID) == 0 {
@@ -258,10 +315,17 @@ func (r readSession) ListOperations(filter dbmodel.OperationFilter) ([]dbmodel.O
 
 	stmt := r.session.Select("o.*").
 		From(dbr.I(OperationTableName).As("o")).
-		OrderBy("o.created_at")
+		OrderBy("o.created_at").
+		OrderBy("o.id")
 
 	// Add pagination if provided
-	if filter.Page > 0 && filter.PageSize > 0 {
+	switch {
+	case filter.After != nil:
+		stmt.Where("(o.created_at, o.id) > (?, ?)", filter.After.CreatedAt, filter.After.ID)
+		if filter.PageSize > 0 {
+			stmt.Limit(uint64(filter.PageSize))
+		}
+	case filter.Page > 0 && filter.PageSize > 0:
 		stmt.Paginate(uint64(filter.Page), uint64(filter.PageSize))
 	}
 
@@ -322,7 +386,8 @@ func (r readSession) CountNotFinishedOperationsByInstanceID(instanceID string) (
 func (r readSession) GetNotFinishedOperationsByType(operationType internal.OperationType) ([]dbmodel.OperationDTO, dberr.Error) {
 	stateInProgress := dbr.Eq("state", domain.InProgress)
 	statePending := dbr.Eq("state", internal.OperationStatePending)
-	stateCondition := dbr.Or(statePending, stateInProgress)
+	stateCanceling := dbr.Eq("state", internal.OperationStateCanceling)
+	stateCondition := dbr.Or(statePending, stateInProgress, stateCanceling)
 	typeCondition := dbr.Eq("type", operationType)
 	var operations []dbmodel.OperationDTO
 
@@ -485,7 +550,7 @@ func (r readSession) GetOperationStats() ([]dbmodel.OperationStatEntry, error) {
 func (r readSession) GetOperationsStatsV2() ([]dbmodel.OperationStatEntryV2, error) {
 	var rows []dbmodel.OperationStatEntryV2
 
-	_, err := r.session.Select("COUNT(*)", "type", "state", "provisioning_parameters ->> 'plan_id' AS plan_id").
+	_, err := r.session.Select("COUNT(*) AS count", "type", "state", "provisioning_parameters ->> 'plan_id' AS plan_id").
 		From(OperationTableName).
 		Where("state = ?", "in progress").
 		Where("type IN (?, ?, ?)", "provision", "deprovision", "update").
@@ -531,7 +596,7 @@ func (r readSession) GetActiveInstanceStats() ([]dbmodel.InstanceByGlobalAccount
 		From(InstancesTableName).
 		Join(dbr.I(OperationTableName).As("o1"), fmt.Sprintf("%s.last_operation_id = o1.id", InstancesTableName)).
 		Where("deleted_at = '0001-01-01T00:00:00.000Z'").
-		Where(buildInstanceStateFilters("o1", filter)).
+		Where(r.buildInstanceStateFilters("o1", filter)).
 		GroupBy(fmt.Sprintf("%s.global_account_id", InstancesTableName))
 
 	_, err := stmt.Load(&rows)
@@ -550,7 +615,7 @@ func (r readSession) GetSubaccountsInstanceStats() ([]dbmodel.InstanceBySubAccou
 		From(InstancesTableName).
 		Join(dbr.I(OperationTableName).As("o1"), fmt.Sprintf("%s.last_operation_id = o1.id", InstancesTableName)).
 		Where("deleted_at = '0001-01-01T00:00:00.000Z'").
-		Where(buildInstanceStateFilters("o1", filter)).
+		Where(r.buildInstanceStateFilters("o1", filter)).
 		GroupBy(fmt.Sprintf("%s.sub_account_id", InstancesTableName))
 
 	_, err := stmt.Load(&rows)
@@ -564,7 +629,7 @@ func (r readSession) GetCredentialsBindingStats() ([]dbmodel.InstanceByCredentia
 		From(InstancesTableName).
 		Where("deleted_at = '0001-01-01T00:00:00.000Z'").
 		Where("subscription_secret_name != ''").
-		Where("((provisioning_parameters::JSONB->>'ers_context')::JSONB->>'active')::BOOLEAN IS NOT false").
+		Where(fmt.Sprintf("%s IS NOT false", r.dialect.ersContextActive("provisioning_parameters"))).
 		GroupBy("global_account_id", "subscription_secret_name").
 		Load(&rows)
 	return rows, err
@@ -573,12 +638,12 @@ func (r readSession) GetCredentialsBindingStats() ([]dbmodel.InstanceByCredentia
 func (r readSession) GetERSContextStats() ([]dbmodel.InstanceERSContextStatsEntry, error) {
 	var rows []dbmodel.InstanceERSContextStatsEntry
 	// group existing instances by license_Type from the last operation
-	_, err := r.session.SelectBySql(`
-SELECT count(*) as total, (o.provisioning_parameters -> 'ers_context' -> 'license_type')::VARCHAR AS license_type
+	_, err := r.session.SelectBySql(fmt.Sprintf(`
+SELECT count(*) as total, %s AS license_type
 FROM instances i
          INNER JOIN operations o ON i.last_operation_id = o.id
 WHERE i.deleted_at = '0001-01-01T00:00:00.000Z'
-GROUP BY license_type;`).Load(&rows)
+GROUP BY license_type;`, r.dialect.ersContextLicenseType("o.provisioning_parameters"))).Load(&rows)
 	return rows, err
 }
 
@@ -641,19 +706,18 @@ func (r readSession) ListInstances(filter dbmodel.InstanceFilter) ([]dbmodel.Ins
 	stmt := r.session.Select("o.data", "o.state", "o.type", fmt.Sprintf("%s.*", InstancesTableName)).
 		From(InstancesTableName).
 		Join(dbr.I(OperationTableName).As("o"), fmt.Sprintf("%s.last_operation_id = o.id", InstancesTableName)).
-		OrderBy(fmt.Sprintf("%s.%s", InstancesTableName, CreatedAtField))
+		OrderBy(fmt.Sprintf("%s.%s", InstancesTableName, CreatedAtField)).
+		OrderBy(fmt.Sprintf("%s.instance_id", InstancesTableName))
 
 	if len(filter.States) > 0 || filter.Suspended != nil {
-		stateFilters := buildInstanceStateFilters("o", filter)
+		stateFilters := r.buildInstanceStateFilters("o", filter)
 		stmt.Where(stateFilters)
 	}
 
 	// Add pagination
-	if filter.Page > 0 && filter.PageSize > 0 {
-		stmt = stmt.Paginate(uint64(filter.Page), uint64(filter.PageSize))
-	}
+	addInstancePagination(stmt, filter)
 
-	addInstanceFilters(stmt, filter, "o")
+	r.addInstanceFilters(stmt, filter, "o")
 
 	_, err := stmt.Load(&instances)
 	if err != nil {
@@ -682,19 +746,18 @@ func (r readSession) ListInstancesWithSubaccountStates(filter dbmodel.InstanceFi
 		From(InstancesTableName).
 		Join(dbr.I(OperationTableName).As("o1"), fmt.Sprintf("%s.last_operation_id = o1.id", InstancesTableName)).
 		LeftJoin(dbr.I(SubaccountStatesTableName).As("ss"), fmt.Sprintf("%s.sub_account_id = ss.id", InstancesTableName)).
-		OrderBy(fmt.Sprintf("%s.%s", InstancesTableName, CreatedAtField))
+		OrderBy(fmt.Sprintf("%s.%s", InstancesTableName, CreatedAtField)).
+		OrderBy(fmt.Sprintf("%s.instance_id", InstancesTableName))
 
 	if len(filter.States) > 0 || filter.Suspended != nil {
-		stateFilters := buildInstanceStateFilters("o1", filter)
+		stateFilters := r.buildInstanceStateFilters("o1", filter)
 		stmt.Where(stateFilters)
 	}
 
 	// Add pagination
-	if filter.Page > 0 && filter.PageSize > 0 {
-		stmt = stmt.Paginate(uint64(filter.Page), uint64(filter.PageSize))
-	}
+	addInstancePagination(stmt, filter)
 
-	addInstanceFilters(stmt, filter, "o1")
+	r.addInstanceFilters(stmt, filter, "o1")
 
 	_, err := stmt.Load(&instances)
 	if err != nil {
@@ -713,6 +776,23 @@ func (r readSession) ListInstancesWithSubaccountStates(filter dbmodel.InstanceFi
 		nil
 }
 
+// addInstancePagination limits the instances to the page, the cursor takes precedence over the page number.
+// The total count of the instances is not limited by the cursor.
+func addInstancePagination(stmt *dbr.SelectStmt, filter dbmodel.InstanceFilter) {
+	switch {
+	case filter.After != nil:
+		stmt.Where(fmt.Sprintf("(%s.%s, %s.instance_id) > (?, ?)", InstancesTableName, CreatedAtField, InstancesTableName), filter.After.CreatedAt, filter.After.ID)
+		if filter.PageSize > 0 {
+			stmt.Limit(uint64(filter.PageSize))
+		}
+	case filter.Page > 0 && filter.PageSize > 0:
+		stmt.Paginate(uint64(filter.Page), uint64(filter.PageSize))
+	}
+}
+
+// likeEscaper escapes the wildcards of the LIKE patterns, so the text is matched literally
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
 func (r readSession) ListEvents(filter events.EventFilter) ([]events.EventDTO, error) {
 	var events []events.EventDTO
 	stmt := r.session.Select("*").From("events")
@@ -722,6 +802,26 @@ func (r readSession) ListEvents(filter events.EventFilter) ([]events.EventDTO, e
 	if len(filter.OperationIDs) != 0 {
 		stmt.Where(dbr.Eq("operation_id", filter.OperationIDs))
 	}
+	if len(filter.Levels) != 0 {
+		stmt.Where(dbr.Eq("level", filter.Levels))
+	}
+	if len(filter.Categories) != 0 {
+		stmt.Where(dbr.Eq("category", filter.Categories))
+	}
+	if !filter.From.IsZero() {
+		stmt.Where(dbr.Gte("created_at", filter.From))
+	}
+	if !filter.To.IsZero() {
+		stmt.Where(dbr.Lt("created_at", filter.To))
+	}
+	if filter.Text != "" {
+		pattern := "%" + likeEscaper.Replace(filter.Text) + "%"
+		like := fmt.Sprintf(`%%s %s ? ESCAPE '\'`, r.dialect.caseInsensitiveLike())
+		stmt.Where(dbr.Or(
+			dbr.Expr(fmt.Sprintf(like, "message"), pattern),
+			dbr.Expr(fmt.Sprintf(like, r.dialect.jsonText("attributes")), pattern),
+		))
+	}
 	stmt.OrderBy("created_at")
 	_, err := stmt.Load(&events)
 	return events, err
@@ -738,17 +838,17 @@ func (r readSession) getInstanceCountByLastOperationID(filter dbmodel.InstanceFi
 		Join(dbr.I(OperationTableName).As("o1"), fmt.Sprintf("%s.last_operation_id = o1.id", InstancesTableName))
 
 	if len(filter.States) > 0 || filter.Suspended != nil {
-		stateFilters := buildInstanceStateFilters("o1", filter)
+		stateFilters := r.buildInstanceStateFilters("o1", filter)
 		stmt.Where(stateFilters)
 	}
 
-	addInstanceFilters(stmt, filter, "o1")
+	r.addInstanceFilters(stmt, filter, "o1")
 	err := stmt.LoadOne(&res)
 
 	return res.Total, err
 }
 
-func buildInstanceStateFilters(table string, filter dbmodel.InstanceFilter) dbr.Builder {
+func (r readSession) buildInstanceStateFilters(table string, filter dbmodel.InstanceFilter) dbr.Builder {
 	var exprs []dbr.Builder
 	for _, s := range filter.States {
 		switch s {
@@ -804,13 +904,13 @@ func buildInstanceStateFilters(table string, filter dbmodel.InstanceFilter) dbr.
 		}
 	}
 	if filter.Suspended != nil && *filter.Suspended {
-		exprs = append(exprs, dbr.Expr("((instances.provisioning_parameters::JSONB->>'ers_context')::JSONB->>'active')::BOOLEAN IS false"))
+		exprs = append(exprs, dbr.Expr(fmt.Sprintf("%s IS false", r.dialect.ersContextActive("instances.provisioning_parameters"))))
 	}
 
 	return dbr.Or(exprs...)
 }
 
-func addInstanceFilters(stmt *dbr.SelectStmt, filter dbmodel.InstanceFilter, table string) {
+func (r readSession) addInstanceFilters(stmt *dbr.SelectStmt, filter dbmodel.InstanceFilter, table string) {
 	if len(filter.GlobalAccountIDs) > 0 {
 		stmt.Where("instances.global_account_id IN ?", filter.GlobalAccountIDs)
 	}
@@ -833,8 +933,7 @@ func addInstanceFilters(stmt *dbr.SelectStmt, filter dbmodel.InstanceFilter, tab
 		stmt.Where("instances.service_plan_id IN ?", filter.PlanIDs)
 	}
 	if len(filter.Shoots) > 0 {
-		shootNameMatch := fmt.Sprintf(`^(%s)$`, strings.Join(filter.Shoots, "|"))
-		stmt.Where(fmt.Sprintf("%s.data::json->>'shoot_name' ~ ?", table), shootNameMatch)
+		stmt.Where(r.dialect.shootNameIn(table, filter.Shoots))
 	}
 
 	if filter.Expired != nil {
@@ -982,7 +1081,7 @@ func (r readSession) ListInstancesArchived(filter dbmodel.InstanceFilter) ([]dbm
 
 func (r readSession) GetBindingsStatistics() (dbmodel.BindingStatsDTO, error) {
 	dto := dbmodel.BindingStatsDTO{}
-	statement := r.session.Select("max(extract(epoch from AGE(now(), expires_at))) as seconds_since_earliest_expiration").From(BindingsTableName)
+	statement := r.session.Select(fmt.Sprintf("%s as seconds_since_earliest_expiration", r.dialect.secondsSinceEarliestExpiration())).From(BindingsTableName)
 
 	err := statement.LoadOne(&dto)
 	if err != nil {
@@ -1016,6 +1115,104 @@ func (r readSession) ListActions(instanceID string) ([]runtime.Action, error) {
 	return actions, err
 }
 
+func (r readSession) ListOperationSteps(operationIDs []string) ([]dbmodel.OperationStepDTO, error) {
+	var steps []dbmodel.OperationStepDTO
+	if len(operationIDs) == 0 {
+		return steps, nil
+	}
+	stmt := r.session.Select("*").From(OperationStepsTableName)
+	stmt.Where("operation_id IN ?", operationIDs)
+	stmt.OrderAsc("started_at")
+	_, err := stmt.Load(&steps)
+	return steps, err
+}
+
+// ListEncryptedData returns the page of the non-empty values of the column with the encrypted data, ordered by the row ID
+func (r readSession) ListEncryptedData(source dbmodel.EncryptedDataSource, after dbmodel.EncryptedDataDTO, limit int) ([]dbmodel.EncryptedDataDTO, error) {
+	var items []dbmodel.EncryptedDataDTO
+	var stmt *dbr.SelectStmt
+	switch source {
+	case dbmodel.InstanceProvisioningParameters:
+		stmt = r.session.Select("instance_id AS id", "instance_id", "provisioning_parameters AS value").
+			From(InstancesTableName).
+			Where("instance_id > ?", after.ID).
+			OrderAsc("instance_id")
+	case dbmodel.OperationProvisioningParameters:
+		stmt = r.session.Select("id", "instance_id", fmt.Sprintf("%s AS value", r.dialect.jsonText("provisioning_parameters"))).
+			From(OperationTableName).
+			Where("provisioning_parameters IS NOT NULL").
+			Where("id > ?", after.ID).
+			OrderAsc("id")
+	case dbmodel.BindingKubeconfig:
+		stmt = r.session.Select("id", "instance_id", "kubeconfig AS value").
+			From(BindingsTableName).
+			Where("kubeconfig IS NOT NULL AND kubeconfig <> ''").
+			Where("(instance_id, id) > (?, ?)", after.InstanceID, after.ID).
+			OrderAsc("instance_id").
+			OrderAsc("id")
+	default:
+		return nil, fmt.Errorf("unknown source of encrypted data %q", source)
+	}
+	_, err := stmt.Limit(uint64(limit)).Load(&items)
+	return items, err
+}
+
+// ListInstanceIDs returns the page of the IDs of the instances ordered by the ID
+func (r readSession) ListInstanceIDs(after string, limit int) ([]string, error) {
+	var ids []string
+	_, err := r.session.Select("instance_id").
+		From(InstancesTableName).
+		Where("instance_id > ?", after).
+		OrderAsc("instance_id").
+		Limit(uint64(limit)).
+		Load(&ids)
+	return ids, err
+}
+
+func (r readSession) CountEvents(level events.EventLevel, until time.Time) (int, error) {
+	var res struct {
+		Total int
+	}
+	err := r.session.Select("count(*) as total").
+		From("events").
+		Where(dbr.Eq("level", level)).
+		Where(dbr.Lte("created_at", until)).
+		LoadOne(&res)
+	if err != nil {
+		return 0, dberr.Internal("Failed to count events: %s", err)
+	}
+	return res.Total, nil
+}
+
+// ListChanges returns the changes with the sequence numbers greater than since, ordered by the sequence number
+func (r readSession) ListChanges(since int64, limit int) ([]changes.ChangeDTO, error) {
+	var result []changes.ChangeDTO
+	_, err := r.session.Select("*").
+		From(ChangesTableName).
+		Where("sequence > ?", since).
+		OrderAsc("sequence").
+		Limit(uint64(limit)).
+		Load(&result)
+	if err != nil {
+		return nil, dberr.Internal("Failed to list changes: %s", err)
+	}
+	return result, nil
+}
+
+func (r readSession) CountChanges(until time.Time) (int, error) {
+	var res struct {
+		Total int
+	}
+	err := r.session.Select("count(*) as total").
+		From(ChangesTableName).
+		Where(dbr.Lte("created_at", until)).
+		LoadOne(&res)
+	if err != nil {
+		return 0, dberr.Internal("Failed to count changes: %s", err)
+	}
+	return res.Total, nil
+}
+
 func addInstanceArchivedFilter(stmt *dbr.SelectStmt, filter dbmodel.InstanceFilter) {
 	if len(filter.InstanceIDs) > 0 {
 		stmt.Where("instance_id IN ?", filter.InstanceIDs)
@@ -1038,4 +1235,30 @@ func addInstanceArchivedFilter(stmt *dbr.SelectStmt, filter dbmodel.InstanceFilt
 	if len(filter.Shoots) > 0 {
 		stmt.Where("shoot_name IN ?", filter.Shoots)
 	}
+	if len(filter.PlanIDs) > 0 {
+		stmt.Where("plan_id IN ?", filter.PlanIDs)
+	}
+	if len(filter.Providers) > 0 {
+		stmt.Where("provider IN ?", filter.Providers)
+	}
+	if !filter.DeprovisionedFrom.IsZero() {
+		stmt.Where(dbr.Gte("last_deprovisioning_finished_at", filter.DeprovisionedFrom))
+	}
+	if !filter.DeprovisionedTo.IsZero() {
+		stmt.Where(dbr.Lt("last_deprovisioning_finished_at", filter.DeprovisionedTo))
+	}
+}
+
+func (r readSession) CountQueueItems(queueName string) (int, dberr.Error) {
+	var res struct {
+		Total int
+	}
+	err := r.session.Select("count(*) as total").
+		From(QueueItemsTableName).
+		Where(dbr.Eq("queue_name", queueName)).
+		LoadOne(&res)
+	if err != nil {
+		return 0, dberr.Internal("Failed to count queue items: %s", err)
+	}
+	return res.Total, nil
 }
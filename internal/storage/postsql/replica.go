@@ -0,0 +1,116 @@
+package postsql
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/gocraft/dbr"
+	"github.com/kyma-project/kyma-environment-broker/internal/storage/dberr"
+)
+
+const (
+	// replicaLagCheckInterval is the time for which the measured lag of the replica is reused
+	replicaLagCheckInterval = 10 * time.Second
+	// replicaLagCheckTimeout limits the lag query, so a replica which does not respond is detected quickly
+	replicaLagCheckTimeout = 2 * time.Second
+)
+
+// replicationLagQuery returns the time since the last transaction replayed by the replica, or 0 when the replica
+// has replayed everything it received, so an idle primary database is not reported as a lag
+const replicationLagQuery = `SELECT CASE
+    WHEN pg_last_wal_receive_lsn() = pg_last_wal_replay_lsn() THEN 0
+    ELSE COALESCE(EXTRACT(EPOCH FROM now() - pg_last_xact_replay_timestamp()), 0)
+END`
+
+// replicaFactory opens the read sessions on the read replica and the write sessions on the primary database.
+// When the replica is not healthy, which means the lag cannot be checked, or it lags behind the primary database
+// by more than maxStaleness, the read sessions are opened on the primary database.
+type replicaFactory struct {
+	*factory
+	replica      *dbr.Connection
+	maxStaleness time.Duration
+
+	mu         sync.Mutex
+	checkedAt  time.Time
+	checking   bool
+	useReplica bool
+	now        func() time.Time
+	lag        func() (time.Duration, error)
+}
+
+// NewReplicaFactory creates the factory which reads from the replica, maxStaleness 0 turns off the lag limit, but the health of the replica is still checked
+func NewReplicaFactory(primary, replica *dbr.Connection, maxStaleness time.Duration) Factory {
+	f := &replicaFactory{
+		factory:      &factory{connection: primary, dialect: postgresDialect{}},
+		replica:      replica,
+		maxStaleness: maxStaleness,
+		now:          time.Now,
+	}
+	f.lag = f.replicationLag
+	return f
+}
+
+// NewReplicaSessions returns the function which opens the sessions on the read replica when it is healthy,
+// otherwise on the primary database. It is meant for the readers which run their own queries.
+func NewReplicaSessions(primary, replica *dbr.Connection, maxStaleness time.Duration) func() *dbr.Session {
+	return NewReplicaFactory(primary, replica, maxStaleness).(*replicaFactory).newSession
+}
+
+func (f *replicaFactory) NewReadSession() ReadSession {
+	return readSession{
+		session: f.newSession(),
+		dialect: f.dialect,
+	}
+}
+
+func (f *replicaFactory) newSession() *dbr.Session {
+	if !f.replicaHealthy() {
+		return f.connection.NewSession(nil)
+	}
+	return f.replica.NewSession(nil)
+}
+
+// replicaHealthy returns the result of the last lag check. The lag is checked outside the lock by one caller at a time,
+// the other callers do not wait for the check and use the last result.
+func (f *replicaFactory) replicaHealthy() bool {
+	f.mu.Lock()
+	if f.checking || f.now().Sub(f.checkedAt) < replicaLagCheckInterval {
+		defer f.mu.Unlock()
+		return f.useReplica
+	}
+	f.checking = true
+	f.mu.Unlock()
+
+	lag, err := f.lag()
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.checking = false
+	f.checkedAt = f.now()
+	switch {
+	case err != nil:
+		slog.Warn(fmt.Sprintf("unable to check the replication lag, reading from the primary database: %s", err))
+		f.useReplica = false
+	case f.maxStaleness > 0 && lag > f.maxStaleness:
+		if f.useReplica {
+			slog.Warn(fmt.Sprintf("the replication lag %s exceeds %s, reading from the primary database", lag, f.maxStaleness))
+		}
+		f.useReplica = false
+	default:
+		f.useReplica = true
+	}
+	return f.useReplica
+}
+
+func (f *replicaFactory) replicationLag() (time.Duration, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), replicaLagCheckTimeout)
+	defer cancel()
+	var seconds float64
+	if err := f.replica.QueryRowContext(ctx, replicationLagQuery).Scan(&seconds); err != nil {
+		return 0, dberr.Internal("Failed to get the replication lag: %s", err)
+	}
+	return time.Duration(seconds * float64(time.Second)), nil
+}
@@ -0,0 +1,95 @@
+package postsql
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReplicaFactory_ReplicaHealthy(t *testing.T) {
+	// given
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	lag := time.Second
+	var lagErr error
+	checks := 0
+	f := &replicaFactory{
+		maxStaleness: 30 * time.Second,
+		now:          func() time.Time { return now },
+		lag: func() (time.Duration, error) {
+			checks++
+			return lag, lagErr
+		},
+	}
+
+	// when the replica is up to date
+	assert.True(t, f.replicaHealthy())
+
+	// when the lag grows, the last result is reused until the next check
+	lag = time.Minute
+	assert.True(t, f.replicaHealthy())
+	assert.Equal(t, 1, checks)
+	now = now.Add(replicaLagCheckInterval)
+	assert.False(t, f.replicaHealthy())
+	assert.Equal(t, 2, checks)
+
+	// when the lag cannot be checked
+	lag, lagErr = 0, errors.New("connection refused")
+	now = now.Add(replicaLagCheckInterval)
+	assert.False(t, f.replicaHealthy())
+
+	// when the replica caught up
+	lagErr = nil
+	now = now.Add(replicaLagCheckInterval)
+	assert.True(t, f.replicaHealthy())
+}
+
+func TestReplicaFactory_WithoutStalenessLimit(t *testing.T) {
+	// given
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	var lagErr error
+	f := &replicaFactory{
+		now: func() time.Time { return now },
+		lag: func() (time.Duration, error) {
+			return time.Hour, lagErr
+		},
+	}
+
+	// when the replica lags behind
+	assert.True(t, f.replicaHealthy())
+
+	// when the replica is not available
+	lagErr = errors.New("connection refused")
+	now = now.Add(replicaLagCheckInterval)
+	assert.False(t, f.replicaHealthy())
+}
+
+func TestReplicaFactory_SlowLagCheck(t *testing.T) {
+	// given
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	started := make(chan struct{})
+	release := make(chan struct{})
+	f := &replicaFactory{
+		maxStaleness: 30 * time.Second,
+		now:          func() time.Time { return now },
+		lag: func() (time.Duration, error) {
+			close(started)
+			<-release
+			return time.Second, nil
+		},
+	}
+	checked := make(chan bool)
+	go func() {
+		checked <- f.replicaHealthy()
+	}()
+	<-started
+
+	// when the lag is checked, the other reads use the primary database without waiting
+	assert.False(t, f.replicaHealthy())
+
+	// when the check is finished
+	close(release)
+	assert.True(t, <-checked)
+	assert.True(t, f.replicaHealthy())
+}
@@ -0,0 +1,49 @@
+package postsql
+
+import (
+	"database/sql"
+	_ "embed"
+	"fmt"
+
+	"github.com/gocraft/dbr"
+	"github.com/gocraft/dbr/dialect"
+)
+
+// SQLiteDriverName is the name of the database/sql driver used for SQLite, the binary which uses the SQLite storage
+// registers the driver by importing modernc.org/sqlite
+const SQLiteDriverName = "sqlite"
+
+//go:embed sqlite_schema.sql
+var sqliteSchema string
+
+// InitializeSQLiteDatabase opens the SQLite database, for example, a file path or ":memory:", and creates the schema if it does not exist
+func InitializeSQLiteDatabase(dataSourceName string) (*dbr.Connection, error) {
+	db, err := sql.Open(SQLiteDriverName, dataSourceName)
+	if err != nil {
+		return nil, fmt.Errorf("while opening SQLite database: %w", err)
+	}
+	// SQLite allows only one writer at a time and every connection to ":memory:" opens a separate database,
+	// so all sessions share one connection which is never closed
+	db.SetMaxOpenConns(1)
+	db.SetMaxIdleConns(1)
+	db.SetConnMaxLifetime(0)
+
+	connection := &dbr.Connection{
+		DB:            db,
+		EventReceiver: &dbr.NullEventReceiver{},
+		Dialect:       sqliteEncoding{Dialect: dialect.SQLite3},
+	}
+
+	for _, pragma := range []string{"PRAGMA journal_mode = WAL", "PRAGMA busy_timeout = 5000"} {
+		if _, err := connection.Exec(pragma); err != nil {
+			closeDBConnection(connection)
+			return nil, fmt.Errorf("while setting %q: %w", pragma, err)
+		}
+	}
+	if _, err := connection.Exec(sqliteSchema); err != nil {
+		closeDBConnection(connection)
+		return nil, fmt.Errorf("while creating SQLite schema: %w", err)
+	}
+
+	return connection, nil
+}
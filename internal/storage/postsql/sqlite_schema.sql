@@ -0,0 +1,164 @@
+-- The schema of the SQLite database used for local development and tests.
+-- It reflects the result of all migrations from resources/keb/migrations, update it together with a new migration.
+-- TestSQLiteSchema in internal/storage/driver/postsql compares its tables and columns with the migrated PostgreSQL database.
+-- The times are kept as UTC text with millisecond precision, see sqliteTimeFormat.
+
+CREATE TABLE IF NOT EXISTS instances (
+    instance_id                    varchar(255) PRIMARY KEY,
+    runtime_id                     varchar(255) NOT NULL,
+    global_account_id              varchar(255) NOT NULL,
+    subscription_global_account_id text DEFAULT '',
+    sub_account_id                 varchar(255) DEFAULT '',
+    service_id                     varchar(255) NOT NULL,
+    service_name                   varchar(255) DEFAULT '',
+    service_plan_id                varchar(255) NOT NULL,
+    service_plan_name              varchar(255) DEFAULT '',
+    subscription_secret_name       varchar(253) DEFAULT '',
+    dashboard_url                  varchar(255) NOT NULL,
+    provisioning_parameters        text NOT NULL,
+    provider_region                varchar(32) DEFAULT '',
+    provider                       varchar(255) DEFAULT '',
+    created_at                     timestamp NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
+    updated_at                     timestamp NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
+    deleted_at                     timestamp NOT NULL DEFAULT '0001-01-01T00:00:00.000Z',
+    expired_at                     timestamp,
+    version                        integer NOT NULL DEFAULT 0,
+    last_operation_id              varchar(255) DEFAULT '',
+    empty_updates                  integer DEFAULT 0
+);
+CREATE INDEX IF NOT EXISTS instances_by_created_at ON instances (created_at);
+CREATE INDEX IF NOT EXISTS instances_by_created_at_instance_id ON instances (created_at, instance_id);
+
+CREATE TABLE IF NOT EXISTS operations (
+    id                      varchar(255) PRIMARY KEY,
+    instance_id             varchar(255) NOT NULL,
+    target_operation_id     varchar(255) NOT NULL,
+    version                 integer NOT NULL,
+    state                   varchar(32) NOT NULL,
+    description             text NOT NULL,
+    type                    varchar(32) NOT NULL,
+    data                    text NOT NULL,
+    created_at              timestamp NOT NULL,
+    updated_at              timestamp NOT NULL,
+    orchestration_id        varchar(64),
+    provisioning_parameters text NOT NULL,
+    finished_stages         text
+);
+CREATE INDEX IF NOT EXISTS operations_by_instance_id ON operations (instance_id);
+CREATE INDEX IF NOT EXISTS operations_by_iid_created_at ON operations (instance_id, created_at);
+CREATE INDEX IF NOT EXISTS operations_by_type_state_created_at ON operations (type, state, created_at);
+CREATE INDEX IF NOT EXISTS operations_by_created_at_id ON operations (created_at, id);
+
+CREATE TABLE IF NOT EXISTS events (
+    id           varchar(255) NOT NULL PRIMARY KEY,
+    level        varchar(32) NOT NULL CHECK (level IN ('debug', 'info', 'warning', 'error')),
+    instance_id  varchar(255),
+    operation_id varchar(255),
+    category     varchar(255) NOT NULL DEFAULT '',
+    message      text NOT NULL,
+    attributes   text,
+    created_at   timestamp NOT NULL
+);
+CREATE INDEX IF NOT EXISTS events_operation_id ON events (operation_id);
+CREATE INDEX IF NOT EXISTS events_created_at ON events (created_at);
+
+CREATE TABLE IF NOT EXISTS instances_archived (
+    instance_id                      varchar(255) NOT NULL PRIMARY KEY,
+    global_account_id                varchar(64) NOT NULL,
+    last_runtime_id                  varchar(64) NOT NULL,
+    subscription_global_account_id   varchar(64) NOT NULL,
+    subaccount_id                    varchar(64) NOT NULL,
+    plan_id                          varchar(40) NOT NULL,
+    plan_name                        varchar(32) NOT NULL,
+    region                           varchar(32) NOT NULL,
+    subaccount_region                varchar(32) NOT NULL,
+    provider                         varchar(32) NOT NULL,
+    shoot_name                       varchar(32) NOT NULL,
+    internal_user                    boolean NOT NULL,
+    provisioning_started_at          timestamp NOT NULL,
+    provisioning_finished_at         timestamp NOT NULL,
+    provisioning_state               varchar(32),
+    first_deprovisioning_started_at  timestamp NOT NULL,
+    first_deprovisioning_finished_at timestamp NOT NULL,
+    last_deprovisioning_finished_at  timestamp NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS subaccount_states (
+    id                  varchar(255) PRIMARY KEY,
+    beta_enabled        varchar(255) NOT NULL,
+    used_for_production varchar(255),
+    modified_at         bigint NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS bindings (
+    id                 varchar(255) NOT NULL,
+    instance_id        varchar(255) NOT NULL,
+    created_at         timestamp NOT NULL,
+    kubeconfig         text,
+    expiration_seconds integer,
+    expires_at         timestamp,
+    created_by         varchar(255),
+    state              varchar(32) NOT NULL DEFAULT 'succeeded',
+    description        text NOT NULL DEFAULT '',
+    operation_id       varchar(255) NOT NULL DEFAULT '',
+    credentials_type   varchar(32) NOT NULL DEFAULT 'service_account',
+    oidc_group         varchar(255) NOT NULL DEFAULT '',
+    auto_renew         boolean NOT NULL DEFAULT false,
+    role               varchar(64) NOT NULL DEFAULT '',
+    namespaces         text NOT NULL DEFAULT '',
+    PRIMARY KEY (id, instance_id)
+);
+CREATE INDEX IF NOT EXISTS bindings_by_instance_id ON bindings (instance_id);
+CREATE INDEX IF NOT EXISTS bindings_by_id ON bindings (id);
+CREATE INDEX IF NOT EXISTS bindings_by_operation_id ON bindings (operation_id);
+CREATE INDEX IF NOT EXISTS bindings_by_state ON bindings (state);
+
+CREATE TABLE IF NOT EXISTS actions (
+    id          varchar(255) NOT NULL PRIMARY KEY,
+    type        varchar(32) NOT NULL CHECK (type IN ('plan_update', 'subaccount_movement', 'operation_retry')),
+    instance_id varchar(255) NOT NULL,
+    message     text NOT NULL,
+    old_value   varchar(255) NOT NULL,
+    new_value   varchar(255) NOT NULL,
+    created_at  timestamp NOT NULL
+);
+CREATE INDEX IF NOT EXISTS actions_instance_id ON actions (instance_id);
+
+CREATE TABLE IF NOT EXISTS queue_items (
+    queue_name       varchar(255) NOT NULL,
+    operation_id     varchar(255) NOT NULL,
+    next_run_at      timestamp NOT NULL,
+    lease_owner      varchar(255) NOT NULL DEFAULT '',
+    lease_expires_at timestamp NOT NULL DEFAULT '0001-01-01T00:00:00.000Z',
+    version          integer NOT NULL DEFAULT 0,
+    created_at       timestamp NOT NULL,
+    PRIMARY KEY (queue_name, operation_id)
+);
+CREATE INDEX IF NOT EXISTS queue_items_by_queue_next_run_at ON queue_items (queue_name, next_run_at);
+
+CREATE TABLE IF NOT EXISTS operation_steps (
+    id                  varchar(255) NOT NULL PRIMARY KEY,
+    operation_id        varchar(255) NOT NULL,
+    stage               varchar(255) NOT NULL DEFAULT '',
+    step                varchar(255) NOT NULL,
+    started_at          timestamp NOT NULL,
+    finished_at         timestamp NOT NULL,
+    outcome             varchar(32) NOT NULL,
+    retry_after_seconds real NOT NULL DEFAULT 0,
+    error_reason        text NOT NULL DEFAULT '',
+    error_component     varchar(255) NOT NULL DEFAULT '',
+    error_message       text NOT NULL DEFAULT ''
+);
+CREATE INDEX IF NOT EXISTS operation_steps_operation_id ON operation_steps (operation_id, started_at);
+
+CREATE TABLE IF NOT EXISTS changes (
+    sequence            INTEGER PRIMARY KEY AUTOINCREMENT,
+    kind                varchar(32) NOT NULL,
+    instance_id         varchar(255) NOT NULL,
+    operation_id        varchar(255) NOT NULL DEFAULT '',
+    operation_type      varchar(32) NOT NULL DEFAULT '',
+    state               varchar(32) NOT NULL,
+    previous_state      varchar(32) NOT NULL DEFAULT '',
+    created_at          timestamp NOT NULL
+);
+CREATE INDEX IF NOT EXISTS changes_created_at ON changes (created_at);
@@ -1,8 +1,10 @@
 package postsql
 
 import (
+	"fmt"
 	"time"
 
+	"github.com/kyma-project/kyma-environment-broker/common/changes"
 	"github.com/kyma-project/kyma-environment-broker/common/events"
 	"github.com/kyma-project/kyma-environment-broker/common/runtime"
 	"github.com/kyma-project/kyma-environment-broker/internal/storage/dberr"
@@ -10,7 +12,6 @@ import (
 
 	"github.com/gocraft/dbr"
 	"github.com/google/uuid"
-	"github.com/lib/pq"
 )
 
 const (
@@ -20,6 +21,7 @@ const (
 type writeSession struct {
 	session     *dbr.Session
 	transaction *dbr.Tx
+	dialect     sqlDialect
 }
 
 func (ws writeSession) UpdateInstanceLastOperation(instanceID, operationID string) error {
@@ -55,13 +57,19 @@ func (ws writeSession) InsertBinding(binding dbmodel.BindingDTO) dberr.Error {
 		Pair("kubeconfig", binding.Kubeconfig).
 		Pair("expiration_seconds", binding.ExpirationSeconds).
 		Pair("created_by", binding.CreatedBy).
+		Pair("state", binding.State).
+		Pair("description", binding.Description).
+		Pair("operation_id", binding.OperationID).
+		Pair("credentials_type", binding.CredentialsType).
+		Pair("oidc_group", binding.Group).
+		Pair("auto_renew", binding.AutoRenew).
+		Pair("role", binding.Role).
+		Pair("namespaces", binding.Namespaces).
 		Exec()
 
 	if err != nil {
-		if err, ok := err.(*pq.Error); ok {
-			if err.Code == UniqueViolationErrorCode {
-				return dberr.AlreadyExists("binding with id %s already exist for runtime %s", binding.ID, binding.InstanceID)
-			}
+		if ws.dialect.isUniqueViolation(err) {
+			return dberr.AlreadyExists("binding with id %s already exist for runtime %s", binding.ID, binding.InstanceID)
 		}
 		return dberr.Internal("Failed to insert record to Binding table: %s", err)
 	}
@@ -73,6 +81,8 @@ func (ws writeSession) UpdateBinding(binding dbmodel.BindingDTO) dberr.Error {
 	_, err := ws.update(BindingsTableName).
 		Set("kubeconfig", binding.Kubeconfig).
 		Set("expires_at", binding.ExpiresAt).
+		Set("state", binding.State).
+		Set("description", binding.Description).
 		Where(dbr.Eq("id", binding.ID)).
 		Where(dbr.Eq("instance_id", binding.InstanceID)).
 		Exec()
@@ -107,10 +117,8 @@ func (ws writeSession) InsertInstanceArchived(instance dbmodel.InstanceArchivedD
 		Exec()
 
 	if err != nil {
-		if err, ok := err.(*pq.Error); ok {
-			if err.Code == UniqueViolationErrorCode {
-				return dberr.AlreadyExists("instance archived with id %s already exist", instance.InstanceID)
-			}
+		if ws.dialect.isUniqueViolation(err) {
+			return dberr.AlreadyExists("instance archived with id %s already exist", instance.InstanceID)
 		}
 		return dberr.Internal("Failed to insert record to Instance table: %s", err)
 	}
@@ -141,10 +149,8 @@ func (ws writeSession) InsertInstance(instance dbmodel.InstanceDTO) dberr.Error
 		Exec()
 
 	if err != nil {
-		if err, ok := err.(*pq.Error); ok {
-			if err.Code == UniqueViolationErrorCode {
-				return dberr.AlreadyExists("operation with id %s already exist", instance.InstanceID)
-			}
+		if ws.dialect.isUniqueViolation(err) {
+			return dberr.AlreadyExists("operation with id %s already exist", instance.InstanceID)
 		}
 		return dberr.Internal("Failed to insert record to Instance table: %s", err)
 	}
@@ -217,10 +223,8 @@ func (ws writeSession) InsertOperation(op dbmodel.OperationDTO) dberr.Error {
 		Exec()
 
 	if err != nil {
-		if err, ok := err.(*pq.Error); ok {
-			if err.Code == UniqueViolationErrorCode {
-				return dberr.AlreadyExists("operation with id %s already exist", op.ID)
-			}
+		if ws.dialect.isUniqueViolation(err) {
+			return dberr.AlreadyExists("operation with id %s already exist", op.ID)
 		}
 		return dberr.Internal("Failed to insert record to operations table: %s", err)
 	}
@@ -377,14 +381,23 @@ func (ws writeSession) UpdateOperation(op dbmodel.OperationDTO) dberr.Error {
 	return nil
 }
 
-func (ws writeSession) InsertEvent(level events.EventLevel, message, instanceID, operationID string) dberr.Error {
+func (ws writeSession) InsertEvent(event events.EventDTO) dberr.Error {
+	event.ID = uuid.NewString()
+	event.CreatedAt = time.Now()
+	return ws.ImportEvent(event)
+}
+
+// ImportEvent stores the event with its ID and creation time
+func (ws writeSession) ImportEvent(event events.EventDTO) dberr.Error {
 	_, err := ws.insertInto("events").
-		Pair("id", uuid.NewString()).
-		Pair("level", level).
-		Pair("instance_id", instanceID).
-		Pair("operation_id", operationID).
-		Pair("message", message).
-		Pair("created_at", time.Now()).
+		Pair("id", event.ID).
+		Pair("level", event.Level).
+		Pair("instance_id", event.InstanceID).
+		Pair("operation_id", event.OperationID).
+		Pair("category", event.Category).
+		Pair("message", event.Message).
+		Pair("attributes", event.Attributes).
+		Pair("created_at", event.CreatedAt).
 		Exec()
 	if err != nil {
 		return dberr.Internal("Failed to insert event: %s", err)
@@ -402,6 +415,31 @@ func (ws writeSession) DeleteEvents(until time.Time) dberr.Error {
 	return nil
 }
 
+func (ws writeSession) DeleteEventsByLevel(level events.EventLevel, until time.Time) (int, dberr.Error) {
+	res, err := ws.deleteFrom("events").
+		Where(dbr.Eq("level", level)).
+		Where(dbr.Lte("created_at", until)).
+		Exec()
+	if err != nil {
+		return 0, dberr.Internal("failed to delete %s events created until %v: %v", level, until.Format(time.RFC1123Z), err)
+	}
+	deleted, err := res.RowsAffected()
+	if err != nil {
+		return 0, dberr.Internal("the DB driver does not support RowsAffected operation")
+	}
+	return int(deleted), nil
+}
+
+func (ws writeSession) DeleteInstanceEvents(instanceID string) dberr.Error {
+	_, err := ws.deleteFrom("events").
+		Where(dbr.Eq("instance_id", instanceID)).
+		Exec()
+	if err != nil {
+		return dberr.Internal("failed to delete events of instance %s: %v", instanceID, err)
+	}
+	return nil
+}
+
 func (ws writeSession) DeleteOperationByID(id string) dberr.Error {
 	_, err := ws.deleteFrom("operations").
 		Where(dbr.Eq("id", id)).
@@ -413,14 +451,27 @@ func (ws writeSession) DeleteOperationByID(id string) dberr.Error {
 }
 
 func (ws writeSession) InsertAction(actionType runtime.ActionType, instanceID, message, oldValue, newValue string) dberr.Error {
+	return ws.ImportAction(runtime.Action{
+		ID:         uuid.NewString(),
+		Type:       actionType,
+		InstanceID: instanceID,
+		Message:    message,
+		OldValue:   oldValue,
+		NewValue:   newValue,
+		CreatedAt:  time.Now(),
+	})
+}
+
+// ImportAction stores the action with its ID and creation time
+func (ws writeSession) ImportAction(action runtime.Action) dberr.Error {
 	_, err := ws.insertInto(ActionsTableName).
-		Pair("id", uuid.NewString()).
-		Pair("type", actionType).
-		Pair("instance_id", instanceID).
-		Pair("message", message).
-		Pair("old_value", oldValue).
-		Pair("new_value", newValue).
-		Pair("created_at", time.Now()).
+		Pair("id", action.ID).
+		Pair("type", action.Type).
+		Pair("instance_id", action.InstanceID).
+		Pair("message", action.Message).
+		Pair("old_value", action.OldValue).
+		Pair("new_value", action.NewValue).
+		Pair("created_at", action.CreatedAt).
 		Exec()
 	if err != nil {
 		return dberr.Internal("failed to insert action: %s", err)
@@ -428,6 +479,269 @@ func (ws writeSession) InsertAction(actionType runtime.ActionType, instanceID, m
 	return nil
 }
 
+func (ws writeSession) DeleteActions(instanceID string) dberr.Error {
+	_, err := ws.deleteFrom(ActionsTableName).
+		Where(dbr.Eq("instance_id", instanceID)).
+		Exec()
+	if err != nil {
+		return dberr.Internal("failed to delete actions of instance %s: %s", instanceID, err)
+	}
+	return nil
+}
+
+func (ws writeSession) InsertOperationStep(step dbmodel.OperationStepDTO) dberr.Error {
+	_, err := ws.insertInto(OperationStepsTableName).
+		Pair("id", step.ID).
+		Pair("operation_id", step.OperationID).
+		Pair("stage", step.Stage).
+		Pair("step", step.Step).
+		Pair("started_at", step.StartedAt).
+		Pair("finished_at", step.FinishedAt).
+		Pair("outcome", step.Outcome).
+		Pair("retry_after_seconds", step.RetryAfterSeconds).
+		Pair("error_reason", step.ErrorReason).
+		Pair("error_component", step.ErrorComponent).
+		Pair("error_message", step.ErrorMessage).
+		Exec()
+	if err != nil {
+		return dberr.Internal("failed to insert operation step: %s", err)
+	}
+	return nil
+}
+
+func (ws writeSession) DeleteOperationSteps(operationID string) dberr.Error {
+	_, err := ws.deleteFrom(OperationStepsTableName).
+		Where(dbr.Eq("operation_id", operationID)).
+		Exec()
+	if err != nil {
+		return dberr.Internal("unable to delete steps of the operation %s: %s", operationID, err)
+	}
+	return nil
+}
+
+// UpdateEncryptedData replaces the value of the column with the encrypted data, the value is not replaced if it was changed
+// after it had been read. The version of the row is not changed, because the decrypted data stays the same.
+func (ws writeSession) UpdateEncryptedData(source dbmodel.EncryptedDataSource, item dbmodel.EncryptedDataDTO, value string) (bool, dberr.Error) {
+	var stmt *dbr.UpdateStmt
+	switch source {
+	case dbmodel.InstanceProvisioningParameters:
+		stmt = ws.update(InstancesTableName).
+			Set("provisioning_parameters", value).
+			Where(dbr.Eq("instance_id", item.ID)).
+			Where(dbr.Eq("provisioning_parameters", item.Value))
+	case dbmodel.OperationProvisioningParameters:
+		stmt = ws.update(OperationTableName).
+			Set("provisioning_parameters", value).
+			Where(dbr.Eq("id", item.ID)).
+			Where(fmt.Sprintf("%s = ?", ws.dialect.jsonText("provisioning_parameters")), item.Value)
+	case dbmodel.BindingKubeconfig:
+		stmt = ws.update(BindingsTableName).
+			Set("kubeconfig", value).
+			Where(dbr.Eq("id", item.ID)).
+			Where(dbr.Eq("instance_id", item.InstanceID)).
+			Where(dbr.Eq("kubeconfig", item.Value))
+	default:
+		return false, dberr.Internal("unknown source of encrypted data %q", source)
+	}
+	result, err := stmt.Exec()
+	if err != nil {
+		return false, dberr.Internal("Failed to update encrypted data in %s: %s", source, err)
+	}
+	rAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, dberr.Internal("the DB driver does not support RowsAffected operation %s", err)
+	}
+	return rAffected > 0, nil
+}
+
+// UpsertQueueItem schedules the item, an already scheduled item is moved to the earlier time and its version is incremented
+func (ws writeSession) UpsertQueueItem(item dbmodel.QueueItemDTO) dberr.Error {
+	_, err := ws.updateBySql(fmt.Sprintf(`
+INSERT INTO %s (queue_name, operation_id, next_run_at, lease_owner, lease_expires_at, version, created_at)
+VALUES (?, ?, ?, '', ?, 0, ?)
+ON CONFLICT (queue_name, operation_id) DO UPDATE
+SET next_run_at = %[2]s(%[1]s.next_run_at, EXCLUDED.next_run_at), version = %[1]s.version + 1`, QueueItemsTableName, ws.dialect.least()),
+		item.QueueName, item.OperationID, item.NextRunAt, time.Time{}, item.CreatedAt).Exec()
+	if err != nil {
+		return dberr.Internal("Failed to upsert record to queue_items table: %s", err)
+	}
+	return nil
+}
+
+func (ws writeSession) InsertQueueItemIfAbsent(item dbmodel.QueueItemDTO) dberr.Error {
+	_, err := ws.updateBySql(fmt.Sprintf(`
+INSERT INTO %s (queue_name, operation_id, next_run_at, lease_owner, lease_expires_at, version, created_at)
+VALUES (?, ?, ?, '', ?, 0, ?)
+ON CONFLICT (queue_name, operation_id) DO NOTHING`, QueueItemsTableName),
+		item.QueueName, item.OperationID, item.NextRunAt, time.Time{}, item.CreatedAt).Exec()
+	if err != nil {
+		return dberr.Internal("Failed to insert record to queue_items table: %s", err)
+	}
+	return nil
+}
+
+// LeaseQueueItem takes the item which is due and not leased (or its lease expired), rows locked by other sessions are skipped
+func (ws writeSession) LeaseQueueItem(queueName, owner string, now, leaseExpiresAt time.Time) (dbmodel.QueueItemDTO, dberr.Error) {
+	var items []dbmodel.QueueItemDTO
+	_, err := ws.selectBySql(fmt.Sprintf(`
+UPDATE %[1]s SET lease_owner = ?, lease_expires_at = ?
+WHERE (queue_name, operation_id) = (
+    SELECT queue_name, operation_id FROM %[1]s
+    WHERE queue_name = ? AND next_run_at <= ? AND lease_expires_at < ?
+    ORDER BY next_run_at
+    LIMIT 1
+    %[2]s
+)
+RETURNING queue_name, operation_id, next_run_at, lease_owner, lease_expires_at, version, created_at`, QueueItemsTableName, ws.dialect.skipLocked()),
+		owner, leaseExpiresAt, queueName, now, now).Load(&items)
+	if err != nil {
+		return dbmodel.QueueItemDTO{}, dberr.Internal("Failed to lease record from queue_items table: %s", err)
+	}
+	if len(items) == 0 {
+		return dbmodel.QueueItemDTO{}, dberr.NotFound("no queue items to process in queue %s", queueName)
+	}
+	return items[0], nil
+}
+
+func (ws writeSession) ExtendQueueItemsLease(queueName, owner string, leaseExpiresAt time.Time) dberr.Error {
+	_, err := ws.update(QueueItemsTableName).
+		Set("lease_expires_at", leaseExpiresAt).
+		Where(dbr.Eq("queue_name", queueName)).
+		Where(dbr.Eq("lease_owner", owner)).
+		Exec()
+	if err != nil {
+		return dberr.Internal("Failed to extend lease of queue_items: %s", err)
+	}
+	return nil
+}
+
+// DeleteQueueItem removes the leased item, returns false if the item was added to the queue again in the meantime
+func (ws writeSession) DeleteQueueItem(item dbmodel.QueueItemDTO) (bool, dberr.Error) {
+	result, err := ws.deleteFrom(QueueItemsTableName).
+		Where(dbr.Eq("queue_name", item.QueueName)).
+		Where(dbr.Eq("operation_id", item.OperationID)).
+		Where(dbr.Eq("lease_owner", item.LeaseOwner)).
+		Where(dbr.Eq("version", item.Version)).
+		Exec()
+	if err != nil {
+		return false, dberr.Internal("Failed to delete record from queue_items table: %s", err)
+	}
+	rAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, dberr.Internal("the DB driver does not support RowsAffected operation %s", err)
+	}
+	return rAffected > 0, nil
+}
+
+// RescheduleQueueItem sets the next run of the leased item and releases the lease, returns false if the item was added to the queue again in the meantime
+func (ws writeSession) RescheduleQueueItem(item dbmodel.QueueItemDTO, nextRunAt time.Time) (bool, dberr.Error) {
+	result, err := ws.update(QueueItemsTableName).
+		Set("next_run_at", nextRunAt).
+		Set("lease_owner", "").
+		Set("lease_expires_at", time.Time{}).
+		Where(dbr.Eq("queue_name", item.QueueName)).
+		Where(dbr.Eq("operation_id", item.OperationID)).
+		Where(dbr.Eq("lease_owner", item.LeaseOwner)).
+		Where(dbr.Eq("version", item.Version)).
+		Exec()
+	if err != nil {
+		return false, dberr.Internal("Failed to reschedule record in queue_items table: %s", err)
+	}
+	rAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, dberr.Internal("the DB driver does not support RowsAffected operation %s", err)
+	}
+	return rAffected > 0, nil
+}
+
+func (ws writeSession) ReleaseQueueItem(item dbmodel.QueueItemDTO) dberr.Error {
+	_, err := ws.update(QueueItemsTableName).
+		Set("lease_owner", "").
+		Set("lease_expires_at", time.Time{}).
+		Where(dbr.Eq("queue_name", item.QueueName)).
+		Where(dbr.Eq("operation_id", item.OperationID)).
+		Where(dbr.Eq("lease_owner", item.LeaseOwner)).
+		Exec()
+	if err != nil {
+		return dberr.Internal("Failed to release record in queue_items table: %s", err)
+	}
+	return nil
+}
+
+// InsertChange writes the change to the outbox, within a transaction the change gets the sequence number
+// after all changes committed before
+func (ws writeSession) InsertChange(change changes.ChangeDTO) dberr.Error {
+	if err := ws.lockChanges(); err != nil {
+		return err
+	}
+	_, err := ws.insertInto(ChangesTableName).
+		Pair("kind", change.Kind).
+		Pair("instance_id", change.InstanceID).
+		Pair("operation_id", change.OperationID).
+		Pair("operation_type", change.OperationType).
+		Pair("state", change.State).
+		Pair("previous_state", change.PreviousState).
+		Pair("created_at", change.CreatedAt).
+		Exec()
+	if err != nil {
+		return dberr.Internal("Failed to insert record to Changes table: %s", err)
+	}
+	return nil
+}
+
+// InsertOperationChange writes the change of the operation state to the outbox. The previous state is taken from
+// the stored version of the operation, the change is not written if the state is not changed or the stored
+// version is different, so it must precede the update of the operation in the same transaction.
+// The changes are locked only when the state is changed, the other updates of the operations run concurrently.
+func (ws writeSession) InsertOperationChange(op dbmodel.OperationDTO) dberr.Error {
+	var changed []string
+	_, err := ws.selectBySql(fmt.Sprintf(`SELECT id FROM %s WHERE id = ? AND version = ? AND state <> ?`, OperationTableName),
+		op.ID, op.Version, op.State).Load(&changed)
+	if err != nil {
+		return dberr.Internal("Failed to get the state of the operation: %s", err)
+	}
+	if len(changed) == 0 {
+		return nil
+	}
+
+	if err := ws.lockChanges(); err != nil {
+		return err
+	}
+	query := fmt.Sprintf(`INSERT INTO %s (kind, instance_id, operation_id, operation_type, state, previous_state, created_at)
+		SELECT ?, instance_id, id, type, ?, state, ? FROM %s WHERE id = ? AND version = ? AND state <> ?`,
+		ChangesTableName, OperationTableName)
+	_, err = ws.insertBySql(query, changes.OperationKind, op.State, time.Now(), op.ID, op.Version, op.State).Exec()
+	if err != nil {
+		return dberr.Internal("Failed to insert record to Changes table: %s", err)
+	}
+	return nil
+}
+
+func (ws writeSession) DeleteChanges(until time.Time) (int, dberr.Error) {
+	res, err := ws.deleteFrom(ChangesTableName).
+		Where(dbr.Lte("created_at", until)).
+		Exec()
+	if err != nil {
+		return 0, dberr.Internal("failed to delete changes created until %v: %v", until.Format(time.RFC1123Z), err)
+	}
+	deleted, err := res.RowsAffected()
+	if err != nil {
+		return 0, dberr.Internal("the DB driver does not support RowsAffected operation")
+	}
+	return int(deleted), nil
+}
+
+func (ws writeSession) lockChanges() dberr.Error {
+	statement := ws.dialect.lockChanges()
+	if ws.transaction == nil || statement == "" {
+		return nil
+	}
+	if _, err := ws.transaction.Exec(statement); err != nil {
+		return dberr.Internal("Failed to lock the changes: %s", err)
+	}
+	return nil
+}
+
 func (ws writeSession) Commit() dberr.Error {
 	err := ws.transaction.Commit()
 	if err != nil {
@@ -449,6 +763,14 @@ func (ws writeSession) insertInto(table string) *dbr.InsertStmt {
 	return ws.session.InsertInto(table)
 }
 
+func (ws writeSession) insertBySql(query string, value ...interface{}) *dbr.InsertStmt {
+	if ws.transaction != nil {
+		return ws.transaction.InsertBySql(query, value...)
+	}
+
+	return ws.session.InsertBySql(query, value...)
+}
+
 func (ws writeSession) deleteFrom(table string) *dbr.DeleteStmt {
 	if ws.transaction != nil {
 		return ws.transaction.DeleteFrom(table)
@@ -457,6 +779,22 @@ func (ws writeSession) deleteFrom(table string) *dbr.DeleteStmt {
 	return ws.session.DeleteFrom(table)
 }
 
+func (ws writeSession) updateBySql(query string, value ...interface{}) *dbr.UpdateStmt {
+	if ws.transaction != nil {
+		return ws.transaction.UpdateBySql(query, value...)
+	}
+
+	return ws.session.UpdateBySql(query, value...)
+}
+
+func (ws writeSession) selectBySql(query string, value ...interface{}) *dbr.SelectStmt {
+	if ws.transaction != nil {
+		return ws.transaction.SelectBySql(query, value...)
+	}
+
+	return ws.session.SelectBySql(query, value...)
+}
+
 func (ws writeSession) update(table string) *dbr.UpdateStmt {
 	if ws.transaction != nil {
 		return ws.transaction.Update(table)
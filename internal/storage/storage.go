@@ -1,13 +1,17 @@
 package storage
 
 import (
+	"errors"
 	"fmt"
 	"log/slog"
+	"strings"
 	"time"
 
 	"github.com/gocraft/dbr"
+	"github.com/google/uuid"
 	eventsapi "github.com/kyma-project/kyma-environment-broker/common/events"
 	"github.com/kyma-project/kyma-environment-broker/internal/events"
+	"github.com/kyma-project/kyma-environment-broker/internal/ptr"
 	"github.com/kyma-project/kyma-environment-broker/internal/storage/driver/memory"
 	postgres "github.com/kyma-project/kyma-environment-broker/internal/storage/driver/postsql"
 	eventstorage "github.com/kyma-project/kyma-environment-broker/internal/storage/driver/postsql/events"
@@ -25,6 +29,19 @@ type BrokerStorage interface {
 	Bindings() Bindings
 	Actions() Actions
 	TimeZones() TimeZones
+	QueueItems() QueueItems
+	OperationSteps() OperationSteps
+	EncryptedData() EncryptedData
+	Retention() Retention
+	Changes() Changes
+	// ReadOnly returns the storage for the reporting queries, which reads from the read replica if it is configured.
+	// The data read from it may be stale, the reads which must see the latest writes use the storage itself.
+	ReadOnly() BrokerStorage
+	// InTransaction runs fn with the storage whose writes are committed together if fn succeeds and rolled back otherwise.
+	// The memory storage does not roll back the writes.
+	InTransaction(fn func(tx BrokerStorage) error) error
+	// Close closes the database connections opened for the storage, including the connection to the read replica.
+	Close() error
 }
 
 const (
@@ -48,32 +65,97 @@ func NewFromConfigAndConnectionURL(cfg Config, evcfg events.Config, cipher postg
 	connection.SetMaxIdleConns(cfg.MaxIdleConns)
 	connection.SetMaxOpenConns(cfg.MaxOpenConns)
 
-	factory := postsql.NewFactory(connection)
+	brokerStorage := newSQLStorage(postsql.NewFactory(connection), evcfg, cipher)
+	brokerStorage.connections = []*dbr.Connection{connection}
+	if cfg.ReadReplicaURL == "" {
+		return brokerStorage, connection, nil
+	}
+
+	// the replica is not required to be available, the reads use the primary database until the replica is healthy
+	replica, err := dbr.Open("postgres", cfg.ReadReplicaURL, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("while opening the connection to the read replica: %w", err)
+	}
+	replica.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+	replica.SetMaxIdleConns(cfg.MaxIdleConns)
+	replica.SetMaxOpenConns(cfg.MaxOpenConns)
+	slog.Info(fmt.Sprintf("Reporting queries read from the read replica, max staleness: %s", cfg.ReadReplicaMaxStaleness))
+
+	brokerStorage.readOnly = newSQLStorage(postsql.NewReplicaFactory(connection, replica, cfg.ReadReplicaMaxStaleness), evcfg, cipher)
+	brokerStorage.connections = append(brokerStorage.connections, replica)
+	return brokerStorage, connection, nil
+}
+
+// NewSQLite creates the storage in the SQLite database, for example, a file path or ":memory:".
+// It is meant for local development and tests, the binary must register the SQLite driver by importing modernc.org/sqlite.
+func NewSQLite(dataSourceName string, evcfg events.Config, cipher postgres.Cipher) (BrokerStorage, *dbr.Connection, error) {
+	connection, err := postsql.InitializeSQLiteDatabase(dataSourceName)
+	if err != nil {
+		return nil, nil, err
+	}
+	brokerStorage := newSQLStorage(postsql.NewSQLiteFactory(connection), evcfg, cipher)
+	brokerStorage.connections = []*dbr.Connection{connection}
+	return brokerStorage, connection, nil
+}
+
+func newSQLStorage(factory postsql.Factory, evcfg events.Config, cipher postgres.Cipher) *storage {
+	s := newSQLStorageWithEvents(factory, events.New(evcfg, eventstorage.New(factory)), cipher)
+	s.inTransaction = func(fn func(tx BrokerStorage) error) error {
+		txFactory, err := factory.BeginTransaction()
+		if err != nil {
+			return err
+		}
+		defer txFactory.RollbackUnlessCommitted()
 
+		// the events of the transaction are written within it, events.New keeps the events storage of the connection
+		var txEvents Events
+		if evcfg.Enabled {
+			txEvents = eventstorage.New(txFactory)
+		}
+		if err := fn(newSQLStorageWithEvents(txFactory, txEvents, cipher)); err != nil {
+			return err
+		}
+		return txFactory.Commit()
+	}
+	return s
+}
+
+func newSQLStorageWithEvents(factory postsql.Factory, eventsStorage Events, cipher postgres.Cipher) *storage {
 	operation := postgres.NewOperation(factory, cipher)
-	return storage{
+	return &storage{
 		instance:          postgres.NewInstance(factory, operation, cipher),
 		operation:         operation,
-		events:            events.New(evcfg, eventstorage.New(factory)),
+		events:            eventsStorage,
 		subaccountStates:  postgres.NewSubaccountStates(factory),
 		instancesArchived: postgres.NewInstanceArchived(factory),
 		bindings:          postgres.NewBinding(factory, cipher),
 		actions:           postgres.NewAction(factory),
 		timezones:         postgres.NewTimeZones(factory),
-	}, connection, nil
+		queueItems:        postgres.NewQueueItems(factory),
+		operationSteps:    postgres.NewOperationSteps(factory),
+		encryptedData:     postgres.NewEncryptedData(factory),
+		retention:         postgres.NewRetention(factory),
+		changes:           postgres.NewChanges(factory),
+	}
 }
 
 func NewMemoryStorage() BrokerStorage {
 	op := memory.NewOperation()
 	ss := memory.NewSubaccountStates()
+	instance := memory.NewInstance(op, ss)
 	return storage{
 		operation:         op,
 		subaccountStates:  ss,
-		instance:          memory.NewInstance(op, ss),
+		instance:          instance,
 		events:            events.New(events.Config{}, newInMemoryEvents()),
 		instancesArchived: memory.NewInstanceArchivedInMemoryStorage(),
 		bindings:          memory.NewBinding(),
 		actions:           memory.NewAction(),
+		queueItems:        memory.NewQueueItems(),
+		operationSteps:    memory.NewOperationSteps(),
+		encryptedData:     memory.NewEncryptedData(),
+		retention:         memory.NewRetention(instance),
+		changes:           op.Changes(),
 	}
 }
 
@@ -91,9 +173,16 @@ func (*inMemoryEvents) RunGarbageCollection(pollingPeriod, retention time.Durati
 	panic("not implemented")
 }
 
-func (e *inMemoryEvents) InsertEvent(eventLevel eventsapi.EventLevel, message, instanceID, operationID string) {
-	e.events = append(e.events, eventsapi.EventDTO{Level: eventLevel, InstanceID: &instanceID, OperationID: &operationID, Message: message})
-	slog.Info(fmt.Sprintf("EVENT [instanceID=%v/operationID=%v] %v: %v", instanceID, operationID, eventLevel, message))
+func (e *inMemoryEvents) InsertEvent(event eventsapi.EventDTO) {
+	event.ID = uuid.NewString()
+	event.CreatedAt = time.Now()
+	e.events = append(e.events, event)
+	slog.Info(fmt.Sprintf("EVENT [instanceID=%v/operationID=%v] %v: %v", ptr.ToString(event.InstanceID), ptr.ToString(event.OperationID), event.Level, event.Message))
+}
+
+func (e *inMemoryEvents) ImportEvent(event eventsapi.EventDTO) error {
+	e.events = append(e.events, event)
+	return nil
 }
 
 func (e *inMemoryEvents) ListEvents(filter eventsapi.EventFilter) ([]eventsapi.EventDTO, error) {
@@ -105,6 +194,21 @@ func (e *inMemoryEvents) ListEvents(filter eventsapi.EventFilter) ([]eventsapi.E
 		if !requiredContains(ev.OperationID, filter.OperationIDs) {
 			continue
 		}
+		if !requiredContains(&ev.Level, filter.Levels) {
+			continue
+		}
+		if !requiredContains(&ev.Category, filter.Categories) {
+			continue
+		}
+		if !filter.From.IsZero() && ev.CreatedAt.Before(filter.From) {
+			continue
+		}
+		if !filter.To.IsZero() && !ev.CreatedAt.Before(filter.To) {
+			continue
+		}
+		if filter.Text != "" && !containsText(ev, filter.Text) {
+			continue
+		}
 		events = append(events, ev)
 	}
 	return events, nil
@@ -134,6 +238,15 @@ type storage struct {
 	bindings          Bindings
 	actions           Actions
 	timezones         TimeZones
+	queueItems        QueueItems
+	operationSteps    OperationSteps
+	encryptedData     EncryptedData
+	retention         Retention
+	changes           Changes
+
+	readOnly      BrokerStorage
+	inTransaction func(fn func(tx BrokerStorage) error) error
+	connections   []*dbr.Connection
 }
 
 func (s storage) Instances() Instances {
@@ -173,3 +286,60 @@ func (s storage) Actions() Actions {
 }
 
 func (s storage) TimeZones() TimeZones { return s.timezones }
+
+func (s storage) QueueItems() QueueItems {
+	return s.queueItems
+}
+
+func (s storage) OperationSteps() OperationSteps {
+	return s.operationSteps
+}
+
+func (s storage) EncryptedData() EncryptedData {
+	return s.encryptedData
+}
+
+func (s storage) Retention() Retention {
+	return s.retention
+}
+
+func (s storage) Changes() Changes {
+	return s.changes
+}
+
+func (s storage) ReadOnly() BrokerStorage {
+	if s.readOnly == nil {
+		return s
+	}
+	return s.readOnly
+}
+
+func (s storage) InTransaction(fn func(tx BrokerStorage) error) error {
+	if s.inTransaction == nil {
+		return fn(s)
+	}
+	return s.inTransaction(fn)
+}
+
+func (s storage) Close() error {
+	var errs []error
+	for _, connection := range s.connections {
+		if err := connection.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func containsText(ev eventsapi.EventDTO, text string) bool {
+	text = strings.ToLower(text)
+	if strings.Contains(strings.ToLower(ev.Message), text) {
+		return true
+	}
+	for key, value := range ev.Attributes {
+		if strings.Contains(strings.ToLower(key), text) || strings.Contains(strings.ToLower(value), text) {
+			return true
+		}
+	}
+	return false
+}
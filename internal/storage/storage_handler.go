@@ -5,6 +5,7 @@ import (
 	"os"
 	"path"
 	"runtime"
+	"strconv"
 	"strings"
 
 	"github.com/gocraft/dbr"
@@ -45,6 +46,9 @@ func GetStorageForTests(config Config, options ...Option) (func() error, BrokerS
 		option(storageForTests)
 	}
 
+	if SQLiteForStorageTests() {
+		return GetSQLiteTestStorage(storageForTests.encrypter)
+	}
 	return GetTestStorage(storageForTests.config, storageForTests.encrypter, storageForTests.connectionURL)
 }
 
@@ -86,6 +90,21 @@ func GetTestStorage(config Config, encrypter *Encrypter, connectionURL string) (
 	return cleanup, storageForTests, nil
 }
 
+// SQLiteForStorageTests returns true if the storage tests use the SQLite database in memory instead of PostgreSQL in a Docker container
+func SQLiteForStorageTests() bool {
+	v, _ := strconv.ParseBool(os.Getenv("DB_SQLITE_FOR_STORAGE_TESTS"))
+	return v
+}
+
+// GetSQLiteTestStorage creates the storage in a new SQLite database in memory, the database is removed when the connection is closed
+func GetSQLiteTestStorage(encrypter *Encrypter) (func() error, BrokerStorage, error) {
+	storageForTests, connection, err := NewSQLite(":memory:", events.Config{}, encrypter)
+	if err != nil {
+		return nil, nil, fmt.Errorf("while creating storage: %w", err)
+	}
+	return connection.Close, storageForTests, nil
+}
+
 func runMigrations(connection *dbr.Connection, order migrationOrder) error {
 	_, currentPath, _, _ := runtime.Caller(0)
 	migrationsPath := fmt.Sprintf("%s/resources/keb/migrations/", path.Join(path.Dir(currentPath), "../../"))
@@ -61,7 +61,7 @@ func (p *Provider) CreateAdditionalWorkers(values internal.ProviderValues, curre
 			if len(workerZones) > 3 {
 				workerZones = workerZones[:3]
 			}
-			if !additionalWorkerNodePool.HAZones || planID == broker.AzureLitePlanID {
+			if plan, _ := broker.AvailablePlans.GetDefinition(broker.PlanIDType(planID)); !additionalWorkerNodePool.HAZones || plan.Schema == configuration.AzureLiteSchema {
 				workerZones = workerZones[:1]
 			}
 		}
@@ -0,0 +1,16 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS queue_items (
+    queue_name       varchar(255) NOT NULL,
+    operation_id     varchar(255) NOT NULL,
+    next_run_at      timestamp with time zone NOT NULL,
+    lease_owner      varchar(255) NOT NULL DEFAULT '',
+    lease_expires_at timestamp with time zone NOT NULL DEFAULT '0001-01-01 00:00:00+00',
+    version          integer NOT NULL DEFAULT 0,
+    created_at       timestamp with time zone NOT NULL,
+    PRIMARY KEY (queue_name, operation_id)
+);
+
+CREATE INDEX IF NOT EXISTS queue_items_by_queue_next_run_at ON queue_items USING btree (queue_name, next_run_at);
+
+COMMIT;
@@ -0,0 +1,19 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS operation_steps (
+    id                  varchar(255) NOT NULL PRIMARY KEY,
+    operation_id        varchar(255) NOT NULL,
+    stage               varchar(255) NOT NULL DEFAULT '',
+    step                varchar(255) NOT NULL,
+    started_at          timestamp with time zone NOT NULL,
+    finished_at         timestamp with time zone NOT NULL,
+    outcome             varchar(32) NOT NULL,
+    retry_after_seconds double precision NOT NULL DEFAULT 0,
+    error_reason        text NOT NULL DEFAULT '',
+    error_component     varchar(255) NOT NULL DEFAULT '',
+    error_message       text NOT NULL DEFAULT ''
+);
+
+CREATE INDEX IF NOT EXISTS operation_steps_operation_id ON operation_steps USING btree (operation_id, started_at);
+
+COMMIT;
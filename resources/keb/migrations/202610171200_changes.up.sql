@@ -0,0 +1,16 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS changes (
+    sequence            bigserial PRIMARY KEY,
+    kind                varchar(32) NOT NULL,
+    instance_id         varchar(255) NOT NULL,
+    operation_id        varchar(255) NOT NULL DEFAULT '',
+    operation_type      varchar(32) NOT NULL DEFAULT '',
+    state               varchar(32) NOT NULL,
+    previous_state      varchar(32) NOT NULL DEFAULT '',
+    created_at          timestamp with time zone NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS changes_created_at ON changes USING btree (created_at);
+
+COMMIT;
@@ -0,0 +1,15 @@
+BEGIN;
+
+DROP INDEX IF EXISTS events_created_at;
+
+ALTER TABLE events DROP COLUMN IF EXISTS attributes;
+ALTER TABLE events DROP COLUMN IF EXISTS category;
+
+DELETE FROM events WHERE level IN ('debug', 'warning');
+
+ALTER TYPE event_level RENAME TO event_level_old;
+CREATE TYPE event_level AS ENUM ('info', 'error');
+ALTER TABLE events ALTER COLUMN level TYPE event_level USING level::text::event_level;
+DROP TYPE event_level_old;
+
+COMMIT;
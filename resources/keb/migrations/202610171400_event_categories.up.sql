@@ -0,0 +1,7 @@
+ALTER TYPE event_level ADD VALUE IF NOT EXISTS 'debug';
+ALTER TYPE event_level ADD VALUE IF NOT EXISTS 'warning';
+
+ALTER TABLE events ADD COLUMN IF NOT EXISTS category varchar(255) NOT NULL DEFAULT '';
+ALTER TABLE events ADD COLUMN IF NOT EXISTS attributes jsonb;
+
+CREATE INDEX IF NOT EXISTS events_created_at ON events USING btree (created_at);
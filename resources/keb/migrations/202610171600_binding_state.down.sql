@@ -0,0 +1,10 @@
+BEGIN;
+
+DROP INDEX IF EXISTS bindings_by_state;
+DROP INDEX IF EXISTS bindings_by_operation_id;
+
+ALTER TABLE bindings DROP COLUMN IF EXISTS operation_id;
+ALTER TABLE bindings DROP COLUMN IF EXISTS description;
+ALTER TABLE bindings DROP COLUMN IF EXISTS state;
+
+COMMIT;
@@ -0,0 +1,11 @@
+BEGIN;
+
+ALTER TABLE bindings
+    ADD COLUMN IF NOT EXISTS state VARCHAR(32) NOT NULL DEFAULT 'succeeded',
+    ADD COLUMN IF NOT EXISTS description TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS operation_id VARCHAR(255) NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS bindings_by_operation_id ON bindings USING btree (operation_id);
+CREATE INDEX IF NOT EXISTS bindings_by_state ON bindings USING btree (state);
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN;
+
+ALTER TABLE bindings DROP COLUMN IF EXISTS oidc_group;
+ALTER TABLE bindings DROP COLUMN IF EXISTS credentials_type;
+
+COMMIT;
@@ -0,0 +1,7 @@
+BEGIN;
+
+ALTER TABLE bindings
+    ADD COLUMN IF NOT EXISTS credentials_type VARCHAR(32) NOT NULL DEFAULT 'service_account',
+    ADD COLUMN IF NOT EXISTS oidc_group VARCHAR(255) NOT NULL DEFAULT '';
+
+COMMIT;
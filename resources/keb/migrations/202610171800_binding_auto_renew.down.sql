@@ -0,0 +1,7 @@
+BEGIN;
+
+DROP INDEX IF EXISTS bindings_by_auto_renew_expires_at;
+
+ALTER TABLE bindings DROP COLUMN IF EXISTS auto_renew;
+
+COMMIT;
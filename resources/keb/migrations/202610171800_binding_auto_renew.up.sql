@@ -0,0 +1,7 @@
+BEGIN;
+
+ALTER TABLE bindings ADD COLUMN IF NOT EXISTS auto_renew BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE INDEX IF NOT EXISTS bindings_by_auto_renew_expires_at ON bindings USING btree (expires_at) WHERE auto_renew;
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN;
+
+ALTER TABLE bindings DROP COLUMN IF EXISTS namespaces;
+ALTER TABLE bindings DROP COLUMN IF EXISTS role;
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN;
+
+ALTER TABLE bindings ADD COLUMN IF NOT EXISTS role VARCHAR(64) NOT NULL DEFAULT '';
+ALTER TABLE bindings ADD COLUMN IF NOT EXISTS namespaces TEXT NOT NULL DEFAULT '';
+
+COMMIT;
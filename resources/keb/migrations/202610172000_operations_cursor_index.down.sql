@@ -0,0 +1,5 @@
+BEGIN;
+
+DROP INDEX IF EXISTS operations_by_created_at_id;
+
+COMMIT;
@@ -0,0 +1,5 @@
+BEGIN;
+
+CREATE INDEX IF NOT EXISTS operations_by_created_at_id ON operations USING btree (created_at, id);
+
+COMMIT;
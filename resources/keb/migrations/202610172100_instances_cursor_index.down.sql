@@ -0,0 +1,5 @@
+BEGIN;
+
+DROP INDEX IF EXISTS instances_by_created_at_instance_id;
+
+COMMIT;
@@ -0,0 +1,5 @@
+BEGIN;
+
+CREATE INDEX IF NOT EXISTS instances_by_created_at_instance_id ON instances USING btree (created_at, instance_id);
+
+COMMIT;
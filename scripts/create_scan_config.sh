@@ -29,6 +29,8 @@ bdba:
   - europe-docker.pkg.dev/kyma-project/prod/kyma-environment-subaccount-sync:${TAG}
   - europe-docker.pkg.dev/kyma-project/prod/kyma-environment-broker-schema-migrator:${TAG}
   - europe-docker.pkg.dev/kyma-project/prod/kyma-environment-service-binding-cleanup-job:${TAG}
+  - europe-docker.pkg.dev/kyma-project/prod/kyma-environment-reencryption-job:${TAG}
+  - europe-docker.pkg.dev/kyma-project/prod/kyma-environment-retention-job:${TAG}
 mend:
   language: golang-mod
   exclude:
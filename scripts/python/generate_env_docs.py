@@ -27,9 +27,11 @@ SINGLE_JOBS = [
     ("resources/keb/templates/deployment.yaml", "docs/contributor/02-30-keb-configuration.md"),
     ("resources/keb/templates/deprovision-retrigger-job.yaml", "docs/contributor/06-50-deprovision-retrigger-cronjob.md"),
     ("resources/keb/templates/service-binding-cleanup-job.yaml", "docs/contributor/06-70-service-binding-cleanup-cronjob.md"),
+    ("resources/keb/templates/retention-job.yaml", "docs/contributor/06-80-retention-cronjob.md"),
     ("resources/keb/templates/runtime-reconciler-deployment.yaml", "docs/contributor/07-10-runtime-reconciler.md"),
     ("resources/keb/templates/subaccount-sync-deployment.yaml", "docs/contributor/07-20-subaccount-sync.md"),
     ("resources/keb/templates/migrator-job.yaml", "docs/contributor/07-30-schema-migrator.md"),
+    ("resources/keb/templates/reencryption-job.yaml", "docs/contributor/07-40-reencryption-job.md"),
     ("resources/keb/templates/subaccount-cleanup-job.yaml", "docs/contributor/06-30-subaccount-cleanup-cronjob.md"),
 ]
 MULTI_JOBS_IN_ONE_TEMPLATE = [
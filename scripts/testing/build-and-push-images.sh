@@ -25,6 +25,8 @@ declare -a IMAGES=(
     "kyma-environment-subaccount-sync:Dockerfile.subaccountsync:BIN=subaccount-sync"
     "kyma-environment-broker-schema-migrator:Dockerfile.schemamigrator:"
     "kyma-environment-service-binding-cleanup-job:Dockerfile.job:BIN=servicebindingcleanup"
+    "kyma-environment-reencryption-job:Dockerfile.job:BIN=reencryption"
+    "kyma-environment-retention-job:Dockerfile.job:BIN=retention"
     "keb-analytics:Dockerfile.keb-analytics:VERSION=${VERSION}"
 )
 